@@ -1,9 +1,11 @@
 package common
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 )
 
 // Route
@@ -15,6 +17,11 @@ type Route struct {
 	Action      ActionFunc
 	Middlewares []MiddlewareFunc
 	Policies    []PolicyFunc
+	Group       *RouteGroup // Group is the group the route was added to, or nil if added to the cosys app.
+	Doc         RouteDoc    // Doc documents the route in the api specification.
+	// Timeout is the maximum duration of requests to the route, after which their context is done,
+	// or unlimited if not positive.
+	Timeout time.Duration
 }
 
 // String returns the route path.
@@ -30,6 +37,7 @@ func NewRoute(method string, path string, action ActionFunc, options ...RouteOpt
 		Action:      action,
 		Middlewares: []MiddlewareFunc{},
 		Policies:    []PolicyFunc{},
+		Group:       nil,
 	}
 
 	for _, option := range options {
@@ -39,6 +47,20 @@ func NewRoute(method string, path string, action ActionFunc, options ...RouteOpt
 	return newRoute
 }
 
+// routeKey is the context key of the route matched by a request.
+type routeKey struct{}
+
+// ContextWithRoute returns a copy of the given context containing the route matched by a request.
+func ContextWithRoute(ctx context.Context, route Route) context.Context {
+	return context.WithValue(ctx, routeKey{}, route)
+}
+
+// RouteFromContext returns the route matched by a request from its context, and whether one was found.
+func RouteFromContext(ctx context.Context) (Route, bool) {
+	route, ok := ctx.Value(routeKey{}).(Route)
+	return route, ok
+}
+
 // GetAction returns the actionFunc based on the actionFunc
 // added to the cosys instance under the given uid.
 // The actionFunc with the is retrieved from the cosys instance
@@ -95,7 +117,7 @@ func UseMiddlewares(middlewares ...MiddlewareFunc) RouteOption {
 // the resulting middlewareFuncs will use the updated middlewareFuncs or throw errors if removed.
 func GetMiddlewares(uids ...string) RouteOption {
 	return func(route *Route) {
-		middlewares := make([]MiddlewareFunc, len(uids))
+		middlewares := make([]MiddlewareFunc, 0, len(uids))
 		for _, uid := range uids {
 			middleware := func(cosys *Cosys) (func(http.HandlerFunc) http.HandlerFunc, error) {
 				m, err := cosys.middlewares.Get(uid)
@@ -118,6 +140,76 @@ func GetMiddlewares(uids ...string) RouteOption {
 	}
 }
 
+// RouteDoc documents a route in the api specification.
+type RouteDoc struct {
+	Summary     string
+	Description string
+	Tags        []string
+	Query       []ParamDoc // Query are the query parameters of the route. Path parameters are documented automatically.
+	Request     *TypeDoc   // Request is the type of the request body, if any.
+	Response    *TypeDoc   // Response is the type of the data of the response body, if any.
+	Hidden      bool       // Hidden specifies that the route is left out of the api specification.
+}
+
+// ParamDoc documents a parameter of a route.
+type ParamDoc struct {
+	Name        string
+	Description string
+	Type        string // Type is the json schema type of the parameter, by default string.
+	Required    bool
+}
+
+// TypeDoc documents the type of a request or response body.
+type TypeDoc struct {
+	Model string // Model is the uid of the model whose entities are the body.
+	Value any    // Value is a value of the go type of the body, used if Model is empty.
+	Many  bool   // Many specifies that the body is a list.
+}
+
+// ModelType returns the type of a single entity of the model of the given uid.
+func ModelType(modelUid string) *TypeDoc {
+	return &TypeDoc{
+		Model: modelUid,
+	}
+}
+
+// ModelListType returns the type of a list of entities of the model of the given uid.
+func ModelListType(modelUid string) *TypeDoc {
+	return &TypeDoc{
+		Model: modelUid,
+		Many:  true,
+	}
+}
+
+// TypeOf returns the go type of the given value, e.g. TypeOf(Post{}).
+func TypeOf(value any) *TypeDoc {
+	return &TypeDoc{
+		Value: value,
+	}
+}
+
+// RouteTimeout sets the maximum duration of requests to the route, after which their context is done.
+func RouteTimeout(timeout time.Duration) RouteOption {
+	return func(route *Route) {
+		if route == nil {
+			return
+		}
+
+		route.Timeout = timeout
+	}
+}
+
+// Describe documents the route in the api specification.
+func Describe(doc RouteDoc) RouteOption {
+	return func(route *Route) {
+		if route == nil {
+			return
+		}
+
+		route.Doc = doc
+	}
+}
+
 // UsePolicies adds policyFuncs to the route.
 func UsePolicies(policies ...PolicyFunc) RouteOption {
 	return func(route *Route) {
@@ -137,7 +229,7 @@ func UsePolicies(policies ...PolicyFunc) RouteOption {
 // the resulting policyFuncs will use the updated policyFuncs or throw errors if removed.
 func GetPolicies(uids ...string) RouteOption {
 	return func(route *Route) {
-		policies := make([]PolicyFunc, len(uids))
+		policies := make([]PolicyFunc, 0, len(uids))
 		for _, uid := range uids {
 			policy := func(cosys *Cosys) (func(*http.Request) bool, error) {
 				p, err := cosys.policies.Get(uid)
@@ -211,6 +303,35 @@ func NewController(uid string, actions map[string]ActionFunc) (Controller, error
 // ActionFunc takes in cosys instance and returns a handler.
 type ActionFunc func(*Cosys) (http.HandlerFunc, error)
 
+// ContextHandlerFunc is a handler taking the context of the request, which is done once the client disconnects,
+// the timeout of the route elapses or the server is shut down.
+type ContextHandlerFunc func(ctx context.Context, w http.ResponseWriter, r *http.Request)
+
+// ContextActionFunc takes in cosys instance and returns a context handler.
+type ContextActionFunc func(*Cosys) (ContextHandlerFunc, error)
+
+// ContextAction returns the context action as an ActionFunc, whose handler is called with the context of the request.
+func ContextAction(action ContextActionFunc) ActionFunc {
+	if action == nil {
+		return nil
+	}
+
+	return func(cosys *Cosys) (http.HandlerFunc, error) {
+		handler, err := action(cosys)
+		if err != nil {
+			return nil, err
+		}
+
+		if handler == nil {
+			return nil, fmt.Errorf("handler is nil")
+		}
+
+		return func(w http.ResponseWriter, r *http.Request) {
+			handler(r.Context(), w, r)
+		}, nil
+	}
+}
+
 // Action is a wrapper around ActionFunc that allows them to be identifiable by uid.
 type Action struct {
 	uid        string
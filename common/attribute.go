@@ -89,7 +89,7 @@ func (a attributeBase) Null() Condition {
 // the value of the attribute is not null.
 func (a attributeBase) NotNull() Condition {
 	return &ExpressionCondition{
-		Null,
+		NotNull,
 		a,
 		nil,
 	}
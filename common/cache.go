@@ -0,0 +1,126 @@
+package common
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Cache is a core service for caching values by key.
+type Cache interface {
+	// Get returns the value of the key, and whether the key was found and has not expired.
+	Get(key string) ([]byte, bool, error)
+	// Set sets the value of the key, expiring after the given ttl, or never if the ttl is not positive.
+	Set(key string, value []byte, ttl time.Duration) error
+	// Delete removes the key, if found.
+	Delete(key string) error
+}
+
+// LRUCache is an in-memory Cache holding up to a fixed number of keys,
+// evicting the least recently used key when full.
+type LRUCache struct {
+	mutex    sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+}
+
+// lruEntry is a value stored in an LRUCache.
+type lruEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// NewLRUCache returns a new empty LRUCache holding up to capacity keys.
+// The cache holds any number of keys if the capacity is not positive.
+func NewLRUCache(capacity int) *LRUCache {
+	return &LRUCache{
+		capacity: capacity,
+		entries:  map[string]*list.Element{},
+		order:    list.New(),
+	}
+}
+
+// Get returns the value of the key, and whether the key was found and has not expired.
+// Safe for concurrent use.
+func (c *LRUCache) Get(key string) ([]byte, bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	entry := element.Value.(*lruEntry)
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		c.remove(element)
+		return nil, false, nil
+	}
+
+	c.order.MoveToFront(element)
+
+	return entry.value, true, nil
+}
+
+// Set sets the value of the key, expiring after the given ttl, or never if the ttl is not positive.
+// Evicts the least recently used key if the cache is full.
+// Safe for concurrent use.
+func (c *LRUCache) Set(key string, value []byte, ttl time.Duration) error {
+	var expires time.Time
+	if ttl > 0 {
+		expires = time.Now().Add(ttl)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*lruEntry)
+		entry.value = value
+		entry.expires = expires
+		c.order.MoveToFront(element)
+		return nil
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{
+		key:     key,
+		value:   value,
+		expires: expires,
+	})
+
+	if c.capacity > 0 && c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+	}
+
+	return nil
+}
+
+// Delete removes the key, if found.
+// Safe for concurrent use.
+func (c *LRUCache) Delete(key string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+
+	return nil
+}
+
+// Len returns the number of keys in the cache, including expired keys not yet removed.
+// Safe for concurrent use.
+func (c *LRUCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.order.Len()
+}
+
+// remove removes the given element from the cache.
+func (c *LRUCache) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*lruEntry).key)
+}
@@ -2,7 +2,6 @@ package common
 
 import (
 	"github.com/spf13/cobra"
-	"log"
 )
 
 // Command takes in a cosys instance and returns a command.
@@ -15,45 +14,61 @@ func (c Command) String() string {
 
 // serveCmd is the command to start the server in production mode.
 func serveCmd(cosys *Cosys) *cobra.Command {
-	return &cobra.Command{
+	return withShutdownTimeout(&cobra.Command{
 		Use:   "serve",
 		Short: "Start the server in production mode",
 		Run: func(cmd *cobra.Command, args []string) {
 			cosys.SetEnvironment(Prod)
+			setShutdownTimeout(cmd, cosys)
 
 			for err := range cosys.startServer() {
-				log.Print(err)
+				cosys.LogError("server error", err)
 			}
 		},
-	}
+	})
 }
 
 // devCmd is the command to start the server in development mode.
 func devCmd(cosys *Cosys) *cobra.Command {
-	return &cobra.Command{
+	return withShutdownTimeout(&cobra.Command{
 		Use:   "dev",
 		Short: "Start the server in development mode",
 		Run: func(cmd *cobra.Command, args []string) {
 			cosys.SetEnvironment(Dev)
+			setShutdownTimeout(cmd, cosys)
 
 			for err := range cosys.startServer() {
-				log.Print(err)
+				cosys.LogError("server error", err)
 			}
 		},
-	}
+	})
 }
 
 // testCmd is the command to start the server in test mode.
 func testCmd(cosys *Cosys) *cobra.Command {
-	return &cobra.Command{
+	return withShutdownTimeout(&cobra.Command{
 		Use:   "test",
 		Short: "Start the server in test mode",
 		Run: func(cmd *cobra.Command, args []string) {
 			cosys.SetEnvironment(Test)
+			setShutdownTimeout(cmd, cosys)
 
 			for err := range cosys.startServer() {
-				log.Print(err)
+				cosys.LogError("server error", err)
 			}
 		},
+	})
+}
+
+// withShutdownTimeout adds the flag for the shutdown timeout to a command that starts the server.
+func withShutdownTimeout(cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().Duration("shutdown-timeout", DefaultShutdownTimeout, "time given to in-flight requests to complete during shutdown")
+	return cmd
+}
+
+// setShutdownTimeout sets the shutdown timeout of the cosys app from the flag of the command.
+func setShutdownTimeout(cmd *cobra.Command, cosys *Cosys) {
+	if timeout, err := cmd.Flags().GetDuration("shutdown-timeout"); err == nil {
+		cosys.SetShutdownTimeout(timeout)
 	}
 }
@@ -0,0 +1,55 @@
+package common
+
+// Component is the value of a component, a reusable group of fields
+// that is embedded in the entities of content types.
+type Component map[string]any
+
+// ComponentTypeKey is the key under which the uid of a component is stored
+// for components in dynamic zones.
+const ComponentTypeKey = "__component"
+
+// Type returns the uid of the component, as stored under ComponentTypeKey.
+func (c Component) Type() string {
+	uid, _ := c[ComponentTypeKey].(string)
+	return uid
+}
+
+// ComponentAttribute is an attribute holding a single component,
+// or a list of components of the same type if repeatable.
+type ComponentAttribute struct {
+	*attributeBase
+}
+
+// NewComponentAttribute returns a new component attribute with the given name.
+func NewComponentAttribute(name string) ComponentAttribute {
+	base := newAttributeBase(name)
+
+	return ComponentAttribute{
+		&base,
+	}
+}
+
+// DynamicZoneAttribute is an attribute holding an ordered list of components of mixed types.
+type DynamicZoneAttribute struct {
+	*attributeBase
+}
+
+// NewDynamicZoneAttribute returns a new dynamic zone attribute with the given name.
+func NewDynamicZoneAttribute(name string) DynamicZoneAttribute {
+	base := newAttributeBase(name)
+
+	return DynamicZoneAttribute{
+		&base,
+	}
+}
+
+// IsEmbedded returns whether the attribute holds embedded components,
+// i.e. whether it is a component or dynamic zone attribute.
+func IsEmbedded(attr Attribute) bool {
+	switch attr.(type) {
+	case ComponentAttribute, DynamicZoneAttribute:
+		return true
+	default:
+		return false
+	}
+}
@@ -22,6 +22,29 @@ func InitConfigs() {
 	}
 }
 
+// ReadConfigs reads the project configurations from the working directory, if they exist.
+// Unlike InitConfigs, missing project configurations are not an error.
+func ReadConfigs() error {
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	viper.SetConfigType("yaml")
+	viper.SetConfigName(".cli_configs")
+	viper.AddConfigPath(dir)
+	if err = viper.ReadInConfig(); err != nil {
+		var notFound viper.ConfigFileNotFoundError
+		if errors.As(err, &notFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	return nil
+}
+
 func GetPathConfig(key string, checkExists bool) (string, error) {
 	if !viper.InConfig(key) {
 		return "", fmt.Errorf("configuration not found: %s", key)
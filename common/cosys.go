@@ -1,57 +1,94 @@
 package common
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/spf13/cobra"
 	"os"
+	"os/signal"
+	"slices"
+	"sync/atomic"
+	"syscall"
+	"time"
 )
 
+// DefaultShutdownTimeout is the default duration that in-flight requests are given to complete during shutdown.
+const DefaultShutdownTimeout = 30 * time.Second
+
 // Cosys is the cosys app.
 type Cosys struct {
-	environment Environment
-	state       State
-	shutdown    <-chan os.Signal
-
-	server   *singleRegister[Server]
-	database *singleRegister[Database]
-	logger   *singleRegister[Logger]
-
+	environment     Environment
+	state           State
+	shutdown        <-chan os.Signal
+	shutdownTimeout time.Duration
+	ready           atomic.Bool
+
+	server     *singleRegister[Server]
+	grpcServer *singleRegister[Server]
+	database   *singleRegister[Database]
+	logger     *singleRegister[Logger]
+	tracer     *singleRegister[Tracer]
+	cache      *singleRegister[Cache]
+
+	global      *RouteGroup
 	routes      *stringerRegister[Route]
 	controllers *stringerRegister[Controller]
 	middlewares *stringerRegister[Middleware]
 	policies    *stringerRegister[Policy]
 
+	grpcServices *stringerRegister[GrpcService]
+
 	commands *stringerRegister[Command]
 	models   *permRegister[Model]
-	services *permRegister[Service]
 
-	bootstrapHooks *multiRegister[BootstrapHook]
-	cleanupHooks   *multiRegister[CleanupHook]
+	container *container // container holds the services provided with Provide.
+
+	modules []string // modules are the names of the named modules, in the order they are registered.
+	module  int      // module is the index of the module being registered, or the number of modules after registration.
+
+	bootstrapHooks *orderedRegister[BootstrapHookContext]
+	cleanupHooks   *orderedRegister[CleanupHookContext]
+	healthChecks   *orderedRegister[HealthCheck]
+
+	databaseWrappers *orderedRegister[DatabaseWrapper]
 }
 
 // New returns a new cosys instance, with modules registered.
 func New() (*Cosys, error) {
 	cosys := &Cosys{
-		environment: "",
-		state:       Registration,
+		environment:     "",
+		state:           Registration,
+		shutdownTimeout: DefaultShutdownTimeout,
 
-		server:   newSingleRegister[Server](itemName("server")),
-		database: newSingleRegister[Database](itemName("database")),
-		logger:   newSingleRegister[Logger](itemName("logger")),
+		server:     newSingleRegister[Server](itemName("server")),
+		grpcServer: newSingleRegister[Server](itemName("grpc server")),
+		database:   newSingleRegister[Database](itemName("database")),
+		logger:     newSingleRegister[Logger](itemName("logger")),
+		tracer:     newSingleRegister[Tracer](itemName("tracer")),
+		cache:      newSingleRegister[Cache](itemName("cache")),
 
 		routes:      newStringerRegister[Route](itemName("routes")),
 		controllers: newStringerRegister[Controller](itemName("controller")),
 		middlewares: newStringerRegister[Middleware](itemName("middleware")),
 		policies:    newStringerRegister[Policy](itemName("policies")),
 
+		grpcServices: newStringerRegister[GrpcService](itemName("grpc service")),
+
 		commands: newStringerRegister[Command](itemName("command")),
 		models:   newPermRegister[Model](itemName("model")),
-		services: newPermRegister[Service](itemName("service")),
 
-		bootstrapHooks: newMultiRegister[BootstrapHook](itemName("bootstrap hook")),
-		cleanupHooks:   newMultiRegister[CleanupHook](itemName("cleanup hook")),
+		container: newContainer(),
+
+		bootstrapHooks: newOrderedRegister[BootstrapHookContext](itemName("bootstrap hook")),
+		cleanupHooks:   newOrderedRegister[CleanupHookContext](itemName("cleanup hook"), reverseOrder),
+		healthChecks:   newOrderedRegister[HealthCheck](itemName("health check")),
+
+		databaseWrappers: newOrderedRegister[DatabaseWrapper](itemName("database wrapper")),
 	}
 
+	cosys.global = newRouteGroup(cosys, nil, "")
+
 	if err := cosys.AddCommands(serveCmd, devCmd, testCmd); err != nil {
 		return nil, err
 	}
@@ -78,6 +115,23 @@ func (c *Cosys) State() State {
 	return c.state
 }
 
+// Ready returns whether the cosys app is ready to serve requests,
+// i.e. it has been bootstrapped and is not shutting down.
+// Safe for concurrent use.
+func (c *Cosys) Ready() bool {
+	return c.ready.Load()
+}
+
+// ShutdownTimeout returns the duration that in-flight requests are given to complete during shutdown.
+func (c *Cosys) ShutdownTimeout() time.Duration {
+	return c.shutdownTimeout
+}
+
+// SetShutdownTimeout specifies the duration that in-flight requests are given to complete during shutdown.
+func (c *Cosys) SetShutdownTimeout(timeout time.Duration) {
+	c.shutdownTimeout = timeout
+}
+
 // ShutdownChannel returns a read-only channel that is sent to
 // when the cosys app is interrupted or terminated.
 func (c *Cosys) ShutdownChannel() <-chan os.Signal {
@@ -95,6 +149,17 @@ func (c *Cosys) Server() (Server, error) {
 	return c.server.Get()
 }
 
+// GrpcServer returns the gRPC server core service.
+// Cannot be used during registration.
+// Safe for concurrent use.
+func (c *Cosys) GrpcServer() (Server, error) {
+	if c.state == Registration {
+		return nil, fmt.Errorf("grpc server cannot be used during registration")
+	}
+
+	return c.grpcServer.Get()
+}
+
 // Database returns the database core service.
 // Cannot be used during registration.
 // Safe for concurrent use.
@@ -106,6 +171,18 @@ func (c *Cosys) Database() (Database, error) {
 	return c.database.Get()
 }
 
+// ContextDatabase returns the database core service as a ContextDatabase.
+// Cannot be used during registration.
+// Safe for concurrent use.
+func (c *Cosys) ContextDatabase() (ContextDatabase, error) {
+	database, err := c.Database()
+	if err != nil {
+		return nil, err
+	}
+
+	return WithContext(database), nil
+}
+
 // Logger returns the logger core service.
 // Cannot be used during registration.
 // Safe for concurrent use.
@@ -117,6 +194,44 @@ func (c *Cosys) Logger() (Logger, error) {
 	return c.logger.Get()
 }
 
+// Tracer returns the tracer core service.
+// Cannot be used during registration.
+// Safe for concurrent use.
+func (c *Cosys) Tracer() (Tracer, error) {
+	if c.state == Registration {
+		return nil, fmt.Errorf("tracer cannot be used during registration")
+	}
+
+	return c.tracer.Get()
+}
+
+// Cache returns the cache core service.
+// Cannot be used during registration.
+// Safe for concurrent use.
+func (c *Cosys) Cache() (Cache, error) {
+	if c.state == Registration {
+		return nil, fmt.Errorf("cache cannot be used during registration")
+	}
+
+	return c.cache.Get()
+}
+
+// StartSpan starts a span with the given name using the tracer core service,
+// as a child of the span or remote span context in the given context.
+// Returns a span that does nothing if no tracer is registered.
+func (c *Cosys) StartSpan(ctx context.Context, name string) (context.Context, Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	tracer, err := c.Tracer()
+	if err != nil {
+		return ctx, noopSpan{}
+	}
+
+	return tracer.Start(ctx, name)
+}
+
 // UseServer registers the server core service.
 // Can only be used during registration.
 // Safe for concurrent use.
@@ -128,6 +243,17 @@ func (c *Cosys) UseServer(server Server) error {
 	return c.server.Register(server)
 }
 
+// UseGrpcServer registers the gRPC server core service, which is started and shut down along with the server.
+// Can only be used during registration.
+// Safe for concurrent use.
+func (c *Cosys) UseGrpcServer(server Server) error {
+	if c.state != Registration {
+		return fmt.Errorf("grpc server must be registered during registration")
+	}
+
+	return c.grpcServer.Register(server)
+}
+
 // UseDatabase registers the database core service.
 // Can only be used during registration.
 // Safe for concurrent use.
@@ -139,6 +265,43 @@ func (c *Cosys) UseDatabase(database Database) error {
 	return c.database.Register(database)
 }
 
+// UseContextDatabase registers a context database as the database core service.
+// Can only be used during registration.
+// Safe for concurrent use.
+func (c *Cosys) UseContextDatabase(database ContextDatabase) error {
+	if database == nil {
+		return fmt.Errorf("database is nil")
+	}
+
+	return c.UseDatabase(WithoutContext(database))
+}
+
+// AddDatabaseWrapper adds a wrapper around the database core service,
+// and returns a uid that can be used to remove the wrapper.
+// The wrappers are applied when the cosys app is bootstrapped, ordered by the given options,
+// and otherwise in the order of the modules that added them, so that the last wrapper applied is the outermost.
+// Can only be used during registration.
+// Safe for concurrent use.
+func (c *Cosys) AddDatabaseWrapper(wrapper DatabaseWrapper, options ...HookOption) (string, error) {
+	if c.state != Registration {
+		return "", fmt.Errorf("database wrapper must be added during registration")
+	}
+
+	return c.databaseWrappers.RegisterOrdered(wrapper, c.hookConfig(options))
+}
+
+// RemoveDatabaseWrapper removes a database wrapper specified by its uid.
+// Throws an error if wrapper with uid does not exist.
+// Can only be used during registration.
+// Safe for concurrent use.
+func (c *Cosys) RemoveDatabaseWrapper(uid string) error {
+	if c.state != Registration {
+		return fmt.Errorf("database wrapper must be removed during registration")
+	}
+
+	return c.databaseWrappers.Remove(uid)
+}
+
 // UseLogger registers the logger core service.
 // Can only be used during registration.
 // Safe for concurrent use.
@@ -150,6 +313,28 @@ func (c *Cosys) UseLogger(logger Logger) error {
 	return c.logger.Register(logger)
 }
 
+// UseTracer registers the tracer core service.
+// Can only be used during registration.
+// Safe for concurrent use.
+func (c *Cosys) UseTracer(tracer Tracer) error {
+	if c.state != Registration {
+		return fmt.Errorf("tracer must be registered during registration")
+	}
+
+	return c.tracer.Register(tracer)
+}
+
+// UseCache registers the cache core service.
+// Can only be used during registration.
+// Safe for concurrent use.
+func (c *Cosys) UseCache(cache Cache) error {
+	if c.state != Registration {
+		return fmt.Errorf("cache must be registered during registration")
+	}
+
+	return c.cache.Register(cache)
+}
+
 func (c *Cosys) Routes() []Route {
 	return c.routes.GetSlice()
 }
@@ -168,6 +353,43 @@ func (c *Cosys) UpdateRoute(path string, route Route) error {
 	return c.routes.Update(path, route)
 }
 
+// Use adds the middlewares and policies of the given route options to all routes of the cosys app.
+// Global middlewares and policies are applied before those of route groups and routes.
+// Safe for concurrent use.
+func (c *Cosys) Use(options ...RouteOption) {
+	c.global.Use(options...)
+}
+
+// Group returns a new route group with the given path prefix and options.
+// Routes added to the group have their paths prefixed, and share the middlewares and policies of the group.
+func (c *Cosys) Group(prefix string, options ...RouteOption) *RouteGroup {
+	return c.global.Group(prefix, options...)
+}
+
+// RouteMiddlewares returns all middlewares applied to the given route in order,
+// i.e. the global middlewares, the middlewares of its groups from the outermost, and its own middlewares.
+// Safe for concurrent use.
+func (c *Cosys) RouteMiddlewares(route Route) []MiddlewareFunc {
+	group := route.Group
+	if group == nil {
+		group = c.global
+	}
+
+	return append(group.Middlewares(), route.Middlewares...)
+}
+
+// RoutePolicies returns all policies applied to the given route in order,
+// i.e. the global policies, the policies of its groups from the outermost, and its own policies.
+// Safe for concurrent use.
+func (c *Cosys) RoutePolicies(route Route) []PolicyFunc {
+	group := route.Group
+	if group == nil {
+		group = c.global
+	}
+
+	return append(group.Policies(), route.Policies...)
+}
+
 // RemoveRoute removes a route specified by its path.
 // Throws error if route with path does not exist.
 // Safe for concurrent use.
@@ -175,6 +397,26 @@ func (c *Cosys) RemoveRoute(path string) error {
 	return c.routes.Remove(path)
 }
 
+// GrpcServices returns the gRPC services of the cosys app.
+// Safe for concurrent use.
+func (c *Cosys) GrpcServices() []GrpcService {
+	return c.grpcServices.GetSlice()
+}
+
+// AddGrpcServices adds gRPC services to the cosys app, served by the gRPC server core service.
+// Throws error if multiple services have the same name.
+// Safe for concurrent use.
+func (c *Cosys) AddGrpcServices(services ...GrpcService) error {
+	return c.grpcServices.RegisterStringers(services...)
+}
+
+// RemoveGrpcService removes a gRPC service specified by its full name.
+// Throws error if service with name does not exist.
+// Safe for concurrent use.
+func (c *Cosys) RemoveGrpcService(name string) error {
+	return c.grpcServices.Remove(name)
+}
+
 // AddControllers adds controllers to the cosys app.
 // Throws error if multiple controllers have the same uid.
 // Safe for concurrent use.
@@ -277,31 +519,41 @@ func (c *Cosys) Models() map[string]Model {
 	return c.models.GetAll()
 }
 
-// AddService adds a service to the cosys app.
-// Throws error if multiple routes have the same uid.
+// AddBootstrapHook adds a bootstrap hooks to the cosys app,
+// and returns a uid that can be used to update or remove the hook.
+// Bootstrap hooks are ordered by the given options, and otherwise called in the order of the modules that added them.
 // Safe for concurrent use.
-func (c *Cosys) AddService(uid string, service Service) error {
-	return c.services.Register(uid, service)
-}
+func (c *Cosys) AddBootstrapHook(hook BootstrapHook, options ...HookOption) (string, error) {
+	if hook == nil {
+		return "", fmt.Errorf("bootstrap hook is nil")
+	}
 
-// AddServices adds services to the cosys app.
-// Throws error if multiple services have the same uid.
-// Safe for concurrent use.
-func (c *Cosys) AddServices(services map[string]Service) error {
-	return c.services.RegisterMany(services)
+	return c.AddBootstrapHookContext(hook.WithContext(), options...)
 }
 
-// AddBootstrapHook adds a bootstrap hooks to the cosys app,
+// AddBootstrapHookContext adds a bootstrap hook taking the context of the bootstrap stage to the cosys app,
 // and returns a uid that can be used to update or remove the hook.
+// Bootstrap hooks are ordered by the given options, and otherwise called in the order of the modules that added them.
 // Safe for concurrent use.
-func (c *Cosys) AddBootstrapHook(hook BootstrapHook) (string, error) {
-	return c.bootstrapHooks.RegisterRandom(hook)
+func (c *Cosys) AddBootstrapHookContext(hook BootstrapHookContext, options ...HookOption) (string, error) {
+	return c.bootstrapHooks.RegisterOrdered(hook, c.hookConfig(options))
 }
 
 // UpdateBootstrapHook updates a bootstrap hook specified by its uid.
 // Throws an error if hook with uid does not exist.
 // Safe for concurrent use.
 func (c *Cosys) UpdateBootstrapHook(uid string, hook BootstrapHook) error {
+	if hook == nil {
+		return fmt.Errorf("bootstrap hook is nil: %s", uid)
+	}
+
+	return c.UpdateBootstrapHookContext(uid, hook.WithContext())
+}
+
+// UpdateBootstrapHookContext updates a bootstrap hook taking the context of the bootstrap stage specified by its uid.
+// Throws an error if hook with uid does not exist.
+// Safe for concurrent use.
+func (c *Cosys) UpdateBootstrapHookContext(uid string, hook BootstrapHookContext) error {
 	return c.bootstrapHooks.Update(uid, hook)
 }
 
@@ -314,23 +566,76 @@ func (c *Cosys) RemoveBootstrapHook(uid string) error {
 
 // AddCleanupHook adds a cleanup hook to the cosys app,
 // and returns a uid that can be used to update or remove the hook.
+// Cleanup hooks are ordered by the given options, and otherwise called in the reverse order of the modules that added them,
+// and in the reverse order they are added.
+// Safe for concurrent use.
+func (c *Cosys) AddCleanupHook(hook CleanupHook, options ...HookOption) (string, error) {
+	if hook == nil {
+		return "", fmt.Errorf("cleanup hook is nil")
+	}
+
+	return c.AddCleanupHookContext(hook.WithContext(), options...)
+}
+
+// AddCleanupHookContext adds a cleanup hook taking the context of the cleanup stage to the cosys app,
+// and returns a uid that can be used to update or remove the hook.
+// Cleanup hooks are ordered by the given options, and otherwise called in the reverse order of the modules that added them,
+// and in the reverse order they are added.
 // Safe for concurrent use.
-func (c *Cosys) AddCleanupHook(hook CleanupHook) (string, error) {
-	return c.cleanupHooks.RegisterRandom(hook)
+func (c *Cosys) AddCleanupHookContext(hook CleanupHookContext, options ...HookOption) (string, error) {
+	return c.cleanupHooks.RegisterOrdered(hook, c.hookConfig(options))
 }
 
 // UpdateCleanupHook updates a cleanup hook specified by its uid.
 // Throws an error if hook with uid does not exist.
 // Safe for concurrent use.
-func (c *Cosys) UpdateCleanupHook(uid string, hook BootstrapHook) error {
-	return c.bootstrapHooks.Update(uid, hook)
+func (c *Cosys) UpdateCleanupHook(uid string, hook CleanupHook) error {
+	if hook == nil {
+		return fmt.Errorf("cleanup hook is nil: %s", uid)
+	}
+
+	return c.UpdateCleanupHookContext(uid, hook.WithContext())
+}
+
+// UpdateCleanupHookContext updates a cleanup hook taking the context of the cleanup stage specified by its uid.
+// Throws an error if hook with uid does not exist.
+// Safe for concurrent use.
+func (c *Cosys) UpdateCleanupHookContext(uid string, hook CleanupHookContext) error {
+	return c.cleanupHooks.Update(uid, hook)
 }
 
 // RemoveCleanupHook removes a cleanup hook specified by its uid.
 // Throws an error if hook with uid does not exist.
 // Safe for concurrent use.
 func (c *Cosys) RemoveCleanupHook(uid string) error {
-	return c.bootstrapHooks.Remove(uid)
+	return c.cleanupHooks.Remove(uid)
+}
+
+// AddHealthCheck adds a health check with the given name to the cosys app.
+// Throws an error if a health check with the name already exists.
+// Safe for concurrent use.
+func (c *Cosys) AddHealthCheck(name string, check HealthCheck) error {
+	return c.healthChecks.Register(name, check)
+}
+
+// UpdateHealthCheck updates a health check specified by its name.
+// Throws an error if health check with name does not exist.
+// Safe for concurrent use.
+func (c *Cosys) UpdateHealthCheck(name string, check HealthCheck) error {
+	return c.healthChecks.Update(name, check)
+}
+
+// RemoveHealthCheck removes a health check specified by its name.
+// Throws an error if health check with name does not exist.
+// Safe for concurrent use.
+func (c *Cosys) RemoveHealthCheck(name string) error {
+	return c.healthChecks.Remove(name)
+}
+
+// HealthChecks returns all health checks added to the cosys app, by their names.
+// Safe for concurrent use.
+func (c *Cosys) HealthChecks() map[string]HealthCheck {
+	return c.healthChecks.GetAll()
 }
 
 // Start executes the cosys app command.
@@ -345,56 +650,237 @@ func (c *Cosys) Start() error {
 	return command.Execute()
 }
 
-// register calls all registered module functions on the cosys instance.
+// Modules returns the names of the named modules, in the order they are registered.
+func (c *Cosys) Modules() []string {
+	return slices.Clone(c.modules)
+}
+
+// register calls all registered module functions on the cosys instance,
+// in topological order of their dependencies, and otherwise in the order they are registered.
 func (c *Cosys) register() error {
-	for _, module := range mdRegister.GetAll() {
-		if err := module(c); err != nil {
-			return err
+	modules, err := sortedModules()
+	if err != nil {
+		return err
+	}
+
+	for _, module := range modules {
+		if !module.anonymous() {
+			c.modules = append(c.modules, module.name)
 		}
 	}
 
+	for index, module := range modules {
+		c.module = index
+
+		if err = module.module(c); err != nil {
+			if module.anonymous() {
+				return err
+			}
+
+			return fmt.Errorf("module %s: %w", module.name, err)
+		}
+	}
+
+	c.module = len(modules)
+
 	return nil
 }
 
-// startServer bootstraps the cosys app, starts the server,
-// and cleanups the cosys app after shutdown.
+// hookConfig returns the hook configuration of the given options, ordering the hook by the module being registered, if any,
+// or after the hooks added by modules otherwise.
+func (c *Cosys) hookConfig(options []HookOption) hookConfig {
+	config := newHookConfig(options)
+	config.group = c.module
+
+	return config
+}
+
+// startServer bootstraps the cosys app and starts the server and the gRPC server, if registered,
+// and shuts down the cosys app when it is interrupted or terminated, or when either server stops.
+// The returned channel is closed after shutdown.
 func (c *Cosys) startServer() <-chan error {
-	errCh := make(chan error, 1)
+	errCh := make(chan error, 2)
+
+	// The app can be interrupted or terminated while bootstrapping, which cancels the context of the bootstrap hooks.
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	err := c.BootstrapContext(ctx)
+	stop()
+
+	if err != nil {
+		errCh <- err
+
+		// The resources acquired by the hooks called before the failure are released.
+		ctx, cancel := context.WithTimeout(context.Background(), c.shutdownTimeout)
+		defer cancel()
+
+		if err = c.Shutdown(ctx); err != nil {
+			errCh <- err
+		}
+
+		close(errCh)
+		return errCh
+	}
+
+	var servers []Server
+
+	server, err := c.Server()
+	if err == nil {
+		servers = append(servers, server)
+	}
 
-	if err := c.Bootstrap(); err != nil {
+	if grpcServer, grpcErr := c.GrpcServer(); grpcErr == nil {
+		servers = append(servers, grpcServer)
+	}
+
+	if len(servers) == 0 {
 		errCh <- err
+		close(errCh)
+		return errCh
+	}
+
+	serverErrCh := make(chan error, len(servers))
+	for _, server := range servers {
+		go func() {
+			serverErrCh <- server.Start()
+		}()
 	}
 
 	go func() {
-		server, err := c.Server()
-		if err != nil {
-			errCh <- err
+		defer close(errCh)
+
+		signals := shutdownChannel()
+		defer signal.Stop(signals)
+
+		// The app is ready once all servers are listening, and shuts down once either stops.
+		listening := listeningChannel(servers)
+	serve:
+		for {
+			select {
+			case <-listening:
+				listening = nil
+				c.ready.Store(true)
+				c.logInfo("server started", F("environment", string(c.environment)))
+			case sig := <-signals:
+				c.logInfo("shutting down", F("signal", sig.String()), F("timeout", c.shutdownTimeout.String()))
+				break serve
+			case err := <-serverErrCh:
+				if err != nil {
+					errCh <- err
+				}
+				break serve
+			}
 		}
 
-		if err = server.Start(); err != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), c.shutdownTimeout)
+		defer cancel()
+
+		// A signal during shutdown forces it, cancelling the context of in-flight requests and cleanup hooks.
+		go func() {
+			select {
+			case sig := <-signals:
+				c.logInfo("forcing shutdown", F("signal", sig.String()))
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+
+		if err := c.Shutdown(ctx); err != nil {
 			errCh <- err
 		}
 	}()
 
-	go func() {
-		<-c.ShutdownChannel()
+	return errCh
+}
 
-		if err := c.Cleanup(); err != nil {
-			errCh <- err
+// listeningChannel returns a channel that is closed once all of the given servers are listening,
+// treating servers that do not implement ListeningServer as listening once started.
+func listeningChannel(servers []Server) <-chan struct{} {
+	listening := make(chan struct{})
+
+	go func() {
+		for _, server := range servers {
+			if listeningServer, ok := server.(ListeningServer); ok {
+				<-listeningServer.Listening()
+			}
 		}
 
-		close(errCh)
+		close(listening)
 	}()
 
-	return errCh
+	return listening
 }
 
-// Bootstrap calls all bootstrap hooks added to the cosys instance.
+// Shutdown gracefully shuts down the cosys app.
+// The app is marked as not ready, the server and the gRPC server stop accepting new requests
+// and wait for in-flight requests to complete until the context is done,
+// then the cleanup hooks are called and the database is closed.
+func (c *Cosys) Shutdown(ctx context.Context) error {
+	c.ready.Store(false)
+
+	var errs []error
+
+	if server, err := c.server.Get(); err == nil {
+		if err = server.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("server shutdown: %w", err))
+		}
+	}
+
+	if grpcServer, err := c.grpcServer.Get(); err == nil {
+		if err = grpcServer.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("grpc server shutdown: %w", err))
+		}
+	}
+
+	if err := c.CleanupContext(ctx); err != nil {
+		errs = append(errs, err)
+	}
+
+	if database, err := c.database.Get(); err == nil {
+		if err = database.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("database close: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Bootstrap applies all database wrappers and calls all bootstrap hooks added to the cosys instance, in their order.
 func (c *Cosys) Bootstrap() error {
+	return c.BootstrapContext(context.Background())
+}
+
+// BootstrapContext applies all database wrappers and calls all bootstrap hooks added to the cosys instance, in their order,
+// with the given context bounded by the timeouts of the hooks.
+// Throws the error of the context if it is done before all hooks are called.
+func (c *Cosys) BootstrapContext(ctx context.Context) error {
 	c.state = Bootstrap
 
-	for _, hook := range c.bootstrapHooks.GetAll() {
-		if err := hook(c); err != nil {
+	wrappers, err := c.databaseWrappers.GetOrdered()
+	if err != nil {
+		return err
+	}
+
+	for _, wrapper := range wrappers {
+		if err = c.database.Wrap(wrapper); err != nil {
+			return err
+		}
+	}
+
+	hooks, err := c.bootstrapHooks.GetOrderedItems()
+	if err != nil {
+		return err
+	}
+
+	for _, hook := range hooks {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+
+		hookCtx, cancel := hookContext(ctx, hook.config.timeout)
+		err = hook.item(hookCtx, c)
+		cancel()
+
+		if err != nil {
 			return err
 		}
 	}
@@ -404,17 +890,35 @@ func (c *Cosys) Bootstrap() error {
 	return nil
 }
 
-// Cleanup calls all cleanup hooks added to the cosys instance.
+// Cleanup calls all cleanup hooks added to the cosys instance, in their order.
 func (c *Cosys) Cleanup() error {
+	return c.CleanupContext(context.Background())
+}
+
+// CleanupContext calls all cleanup hooks added to the cosys instance, in their order,
+// with the given context bounded by the timeouts of the hooks.
+// Hooks are called even if the context is done or previous hooks fail, so that they release the resources of the cosys app,
+// and the errors of all failed hooks are returned.
+func (c *Cosys) CleanupContext(ctx context.Context) error {
 	c.state = Cleanup
 
-	for _, hook := range c.cleanupHooks.GetAll() {
-		if err := hook(c); err != nil {
-			return err
+	hooks, err := c.cleanupHooks.GetOrderedItems()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, hook := range hooks {
+		hookCtx, cancel := hookContext(ctx, hook.config.timeout)
+		err = hook.item(hookCtx, c)
+		cancel()
+
+		if err != nil {
+			errs = append(errs, err)
 		}
 	}
 
 	c.state = Execution
 
-	return nil
+	return errors.Join(errs...)
 }
@@ -0,0 +1,97 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// hangingServer is a server whose shutdown hangs until its context is done.
+type hangingServer struct {
+	shutdown chan struct{} // shutdown is closed when the server starts shutting down.
+	stopped  chan struct{}
+}
+
+func newHangingServer() *hangingServer {
+	return &hangingServer{
+		shutdown: make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+}
+
+func (s *hangingServer) Start() error {
+	<-s.stopped
+	return nil
+}
+
+func (s *hangingServer) Shutdown(ctx context.Context) error {
+	close(s.shutdown)
+	<-ctx.Done()
+	close(s.stopped)
+	return ctx.Err()
+}
+
+// interrupt sends an interrupt signal to the test process.
+func interrupt(t *testing.T) {
+	t.Helper()
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGINT); err != nil {
+		t.Fatalf("could not interrupt: %v", err)
+	}
+}
+
+func TestStartServerForcesShutdownOnSecondSignal(t *testing.T) {
+	withModules(t)
+
+	cosys, err := New()
+	if err != nil {
+		t.Fatalf("could not create cosys app: %v", err)
+	}
+	cosys.SetShutdownTimeout(time.Minute)
+
+	server := newHangingServer()
+	if err = cosys.UseServer(server); err != nil {
+		t.Fatalf("could not use server: %v", err)
+	}
+
+	errCh := cosys.startServer()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !cosys.Ready() {
+		if time.Now().After(deadline) {
+			t.Fatal("server not started")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	interrupt(t)
+
+	select {
+	case <-server.shutdown:
+	case <-time.After(5 * time.Second):
+		t.Fatal("server not shut down on the first signal")
+	}
+
+	interrupt(t)
+
+	var errs []error
+	timeout := time.After(5 * time.Second)
+	for done := false; !done; {
+		select {
+		case err, ok := <-errCh:
+			if !ok {
+				done = true
+				break
+			}
+			errs = append(errs, err)
+		case <-timeout:
+			t.Fatal("shutdown not forced on the second signal")
+		}
+	}
+
+	if err = errors.Join(errs...); !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want the cancelled shutdown", err)
+	}
+}
@@ -1,5 +1,7 @@
 package common
 
+import "context"
+
 // Database is a core service for interacting with the relational database.
 type Database interface {
 	FindOne(uid string, params DBParams) (Entity, error)
@@ -10,41 +12,233 @@ type Database interface {
 	UpdateMany(uid string, data Entity, params DBParams) ([]Entity, error)
 	Delete(uid string, params DBParams) (Entity, error)
 	DeleteMany(uid string, params DBParams) ([]Entity, error)
+	Close() error
+}
+
+// ContextDatabase is the database core service taking the context of each query,
+// which overrides the context of the params, so that queries are cancelled once the context is done.
+type ContextDatabase interface {
+	FindOneContext(ctx context.Context, uid string, params DBParams) (Entity, error)
+	FindManyContext(ctx context.Context, uid string, params DBParams) ([]Entity, error)
+	CreateContext(ctx context.Context, uid string, data Entity, params DBParams) (Entity, error)
+	CreateManyContext(ctx context.Context, uid string, data []Entity, params DBParams) ([]Entity, error)
+	UpdateContext(ctx context.Context, uid string, data Entity, params DBParams) (Entity, error)
+	UpdateManyContext(ctx context.Context, uid string, data Entity, params DBParams) ([]Entity, error)
+	DeleteContext(ctx context.Context, uid string, params DBParams) (Entity, error)
+	DeleteManyContext(ctx context.Context, uid string, params DBParams) ([]Entity, error)
+	Close() error
+}
+
+// WithContext returns the database as a ContextDatabase, which queries the database with the params
+// whose context is set to the given context, unless the context is done.
+func WithContext(database Database) ContextDatabase {
+	if adapter, ok := database.(withoutContext); ok {
+		return adapter.database
+	}
+
+	return withContext{database}
+}
+
+// WithoutContext returns the context database as a Database,
+// which queries the database with the context of the params, or the background context if not set.
+func WithoutContext(database ContextDatabase) Database {
+	if adapter, ok := database.(withContext); ok {
+		return adapter.database
+	}
+
+	return withoutContext{database}
+}
+
+// withContext is a ContextDatabase adapting a Database.
+type withContext struct {
+	database Database
+}
+
+// withParams returns the params with the given context, or the error of the context if it is done.
+func withParams(ctx context.Context, params DBParams) (DBParams, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	params.Context = ctx
+	return params, ctx.Err()
+}
+
+func (d withContext) FindOneContext(ctx context.Context, uid string, params DBParams) (Entity, error) {
+	params, err := withParams(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.database.FindOne(uid, params)
+}
+
+func (d withContext) FindManyContext(ctx context.Context, uid string, params DBParams) ([]Entity, error) {
+	params, err := withParams(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.database.FindMany(uid, params)
+}
+
+func (d withContext) CreateContext(ctx context.Context, uid string, data Entity, params DBParams) (Entity, error) {
+	params, err := withParams(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.database.Create(uid, data, params)
+}
+
+func (d withContext) CreateManyContext(ctx context.Context, uid string, data []Entity, params DBParams) ([]Entity, error) {
+	params, err := withParams(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.database.CreateMany(uid, data, params)
+}
+
+func (d withContext) UpdateContext(ctx context.Context, uid string, data Entity, params DBParams) (Entity, error) {
+	params, err := withParams(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.database.Update(uid, data, params)
+}
+
+func (d withContext) UpdateManyContext(ctx context.Context, uid string, data Entity, params DBParams) ([]Entity, error) {
+	params, err := withParams(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.database.UpdateMany(uid, data, params)
+}
+
+func (d withContext) DeleteContext(ctx context.Context, uid string, params DBParams) (Entity, error) {
+	params, err := withParams(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.database.Delete(uid, params)
+}
+
+func (d withContext) DeleteManyContext(ctx context.Context, uid string, params DBParams) ([]Entity, error) {
+	params, err := withParams(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.database.DeleteMany(uid, params)
+}
+
+func (d withContext) Close() error {
+	return d.database.Close()
+}
+
+// withoutContext is a Database adapting a ContextDatabase.
+type withoutContext struct {
+	database ContextDatabase
+}
+
+// paramsContext returns the context of the params, or the background context if not set.
+func paramsContext(params DBParams) context.Context {
+	if params.Context == nil {
+		return context.Background()
+	}
+
+	return params.Context
+}
+
+func (d withoutContext) FindOne(uid string, params DBParams) (Entity, error) {
+	return d.database.FindOneContext(paramsContext(params), uid, params)
+}
+
+func (d withoutContext) FindMany(uid string, params DBParams) ([]Entity, error) {
+	return d.database.FindManyContext(paramsContext(params), uid, params)
+}
+
+func (d withoutContext) Create(uid string, data Entity, params DBParams) (Entity, error) {
+	return d.database.CreateContext(paramsContext(params), uid, data, params)
+}
+
+func (d withoutContext) CreateMany(uid string, data []Entity, params DBParams) ([]Entity, error) {
+	return d.database.CreateManyContext(paramsContext(params), uid, data, params)
+}
+
+func (d withoutContext) Update(uid string, data Entity, params DBParams) (Entity, error) {
+	return d.database.UpdateContext(paramsContext(params), uid, data, params)
+}
+
+func (d withoutContext) UpdateMany(uid string, data Entity, params DBParams) ([]Entity, error) {
+	return d.database.UpdateManyContext(paramsContext(params), uid, data, params)
+}
+
+func (d withoutContext) Delete(uid string, params DBParams) (Entity, error) {
+	return d.database.DeleteContext(paramsContext(params), uid, params)
 }
 
+func (d withoutContext) DeleteMany(uid string, params DBParams) ([]Entity, error) {
+	return d.database.DeleteManyContext(paramsContext(params), uid, params)
+}
+
+func (d withoutContext) Close() error {
+	return d.database.Close()
+}
+
+// DatabaseWrapper takes in the database core service and returns a database wrapping it,
+// e.g. to instrument or intercept database calls.
+type DatabaseWrapper func(Database) Database
+
 // DBParams are query conditions.
 type DBParams struct {
-	Select   []Attribute
-	Columns  []Attribute
-	Where    []Condition
-	Limit    int64
-	Offset   int64
-	OrderBy  []*Order
-	Populate []Attribute
+	Select         []Attribute
+	Columns        []Attribute
+	Where          []Condition
+	Limit          int64
+	Offset         int64
+	OrderBy        []*Order
+	Populate       []Attribute
+	Locale         string
+	IncludeDeleted bool
+	Permanent      bool
+	Context        context.Context
 }
 
 // NewDBParams returns a new DBParams with default conditions.
 func NewDBParams() DBParams {
 	return DBParams{
-		Select:   []Attribute{},
-		Columns:  []Attribute{},
-		Where:    []Condition{},
-		Limit:    -1,
-		Offset:   0,
-		OrderBy:  []*Order{},
-		Populate: []Attribute{},
+		Select:         []Attribute{},
+		Columns:        []Attribute{},
+		Where:          []Condition{},
+		Limit:          -1,
+		Offset:         0,
+		OrderBy:        []*Order{},
+		Populate:       []Attribute{},
+		Locale:         "",
+		IncludeDeleted: false,
+		Permanent:      false,
+		Context:        nil,
 	}
 }
 
 // DBParamsBuilder is a builder for DBParams.
 type DBParamsBuilder struct {
-	selectFields []Attribute
-	columns      []Attribute
-	where        []Condition
-	limit        int64
-	offset       int64
-	orderBy      []*Order
-	populate     []Attribute
+	selectFields   []Attribute
+	columns        []Attribute
+	where          []Condition
+	limit          int64
+	offset         int64
+	orderBy        []*Order
+	populate       []Attribute
+	locale         string
+	includeDeleted bool
+	permanent      bool
+	context        context.Context
 }
 
 // NewDBParamsBuilder returns a new DBParamsBuilder with default conditions.
@@ -57,6 +251,10 @@ func NewDBParamsBuilder() DBParamsBuilder {
 		0,
 		[]*Order{},
 		[]Attribute{},
+		"",
+		false,
+		false,
+		nil,
 	}
 }
 
@@ -108,15 +306,65 @@ func (p DBParamsBuilder) Populate(populate ...Attribute) DBParamsBuilder {
 	return p
 }
 
+// Locale sets the locale of the entities for all queries on localized models.
+func (p DBParamsBuilder) Locale(locale string) DBParamsBuilder {
+	p.locale = locale
+	return p
+}
+
+// IncludeDeleted specifies that find queries on soft delete models also return soft-deleted entities.
+func (p DBParamsBuilder) IncludeDeleted() DBParamsBuilder {
+	p.includeDeleted = true
+	return p
+}
+
+// Permanent specifies that delete queries on soft delete models permanently remove the entities.
+func (p DBParamsBuilder) Permanent() DBParamsBuilder {
+	p.permanent = true
+	return p
+}
+
+// Context sets the context of all queries, which cancels the queries once it is done,
+// and carries the span that query spans are children of.
+func (p DBParamsBuilder) Context(ctx context.Context) DBParamsBuilder {
+	p.context = ctx
+	return p
+}
+
 // Build returns the DBParams with the set conditions.
 func (p DBParamsBuilder) Build() DBParams {
 	return DBParams{
-		Select:   p.selectFields,
-		Columns:  p.columns,
-		Where:    p.where,
-		Limit:    p.limit,
-		Offset:   p.offset,
-		OrderBy:  p.orderBy,
-		Populate: p.populate,
+		Select:         p.selectFields,
+		Columns:        p.columns,
+		Where:          p.where,
+		Limit:          p.limit,
+		Offset:         p.offset,
+		OrderBy:        p.orderBy,
+		Populate:       p.populate,
+		Locale:         p.locale,
+		IncludeDeleted: p.includeDeleted,
+		Permanent:      p.permanent,
+		Context:        p.context,
 	}
 }
+
+// DeletedAtName is the name of the attribute holding the deletion time of entities of soft delete models.
+const DeletedAtName = "deletedAt"
+
+// SoftDeleteAttribute returns the deletedAt attribute of the given model,
+// and whether soft delete is enabled for the model.
+// Soft delete is enabled for models with a datetime deletedAt attribute.
+// Deleting entities of soft delete models sets their deletedAt attribute instead of removing them,
+// unless the delete is permanent.
+func SoftDeleteAttribute(model Model) (TimeAttribute, bool) {
+	for _, attr := range model.Attributes_() {
+		if attr.CamelName() != DeletedAtName {
+			continue
+		}
+
+		deletedAt, ok := attr.(TimeAttribute)
+		return deletedAt, ok
+	}
+
+	return TimeAttribute{}, false
+}
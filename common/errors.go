@@ -0,0 +1,109 @@
+package common
+
+import (
+	"errors"
+)
+
+// ErrorCode identifies the kind of an error, and is reported in error responses.
+type ErrorCode string
+
+const (
+	NotFoundCode     ErrorCode = "NOT_FOUND"             // NotFoundCode is the code of errors for missing resources.
+	ValidationCode   ErrorCode = "VALIDATION_ERROR"      // ValidationCode is the code of errors for invalid input.
+	ConflictCode     ErrorCode = "CONFLICT"              // ConflictCode is the code of errors for conflicting changes.
+	UnauthorizedCode ErrorCode = "UNAUTHORIZED"          // UnauthorizedCode is the code of errors for unauthenticated requests.
+	ForbiddenCode    ErrorCode = "FORBIDDEN"             // ForbiddenCode is the code of errors for denied requests.
+	InternalCode     ErrorCode = "INTERNAL_SERVER_ERROR" // InternalCode is the code of untyped errors.
+)
+
+// TypedError is an error whose code is mapped to a response status by the server,
+// and whose message is safe to report to clients.
+type TypedError struct {
+	Code    ErrorCode
+	Message string
+	Err     error // Err is the underlying error, which is not reported to clients.
+}
+
+// Error returns the message of the error, followed by the underlying error, if any.
+func (e *TypedError) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+
+	return e.Message + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *TypedError) Unwrap() error {
+	return e.Err
+}
+
+// Is returns whether the target is a typed error with the same code,
+// so that errors.Is(err, &TypedError{Code: NotFoundCode}) matches all not found errors.
+func (e *TypedError) Is(target error) bool {
+	targetErr, ok := target.(*TypedError)
+	if !ok {
+		return false
+	}
+
+	return targetErr.Code == e.Code && (targetErr.Message == "" || targetErr.Message == e.Message)
+}
+
+// NewTypedError returns a typed error with the given code and message, wrapping the given error, which may be nil.
+func NewTypedError(code ErrorCode, message string, err error) *TypedError {
+	return &TypedError{
+		Code:    code,
+		Message: message,
+		Err:     err,
+	}
+}
+
+// NewNotFoundError returns a typed error for a missing resource.
+func NewNotFoundError(message string) *TypedError {
+	return NewTypedError(NotFoundCode, message, nil)
+}
+
+// NewValidationError returns a typed error for invalid input.
+func NewValidationError(message string) *TypedError {
+	return NewTypedError(ValidationCode, message, nil)
+}
+
+// NewConflictError returns a typed error for a change conflicting with the current state.
+func NewConflictError(message string) *TypedError {
+	return NewTypedError(ConflictCode, message, nil)
+}
+
+// NewUnauthorizedError returns a typed error for a request that is not authenticated.
+func NewUnauthorizedError(message string) *TypedError {
+	return NewTypedError(UnauthorizedCode, message, nil)
+}
+
+// NewForbiddenError returns a typed error for a request that is denied.
+func NewForbiddenError(message string) *TypedError {
+	return NewTypedError(ForbiddenCode, message, nil)
+}
+
+// AsTypedError returns the first typed error in the chain of the given error, and whether one was found.
+func AsTypedError(err error) (*TypedError, bool) {
+	var typedErr *TypedError
+	if !errors.As(err, &typedErr) {
+		return nil, false
+	}
+
+	return typedErr, true
+}
+
+// ErrorCodeOf returns the code of the first typed error in the chain of the given error,
+// or InternalCode if there is none.
+func ErrorCodeOf(err error) ErrorCode {
+	if typedErr, ok := AsTypedError(err); ok {
+		return typedErr.Code
+	}
+
+	return InternalCode
+}
+
+// IsNotFound returns whether the given error is a not found error.
+func IsNotFound(err error) bool {
+	return ErrorCodeOf(err) == NotFoundCode
+}
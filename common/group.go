@@ -0,0 +1,124 @@
+package common
+
+import (
+	"strings"
+	"sync"
+)
+
+// RouteGroup is a group of routes sharing a path prefix, middlewares and policies.
+// Groups can be nested, in which case the routes of the inner group share the prefix,
+// middlewares and policies of all enclosing groups.
+//
+// The middlewares and policies of a route are applied in the following order:
+// the global middlewares of the cosys app, the middlewares of the enclosing groups from the outermost,
+// the middlewares of the route, then the policies in the same order, and finally the action of the route.
+// Middlewares therefore also wrap requests rejected by policies.
+type RouteGroup struct {
+	cosys  *Cosys
+	parent *RouteGroup
+	prefix string
+
+	mutex       sync.RWMutex
+	middlewares []MiddlewareFunc
+	policies    []PolicyFunc
+}
+
+// newRouteGroup returns a new route group with the given prefix inside the given parent group,
+// configured with the given options.
+func newRouteGroup(cosys *Cosys, parent *RouteGroup, prefix string, options ...RouteOption) *RouteGroup {
+	group := &RouteGroup{
+		cosys:       cosys,
+		parent:      parent,
+		prefix:      joinPath(parent.Prefix(), prefix),
+		middlewares: []MiddlewareFunc{},
+		policies:    []PolicyFunc{},
+	}
+
+	group.Use(options...)
+	return group
+}
+
+// Prefix returns the full path prefix of the routes of the group, including the prefixes of enclosing groups.
+func (g *RouteGroup) Prefix() string {
+	if g == nil {
+		return ""
+	}
+
+	return g.prefix
+}
+
+// Use adds the middlewares and policies of the given route options to the group.
+// Only middlewares and policies of the options are used.
+// Safe for concurrent use.
+func (g *RouteGroup) Use(options ...RouteOption) {
+	var route Route
+	for _, option := range options {
+		option(&route)
+	}
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	g.middlewares = append(g.middlewares, route.Middlewares...)
+	g.policies = append(g.policies, route.Policies...)
+}
+
+// Group returns a new group nested in the group, with the given path prefix and options.
+func (g *RouteGroup) Group(prefix string, options ...RouteOption) *RouteGroup {
+	return newRouteGroup(g.cosys, g, prefix, options...)
+}
+
+// AddRoutes adds routes to the group, prefixing their paths with the prefix of the group.
+// Throws error if multiple routes have the same path.
+// Safe for concurrent use.
+func (g *RouteGroup) AddRoutes(routes ...Route) error {
+	groupRoutes := make([]Route, len(routes))
+	for index, route := range routes {
+		route.Path = joinPath(g.prefix, route.Path)
+		route.Group = g
+
+		groupRoutes[index] = route
+	}
+
+	return g.cosys.routes.RegisterStringers(groupRoutes...)
+}
+
+// Middlewares returns the middlewares of the group, preceded by those of the enclosing groups.
+// Safe for concurrent use.
+func (g *RouteGroup) Middlewares() []MiddlewareFunc {
+	if g == nil {
+		return []MiddlewareFunc{}
+	}
+
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	return append(g.parent.Middlewares(), g.middlewares...)
+}
+
+// Policies returns the policies of the group, preceded by those of the enclosing groups.
+// Safe for concurrent use.
+func (g *RouteGroup) Policies() []PolicyFunc {
+	if g == nil {
+		return []PolicyFunc{}
+	}
+
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	return append(g.parent.Policies(), g.policies...)
+}
+
+// joinPath returns the path prefixed with the given prefix, with a single slash between them.
+func joinPath(prefix string, path string) string {
+	if prefix == "" {
+		return path
+	}
+
+	prefix = strings.TrimSuffix(prefix, "/")
+	if path == "" {
+		return prefix
+	}
+
+	return prefix + "/" + strings.TrimPrefix(path, "/")
+}
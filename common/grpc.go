@@ -0,0 +1,50 @@
+package common
+
+import (
+	"google.golang.org/grpc"
+)
+
+// GrpcService is an implementation of a gRPC service, served by the gRPC server core service,
+// with the middlewares and policies applied to its methods.
+type GrpcService struct {
+	Desc        *grpc.ServiceDesc // Desc is the description of the service, as generated by protoc-gen-go-grpc.
+	Impl        any               // Impl is the implementation of the service, which must implement Desc.HandlerType.
+	Middlewares []MiddlewareFunc
+	Policies    []PolicyFunc
+}
+
+// String returns the full name of the service.
+func (s GrpcService) String() string {
+	if s.Desc == nil {
+		return ""
+	}
+
+	return s.Desc.ServiceName
+}
+
+// NewGrpcService returns a new gRPC service with the given description and implementation,
+// and the middlewares and policies of the given route options, e.g. common.GetPolicies("isAuthenticated").
+// Middlewares and policies are called with an http request built from the gRPC call,
+// whose path is the full method name and whose headers are the incoming metadata.
+func NewGrpcService(desc *grpc.ServiceDesc, impl any, options ...RouteOption) GrpcService {
+	route := Route{
+		Method:      "POST",
+		Middlewares: []MiddlewareFunc{},
+		Policies:    []PolicyFunc{},
+	}
+
+	if desc != nil {
+		route.Path = "/" + desc.ServiceName + "/"
+	}
+
+	for _, option := range options {
+		option(&route)
+	}
+
+	return GrpcService{
+		Desc:        desc,
+		Impl:        impl,
+		Middlewares: route.Middlewares,
+		Policies:    route.Policies,
+	}
+}
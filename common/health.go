@@ -0,0 +1,7 @@
+package common
+
+import "context"
+
+// HealthCheck is a check of whether a part of the cosys app is healthy,
+// which returns an error if it is not.
+type HealthCheck func(ctx context.Context) error
@@ -0,0 +1,173 @@
+package common
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// hookConfig is the configuration of a hook, i.e. its position among the hooks it is called with and its timeout.
+type hookConfig struct {
+	name     string        // name is the uid of the hook, or a generated uid if empty.
+	group    int           // group is the index of the module that added the hook, which orders hooks with the same priority.
+	priority int           // priority orders hooks in ascending order.
+	before   []string      // before are the uids of the hooks the hook is called before.
+	after    []string      // after are the uids of the hooks the hook is called after.
+	timeout  time.Duration // timeout is the maximum time the hook takes, or unlimited if not positive.
+}
+
+// HookOption is a configuration of the order or timeout of a hook,
+// i.e. bootstrap, cleanup and lifecycle hooks, lifecycle observers and database wrappers.
+// Hooks are called in ascending order of their priorities, and otherwise in the order of the modules that added them
+// and the order they are added, unless they are constrained to be called before or after other hooks.
+type HookOption func(*hookConfig)
+
+// HookName sets the uid of the hook, which other hooks can be ordered before or after,
+// instead of a generated uid.
+func HookName(name string) HookOption {
+	return func(config *hookConfig) {
+		config.name = name
+	}
+}
+
+// HookPriority sets the priority of the hook, which is 0 by default.
+// Hooks with lower priorities are called first.
+func HookPriority(priority int) HookOption {
+	return func(config *hookConfig) {
+		config.priority = priority
+	}
+}
+
+// HookBefore orders the hook before the hooks with the given uids, if they are added.
+func HookBefore(uids ...string) HookOption {
+	return func(config *hookConfig) {
+		config.before = append(config.before, uids...)
+	}
+}
+
+// HookAfter orders the hook after the hooks with the given uids, if they are added.
+func HookAfter(uids ...string) HookOption {
+	return func(config *hookConfig) {
+		config.after = append(config.after, uids...)
+	}
+}
+
+// HookTimeout sets the maximum time the hook takes, after which its context is done, or unlimited if not positive.
+// Applies to bootstrap, cleanup and lifecycle hooks, which must return once their context is done.
+func HookTimeout(timeout time.Duration) HookOption {
+	return func(config *hookConfig) {
+		config.timeout = timeout
+	}
+}
+
+// newHookConfig returns the configuration of a hook configured with the given options.
+func newHookConfig(options []HookOption) hookConfig {
+	var config hookConfig
+	for _, option := range options {
+		option(&config)
+	}
+
+	return config
+}
+
+// hookContext returns the given context with the given hook timeout, if positive.
+func hookContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+
+	return context.WithCancel(ctx)
+}
+
+// sortHooks returns the given uids, in the order they are registered, sorted by their configurations.
+// Hooks are sorted by their priorities, groups and the order they are registered,
+// in reverse order of groups and registration if reverse is true,
+// and then reordered to satisfy their before and after constraints, ignoring uids that are not registered.
+// Throws an error if the constraints form a cycle.
+func sortHooks(uids []string, configs map[string]hookConfig, reverse bool) ([]string, error) {
+	ranked := slices.Clone(uids)
+
+	if reverse {
+		slices.Reverse(ranked)
+	}
+
+	slices.SortStableFunc(ranked, func(a, b string) int {
+		configA, configB := configs[a], configs[b]
+		if configA.priority != configB.priority {
+			return cmp.Compare(configA.priority, configB.priority)
+		}
+
+		if reverse {
+			return cmp.Compare(configB.group, configA.group)
+		}
+
+		return cmp.Compare(configA.group, configB.group)
+	})
+
+	rank := make(map[string]int, len(ranked))
+	for index, uid := range ranked {
+		rank[uid] = index
+	}
+
+	next := make(map[string][]string, len(ranked))
+	inDegree := make(map[string]int, len(ranked))
+	addEdge := func(from, to string) {
+		if _, ok := rank[from]; !ok {
+			return
+		}
+		if _, ok := rank[to]; !ok || from == to {
+			return
+		}
+
+		next[from] = append(next[from], to)
+		inDegree[to]++
+	}
+
+	for _, uid := range ranked {
+		config := configs[uid]
+		for _, before := range config.before {
+			addEdge(uid, before)
+		}
+		for _, after := range config.after {
+			addEdge(after, uid)
+		}
+	}
+
+	sorted := make([]string, 0, len(ranked))
+	done := make(map[string]bool, len(ranked))
+	for len(sorted) < len(ranked) {
+		ready := ""
+		for _, uid := range ranked {
+			if !done[uid] && inDegree[uid] == 0 {
+				ready = uid
+				break
+			}
+		}
+
+		if ready == "" {
+			var cyclic []string
+			for _, uid := range ranked {
+				if !done[uid] {
+					cyclic = append(cyclic, uid)
+				}
+			}
+
+			return nil, fmt.Errorf("cyclic hook order: %s", strings.Join(cyclic, ", "))
+		}
+
+		done[ready] = true
+		sorted = append(sorted, ready)
+		for _, uid := range next[ready] {
+			inDegree[uid]--
+		}
+	}
+
+	return sorted, nil
+}
@@ -1,7 +1,36 @@
 package common
 
+import "context"
+
 // BootstrapHook is a hook called during the bootstrap stage of the cosys app.
 type BootstrapHook func(*Cosys) error
 
 // CleanupHook is a hook called during the cleanup stage of the cosys app.
 type CleanupHook func(*Cosys) error
+
+// BootstrapHookContext is a bootstrap hook taking the context of the bootstrap stage,
+// which is done once the hook times out or the cosys app is shut down while bootstrapping.
+type BootstrapHookContext func(context.Context, *Cosys) error
+
+// CleanupHookContext is a cleanup hook taking the context of the cleanup stage,
+// which is done once the hook times out or the shutdown timeout elapses.
+type CleanupHookContext func(context.Context, *Cosys) error
+
+// WithContext returns the bootstrap hook as a BootstrapHookContext, which is not called if the context is done.
+func (h BootstrapHook) WithContext() BootstrapHookContext {
+	return func(ctx context.Context, cosys *Cosys) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		return h(cosys)
+	}
+}
+
+// WithContext returns the cleanup hook as a CleanupHookContext, which ignores the context.
+// Cleanup hooks are called even if the context is done, to release the resources of the cosys app.
+func (h CleanupHook) WithContext() CleanupHookContext {
+	return func(_ context.Context, cosys *Cosys) error {
+		return h(cosys)
+	}
+}
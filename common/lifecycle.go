@@ -1,39 +1,59 @@
 package common
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"time"
+)
 
 // EventQuery is the query data associated with a lifecycle event.
 type EventQuery struct {
 	Params DBParams
 	Result any
 	State  any
+	// Context is the context of the query, e.g. of the request it is made for, bounded by the timeout of the hook,
+	// which carries the span of the lifecycle event, if tracing is enabled.
+	Context context.Context
 }
 
-// LifecycleHook is a hook that is called when a model's lifecycle event happens.
+// LifecycleHook is a hook that is called when a model's lifecycle event happens,
+// which should return once the context of the query is done.
 type LifecycleHook func(query EventQuery) (err error)
 
+// LifecycleObserver is notified after the hooks for a lifecycle event are called,
+// with the time taken to call the hooks and the error returned, if any.
+type LifecycleObserver func(event string, duration time.Duration, err error)
+
 // Lifecycle is a group of lifecycle hooks associated with a model.
 type Lifecycle struct {
-	beforeFindOne    *multiRegister[LifecycleHook]
-	afterFindOne     *multiRegister[LifecycleHook]
-	beforeFindMany   *multiRegister[LifecycleHook]
-	afterFindMany    *multiRegister[LifecycleHook]
-	beforeCreate     *multiRegister[LifecycleHook]
-	afterCreate      *multiRegister[LifecycleHook]
-	beforeCreateMany *multiRegister[LifecycleHook]
-	afterCreateMany  *multiRegister[LifecycleHook]
-	beforeUpdate     *multiRegister[LifecycleHook]
-	afterUpdate      *multiRegister[LifecycleHook]
-	beforeUpdateMany *multiRegister[LifecycleHook]
-	afterUpdateMany  *multiRegister[LifecycleHook]
-	beforeDelete     *multiRegister[LifecycleHook]
-	afterDelete      *multiRegister[LifecycleHook]
-	beforeDeleteMany *multiRegister[LifecycleHook]
-	afterDeleteMany  *multiRegister[LifecycleHook]
+	beforeFindOne    *orderedRegister[LifecycleHook]
+	afterFindOne     *orderedRegister[LifecycleHook]
+	beforeFindMany   *orderedRegister[LifecycleHook]
+	afterFindMany    *orderedRegister[LifecycleHook]
+	beforeCreate     *orderedRegister[LifecycleHook]
+	afterCreate      *orderedRegister[LifecycleHook]
+	beforeCreateMany *orderedRegister[LifecycleHook]
+	afterCreateMany  *orderedRegister[LifecycleHook]
+	beforeUpdate     *orderedRegister[LifecycleHook]
+	afterUpdate      *orderedRegister[LifecycleHook]
+	beforeUpdateMany *orderedRegister[LifecycleHook]
+	afterUpdateMany  *orderedRegister[LifecycleHook]
+	beforeDelete     *orderedRegister[LifecycleHook]
+	afterDelete      *orderedRegister[LifecycleHook]
+	beforeDeleteMany *orderedRegister[LifecycleHook]
+	afterDeleteMany  *orderedRegister[LifecycleHook]
+	beforePublish    *orderedRegister[LifecycleHook]
+	afterPublish     *orderedRegister[LifecycleHook]
+	beforeUnpublish  *orderedRegister[LifecycleHook]
+	afterUnpublish   *orderedRegister[LifecycleHook]
+	beforeRestore    *orderedRegister[LifecycleHook]
+	afterRestore     *orderedRegister[LifecycleHook]
+
+	observers *orderedRegister[LifecycleObserver]
 }
 
 // getRegister returns the register corresponding to the lifecycle event.
-func (l Lifecycle) getRegister(event string) (*multiRegister[LifecycleHook], error) {
+func (l Lifecycle) getRegister(event string) (*orderedRegister[LifecycleHook], error) {
 	switch event {
 	case "beforeFindOne":
 		return l.beforeFindOne, nil
@@ -67,6 +87,18 @@ func (l Lifecycle) getRegister(event string) (*multiRegister[LifecycleHook], err
 		return l.beforeDeleteMany, nil
 	case "afterDeleteMany":
 		return l.afterDeleteMany, nil
+	case "beforePublish":
+		return l.beforePublish, nil
+	case "afterPublish":
+		return l.afterPublish, nil
+	case "beforeUnpublish":
+		return l.beforeUnpublish, nil
+	case "afterUnpublish":
+		return l.afterUnpublish, nil
+	case "beforeRestore":
+		return l.beforeRestore, nil
+	case "afterRestore":
+		return l.afterRestore, nil
 	default:
 		return nil, fmt.Errorf("unknown lifecycle event: %s", event)
 	}
@@ -83,16 +115,56 @@ func (l Lifecycle) Get(event string, uid string) (LifecycleHook, error) {
 	return register.Get(uid)
 }
 
-// Call calls all hooks for a lifecycle event and returns the after state.
+// Call calls all hooks for a lifecycle event in their order, and notifies the observers of the lifecycle.
+// If the query context contains a span, the hooks are called within a child span of the event.
+// Throws the error of the query context if it is done before all hooks are called.
 func (l Lifecycle) Call(event string, query EventQuery) error {
 	register, err := l.getRegister(event)
 	if err != nil {
 		return err
 	}
 
-	hooks := register.GetAll()
+	hooks, err := register.GetOrderedItems()
+	if err != nil {
+		return err
+	}
+
+	observers, err := l.observers.GetOrdered()
+	if err != nil {
+		return err
+	}
+
+	ctx, span := StartSpan(query.Context, "lifecycle "+event)
+	defer span.End()
+	query.Context = ctx
+
+	start := time.Now()
+	err = callHooks(hooks, query)
+	span.RecordError(err)
+
+	duration := time.Since(start)
+	for _, observer := range observers {
+		observer(event, duration, err)
+	}
+
+	return err
+}
+
+// callHooks calls the given hooks until one returns an error or the query context is done,
+// with the query context bounded by the timeouts of the hooks.
+func callHooks(hooks []orderedItem[LifecycleHook], query EventQuery) error {
+	ctx := query.Context
 	for _, hook := range hooks {
-		if err = hook(query); err != nil {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		hookCtx, cancel := hookContext(ctx, hook.config.timeout)
+		query.Context = hookCtx
+		err := hook.item(query)
+		cancel()
+
+		if err != nil {
 			return err
 		}
 	}
@@ -100,15 +172,29 @@ func (l Lifecycle) Call(event string, query EventQuery) error {
 	return nil
 }
 
-// Add adds a hook for a lifecycle event and returns a uid used for updating and removing.
+// AddObserver adds an observer notified after the hooks for any lifecycle event are called,
+// ordered by the given options, and returns a uid used for removing.
+// Safe for concurrent use.
+func (l Lifecycle) AddObserver(observer LifecycleObserver, options ...HookOption) (string, error) {
+	return l.observers.RegisterOrdered(observer, newHookConfig(options))
+}
+
+// RemoveObserver removes an observer specified by its uid.
+// Safe for concurrent use.
+func (l Lifecycle) RemoveObserver(uid string) error {
+	return l.observers.Remove(uid)
+}
+
+// Add adds a hook for a lifecycle event, ordered by the given options,
+// and returns a uid used for updating and removing.
 // Safe for concurrent use.
-func (l Lifecycle) Add(event string, hook LifecycleHook) (string, error) {
+func (l Lifecycle) Add(event string, hook LifecycleHook, options ...HookOption) (string, error) {
 	register, err := l.getRegister(event)
 	if err != nil {
 		return "", err
 	}
 
-	return register.RegisterRandom(hook)
+	return register.RegisterOrdered(hook, newHookConfig(options))
 }
 
 // Update updates a hook specified by its uid for a lifecycle event.
@@ -136,21 +222,29 @@ func (l Lifecycle) Remove(event string, uid string) error {
 // NewLifecycle returns a new lifecycle.
 func NewLifecycle() Lifecycle {
 	return Lifecycle{
-		beforeFindOne:    newMultiRegister[LifecycleHook](),
-		afterFindOne:     newMultiRegister[LifecycleHook](),
-		beforeFindMany:   newMultiRegister[LifecycleHook](),
-		afterFindMany:    newMultiRegister[LifecycleHook](),
-		beforeCreate:     newMultiRegister[LifecycleHook](),
-		afterCreate:      newMultiRegister[LifecycleHook](),
-		beforeCreateMany: newMultiRegister[LifecycleHook](),
-		afterCreateMany:  newMultiRegister[LifecycleHook](),
-		beforeUpdate:     newMultiRegister[LifecycleHook](),
-		afterUpdate:      newMultiRegister[LifecycleHook](),
-		beforeUpdateMany: newMultiRegister[LifecycleHook](),
-		afterUpdateMany:  newMultiRegister[LifecycleHook](),
-		beforeDelete:     newMultiRegister[LifecycleHook](),
-		afterDelete:      newMultiRegister[LifecycleHook](),
-		beforeDeleteMany: newMultiRegister[LifecycleHook](),
-		afterDeleteMany:  newMultiRegister[LifecycleHook](),
+		beforeFindOne:    newOrderedRegister[LifecycleHook](itemName("lifecycle hook")),
+		afterFindOne:     newOrderedRegister[LifecycleHook](itemName("lifecycle hook")),
+		beforeFindMany:   newOrderedRegister[LifecycleHook](itemName("lifecycle hook")),
+		afterFindMany:    newOrderedRegister[LifecycleHook](itemName("lifecycle hook")),
+		beforeCreate:     newOrderedRegister[LifecycleHook](itemName("lifecycle hook")),
+		afterCreate:      newOrderedRegister[LifecycleHook](itemName("lifecycle hook")),
+		beforeCreateMany: newOrderedRegister[LifecycleHook](itemName("lifecycle hook")),
+		afterCreateMany:  newOrderedRegister[LifecycleHook](itemName("lifecycle hook")),
+		beforeUpdate:     newOrderedRegister[LifecycleHook](itemName("lifecycle hook")),
+		afterUpdate:      newOrderedRegister[LifecycleHook](itemName("lifecycle hook")),
+		beforeUpdateMany: newOrderedRegister[LifecycleHook](itemName("lifecycle hook")),
+		afterUpdateMany:  newOrderedRegister[LifecycleHook](itemName("lifecycle hook")),
+		beforeDelete:     newOrderedRegister[LifecycleHook](itemName("lifecycle hook")),
+		afterDelete:      newOrderedRegister[LifecycleHook](itemName("lifecycle hook")),
+		beforeDeleteMany: newOrderedRegister[LifecycleHook](itemName("lifecycle hook")),
+		afterDeleteMany:  newOrderedRegister[LifecycleHook](itemName("lifecycle hook")),
+		beforePublish:    newOrderedRegister[LifecycleHook](itemName("lifecycle hook")),
+		afterPublish:     newOrderedRegister[LifecycleHook](itemName("lifecycle hook")),
+		beforeUnpublish:  newOrderedRegister[LifecycleHook](itemName("lifecycle hook")),
+		afterUnpublish:   newOrderedRegister[LifecycleHook](itemName("lifecycle hook")),
+		beforeRestore:    newOrderedRegister[LifecycleHook](itemName("lifecycle hook")),
+		afterRestore:     newOrderedRegister[LifecycleHook](itemName("lifecycle hook")),
+
+		observers: newOrderedRegister[LifecycleObserver](itemName("lifecycle observer")),
 	}
 }
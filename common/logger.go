@@ -2,6 +2,8 @@ package common
 
 import (
 	"fmt"
+	"log"
+	"strings"
 )
 
 // LogLevel is the alert level of a log message.
@@ -14,11 +16,124 @@ const (
 	Error LogLevel = "ERROR"
 )
 
+// ParseLogLevel returns the log level with the given name, ignoring case.
+// Throws an error if the name is not a log level.
+func ParseLogLevel(name string) (LogLevel, error) {
+	switch level := LogLevel(strings.ToUpper(strings.TrimSpace(name))); level {
+	case Debug, Info, Warn, Error:
+		return level, nil
+	case "WARNING":
+		return Warn, nil
+	default:
+		return "", fmt.Errorf("log level not found: %s", name)
+	}
+}
+
+// Field is a key/value pair attached to a log message.
+type Field struct {
+	Key   string
+	Value any
+}
+
+// F returns a field with the given key and value.
+func F(key string, value any) Field {
+	return Field{
+		Key:   key,
+		Value: value,
+	}
+}
+
+// Err returns a field with the given error under the "error" key.
+func Err(err error) Field {
+	return F("error", err)
+}
+
 // Logger is a core service for logging.
 type Logger interface {
-	Log(stringer fmt.Stringer, logLevel LogLevel)
-	Info(stringer fmt.Stringer)
-	Warn(stringer fmt.Stringer)
-	Debug(stringer fmt.Stringer)
-	Error(stringer fmt.Stringer)
+	Log(logLevel LogLevel, msg string, fields ...Field)
+	Debug(msg string, fields ...Field)
+	Info(msg string, fields ...Field)
+	Warn(msg string, fields ...Field)
+	Error(msg string, fields ...Field)
+
+	// With returns a child logger that adds the given fields to all of its messages.
+	With(fields ...Field) Logger
+	// Enabled returns whether messages at the given log level are logged.
+	Enabled(logLevel LogLevel) bool
+}
+
+// LogError logs an error with the given fields using the logger core service,
+// or the standard logger if no logger is registered, e.g. during registration.
+// Safe for concurrent use.
+func (c *Cosys) LogError(msg string, err error, fields ...Field) {
+	logger, lErr := c.logger.Get()
+	if lErr != nil {
+		logger = StandardLogger()
+	}
+
+	logger.Error(msg, append(fields[:len(fields):len(fields)], Err(err))...)
+}
+
+// logInfo logs a message using the logger core service, if one is registered.
+func (c *Cosys) logInfo(msg string, fields ...Field) {
+	if logger, err := c.logger.Get(); err == nil {
+		logger.Info(msg, fields...)
+	}
+}
+
+// StandardLogger returns a Logger writing messages of all levels with the standard logger of the log package,
+// e.g. as a fallback if no logger core service is registered.
+func StandardLogger() Logger {
+	return standardLogger{}
+}
+
+// standardLogger is the Logger returned by StandardLogger.
+type standardLogger struct {
+	fields []Field
+}
+
+// Log writes a message at the given log level, with the fields of the logger followed by the given fields.
+func (l standardLogger) Log(logLevel LogLevel, msg string, fields ...Field) {
+	var builder strings.Builder
+	builder.WriteString(string(logLevel))
+	builder.WriteString(" ")
+	builder.WriteString(msg)
+
+	for _, field := range append(l.fields[:len(l.fields):len(l.fields)], fields...) {
+		_, _ = fmt.Fprintf(&builder, " %s=%v", field.Key, field.Value)
+	}
+
+	log.Print(builder.String())
+}
+
+// Debug writes a message at the debug level.
+func (l standardLogger) Debug(msg string, fields ...Field) {
+	l.Log(Debug, msg, fields...)
+}
+
+// Info writes a message at the info level.
+func (l standardLogger) Info(msg string, fields ...Field) {
+	l.Log(Info, msg, fields...)
+}
+
+// Warn writes a message at the warn level.
+func (l standardLogger) Warn(msg string, fields ...Field) {
+	l.Log(Warn, msg, fields...)
+}
+
+// Error writes a message at the error level.
+func (l standardLogger) Error(msg string, fields ...Field) {
+	l.Log(Error, msg, fields...)
+}
+
+// With returns a child logger that adds the given fields to all of its messages.
+func (l standardLogger) With(fields ...Field) Logger {
+	return standardLogger{
+		fields: append(l.fields[:len(l.fields):len(l.fields)], fields...),
+	}
+}
+
+// Enabled returns true, as messages of all levels are logged.
+func (l standardLogger) Enabled(LogLevel) bool {
+	return true
 }
@@ -15,9 +15,11 @@ type Model interface {
 
 	GetLifecycleHook_(event string, uid string) (LifecycleHook, error)
 	CallLifecycle_(event string, query EventQuery) error
-	AddLifecycleHook_(event string, hook LifecycleHook) (string, error)
+	AddLifecycleHook_(event string, hook LifecycleHook, options ...HookOption) (string, error)
 	UpdateLifecycleHook_(event string, uid string, hook LifecycleHook) error
 	RemoveLifecycleHook_(event string, uid string) error
+	AddLifecycleObserver_(observer LifecycleObserver, options ...HookOption) (string, error)
+	RemoveLifecycleObserver_(uid string) error
 
 	DBName_() string
 	SingularCamelName_() string
@@ -77,6 +79,12 @@ func NewModel[E Entity, M Model](dbname, singularName, pluralName string, schema
 			}
 		case StringAttribute:
 			attr = NewStringAttribute(name)
+		case TimeAttribute:
+			attr = NewTimeAttribute(name)
+		case ComponentAttribute:
+			attr = NewComponentAttribute(name)
+		case DynamicZoneAttribute:
+			attr = NewDynamicZoneAttribute(name)
 		case *ModelBase:
 			fieldValue.Set(reflect.ValueOf(base))
 			continue
@@ -149,9 +157,9 @@ func (m ModelBase) CallLifecycle_(event string, query EventQuery) error {
 }
 
 // AddLifecycleHook_ adds a hook for the given event to the lifecycle of the model,
-// and returns a generated uid for updating or removing.
-func (m ModelBase) AddLifecycleHook_(event string, hook LifecycleHook) (string, error) {
-	return m.lifecycle.Add(event, hook)
+// ordered by the given options, and returns its uid for updating or removing.
+func (m ModelBase) AddLifecycleHook_(event string, hook LifecycleHook, options ...HookOption) (string, error) {
+	return m.lifecycle.Add(event, hook, options...)
 }
 
 // UpdateLifecycleHook_ updates a hook specified by the given uid
@@ -166,6 +174,19 @@ func (m ModelBase) RemoveLifecycleHook_(event string, uid string) error {
 	return m.lifecycle.Remove(event, uid)
 }
 
+// AddLifecycleObserver_ adds an observer notified after the hooks
+// for any event in the lifecycle of the model are called,
+// ordered by the given options, and returns its uid for removing.
+func (m ModelBase) AddLifecycleObserver_(observer LifecycleObserver, options ...HookOption) (string, error) {
+	return m.lifecycle.AddObserver(observer, options...)
+}
+
+// RemoveLifecycleObserver_ removes an observer specified by the given uid
+// from the lifecycle of the model.
+func (m ModelBase) RemoveLifecycleObserver_(uid string) error {
+	return m.lifecycle.RemoveObserver(uid)
+}
+
 func (m ModelBase) DBName_() string {
 	return m.dbname
 }
@@ -213,3 +234,25 @@ func (m ModelBase) PluralHumanName_() string {
 // Entity is a record/document.
 type Entity interface {
 }
+
+// SetEntityField sets the field with the given name of an entity, which must be a pointer to a struct.
+// Throws an error if the field does not exist or the value is not assignable to it.
+func SetEntityField(entity Entity, name string, value any) error {
+	entityValue := reflect.Indirect(reflect.ValueOf(entity))
+	if entityValue.Kind() != reflect.Struct {
+		return fmt.Errorf("entity is not a struct")
+	}
+
+	field := entityValue.FieldByName(name)
+	if !field.IsValid() {
+		return fmt.Errorf("field not found: %s", name)
+	}
+
+	newValue := reflect.ValueOf(value)
+	if !newValue.IsValid() || !newValue.Type().AssignableTo(field.Type()) || !field.CanSet() {
+		return fmt.Errorf("invalid type for field: %s", name)
+	}
+
+	field.Set(newValue)
+	return nil
+}
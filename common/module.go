@@ -1,20 +1,189 @@
 package common
 
 import (
+	"fmt"
+	"strings"
 	"sync"
 )
 
 var (
-	mdMutex    sync.RWMutex
-	mdRegister = newPermRegister[Module]()
+	mdMutex   sync.RWMutex
+	mdModules []moduleEntry // mdModules are the registered modules, in the order they are registered.
 )
 
-// RegisterModule registers a module to the cosys app.
+// Module is a hook that is called during the registration stage.
+type Module func(*Cosys) error
+
+// moduleEntry is a registered module, along with its name and dependencies.
+type moduleEntry struct {
+	name     string
+	module   Module
+	requires []string
+	optional []string
+}
+
+// anonymous returns whether the module was registered without a name.
+func (m moduleEntry) anonymous() bool {
+	return strings.HasPrefix(m.name, "$")
+}
+
+// ModuleOption is a configuration of a named module.
+type ModuleOption func(*moduleEntry)
+
+// DependsOn declares modules that must be registered for the module to be registered,
+// and that are registered, bootstrapped before and cleaned up after the module.
+func DependsOn(names ...string) ModuleOption {
+	return func(entry *moduleEntry) {
+		entry.requires = append(entry.requires, names...)
+	}
+}
+
+// OptionallyDependsOn declares modules that are registered, bootstrapped before and cleaned up after the module,
+// if they are registered.
+// As importing the package of a module registers it, optional dependencies are named
+// rather than referred to by the ModuleName constants of their packages.
+func OptionallyDependsOn(names ...string) ModuleOption {
+	return func(entry *moduleEntry) {
+		entry.optional = append(entry.optional, names...)
+	}
+}
+
+// RegisterModule registers an anonymous module to the cosys app, which no module can depend on.
+// Modules without dependencies are registered in the order they are registered.
 // Safe for concurrent use.
 func RegisterModule(module Module) error {
-	_, err := mdRegister.RegisterRandom(module)
-	return err
+	if module == nil {
+		return fmt.Errorf("module is nil")
+	}
+
+	mdMutex.Lock()
+	defer mdMutex.Unlock()
+
+	mdModules = append(mdModules, moduleEntry{
+		name:   randomString([]byte{'$'}, 8),
+		module: module,
+	})
+
+	return nil
 }
 
-// Module is a hook that is called during the registration stage.
-type Module func(*Cosys) error
+// RegisterNamedModule registers a module with the given name to the cosys app, which other modules can depend on.
+// Modules are registered after their dependencies, and otherwise in the order they are registered,
+// and their bootstrap hooks are called in the same order, and cleanup hooks in the reverse order.
+// Throws an error if the module is nil, if the name is empty or prefixed with $,
+// or if a module with the same name has been registered.
+// Safe for concurrent use.
+func RegisterNamedModule(name string, module Module, options ...ModuleOption) error {
+	if module == nil {
+		return fmt.Errorf("module is nil: %s", name)
+	}
+
+	if name == "" || strings.HasPrefix(name, "$") {
+		return fmt.Errorf("invalid module name: %s", name)
+	}
+
+	entry := moduleEntry{
+		name:   name,
+		module: module,
+	}
+
+	for _, option := range options {
+		option(&entry)
+	}
+
+	mdMutex.Lock()
+	defer mdMutex.Unlock()
+
+	for _, registered := range mdModules {
+		if registered.name == name {
+			return fmt.Errorf("duplicate module: %s", name)
+		}
+	}
+
+	mdModules = append(mdModules, entry)
+
+	return nil
+}
+
+// sortedModules returns the registered modules in topological order of their dependencies,
+// and otherwise in the order they are registered.
+// Throws an error if a required dependency is not registered, or if dependencies form a cycle.
+// Safe for concurrent use.
+func sortedModules() ([]moduleEntry, error) {
+	mdMutex.RLock()
+	entries := make([]moduleEntry, len(mdModules))
+	copy(entries, mdModules)
+	mdMutex.RUnlock()
+
+	byName := make(map[string]moduleEntry, len(entries))
+	for _, entry := range entries {
+		byName[entry.name] = entry
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	states := make(map[string]int, len(entries))
+	sorted := make([]moduleEntry, 0, len(entries))
+
+	var path []string
+	var visit func(entry moduleEntry) error
+	visit = func(entry moduleEntry) error {
+		switch states[entry.name] {
+		case visited:
+			return nil
+		case visiting:
+			start := 0
+			for index, name := range path {
+				if name == entry.name {
+					start = index
+				}
+			}
+
+			cycle := append(path[start:len(path):len(path)], entry.name)
+			return fmt.Errorf("module dependency cycle: %s", strings.Join(cycle, " -> "))
+		}
+
+		states[entry.name] = visiting
+		path = append(path, entry.name)
+
+		for _, name := range entry.requires {
+			dependency, ok := byName[name]
+			if !ok || dependency.anonymous() {
+				return fmt.Errorf("module %s depends on module %s, which is not registered", entry.name, name)
+			}
+
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+
+		for _, name := range entry.optional {
+			dependency, ok := byName[name]
+			if !ok || dependency.anonymous() {
+				continue
+			}
+
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+
+		path = path[:len(path)-1]
+		states[entry.name] = visited
+		sorted = append(sorted, entry)
+
+		return nil
+	}
+
+	for _, entry := range entries {
+		if err := visit(entry); err != nil {
+			return nil, err
+		}
+	}
+
+	return sorted, nil
+}
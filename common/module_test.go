@@ -0,0 +1,187 @@
+package common
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// withModules replaces the registered modules for the duration of the test.
+func withModules(t *testing.T) {
+	t.Helper()
+
+	mdMutex.Lock()
+	registered := mdModules
+	mdModules = nil
+	mdMutex.Unlock()
+
+	t.Cleanup(func() {
+		mdMutex.Lock()
+		mdModules = registered
+		mdMutex.Unlock()
+	})
+}
+
+// recordingModule returns a module adding bootstrap and cleanup hooks, which record the name of the module in the given slices.
+func recordingModule(name string, registered, bootstrapped, cleanedUp *[]string) Module {
+	return func(cosys *Cosys) error {
+		*registered = append(*registered, name)
+
+		if _, err := cosys.AddBootstrapHook(func(*Cosys) error {
+			*bootstrapped = append(*bootstrapped, name)
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		_, err := cosys.AddCleanupHook(func(*Cosys) error {
+			*cleanedUp = append(*cleanedUp, name)
+			return nil
+		})
+		return err
+	}
+}
+
+// moduleNames returns the names of the given modules.
+func moduleNames(modules []moduleEntry) []string {
+	names := make([]string, 0, len(modules))
+	for _, module := range modules {
+		names = append(names, module.name)
+	}
+
+	return names
+}
+
+func TestModulesTopologicalOrder(t *testing.T) {
+	withModules(t)
+
+	var registered, bootstrapped, cleanedUp []string
+
+	for _, err := range []error{
+		RegisterNamedModule("cms", recordingModule("cms", &registered, &bootstrapped, &cleanedUp), DependsOn("sqlite3")),
+		RegisterNamedModule("cache", recordingModule("cache", &registered, &bootstrapped, &cleanedUp), OptionallyDependsOn("cms", "logger")),
+		RegisterNamedModule("middleware", recordingModule("middleware", &registered, &bootstrapped, &cleanedUp), OptionallyDependsOn("logger", "missing")),
+		RegisterNamedModule("sqlite3", recordingModule("sqlite3", &registered, &bootstrapped, &cleanedUp)),
+		RegisterNamedModule("logger", recordingModule("logger", &registered, &bootstrapped, &cleanedUp)),
+	} {
+		if err != nil {
+			t.Fatalf("could not register module: %v", err)
+		}
+	}
+
+	cosys, err := New()
+	if err != nil {
+		t.Fatalf("could not create cosys app: %v", err)
+	}
+
+	want := []string{"sqlite3", "cms", "logger", "cache", "middleware"}
+	if !slices.Equal(registered, want) {
+		t.Errorf("registration order = %v, want %v", registered, want)
+	}
+	if !slices.Equal(cosys.Modules(), want) {
+		t.Errorf("modules = %v, want %v", cosys.Modules(), want)
+	}
+
+	if err = cosys.Bootstrap(); err != nil {
+		t.Fatalf("could not bootstrap: %v", err)
+	}
+	if !slices.Equal(bootstrapped, want) {
+		t.Errorf("bootstrap order = %v, want %v", bootstrapped, want)
+	}
+
+	if err = cosys.Cleanup(); err != nil {
+		t.Fatalf("could not clean up: %v", err)
+	}
+	reversed := slices.Clone(want)
+	slices.Reverse(reversed)
+	if !slices.Equal(cleanedUp, reversed) {
+		t.Errorf("cleanup order = %v, want %v", cleanedUp, reversed)
+	}
+}
+
+func TestModulesRegistrationOrder(t *testing.T) {
+	withModules(t)
+
+	noop := func(*Cosys) error { return nil }
+
+	_ = RegisterNamedModule("b", noop)
+	_ = RegisterModule(noop)
+	_ = RegisterNamedModule("a", noop)
+
+	modules, err := sortedModules()
+	if err != nil {
+		t.Fatalf("could not sort modules: %v", err)
+	}
+
+	names := moduleNames(modules)
+	if len(names) != 3 || names[0] != "b" || !strings.HasPrefix(names[1], "$") || names[2] != "a" {
+		t.Errorf("modules = %v, want b, the anonymous module and a, in the order they are registered", names)
+	}
+}
+
+func TestModulesCycle(t *testing.T) {
+	withModules(t)
+
+	noop := func(*Cosys) error { return nil }
+
+	_ = RegisterNamedModule("a", noop, DependsOn("b"))
+	_ = RegisterNamedModule("b", noop, OptionallyDependsOn("c"))
+	_ = RegisterNamedModule("c", noop, DependsOn("a"))
+
+	_, err := sortedModules()
+	if err == nil {
+		t.Fatal("expected an error for a dependency cycle")
+	}
+
+	if want := "module dependency cycle: a -> b -> c -> a"; err.Error() != want {
+		t.Errorf("error = %q, want %q", err, want)
+	}
+
+	if _, err = New(); err == nil {
+		t.Error("expected New to fail with a dependency cycle")
+	}
+}
+
+func TestModulesMissingDependency(t *testing.T) {
+	withModules(t)
+
+	noop := func(*Cosys) error { return nil }
+
+	_ = RegisterNamedModule("cms", noop, DependsOn("sqlite3"))
+
+	_, err := sortedModules()
+	if err == nil {
+		t.Fatal("expected an error for a missing dependency")
+	}
+
+	if want := "module cms depends on module sqlite3, which is not registered"; err.Error() != want {
+		t.Errorf("error = %q, want %q", err, want)
+	}
+}
+
+func TestRegisterNamedModuleErrors(t *testing.T) {
+	withModules(t)
+
+	noop := func(*Cosys) error { return nil }
+
+	if err := RegisterNamedModule("a", noop); err != nil {
+		t.Fatalf("could not register module: %v", err)
+	}
+
+	for _, test := range []struct {
+		name       string
+		moduleName string
+		module     Module
+	}{
+		{name: "duplicate", moduleName: "a", module: noop},
+		{name: "empty name", moduleName: "", module: noop},
+		{name: "anonymous name", moduleName: "$a", module: noop},
+		{name: "nil module", moduleName: "b", module: nil},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if err := RegisterNamedModule(test.moduleName, test.module); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"maps"
 	"math/rand"
 	"reflect"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 	"unsafe"
@@ -14,9 +16,10 @@ import (
 
 // options are configurations for registers.
 type options struct {
-	itemName   string
-	checkZero  bool
-	checkExist bool
+	itemName     string
+	checkZero    bool
+	checkExist   bool
+	reverseOrder bool
 }
 
 // defaultOptions returns the default configuration.
@@ -50,6 +53,11 @@ var skipCheckExist option = func(opts *options) {
 	opts.checkExist = true
 }
 
+// reverseOrder orders the values of ordered registers in the reverse order they are registered.
+var reverseOrder option = func(opts *options) {
+	opts.reverseOrder = true
+}
+
 // Single Register
 
 // singleRegister is a register for a single value.
@@ -126,12 +134,30 @@ func (r *singleRegister[T]) Register(item T) error {
 	return nil
 }
 
+// Wrap replaces the registered value with the value returned by the wrapper,
+// and returns an error if a value has not been registered.
+// Safe for concurrent use.
+func (r *singleRegister[T]) Wrap(wrapper func(T) T) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if !r.registered {
+		return fmt.Errorf("%s not registered", r.options.itemName)
+	}
+
+	r.register = wrapper(r.register)
+
+	return nil
+}
+
 // Permanent Register
 
 // permRegister is a register for multiple values which cannot be updated or deleted.
+// The order the values are registered in is kept.
 type permRegister[T any] struct {
 	mutex    *sync.RWMutex
 	register map[string]T
+	order    []string
 	options  options
 }
 
@@ -145,6 +171,7 @@ func newPermRegister[T any](cfg ...option) *permRegister[T] {
 	return &permRegister[T]{
 		mutex:    &sync.RWMutex{},
 		register: make(map[string]T),
+		order:    []string{},
 		options:  opts,
 	}
 }
@@ -172,22 +199,29 @@ func (r *permRegister[T]) GetAll() map[string]T {
 	return maps.Clone(r.register)
 }
 
-// GetSlice returns a slice of all registered values.
+// GetSlice returns a slice of all registered values, in the order they are registered.
 // Safe for concurrent use.
 func (r *permRegister[T]) GetSlice() []T {
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
 
-	items := make([]T, len(r.register))
-	index := 0
-	for _, item := range r.register {
-		items[index] = item
-		index++
+	items := make([]T, len(r.order))
+	for index, uid := range r.order {
+		items[index] = r.register[uid]
 	}
 
 	return items
 }
 
+// Uids returns the uids of all registered values, in the order they are registered.
+// Safe for concurrent use.
+func (r *permRegister[T]) Uids() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return slices.Clone(r.order)
+}
+
 // Clone returns a cloned multi register.
 // Safe for concurrent use.
 func (r *permRegister[T]) Clone() *permRegister[T] {
@@ -197,6 +231,7 @@ func (r *permRegister[T]) Clone() *permRegister[T] {
 	return &permRegister[T]{
 		mutex:    &sync.RWMutex{},
 		register: maps.Clone(r.register),
+		order:    slices.Clone(r.order),
 
 		options: r.options,
 	}
@@ -207,18 +242,25 @@ func (r *permRegister[T]) Clone() *permRegister[T] {
 // of if a value has been registered under that uid.
 // Safe for concurrent use.
 func (r *permRegister[T]) Register(uid string, item T) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.set(uid, item)
+}
+
+// set sets a value under the given uid, see Register.
+// Must be called with the mutex locked.
+func (r *permRegister[T]) set(uid string, item T) error {
 	if r.options.checkZero && isZero(item) {
 		return fmt.Errorf("%s is nil: %s", r.options.itemName, uid)
 	}
 
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
-
 	if isDup(r.register, uid) {
 		return fmt.Errorf("duplicate %s: %s", r.options.itemName, uid)
 	}
 
 	r.register[uid] = item
+	r.order = append(r.order, uid)
 
 	return nil
 }
@@ -227,6 +269,7 @@ func (r *permRegister[T]) Register(uid string, item T) error {
 // if the checkZero configuration is true and any value is a zero-value
 // or if a value has been registered under any uid.
 // The operation is atomic, either all or no values will be set.
+// The values are ordered by their uids.
 // Safe for concurrent use.
 func (r *permRegister[T]) RegisterMany(items map[string]T) error {
 	if r.options.checkZero {
@@ -242,8 +285,9 @@ func (r *permRegister[T]) RegisterMany(items map[string]T) error {
 		return err
 	}
 
-	for uid, item := range items {
-		r.register[uid] = item
+	for _, uid := range slices.Sorted(maps.Keys(items)) {
+		r.register[uid] = items[uid]
+		r.order = append(r.order, uid)
 	}
 
 	return nil
@@ -256,18 +300,24 @@ func (r *permRegister[T]) RegisterMany(items map[string]T) error {
 // do not use uid prefixed by $ for Register.
 // Safe for concurrent use.
 func (r *permRegister[T]) RegisterRandom(item T) (string, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.setRandom(item)
+}
+
+// setRandom sets a value under a random uid, see RegisterRandom.
+// Must be called with the mutex locked.
+func (r *permRegister[T]) setRandom(item T) (string, error) {
 	if r.options.checkZero && isZero(item) {
 		return "", fmt.Errorf("%s is nil", r.options.itemName)
 	}
 
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
-
 	var uid string
 	for _ = range 1000 {
-		uid = randomString([]byte{'$'}, 8)
-		if isDup(r.register, uid) {
-			continue
+		if candidate := randomString([]byte{'$'}, 8); !isDup(r.register, candidate) {
+			uid = candidate
+			break
 		}
 	}
 
@@ -276,6 +326,7 @@ func (r *permRegister[T]) RegisterRandom(item T) (string, error) {
 	}
 
 	r.register[uid] = item
+	r.order = append(r.order, uid)
 
 	return uid, nil
 }
@@ -335,15 +386,177 @@ func (r *multiRegister[T]) Remove(uid string) error {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
+	return r.remove(uid)
+}
+
+// remove deletes the value under the given uid, see Remove.
+// Must be called with the mutex locked.
+func (r *multiRegister[T]) remove(uid string) error {
 	if r.options.checkExist && isMissing(r.register, uid) {
 		return fmt.Errorf("%s not exist: %s", r.options.itemName, uid)
 	}
 
+	if _, ok := r.register[uid]; ok {
+		r.order = slices.DeleteFunc(r.order, func(registered string) bool {
+			return registered == uid
+		})
+	}
+
 	delete(r.register, uid)
 
 	return nil
 }
 
+// Ordered Register
+
+// orderedRegister is a register for multiple values, which are ordered by their hook configurations,
+// and otherwise in the order they are registered.
+type orderedRegister[T any] struct {
+	*multiRegister[T]
+	configs map[string]hookConfig
+	sorted  []string // sorted are the sorted uids, which are sorted again if values are registered or removed.
+}
+
+// newOrderedRegister returns a new ordered register with configurations.
+func newOrderedRegister[T any](cfg ...option) *orderedRegister[T] {
+	return &orderedRegister[T]{
+		multiRegister: newMultiRegister[T](cfg...),
+		configs:       make(map[string]hookConfig),
+	}
+}
+
+// Clone returns a cloned ordered register.
+// Safe for concurrent use.
+func (r *orderedRegister[T]) Clone() *orderedRegister[T] {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return &orderedRegister[T]{
+		multiRegister: &multiRegister[T]{
+			&permRegister[T]{
+				mutex:    &sync.RWMutex{},
+				register: maps.Clone(r.register),
+				order:    slices.Clone(r.order),
+
+				options: r.options,
+			},
+		},
+		configs: maps.Clone(r.configs),
+	}
+}
+
+// RegisterOrdered sets a value under the uid set with HookName, or a random uid,
+// ordered with the given hook configuration, and returns the uid or returns an error
+// if the checkZero configuration is true and the value is a zero-value,
+// or if the uid is prefixed with $ or a value has been registered under that uid,
+// or if it fails to generate a valid random uid.
+// Safe for concurrent use.
+func (r *orderedRegister[T]) RegisterOrdered(item T, config hookConfig) (string, error) {
+	uid := config.name
+	if strings.HasPrefix(uid, "$") {
+		return "", fmt.Errorf("invalid %s uid: %s", r.options.itemName, uid)
+	}
+
+	// The value and its configuration are set together, so that it is never sorted without its configuration.
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if uid == "" {
+		var err error
+		if uid, err = r.setRandom(item); err != nil {
+			return "", err
+		}
+	} else if err := r.set(uid, item); err != nil {
+		return "", err
+	}
+
+	r.configs[uid] = config
+	r.sorted = nil
+
+	return uid, nil
+}
+
+// Remove deletes the value under the given uid, and returns an error
+// if the mustExist configuration is true and no value has been registered under that uid.
+// Safe for concurrent use.
+func (r *orderedRegister[T]) Remove(uid string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := r.remove(uid); err != nil {
+		return err
+	}
+
+	delete(r.configs, uid)
+	r.sorted = nil
+
+	return nil
+}
+
+// orderedItem is a value of an ordered register, along with its hook configuration.
+type orderedItem[T any] struct {
+	item   T
+	config hookConfig
+}
+
+// GetOrdered returns a slice of all registered values, ordered by their hook configurations,
+// and otherwise in the order they are registered, or in the reverse order if the reverseOrder configuration is true.
+// Throws an error if the before and after constraints of the values form a cycle.
+// Safe for concurrent use.
+func (r *orderedRegister[T]) GetOrdered() ([]T, error) {
+	items, err := r.GetOrderedItems()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]T, len(items))
+	for index, item := range items {
+		values[index] = item.item
+	}
+
+	return values, nil
+}
+
+// GetOrderedItems returns a slice of all registered values along with their hook configurations, ordered as GetOrdered.
+// Throws an error if the before and after constraints of the values form a cycle.
+// Safe for concurrent use.
+func (r *orderedRegister[T]) GetOrderedItems() ([]orderedItem[T], error) {
+	r.mutex.RLock()
+	if r.sorted != nil && len(r.sorted) == len(r.order) {
+		items := r.items(r.sorted)
+		r.mutex.RUnlock()
+
+		return items, nil
+	}
+	r.mutex.RUnlock()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	sorted, err := sortHooks(r.order, r.configs, r.options.reverseOrder)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", r.options.itemName, err)
+	}
+
+	r.sorted = sorted
+
+	return r.items(sorted), nil
+}
+
+// items returns the values registered under the given uids, along with their hook configurations.
+// Must be called with the mutex locked.
+func (r *orderedRegister[T]) items(uids []string) []orderedItem[T] {
+	items := make([]orderedItem[T], len(uids))
+	for index, uid := range uids {
+		items[index] = orderedItem[T]{
+			item:   r.register[uid],
+			config: r.configs[uid],
+		}
+	}
+
+	return items
+}
+
 // Stringer Register
 
 // stringerRegister is a register of stringer values.
@@ -363,6 +576,7 @@ func newStringerRegister[T fmt.Stringer](cfg ...option) *stringerRegister[T] {
 // or if the checkZero configuration is true and any value is a zero-value,
 // or if a value has been registered under any String() return value.
 // The operation is atomic, either all or no values will be set.
+// The values are ordered as they are given.
 // Safe for concurrent use.
 func (r *stringerRegister[T]) RegisterStringers(items ...T) error {
 	itemMap, err := toMap(r.options.itemName, items)
@@ -383,8 +597,10 @@ func (r *stringerRegister[T]) RegisterStringers(items ...T) error {
 		return err
 	}
 
-	for uid, item := range itemMap {
+	for _, item := range items {
+		uid := item.String()
 		r.register[uid] = item
+		r.order = append(r.order, uid)
 	}
 
 	return nil
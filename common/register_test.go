@@ -0,0 +1,53 @@
+package common
+
+import (
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestOrderedRegisterConcurrentRegistration(t *testing.T) {
+	register := newOrderedRegister[int]()
+
+	const count = 5000
+
+	var wg sync.WaitGroup
+	for item := 1; item <= count; item++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if _, err := register.RegisterOrdered(item, hookConfig{priority: -item}); err != nil {
+				t.Errorf("could not register %d: %v", item, err)
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	// Values are never ordered without their configurations, which would order them last.
+	descending := func(a, b int) int { return b - a }
+	for sorting := true; sorting; {
+		select {
+		case <-done:
+			sorting = false
+		default:
+		}
+
+		items, err := register.GetOrdered()
+		if err != nil {
+			t.Fatalf("could not order values: %v", err)
+		}
+
+		if !slices.IsSortedFunc(items, descending) {
+			t.Fatalf("values = %v, want them ordered by priority", items)
+		}
+		if !sorting && len(items) != count {
+			t.Fatalf("%d values ordered, want %d", len(items), count)
+		}
+	}
+}
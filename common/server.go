@@ -1,6 +1,17 @@
 package common
 
+import "context"
+
 // Server is a core service for the external API.
 type Server interface {
 	Start() error
+	Shutdown(ctx context.Context) error
+}
+
+// ListeningServer is a server that signals once it is listening,
+// so that the cosys app is only reported ready once the server accepts connections.
+type ListeningServer interface {
+	Server
+	// Listening returns a channel that is closed once the server is listening.
+	Listening() <-chan struct{}
 }
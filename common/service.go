@@ -1,69 +1,405 @@
 package common
 
 import (
-	"errors"
+	"context"
 	"fmt"
 	"reflect"
+	"slices"
+	"strings"
 	"sync"
 )
 
-type Service map[string]any
+// Scope is the lifetime of the instances of a service.
+type Scope int
 
-// A singleton Service map to be used throughout the application
-var singleService Service = make(Service)
+const (
+	Singleton  Scope = iota // Singleton services are constructed once, when they are first resolved.
+	PerRequest              // PerRequest services are constructed once per request scope, when they are first resolved in it.
+)
+
+// String returns the name of the scope.
+func (s Scope) String() string {
+	switch s {
+	case Singleton:
+		return "singleton"
+	case PerRequest:
+		return "per-request"
+	default:
+		return fmt.Sprintf("Scope(%d)", int(s))
+	}
+}
+
+// ProvideOption is a configuration of a provided service.
+type ProvideOption func(*provider)
+
+// InScope sets the scope of the service, which is Singleton by default.
+func InScope(scope Scope) ProvideOption {
+	return func(p *provider) {
+		p.scope = scope
+	}
+}
 
-var registerLock = &sync.Mutex{}
+// provider is a provided service, along with its constructor and its instance, if it is a constructed singleton.
+// The construction state of singletons is guarded by the mutex of the container.
+type provider struct {
+	name        string // name is the name of the type of the service.
+	scope       Scope
+	construct   func(context.Context, *Cosys) (any, error)
+	constructed bool
+	instance    any
 
-func isFunc(v any) bool {
-	return reflect.TypeOf(v).Kind() == reflect.Func
+	constructor *resolver     // constructor is the resolver constructing the singleton, if it is being constructed.
+	done        chan struct{} // done is closed when the construction in progress ends.
+}
+
+// resolver is the identity of a chain of resolutions sharing a context,
+// which waits for singletons being constructed by other resolvers.
+type resolver struct {
+	waiting *provider // waiting is the singleton the resolver waits for, guarded by the mutex of the container.
+}
+
+// container is the dependency injection container of the services of the cosys app, keyed by their types.
+type container struct {
+	mutex     sync.RWMutex
+	providers map[reflect.Type]*provider
+}
+
+// newContainer returns a new empty container.
+func newContainer() *container {
+	return &container{
+		providers: make(map[reflect.Type]*provider),
+	}
 }
 
-func keyCollision(key string, service Service) bool {
-	_, ok := service[key]
-	return ok
+// requestScope holds the instances of the per-request services resolved in a request scope.
+type requestScope struct {
+	mutex     sync.Mutex // mutex is held while per-request services are constructed in the scope.
+	instances map[*provider]any
+}
+
+type requestScopeKey struct{}
+
+// ContextWithRequestScope returns a copy of the given context containing a new request scope,
+// which per-request services resolved with the context are constructed once in.
+// The server starts a request scope for each request.
+func ContextWithRequestScope(ctx context.Context) context.Context {
+	return context.WithValue(ctx, requestScopeKey{}, &requestScope{
+		instances: make(map[*provider]any),
+	})
+}
+
+// resolution is the state of the resolution of a service, carried by the context passed to its constructor.
+type resolution struct {
+	resolver  *resolver     // resolver is the resolver of the resolution, shared by the resolutions of dependencies.
+	path      []*provider   // path are the services being constructed, in the order they depend on each other.
+	singleton bool          // singleton specifies that a singleton is being constructed.
+	scope     *requestScope // scope is the request scope whose mutex is held, if any.
+}
+
+type resolutionKey struct{}
+
+// with returns the resolution of a dependency of the services being constructed.
+func (r resolution) with(p *provider) resolution {
+	r.path = append(slices.Clip(r.path), p)
+	return r
+}
+
+// cycle returns the error of the dependency cycle formed by resolving the given service, if any.
+func (r resolution) cycle(p *provider) error {
+	index := slices.Index(r.path, p)
+	if index < 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(r.path)-index+1)
+	for _, dependency := range r.path[index:] {
+		names = append(names, dependency.name)
+	}
+	names = append(names, p.name)
+
+	return fmt.Errorf("service dependency cycle: %s", strings.Join(names, " -> "))
 }
 
-// RegisterServices registers a map of services to the singleton Service map
-// RegisterServices will return an error if a service name already exists in the singleton Service map
-// Safe for concurrent use
-func RegisterServices(services Service) error {
-	serviceSingleton := GetService()
-	registerLock.Lock()
-	defer registerLock.Unlock()
-	for key, value := range services {
-		if !isFunc(value) {
-			return fmt.Errorf("service %s is not a function", key)
+// waitCycle returns the error of the dependency cycle formed by waiting for the given singleton,
+// if its constructor waits, directly or through other resolvers, for a singleton being constructed by the resolution.
+// Singletons constructed by other goroutines sharing the context of the resolution are waited for.
+// Must be called with the mutex of the container locked.
+func (r resolution) waitCycle(p *provider) error {
+	var waited []*provider
+	for next := p; next != nil && next.constructor != nil && !slices.Contains(waited, next); next = next.constructor.waiting {
+		waited = append(waited, next)
+
+		index := slices.Index(r.path, next)
+		if next.constructor != r.resolver || index < 0 {
+			continue
+		}
+
+		names := make([]string, 0, len(r.path)-index+len(waited))
+		for _, dependency := range r.path[index:] {
+			names = append(names, dependency.name)
 		}
-		if keyCollision(key, serviceSingleton) {
-			return errors.New("service name already exists")
+		for _, dependency := range waited {
+			names = append(names, dependency.name)
 		}
-		serviceSingleton[key] = value
+
+		return fmt.Errorf("service dependency cycle: %s", strings.Join(names, " -> "))
 	}
 
 	return nil
 }
 
-// RegisterService registers a service to the singleton Service map
-// RegisterService will return an error if the service name already exists in the singleton Service map
-// Safe for concurrent use
-func RegisterService(serviceName string, service any) error {
-	if !isFunc(service) {
-		return fmt.Errorf("service %s is not a function", serviceName)
+// Provide provides the service of type T to the cosys app, which is constructed lazily when it is resolved,
+// once per scope given by the options, by default as a singleton.
+// The constructor is called with the context the service is resolved with,
+// which dependencies of the service are resolved with.
+// Throws an error if the constructor is nil, or if a service of type T has been provided.
+// Can only be used during registration.
+// Safe for concurrent use.
+func Provide[T any](cosys *Cosys, constructor func(context.Context, *Cosys) (T, error), options ...ProvideOption) error {
+	if cosys == nil {
+		return fmt.Errorf("cosys is nil")
 	}
 
-	serviceSingleton := GetService()
-	registerLock.Lock()
-	defer registerLock.Unlock()
-	if keyCollision(serviceName, serviceSingleton) {
-		return errors.New("service name already exists")
+	typ := reflect.TypeFor[T]()
+
+	if constructor == nil {
+		return fmt.Errorf("constructor is nil: %s", typ)
+	}
+
+	if cosys.state != Registration {
+		return fmt.Errorf("services must be provided during registration")
+	}
+
+	p := &provider{
+		name:  typ.String(),
+		scope: Singleton,
+		construct: func(ctx context.Context, cosys *Cosys) (any, error) {
+			return constructor(ctx, cosys)
+		},
+	}
+
+	for _, option := range options {
+		option(p)
+	}
+
+	return cosys.container.provide(typ, p)
+}
+
+// ProvideValue provides the given value as the singleton service of type T to the cosys app.
+// Throws an error if a service of type T has been provided.
+// Can only be used during registration.
+// Safe for concurrent use.
+func ProvideValue[T any](cosys *Cosys, value T) error {
+	return Provide(cosys, func(context.Context, *Cosys) (T, error) {
+		return value, nil
+	})
+}
+
+// Resolve returns the service of type T, constructing it and its dependencies if not constructed in their scopes.
+// Per-request services are resolved in the request scope of the given context.
+// Throws an error if the service or one of its dependencies is not provided or fails to be constructed,
+// if their dependencies form a cycle, if a singleton service depends on a per-request service,
+// or if a per-request service is resolved without a request scope.
+// Cannot be used during registration.
+// Safe for concurrent use.
+func Resolve[T any](ctx context.Context, cosys *Cosys) (T, error) {
+	var zero T
+
+	if cosys == nil {
+		return zero, fmt.Errorf("cosys is nil")
+	}
+
+	if cosys.state == Registration {
+		return zero, fmt.Errorf("services cannot be resolved during registration")
+	}
+
+	instance, err := cosys.container.resolve(ctx, cosys, reflect.TypeFor[T]())
+	if err != nil {
+		return zero, err
+	}
+
+	service, _ := instance.(T)
+	return service, nil
+}
+
+// provide adds the given provider of the service of the given type.
+// Throws an error if the scope is invalid, or if a service of the type has been provided.
+// Safe for concurrent use.
+func (c *container) provide(typ reflect.Type, p *provider) error {
+	if p.scope != Singleton && p.scope != PerRequest {
+		return fmt.Errorf("invalid scope of service %s: %s", p.name, p.scope)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, ok := c.providers[typ]; ok {
+		return fmt.Errorf("duplicate service: %s", p.name)
 	}
-	serviceSingleton[serviceName] = service
+
+	c.providers[typ] = p
 
 	return nil
 }
 
-// GetService returns the singleton Service map
-// Unsafe for concurrent use as it exposes the underlying singleService object to everyone
-func GetService() Service {
-	return singleService
+// resolve returns the service of the given type, see Resolve.
+// Safe for concurrent use.
+func (c *container) resolve(ctx context.Context, cosys *Cosys, typ reflect.Type) (any, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	c.mutex.RLock()
+	p, ok := c.providers[typ]
+	c.mutex.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("service %s is not provided", typ)
+	}
+
+	res, _ := ctx.Value(resolutionKey{}).(resolution)
+	if err := res.cycle(p); err != nil {
+		return nil, err
+	}
+
+	if res.resolver == nil {
+		res.resolver = &resolver{}
+	}
+
+	if p.scope == PerRequest {
+		return c.resolvePerRequest(ctx, cosys, p, res)
+	}
+
+	return c.resolveSingleton(ctx, cosys, p, res)
+}
+
+// resolveSingleton returns the instance of the given singleton service, constructing it if not constructed.
+// Concurrent resolutions of the service wait for the construction in progress, and construct it again if it fails.
+// Throws the error of the context if it is done while waiting.
+// Safe for concurrent use.
+func (c *container) resolveSingleton(ctx context.Context, cosys *Cosys, p *provider, res resolution) (any, error) {
+	c.mutex.RLock()
+	constructed, instance := p.constructed, p.instance
+	c.mutex.RUnlock()
+
+	if constructed {
+		return instance, nil
+	}
+
+	constructed, err := c.startConstruction(ctx, p, res)
+	if err != nil {
+		return nil, err
+	}
+
+	if constructed {
+		c.mutex.RLock()
+		defer c.mutex.RUnlock()
+
+		return p.instance, nil
+	}
+
+	// The construction ends even if the constructor panics, so that concurrent resolutions do not wait forever.
+	defer func() {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+
+		if constructed {
+			p.constructed, p.instance = true, instance
+		}
+		p.constructor = nil
+		close(p.done)
+	}()
+
+	res = res.with(p)
+	res.singleton = true
+
+	instance, err = p.construct(context.WithValue(ctx, resolutionKey{}, res), cosys)
+	if err != nil {
+		return nil, fmt.Errorf("service %s: %w", p.name, err)
+	}
+
+	constructed = true
+
+	return instance, nil
+}
+
+// startConstruction waits for the construction in progress of the given singleton, if any,
+// and returns whether it is constructed, or marks it as being constructed by the resolution otherwise.
+// Throws an error if waiting forms a dependency cycle, or the error of the context if it is done while waiting.
+// Safe for concurrent use.
+func (c *container) startConstruction(ctx context.Context, p *provider, res resolution) (bool, error) {
+	for {
+		c.mutex.Lock()
+
+		if p.constructed {
+			c.mutex.Unlock()
+			return true, nil
+		}
+
+		if p.constructor == nil {
+			p.constructor = res.resolver
+			p.done = make(chan struct{})
+			c.mutex.Unlock()
+			return false, nil
+		}
+
+		if err := res.waitCycle(p); err != nil {
+			c.mutex.Unlock()
+			return false, err
+		}
+
+		res.resolver.waiting = p
+		done := p.done
+		c.mutex.Unlock()
+
+		var err error
+		select {
+		case <-done:
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+
+		c.mutex.Lock()
+		res.resolver.waiting = nil
+		c.mutex.Unlock()
+
+		if err != nil {
+			return false, err
+		}
+	}
+}
+
+// resolvePerRequest returns the instance of the given per-request service in the request scope of the context,
+// constructing it if not constructed in the scope.
+// Safe for concurrent use.
+func (c *container) resolvePerRequest(ctx context.Context, cosys *Cosys, p *provider, res resolution) (any, error) {
+	if res.singleton {
+		return nil, fmt.Errorf("singleton service %s depends on per-request service %s", res.path[len(res.path)-1].name, p.name)
+	}
+
+	scope, ok := ctx.Value(requestScopeKey{}).(*requestScope)
+	if !ok {
+		return nil, fmt.Errorf("per-request service %s is resolved outside of a request scope", p.name)
+	}
+
+	if res.scope != scope {
+		scope.mutex.Lock()
+		defer scope.mutex.Unlock()
+	}
+
+	if instance, ok := scope.instances[p]; ok {
+		return instance, nil
+	}
+
+	res = res.with(p)
+	res.scope = scope
+
+	instance, err := p.construct(context.WithValue(ctx, resolutionKey{}, res), cosys)
+	if err != nil {
+		return nil, fmt.Errorf("service %s: %w", p.name, err)
+	}
+
+	scope.instances[p] = instance
+
+	return instance, nil
 }
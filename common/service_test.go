@@ -0,0 +1,275 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type (
+	serviceA struct{ b *serviceB }
+	serviceB struct{}
+)
+
+// newServiceCosys returns a cosys app with the services provided by the given function, ready to resolve them.
+func newServiceCosys(t *testing.T, provide func(cosys *Cosys) error) *Cosys {
+	t.Helper()
+
+	withModules(t)
+
+	cosys, err := New()
+	if err != nil {
+		t.Fatalf("could not create cosys app: %v", err)
+	}
+
+	if err = provide(cosys); err != nil {
+		t.Fatalf("could not provide services: %v", err)
+	}
+
+	if err = cosys.Bootstrap(); err != nil {
+		t.Fatalf("could not bootstrap: %v", err)
+	}
+
+	return cosys
+}
+
+// withTimeout fails the test if the given function does not return within a few seconds, e.g. if it deadlocks.
+func withTimeout(t *testing.T, f func()) {
+	t.Helper()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("resolution deadlocked")
+	}
+}
+
+func TestResolveSingletonOnce(t *testing.T) {
+	var constructions atomic.Int32
+
+	cosys := newServiceCosys(t, func(cosys *Cosys) error {
+		return Provide(cosys, func(context.Context, *Cosys) (*serviceB, error) {
+			constructions.Add(1)
+			time.Sleep(10 * time.Millisecond)
+			return &serviceB{}, nil
+		})
+	})
+
+	var wg sync.WaitGroup
+	instances := make([]*serviceB, 20)
+	for i := range instances {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			instance, err := Resolve[*serviceB](context.Background(), cosys)
+			if err != nil {
+				t.Errorf("could not resolve service: %v", err)
+			}
+			instances[i] = instance
+		}()
+	}
+	wg.Wait()
+
+	if constructions.Load() != 1 {
+		t.Errorf("constructions = %d, want 1", constructions.Load())
+	}
+	for _, instance := range instances {
+		if instance != instances[0] {
+			t.Fatal("resolved different instances of a singleton")
+		}
+	}
+}
+
+func TestResolveRetriesFailedConstruction(t *testing.T) {
+	var constructions atomic.Int32
+
+	cosys := newServiceCosys(t, func(cosys *Cosys) error {
+		return Provide(cosys, func(context.Context, *Cosys) (*serviceB, error) {
+			if constructions.Add(1) == 1 {
+				return nil, errors.New("unavailable")
+			}
+			return &serviceB{}, nil
+		})
+	})
+
+	if _, err := Resolve[*serviceB](context.Background(), cosys); err == nil || err.Error() != "service *common.serviceB: unavailable" {
+		t.Errorf("error = %v, want the error of the constructor", err)
+	}
+
+	if _, err := Resolve[*serviceB](context.Background(), cosys); err != nil {
+		t.Errorf("could not resolve service after a failed construction: %v", err)
+	}
+}
+
+func TestResolveFreshContextInConstructor(t *testing.T) {
+	cosys := newServiceCosys(t, func(cosys *Cosys) error {
+		if err := Provide(cosys, func(_ context.Context, cosys *Cosys) (*serviceA, error) {
+			// The dependency is resolved without the context of the resolution.
+			b, err := Resolve[*serviceB](context.Background(), cosys)
+			return &serviceA{b: b}, err
+		}); err != nil {
+			return err
+		}
+
+		return ProvideValue(cosys, &serviceB{})
+	})
+
+	withTimeout(t, func() {
+		a, err := Resolve[*serviceA](context.Background(), cosys)
+		if err != nil {
+			t.Errorf("could not resolve service: %v", err)
+			return
+		}
+
+		if a.b == nil {
+			t.Error("dependency not resolved")
+		}
+	})
+}
+
+func TestResolveUnrelatedSingletonsConcurrently(t *testing.T) {
+	cosys := newServiceCosys(t, func(cosys *Cosys) error {
+		if err := Provide(cosys, func(_ context.Context, cosys *Cosys) (*serviceA, error) {
+			// The dependency is resolved in another goroutine while the service is being constructed.
+			result := make(chan *serviceB)
+			go func() {
+				b, _ := Resolve[*serviceB](context.Background(), cosys)
+				result <- b
+			}()
+
+			return &serviceA{b: <-result}, nil
+		}); err != nil {
+			return err
+		}
+
+		return Provide(cosys, func(context.Context, *Cosys) (*serviceB, error) {
+			return &serviceB{}, nil
+		})
+	})
+
+	withTimeout(t, func() {
+		a, err := Resolve[*serviceA](context.Background(), cosys)
+		if err != nil {
+			t.Errorf("could not resolve service: %v", err)
+			return
+		}
+
+		if a.b == nil {
+			t.Error("dependency not resolved")
+		}
+	})
+}
+
+func TestResolveCycle(t *testing.T) {
+	cosys := newServiceCosys(t, func(cosys *Cosys) error {
+		if err := Provide(cosys, func(ctx context.Context, cosys *Cosys) (*serviceA, error) {
+			b, err := Resolve[*serviceB](ctx, cosys)
+			return &serviceA{b: b}, err
+		}); err != nil {
+			return err
+		}
+
+		return Provide(cosys, func(ctx context.Context, cosys *Cosys) (*serviceB, error) {
+			_, err := Resolve[*serviceA](ctx, cosys)
+			return &serviceB{}, err
+		})
+	})
+
+	_, err := Resolve[*serviceA](context.Background(), cosys)
+	if err == nil {
+		t.Fatal("expected an error for a dependency cycle")
+	}
+
+	if want := "service dependency cycle: *common.serviceA -> *common.serviceB -> *common.serviceA"; !strings.HasSuffix(err.Error(), want) {
+		t.Errorf("error = %q, want %q", err, want)
+	}
+}
+
+func TestResolveConcurrentCycle(t *testing.T) {
+	var startedA, startedB sync.WaitGroup
+	startedA.Add(1)
+	startedB.Add(1)
+
+	var onceA, onceB sync.Once
+
+	cosys := newServiceCosys(t, func(cosys *Cosys) error {
+		if err := Provide(cosys, func(ctx context.Context, cosys *Cosys) (*serviceA, error) {
+			onceA.Do(startedA.Done)
+			startedB.Wait()
+
+			b, err := Resolve[*serviceB](ctx, cosys)
+			return &serviceA{b: b}, err
+		}); err != nil {
+			return err
+		}
+
+		return Provide(cosys, func(ctx context.Context, cosys *Cosys) (*serviceB, error) {
+			onceB.Do(startedB.Done)
+			startedA.Wait()
+
+			_, err := Resolve[*serviceA](ctx, cosys)
+			return &serviceB{}, err
+		})
+	})
+
+	// Each service is constructed by its own resolution, which waits for the other: the cycle is reported rather than deadlocking.
+	withTimeout(t, func() {
+		var wg sync.WaitGroup
+		errs := make([]error, 2)
+
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, errs[0] = Resolve[*serviceA](context.Background(), cosys)
+		}()
+		go func() {
+			defer wg.Done()
+			_, errs[1] = Resolve[*serviceB](context.Background(), cosys)
+		}()
+		wg.Wait()
+
+		for _, err := range errs {
+			if err == nil || !strings.Contains(err.Error(), "service dependency cycle") {
+				t.Errorf("error = %v, want a dependency cycle", err)
+			}
+		}
+	})
+}
+
+func TestResolveWaitingCanceled(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	cosys := newServiceCosys(t, func(cosys *Cosys) error {
+		return Provide(cosys, func(context.Context, *Cosys) (*serviceB, error) {
+			close(started)
+			<-release
+			return &serviceB{}, nil
+		})
+	})
+
+	go func() {
+		_, _ = Resolve[*serviceB](context.Background(), cosys)
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := Resolve[*serviceB](ctx, cosys); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	close(release)
+}
@@ -26,9 +26,9 @@ const (
 	Cleanup      State = "cleanup"
 )
 
-// shutdownChannel returns a read-only channel that is sent to
-// when the cosys app is interrupted or terminated.
-func shutdownChannel() <-chan os.Signal {
+// shutdownChannel returns a channel that is sent to when the cosys app is interrupted or terminated,
+// until it is passed to signal.Stop.
+func shutdownChannel() chan os.Signal {
 	shutdownChan := make(chan os.Signal, 1)
 	signal.Notify(shutdownChan, syscall.SIGINT, syscall.SIGTERM)
 
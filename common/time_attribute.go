@@ -0,0 +1,77 @@
+package common
+
+import "time"
+
+// TimeAttribute is an attribute of datetime datatype.
+type TimeAttribute struct {
+	*attributeBase
+}
+
+// NewTimeAttribute returns a new datetime attribute with the given name.
+func NewTimeAttribute(name string) TimeAttribute {
+	base := newAttributeBase(name)
+
+	return TimeAttribute{
+		&base,
+	}
+}
+
+// Eq returns the where condition, whether the value of
+// the datetime attribute is equals to the given time.
+func (t TimeAttribute) Eq(right time.Time) Condition {
+	return &ExpressionCondition{
+		Eq,
+		t,
+		right,
+	}
+}
+
+// NEq returns the where condition, whether the value of
+// the datetime attribute is not equals to the given time.
+func (t TimeAttribute) NEq(right time.Time) Condition {
+	return &ExpressionCondition{
+		Neq,
+		t,
+		right,
+	}
+}
+
+// Lt returns the where condition, whether the value of
+// the datetime attribute is before the given time.
+func (t TimeAttribute) Lt(right time.Time) Condition {
+	return &ExpressionCondition{
+		Lt,
+		t,
+		right,
+	}
+}
+
+// Gt returns the where condition, whether the value of
+// the datetime attribute is after the given time.
+func (t TimeAttribute) Gt(right time.Time) Condition {
+	return &ExpressionCondition{
+		Gt,
+		t,
+		right,
+	}
+}
+
+// Lte returns the where condition, whether the value of
+// the datetime attribute is before or equals to the given time.
+func (t TimeAttribute) Lte(right time.Time) Condition {
+	return &ExpressionCondition{
+		Lte,
+		t,
+		right,
+	}
+}
+
+// Gte returns the where condition, whether the value of
+// the datetime attribute is after or equals to the given time.
+func (t TimeAttribute) Gte(right time.Time) Condition {
+	return &ExpressionCondition{
+		Gte,
+		t,
+		right,
+	}
+}
@@ -0,0 +1,192 @@
+package common
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// TraceparentHeader is the W3C trace context header propagating the parent span of a request.
+const TraceparentHeader = "traceparent"
+
+// TraceId is the id of a trace.
+type TraceId [16]byte
+
+// String returns the trace id in lowercase hex.
+func (t TraceId) String() string {
+	return hex.EncodeToString(t[:])
+}
+
+// SpanId is the id of a span.
+type SpanId [8]byte
+
+// String returns the span id in lowercase hex.
+func (s SpanId) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+// SpanContext identifies a span within a trace, and is propagated across process boundaries.
+type SpanContext struct {
+	TraceId TraceId
+	SpanId  SpanId
+	Sampled bool
+}
+
+// IsValid returns whether the span context has a non-zero trace id and span id.
+func (s SpanContext) IsValid() bool {
+	return s.TraceId != TraceId{} && s.SpanId != SpanId{}
+}
+
+// Traceparent returns the span context as the value of a W3C traceparent header.
+func (s SpanContext) Traceparent() string {
+	flags := "00"
+	if s.Sampled {
+		flags = "01"
+	}
+
+	return "00-" + s.TraceId.String() + "-" + s.SpanId.String() + "-" + flags
+}
+
+// ParseTraceparent returns the span context from the value of a W3C traceparent header.
+// Throws an error if the value is malformed or has an all-zero trace id or span id.
+func ParseTraceparent(traceparent string) (SpanContext, error) {
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return SpanContext{}, fmt.Errorf("invalid traceparent: %s", traceparent)
+	}
+
+	var spanContext SpanContext
+
+	traceId, err := hex.DecodeString(parts[1])
+	if err != nil || len(traceId) != len(spanContext.TraceId) {
+		return SpanContext{}, fmt.Errorf("invalid trace id: %s", parts[1])
+	}
+	copy(spanContext.TraceId[:], traceId)
+
+	spanId, err := hex.DecodeString(parts[2])
+	if err != nil || len(spanId) != len(spanContext.SpanId) {
+		return SpanContext{}, fmt.Errorf("invalid span id: %s", parts[2])
+	}
+	copy(spanContext.SpanId[:], spanId)
+
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil || len(flags) != 1 {
+		return SpanContext{}, fmt.Errorf("invalid trace flags: %s", parts[3])
+	}
+	spanContext.Sampled = flags[0]&1 == 1
+
+	if !spanContext.IsValid() {
+		return SpanContext{}, fmt.Errorf("invalid traceparent: %s", traceparent)
+	}
+
+	return spanContext, nil
+}
+
+// Span is a timed operation within a trace.
+type Span interface {
+	SpanContext() SpanContext
+	Tracer() Tracer
+	SetAttribute(key string, value any)
+	RecordError(err error)
+	End()
+}
+
+// Tracer is a core service for creating spans.
+type Tracer interface {
+	// Start starts a span with the given name, as a child of the span or remote span context
+	// in the given context, and returns a context containing the new span.
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// spanKey is the context key of the current span.
+type spanKey struct{}
+
+// remoteSpanContextKey is the context key of a span context propagated from another process.
+type remoteSpanContextKey struct{}
+
+// ContextWithSpan returns a copy of the given context containing the given span.
+func ContextWithSpan(ctx context.Context, span Span) context.Context {
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+// SpanFromContext returns the span in the given context, and whether one was found.
+func SpanFromContext(ctx context.Context) (Span, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+
+	span, ok := ctx.Value(spanKey{}).(Span)
+	return span, ok
+}
+
+// ContextWithRemoteSpanContext returns a copy of the given context
+// containing a span context propagated from another process.
+func ContextWithRemoteSpanContext(ctx context.Context, spanContext SpanContext) context.Context {
+	return context.WithValue(ctx, remoteSpanContextKey{}, spanContext)
+}
+
+// SpanContextFromContext returns the span context of the span in the given context,
+// or the remote span context if the context contains no span, and whether a valid one was found.
+func SpanContextFromContext(ctx context.Context) (SpanContext, bool) {
+	if ctx == nil {
+		return SpanContext{}, false
+	}
+
+	if span, ok := SpanFromContext(ctx); ok {
+		spanContext := span.SpanContext()
+		return spanContext, spanContext.IsValid()
+	}
+
+	spanContext, ok := ctx.Value(remoteSpanContextKey{}).(SpanContext)
+	return spanContext, ok && spanContext.IsValid()
+}
+
+// ExtractTraceparent returns a copy of the given context containing the span context
+// from the traceparent header of the given headers.
+// Returns the given context if the header is missing or malformed.
+func ExtractTraceparent(ctx context.Context, header http.Header) context.Context {
+	spanContext, err := ParseTraceparent(header.Get(TraceparentHeader))
+	if err != nil {
+		return ctx
+	}
+
+	return ContextWithRemoteSpanContext(ctx, spanContext)
+}
+
+// InjectTraceparent sets the traceparent header of the given headers
+// to the span context in the given context, if any.
+func InjectTraceparent(ctx context.Context, header http.Header) {
+	spanContext, ok := SpanContextFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	header.Set(TraceparentHeader, spanContext.Traceparent())
+}
+
+// StartSpan starts a span with the given name as a child of the span in the given context,
+// using the tracer of that span.
+// Returns a span that does nothing if the context contains no span.
+func StartSpan(ctx context.Context, name string) (context.Context, Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	parent, ok := SpanFromContext(ctx)
+	if !ok || parent.Tracer() == nil {
+		return ctx, noopSpan{}
+	}
+
+	return parent.Tracer().Start(ctx, name)
+}
+
+// noopSpan is a span that does nothing, used when tracing is disabled.
+type noopSpan struct{}
+
+func (noopSpan) SpanContext() SpanContext { return SpanContext{} }
+func (noopSpan) Tracer() Tracer           { return nil }
+func (noopSpan) SetAttribute(string, any) {}
+func (noopSpan) RecordError(error)        {}
+func (noopSpan) End()                     {}
@@ -0,0 +1,29 @@
+package cache
+
+import (
+	"github.com/cosys-io/cosys/common"
+	"github.com/spf13/viper"
+)
+
+// Keys of the project configurations of the cache module.
+const (
+	CapacityKey = "cache_capacity"
+	TTLKey      = "cache_ttl"
+)
+
+// withProjectConfigs returns the given configuration,
+// overridden by the configurations set in the project configurations.
+func withProjectConfigs(config Config) (Config, error) {
+	if err := common.ReadConfigs(); err != nil {
+		return Config{}, err
+	}
+
+	if viper.IsSet(CapacityKey) {
+		config.Capacity = viper.GetInt(CapacityKey)
+	}
+	if viper.IsSet(TTLKey) {
+		config.TTL = viper.GetDuration(TTLKey)
+	}
+
+	return config, nil
+}
@@ -0,0 +1,68 @@
+package internal
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/cosys-io/cosys/common"
+)
+
+// AllModels is the pseudo model uid whose generation changes when any model changes.
+const AllModels = "*"
+
+// generationPrefix is the prefix of the cache keys of model generations.
+const generationPrefix = "cosys:cache:generation:"
+
+// Generation returns the current generation of the model of the given uid,
+// which changes whenever an entity of the model is created, updated or deleted.
+// Cached responses are keyed by the generations of the models they depend on,
+// so that changing a generation invalidates them, across all instances sharing the cache.
+// If the generation is not found, e.g. as it was evicted, a new generation is set,
+// so that responses cached with the lost generation are not served.
+func Generation(cache common.Cache, modelUid string) (string, error) {
+	generation, found, err := cache.Get(generationPrefix + modelUid)
+	if err != nil {
+		return "", err
+	}
+
+	if found {
+		return string(generation), nil
+	}
+
+	replacement, err := newGeneration()
+	if err != nil {
+		return "", err
+	}
+
+	if err = cache.Set(generationPrefix+modelUid, []byte(replacement), 0); err != nil {
+		return "", err
+	}
+
+	return replacement, nil
+}
+
+// Invalidate invalidates all cached responses depending on the model of the given uid,
+// including those depending on all models.
+func Invalidate(cache common.Cache, modelUid string) error {
+	for _, uid := range []string{modelUid, AllModels} {
+		generation, err := newGeneration()
+		if err != nil {
+			return err
+		}
+
+		if err = cache.Set(generationPrefix+uid, []byte(generation), 0); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// newGeneration returns a new random generation.
+func newGeneration() (string, error) {
+	generation := make([]byte, 8)
+	if _, err := rand.Read(generation); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(generation), nil
+}
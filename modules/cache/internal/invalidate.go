@@ -0,0 +1,47 @@
+package internal
+
+import (
+	"github.com/cosys-io/cosys/common"
+)
+
+// InvalidatingEvents are the lifecycle events after which the cached responses depending on a model are invalidated.
+var InvalidatingEvents = []string{
+	"afterCreate", "afterCreateMany",
+	"afterUpdate", "afterUpdateMany",
+	"afterDelete", "afterDeleteMany",
+	"afterPublish", "afterUnpublish",
+	"afterRestore",
+}
+
+// AddInvalidationHooks adds lifecycle hooks to all models of the cosys app,
+// invalidating the cached responses depending on a model whenever it changes.
+// Failures to invalidate are logged rather than failing the change, which has already been made.
+func AddInvalidationHooks(cosys *common.Cosys) error {
+	cache, err := cosys.Cache()
+	if err != nil {
+		return err
+	}
+
+	for modelUid, model := range cosys.Models() {
+		hook := invalidationHook(cosys, cache, modelUid)
+
+		for _, event := range InvalidatingEvents {
+			if _, err = model.AddLifecycleHook_(event, hook); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// invalidationHook returns the lifecycle hook invalidating the cached responses depending on the model of the given uid.
+func invalidationHook(cosys *common.Cosys, cache common.Cache, modelUid string) common.LifecycleHook {
+	return func(query common.EventQuery) error {
+		if err := Invalidate(cache, modelUid); err != nil {
+			cosys.LogError("cache invalidation failed", err, common.F("model", modelUid))
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,264 @@
+package internal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/cosys-io/cosys/common"
+	"github.com/cosys-io/cosys/modules/server/response"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheStatusHeader is the header reporting whether a response was served from the cache.
+const CacheStatusHeader = "X-Cache"
+
+// responsePrefix is the prefix of the cache keys of responses.
+const responsePrefix = "cosys:cache:response:"
+
+// cachedHeaders are the response headers stored with cached responses.
+var cachedHeaders = []string{"Content-Type", "Content-Language"}
+
+// cachedResponse is a response stored in the cache.
+type cachedResponse struct {
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+	ETag   string      `json:"etag"`
+}
+
+// credentialHeaders are the request headers of credentials, with which requests are not cached,
+// as their responses may depend on the user.
+var credentialHeaders = []string{"Authorization", "Cookie"}
+
+// Middleware returns the MiddlewareFunc caching successful GET and HEAD responses for the given ttl,
+// keyed by path and query, and by the generations of the given models, or of all models if none are given.
+// Requests with credentials are neither cached nor served from the cache,
+// and cached responses are only served to requests allowed by the policies of their route.
+// Responses carry an ETag header, and requests with a matching If-None-Match header receive 304 Not Modified.
+// If the cache fails, requests are handled without caching.
+func Middleware(ttl time.Duration, modelUids []string) common.MiddlewareFunc {
+	if len(modelUids) == 0 {
+		modelUids = []string{AllModels}
+	}
+
+	return func(cosys *common.Cosys) (func(http.HandlerFunc) http.HandlerFunc, error) {
+		cache, err := cosys.Cache()
+		if err != nil {
+			return nil, err
+		}
+
+		policies := newRoutePolicies(cosys)
+
+		return func(next http.HandlerFunc) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				if (r.Method != http.MethodGet && r.Method != http.MethodHead) || hasCredentials(r) {
+					next(w, r)
+					return
+				}
+
+				key, err := responseKey(cache, r, modelUids)
+				if err != nil {
+					next(w, r)
+					return
+				}
+
+				if data, found, err := cache.Get(key); err == nil && found && policies.allow(r) {
+					var cached cachedResponse
+					if err = json.Unmarshal(data, &cached); err == nil {
+						writeResponse(w, r, cached, "HIT")
+						return
+					}
+				}
+
+				buffer := response.NewBuffer(nil)
+				next(buffer, r)
+
+				if buffer.Status() != http.StatusOK || r.Method == http.MethodHead {
+					writeBuffer(w, buffer)
+					return
+				}
+
+				cached := cachedResponse{
+					Header: http.Header{},
+					Body:   buffer.Body(),
+					ETag:   eTag(buffer.Body()),
+				}
+				for _, name := range cachedHeaders {
+					if values := buffer.Header().Values(name); len(values) > 0 {
+						cached.Header[name] = values
+					}
+				}
+
+				if data, err := json.Marshal(cached); err == nil {
+					_ = cache.Set(key, data, ttl)
+				}
+
+				for name, values := range buffer.Header() {
+					w.Header()[name] = values
+				}
+				writeResponse(w, r, cached, "MISS")
+			}
+		}, nil
+	}
+}
+
+// hasCredentials returns whether the given request has credentials.
+func hasCredentials(r *http.Request) bool {
+	for _, name := range credentialHeaders {
+		if r.Header.Get(name) != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// routePolicies are the policies of the routes of the cosys app, built once per route.
+// Policies are applied within the middlewares of a route,
+// so cached responses are checked against them before being served.
+type routePolicies struct {
+	cosys *common.Cosys
+
+	mutex    sync.Mutex
+	policies map[string][]func(*http.Request) bool
+}
+
+// newRoutePolicies returns the policies of the routes of the given cosys app.
+func newRoutePolicies(cosys *common.Cosys) *routePolicies {
+	return &routePolicies{
+		cosys:    cosys,
+		policies: map[string][]func(*http.Request) bool{},
+	}
+}
+
+// allow returns whether the policies of the route of the given request allow it.
+// Requests without a route, or whose policies cannot be built, are not allowed.
+// Safe for concurrent use.
+func (p *routePolicies) allow(r *http.Request) bool {
+	route, ok := common.RouteFromContext(r.Context())
+	if !ok {
+		return false
+	}
+
+	policies, err := p.get(route)
+	if err != nil {
+		return false
+	}
+
+	for _, policy := range policies {
+		if !policy(r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// get returns the policies of the given route, building them on first use.
+func (p *routePolicies) get(route common.Route) ([]func(*http.Request) bool, error) {
+	key := route.Method + " " + route.Path
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if policies, ok := p.policies[key]; ok {
+		return policies, nil
+	}
+
+	policyFuncs := p.cosys.RoutePolicies(route)
+	policies := make([]func(*http.Request) bool, 0, len(policyFuncs))
+	for _, policyFunc := range policyFuncs {
+		policy, err := policyFunc(p.cosys)
+		if err != nil {
+			return nil, err
+		}
+
+		policies = append(policies, policy)
+	}
+
+	p.policies[key] = policies
+	return policies, nil
+}
+
+// responseKey returns the cache key of the response to the given request,
+// from its method, path and query, and the current generations of the given models.
+func responseKey(cache common.Cache, r *http.Request, modelUids []string) (string, error) {
+	generations := make([]string, 0, len(modelUids))
+	for _, modelUid := range modelUids {
+		generation, err := Generation(cache, modelUid)
+		if err != nil {
+			return "", err
+		}
+
+		generations = append(generations, modelUid+"="+generation)
+	}
+
+	// Encode sorts the query by key, so that the order of query parameters does not matter.
+	return responsePrefix + strings.Join(generations, ",") + ":" + r.URL.Path + "?" + r.URL.Query().Encode(), nil
+}
+
+// writeResponse writes a cached response, or 304 Not Modified if the request has a matching If-None-Match header.
+// The response varies by the credential headers, as requests with credentials are not served from the cache.
+func writeResponse(w http.ResponseWriter, r *http.Request, cached cachedResponse, status string) {
+	header := w.Header()
+	for name, values := range cached.Header {
+		header[name] = values
+	}
+	header.Set("ETag", cached.ETag)
+	header.Set(CacheStatusHeader, status)
+	for _, name := range credentialHeaders {
+		header.Add("Vary", name)
+	}
+
+	if matchesETag(r.Header.Get("If-None-Match"), cached.ETag) {
+		header.Del("Content-Type")
+		header.Del("Content-Length")
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if r.Method != http.MethodHead {
+		_, _ = w.Write(cached.Body)
+	}
+}
+
+// writeBuffer writes a buffered response as is.
+func writeBuffer(w http.ResponseWriter, buffer *response.Buffer) {
+	for name, values := range buffer.Header() {
+		w.Header()[name] = values
+	}
+
+	status := buffer.Status()
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	w.WriteHeader(status)
+	_, _ = w.Write(buffer.Body())
+}
+
+// eTag returns the strong entity tag of the given response body.
+func eTag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// matchesETag returns whether the value of an If-None-Match header matches the given entity tag,
+// using the weak comparison.
+func matchesETag(ifNoneMatch string, eTag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == eTag {
+			return true
+		}
+	}
+
+	return false
+}
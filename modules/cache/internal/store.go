@@ -0,0 +1,70 @@
+package internal
+
+import (
+	"github.com/cosys-io/cosys/common"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Store is an in-memory Cache holding the generations of models in a map that is never evicted,
+// and all other keys, i.e. cached responses, in an lru cache.
+// Generations must outlive the responses cached with them, and there is only one per model.
+type Store struct {
+	responses *common.LRUCache
+
+	mutex       sync.RWMutex
+	generations map[string][]byte
+}
+
+// NewStore returns a new empty Store holding up to capacity cached responses.
+// The store holds any number of cached responses if the capacity is not positive.
+func NewStore(capacity int) *Store {
+	return &Store{
+		responses:   common.NewLRUCache(capacity),
+		generations: map[string][]byte{},
+	}
+}
+
+// Get returns the value of the key, and whether the key was found and has not expired.
+// Safe for concurrent use.
+func (s *Store) Get(key string) ([]byte, bool, error) {
+	if !strings.HasPrefix(key, generationPrefix) {
+		return s.responses.Get(key)
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	value, ok := s.generations[key]
+	return value, ok, nil
+}
+
+// Set sets the value of the key, expiring after the given ttl, or never if the ttl is not positive.
+// Generations never expire, and the ttl is ignored for them.
+// Safe for concurrent use.
+func (s *Store) Set(key string, value []byte, ttl time.Duration) error {
+	if !strings.HasPrefix(key, generationPrefix) {
+		return s.responses.Set(key, value, ttl)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.generations[key] = value
+	return nil
+}
+
+// Delete removes the key, if found.
+// Safe for concurrent use.
+func (s *Store) Delete(key string) error {
+	if !strings.HasPrefix(key, generationPrefix) {
+		return s.responses.Delete(key)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.generations, key)
+	return nil
+}
@@ -0,0 +1,125 @@
+package cache
+
+import (
+	"github.com/cosys-io/cosys/common"
+	"github.com/cosys-io/cosys/modules/cache/internal"
+	"sync"
+	"time"
+)
+
+// ModuleName is the name the module is registered with, which other modules can depend on.
+const ModuleName = "cache"
+
+// CacheUid is the uid of the caching middleware configured with the module configuration.
+const CacheUid = "cache"
+
+// CacheStatusHeader is the header reporting whether a response was served from the cache, either HIT or MISS.
+const CacheStatusHeader = internal.CacheStatusHeader
+
+// Options are the options of a caching middleware.
+type Options struct {
+	// TTL is the duration responses are cached for, by default the ttl of the module configuration.
+	TTL time.Duration
+	// Models are the uids of the models the responses depend on.
+	// Responses are invalidated when any of the models changes, or when any model changes if none are given.
+	Models []string
+}
+
+// Config is the configuration of the cache module.
+// Configurations set in the project configurations override those set with Configure.
+type Config struct {
+	Capacity int           // Capacity is the maximum number of responses held by the default in-memory cache.
+	TTL      time.Duration // TTL is the duration responses are cached for.
+	// Cache is the cache core service registered by the module, by default an in-memory lru cache.
+	Cache common.Cache
+}
+
+// DefaultConfig returns the default configuration of the cache module,
+// which caches responses for a minute in an in-memory cache of up to 10000 responses.
+func DefaultConfig() Config {
+	return Config{
+		Capacity: 10000,
+		TTL:      time.Minute,
+	}
+}
+
+var (
+	configMutex sync.RWMutex
+	config      = DefaultConfig()
+
+	BootstrapHookKey string // BootstrapHookKey can be used to update or remove the bootstrap hook.
+)
+
+// Configure sets the configuration of the cache module.
+// Must be called before the cosys app is created.
+// Safe for concurrent use.
+func Configure(newConfig Config) {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	config = newConfig
+}
+
+// Middleware returns a caching middleware with the given options,
+// which can be applied to read routes with common.UseMiddlewares, or to route groups with cosys.Group.
+// Successful GET and HEAD responses to requests without credentials are cached by path and query,
+// are only served to requests allowed by the policies of their route, carry an ETag header, and are answered with 304 Not Modified if the If-None-Match header matches.
+func Middleware(options Options) common.MiddlewareFunc {
+	if options.TTL <= 0 {
+		configMutex.RLock()
+		options.TTL = config.TTL
+		configMutex.RUnlock()
+	}
+
+	return internal.Middleware(options.TTL, options.Models)
+}
+
+// Invalidate invalidates all cached responses depending on the model of the given uid.
+// Cached responses are invalidated automatically when entities are created, updated or deleted,
+// so this is only needed when the data of a model is changed outside of the database core service.
+func Invalidate(cosys *common.Cosys, modelUid string) error {
+	cache, err := cosys.Cache()
+	if err != nil {
+		return err
+	}
+
+	return internal.Invalidate(cache, modelUid)
+}
+
+// init registers the module to register the cache core service and the caching middleware,
+// and the bootstrap hook invalidating cached responses when models change.
+// It is registered after the cms module, if registered, whose models it invalidates cached responses of,
+// and the logger module, which invalidation failures are logged to.
+func init() {
+	_ = common.RegisterNamedModule(ModuleName, func(cosys *common.Cosys) error {
+		configMutex.RLock()
+		moduleConfig := config
+		configMutex.RUnlock()
+
+		moduleConfig, err := withProjectConfigs(moduleConfig)
+		if err != nil {
+			return err
+		}
+
+		cache := moduleConfig.Cache
+		if cache == nil {
+			cache = internal.NewStore(moduleConfig.Capacity)
+		}
+
+		if err = cosys.UseCache(cache); err != nil {
+			return err
+		}
+
+		middleware, err := common.NewMiddleware(CacheUid, internal.Middleware(moduleConfig.TTL, nil))
+		if err != nil {
+			return err
+		}
+
+		if err = cosys.AddMiddlewares(middleware); err != nil {
+			return err
+		}
+
+		BootstrapHookKey, err = cosys.AddBootstrapHook(internal.AddInvalidationHooks)
+		return err
+	}, common.OptionallyDependsOn("cms", "logger"))
+}
@@ -3,22 +3,55 @@ package admin
 import (
 	"github.com/cosys-io/cosys/common"
 	"github.com/cosys-io/cosys/modules/cms/routes"
+	"github.com/cosys-io/cosys/modules/cms/schema"
 )
 
-// AddAdminRoutes registers admin crud routes for the given models.
+// adminDoc returns the documentation of the admin route for the cms action with the given name.
+func adminDoc(action string, modelUid string, model common.Model) common.RouteDoc {
+	doc := routes.Doc(action, modelUid, model)
+	doc.Tags = []string{"admin"}
+
+	return doc
+}
+
+// AddAdminRoutes registers admin crud routes for the given models,
+// publish routes for models with draft and publish enabled,
+// and restore routes for models with soft delete enabled.
+// Admin find routes return draft entities.
 func AddAdminRoutes(cosys *common.Cosys, models map[string]common.Model) error {
-	adminRoutes := make([]common.Route, len(models)*5)
+	adminRoutes := make([]common.Route, 0, len(models)*8)
 
-	index := 0
 	for modelUid, model := range models {
 		modelApi := model.PluralKebabName_()
 
-		adminRoutes[index] = common.NewRoute("GET", `/admin/`+modelApi, routes.FindMany(modelUid))
-		adminRoutes[index+1] = common.NewRoute("GET", `/admin/`+modelApi+`/{id}`, routes.FindOne(modelUid))
-		adminRoutes[index+2] = common.NewRoute("POST", `/admin/`+modelApi, routes.Create(modelUid))
-		adminRoutes[index+3] = common.NewRoute("PUT", `/admin/`+modelApi+`/{id}`, routes.Update(modelUid))
-		adminRoutes[index+4] = common.NewRoute("DELETE", `/admin/`+modelApi+`/{id}`, routes.Delete(modelUid))
-		index += 5
+		adminRoutes = append(adminRoutes,
+			common.NewRoute("GET", `/admin/`+modelApi, routes.FindMany(modelUid, routes.WithDrafts),
+				common.Describe(adminDoc("findMany", modelUid, model))),
+			common.NewRoute("GET", `/admin/`+modelApi+`/{id}`, routes.FindOne(modelUid, routes.WithDrafts),
+				common.Describe(adminDoc("findOne", modelUid, model))),
+			common.NewRoute("POST", `/admin/`+modelApi, routes.Create(modelUid),
+				common.Describe(adminDoc("create", modelUid, model))),
+			common.NewRoute("PUT", `/admin/`+modelApi+`/{id}`, routes.Update(modelUid),
+				common.Describe(adminDoc("update", modelUid, model))),
+			common.NewRoute("DELETE", `/admin/`+modelApi+`/{id}`, routes.Delete(modelUid),
+				common.Describe(adminDoc("delete", modelUid, model))),
+		)
+
+		if modelSchema, ok := model.Schema_().(*schema.ModelSchema); ok && modelSchema.DraftAndPublish() {
+			adminRoutes = append(adminRoutes,
+				common.NewRoute("POST", `/admin/`+modelApi+`/{id}/publish`, routes.Publish(modelUid),
+					common.Describe(adminDoc("publish", modelUid, model))),
+				common.NewRoute("POST", `/admin/`+modelApi+`/{id}/unpublish`, routes.Unpublish(modelUid),
+					common.Describe(adminDoc("unpublish", modelUid, model))),
+			)
+		}
+
+		if _, ok := common.SoftDeleteAttribute(model); ok {
+			adminRoutes = append(adminRoutes,
+				common.NewRoute("POST", `/admin/`+modelApi+`/{id}/restore`, routes.Restore(modelUid),
+					common.Describe(adminDoc("restore", modelUid, model))),
+			)
+		}
 	}
 
 	return cosys.AddRoutes(adminRoutes...)
@@ -0,0 +1,202 @@
+package admin
+
+import (
+	"encoding/json"
+	"github.com/cosys-io/cosys/common"
+	"github.com/cosys-io/cosys/modules/cms/history"
+	"github.com/cosys-io/cosys/modules/server/response"
+	"net/http"
+	"strconv"
+)
+
+// AddHistoryRoutes enables versioning for the given versioned models,
+// and registers routes for listing, diffing and restoring their versions.
+func AddHistoryRoutes(cosys *common.Cosys, models map[string]common.Model) error {
+	if err := history.Enable(cosys, models); err != nil {
+		return err
+	}
+
+	var historyRoutes []common.Route
+	for modelUid, model := range models {
+		if !history.IsVersioned(model) {
+			continue
+		}
+
+		modelApi := model.PluralKebabName_()
+
+		historyRoutes = append(historyRoutes,
+			common.NewRoute("GET", `/admin/`+modelApi+`/{id}/versions`, listVersions(modelUid),
+				common.Describe(common.RouteDoc{
+					Summary:  "Find the versions of a " + model.SingularHumanName_(),
+					Tags:     []string{"admin"},
+					Response: &common.TypeDoc{Value: history.Version{}, Many: true},
+				})),
+			common.NewRoute("GET", `/admin/`+modelApi+`/{id}/versions/diff`, diffVersions(modelUid),
+				common.Describe(common.RouteDoc{
+					Summary: "Diff two versions of a " + model.SingularHumanName_(),
+					Tags:    []string{"admin"},
+					Query: []common.ParamDoc{
+						{Name: "from", Description: "The version to diff from.", Type: "integer", Required: true},
+						{Name: "to", Description: "The version to diff to, by default the current entry.", Type: "integer"},
+					},
+				})),
+			common.NewRoute("POST", `/admin/`+modelApi+`/{id}/versions/{version}/restore`, restoreVersion(modelUid),
+				common.Describe(common.RouteDoc{
+					Summary:  "Restore a version of a " + model.SingularHumanName_(),
+					Tags:     []string{"admin"},
+					Response: common.ModelType(modelUid),
+				})),
+		)
+	}
+
+	return cosys.AddRoutes(historyRoutes...)
+}
+
+// versionResponse is the response for a version, with the snapshot as a json object.
+type versionResponse struct {
+	Version   int             `json:"version"`
+	Event     string          `json:"event"`
+	Snapshot  json.RawMessage `json:"snapshot"`
+	CreatedAt any             `json:"createdAt"`
+}
+
+// listVersions returns the ActionFunc for listing the versions of an entity of the model with the given uid.
+func listVersions(modelUid string) common.ActionFunc {
+	return func(cosys *common.Cosys) (http.HandlerFunc, error) {
+		return func(w http.ResponseWriter, r *http.Request) {
+			id, err := strconv.Atoi(r.PathValue("id"))
+			if err != nil {
+				response.RespondError(w, "Could not find versions", http.StatusBadRequest)
+				return
+			}
+
+			versions, err := history.Versions(cosys, modelUid, id)
+			if err != nil {
+				response.RespondError(w, "Could not find versions", http.StatusBadRequest)
+				return
+			}
+
+			versionResponses := make([]versionResponse, len(versions))
+			for index, version := range versions {
+				versionResponses[index] = versionResponse{
+					Version:   version.Version,
+					Event:     version.Event,
+					Snapshot:  json.RawMessage(version.Snapshot),
+					CreatedAt: version.CreatedAt,
+				}
+			}
+
+			response.RespondMany(w, versionResponses, 1, http.StatusOK)
+		}, nil
+	}
+}
+
+// diffVersions returns the ActionFunc for diffing two versions of an entity of the model with the given uid.
+// The versions are specified by the from and to query parameters,
+// and the current entity is used if the to query parameter is omitted.
+func diffVersions(modelUid string) common.ActionFunc {
+	return func(cosys *common.Cosys) (http.HandlerFunc, error) {
+		model, err := cosys.Model(modelUid)
+		if err != nil {
+			return nil, err
+		}
+
+		database, err := cosys.Database()
+		if err != nil {
+			return nil, err
+		}
+
+		return func(w http.ResponseWriter, r *http.Request) {
+			id, err := strconv.Atoi(r.PathValue("id"))
+			if err != nil {
+				response.RespondError(w, "Could not diff versions", http.StatusBadRequest)
+				return
+			}
+
+			fromVersion, err := strconv.Atoi(r.URL.Query().Get("from"))
+			if err != nil {
+				response.RespondError(w, "Could not diff versions", http.StatusBadRequest)
+				return
+			}
+
+			from, err := history.GetVersion(cosys, modelUid, id, fromVersion)
+			if err != nil {
+				response.RespondError(w, "Could not find version", http.StatusNotFound)
+				return
+			}
+
+			var toSnapshot []byte
+			if toString := r.URL.Query().Get("to"); toString != "" {
+				toVersion, err := strconv.Atoi(toString)
+				if err != nil {
+					response.RespondError(w, "Could not diff versions", http.StatusBadRequest)
+					return
+				}
+
+				to, err := history.GetVersion(cosys, modelUid, id, toVersion)
+				if err != nil {
+					response.RespondError(w, "Could not find version", http.StatusNotFound)
+					return
+				}
+
+				toSnapshot = []byte(to.Snapshot)
+			} else {
+				dbParams := common.NewDBParamsBuilder().
+					Where(model.IdAttribute_().(common.IntAttribute).Eq(id)).
+					Build()
+
+				entity, err := database.FindOne(modelUid, dbParams)
+				if err != nil {
+					response.RespondError(w, "Could not find "+model.SingularHumanName_(), http.StatusNotFound)
+					return
+				}
+
+				toSnapshot, err = json.Marshal(entity)
+				if err != nil {
+					response.RespondInternalError(w)
+					return
+				}
+			}
+
+			diffs, err := history.Diff([]byte(from.Snapshot), toSnapshot)
+			if err != nil {
+				response.RespondInternalError(w)
+				return
+			}
+
+			response.RespondMany(w, diffs, 1, http.StatusOK)
+		}, nil
+	}
+}
+
+// restoreVersion returns the ActionFunc for restoring a version of an entity of the model with the given uid.
+func restoreVersion(modelUid string) common.ActionFunc {
+	return func(cosys *common.Cosys) (http.HandlerFunc, error) {
+		model, err := cosys.Model(modelUid)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(w http.ResponseWriter, r *http.Request) {
+			id, err := strconv.Atoi(r.PathValue("id"))
+			if err != nil {
+				response.RespondError(w, "Could not restore "+model.SingularHumanName_(), http.StatusBadRequest)
+				return
+			}
+
+			version, err := strconv.Atoi(r.PathValue("version"))
+			if err != nil {
+				response.RespondError(w, "Could not restore "+model.SingularHumanName_(), http.StatusBadRequest)
+				return
+			}
+
+			entity, err := history.Restore(cosys, modelUid, id, version)
+			if err != nil {
+				response.RespondError(w, "Could not restore "+model.SingularHumanName_(), http.StatusBadRequest)
+				return
+			}
+
+			response.RespondOne(w, entity, http.StatusOK)
+		}, nil
+	}
+}
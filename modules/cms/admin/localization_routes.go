@@ -0,0 +1,97 @@
+package admin
+
+import (
+	"github.com/cosys-io/cosys/common"
+	"github.com/cosys-io/cosys/modules/cms/i18n"
+	"github.com/cosys-io/cosys/modules/server/response"
+	"net/http"
+	"strconv"
+)
+
+// AddLocalizationRoutes enables localization for the given localized models,
+// and registers routes for listing and creating translations of their entries.
+func AddLocalizationRoutes(cosys *common.Cosys, models map[string]common.Model) error {
+	if err := i18n.Enable(cosys, models); err != nil {
+		return err
+	}
+
+	var localizationRoutes []common.Route
+	for modelUid, model := range models {
+		if !i18n.IsLocalized(model) {
+			continue
+		}
+
+		modelApi := model.PluralKebabName_()
+
+		localizationRoutes = append(localizationRoutes,
+			common.NewRoute("GET", `/admin/`+modelApi+`/{id}/localizations`, listTranslations(modelUid),
+				common.Describe(common.RouteDoc{
+					Summary:  "Find the translations of a " + model.SingularHumanName_(),
+					Tags:     []string{"admin"},
+					Response: common.ModelListType(modelUid),
+				})),
+			common.NewRoute("POST", `/admin/`+modelApi+`/{id}/localizations`, createTranslation(modelUid),
+				common.Describe(common.RouteDoc{
+					Summary:  "Create a translation of a " + model.SingularHumanName_(),
+					Tags:     []string{"admin"},
+					Request:  common.ModelType(modelUid),
+					Response: common.ModelType(modelUid),
+				})),
+		)
+	}
+
+	return cosys.AddRoutes(localizationRoutes...)
+}
+
+// listTranslations returns the ActionFunc for listing the translations of an entry of the model with the given uid.
+func listTranslations(modelUid string) common.ActionFunc {
+	return func(cosys *common.Cosys) (http.HandlerFunc, error) {
+		model, err := cosys.Model(modelUid)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(w http.ResponseWriter, r *http.Request) {
+			id, err := strconv.Atoi(r.PathValue("id"))
+			if err != nil {
+				response.RespondError(w, "Could not find translations", http.StatusBadRequest)
+				return
+			}
+
+			translations, err := i18n.Translations(cosys, modelUid, id)
+			if err != nil {
+				response.RespondError(w, "Could not find "+model.SingularHumanName_(), http.StatusNotFound)
+				return
+			}
+
+			response.RespondMany(w, translations, 1, http.StatusOK)
+		}, nil
+	}
+}
+
+// createTranslation returns the ActionFunc for creating a translation of an entry of the model with the given uid,
+// in the locale specified by the locale query parameter.
+func createTranslation(modelUid string) common.ActionFunc {
+	return func(cosys *common.Cosys) (http.HandlerFunc, error) {
+		model, err := cosys.Model(modelUid)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(w http.ResponseWriter, r *http.Request) {
+			id, err := strconv.Atoi(r.PathValue("id"))
+			if err != nil {
+				response.RespondError(w, "Could not create translation", http.StatusBadRequest)
+				return
+			}
+
+			translation, err := i18n.CreateTranslation(cosys, modelUid, id, r.URL.Query().Get("locale"))
+			if err != nil {
+				response.RespondError(w, "Could not create translation of "+model.SingularHumanName_(), http.StatusBadRequest)
+				return
+			}
+
+			response.RespondOne(w, translation, http.StatusOK)
+		}, nil
+	}
+}
@@ -16,8 +16,12 @@ func AddSchemaRoutes(cosys *common.Cosys, models map[string]common.Model) error
 	}
 
 	schemaRoutes := []common.Route{
-		common.NewRoute("GET", `/admin/schema`, getAction),
-		common.NewRoute("POST", `/admin/schema`, createSchema),
+		common.NewRoute("GET", `/admin/schema`, getAction,
+			common.Describe(common.RouteDoc{Summary: "Find model schemas", Tags: []string{"admin"}})),
+		common.NewRoute("POST", `/admin/schema`, createSchema,
+			common.Describe(common.RouteDoc{Summary: "Create a model schema", Tags: []string{"admin"}})),
+		common.NewRoute("GET", `/admin/schema/components`, getComponents,
+			common.Describe(common.RouteDoc{Summary: "Find component schemas", Tags: []string{"admin"}})),
 	}
 
 	return cosys.AddRoutes(schemaRoutes...)
@@ -54,6 +58,21 @@ var createSchema common.ActionFunc = func(cosys *common.Cosys) (http.HandlerFunc
 			return
 		}
 
+		if err := schema.CheckComponents(&newSchema); err != nil {
+			response.RespondError(w, "Could not create content type.", http.StatusBadRequest)
+			return
+		}
+
+		if newSchema.ModelType() == "component" {
+			if err := generators.GenerateComponent(&newSchema); err != nil {
+				response.RespondError(w, "Could not create component.", http.StatusBadRequest)
+				return
+			}
+
+			response.RespondOne(w, nil, http.StatusOK)
+			return
+		}
+
 		if err := generators.GenerateType(&newSchema); err != nil {
 			response.RespondError(w, "Could not create content type.", http.StatusBadRequest)
 			return
@@ -62,3 +81,22 @@ var createSchema common.ActionFunc = func(cosys *common.Cosys) (http.HandlerFunc
 		response.RespondOne(w, nil, http.StatusOK)
 	}, nil
 }
+
+// getComponents is the ActionFunc for getting component schemas.
+var getComponents common.ActionFunc = func(cosys *common.Cosys) (http.HandlerFunc, error) {
+	components := schema.AllComponents()
+
+	schemas := make([]schema.ModelSerializable, 0, len(components))
+	for _, component := range components {
+		componentSchema, err := schema.ToModelSerializable(component)
+		if err != nil {
+			return nil, err
+		}
+
+		schemas = append(schemas, componentSchema)
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		response.RespondMany(w, schemas, 1, http.StatusOK)
+	}, nil
+}
@@ -0,0 +1,75 @@
+package generators
+
+import (
+	"github.com/cosys-io/cosys/common"
+	gen "github.com/cosys-io/cosys/cosys_cli/generator"
+	"github.com/cosys-io/cosys/modules/cms/schema"
+	"github.com/iancoleman/strcase"
+	"path/filepath"
+)
+
+// GenerateComponent generates the files for a new component.
+func GenerateComponent(componentSchema *schema.ModelSchema) error {
+	common.InitConfigs()
+
+	componentsDir, err := common.GetPathConfig("cms_components_path", true)
+	if err != nil {
+		return err
+	}
+
+	ctx := &componentCtx{
+		ModelSchema: componentSchema,
+		VarName:     strcase.ToCamel(componentSchema.Category()) + strcase.ToCamel(componentSchema.SingularName()),
+	}
+
+	fileName := strcase.ToSnake(componentSchema.Category()) + "_" + strcase.ToSnake(componentSchema.SingularName())
+
+	generator := gen.NewGenerator(
+		gen.NewFile(filepath.Join(componentsDir, fileName+".yaml"), componentYamlTmpl, ctx),
+		gen.NewFile(filepath.Join(componentsDir, fileName+".go"), componentGoTmpl, ctx),
+		gen.ModifyFile(filepath.Join(componentsDir, "components.go"), `var Components = \[\]\*schema\.ModelSchema\{`, componentsSliceTmpl, ctx),
+	)
+	if err = generator.Generate(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// componentCtx contains the data for generating code for a new component.
+type componentCtx struct {
+	*schema.ModelSchema
+	VarName string
+}
+
+// componentGoTmpl is the template for creating the schema struct of a new component.
+var componentGoTmpl = `package components
+
+import (
+	"github.com/cosys-io/cosys/modules/cms/schema"
+)
+
+var {{.VarName}}Component = schema.NewComponentSchema(
+	"{{.CollectionName}}",
+	"{{.DisplayName}}",
+	"{{.Category}}",
+	"{{.SingularName}}",
+	"{{.Description}}",
+` + attrsGoTmpl + `)
+`
+
+// componentYamlTmpl is the template for creating the yaml configuration of a new component.
+var componentYamlTmpl = `modelType: {{.ModelType}}
+category: {{.Category}}
+collectionName: {{.CollectionName}}
+displayName: {{.DisplayName}}
+singularName: {{.SingularName}}
+pluralName: {{.PluralName}}
+description: {{.Description}}
+attributes:
+` + attrsYamlTmpl
+
+// componentsSliceTmpl is the template for adding a new component
+// to the components slice in the components.go file.
+var componentsSliceTmpl = `var Components = []*schema.ModelSchema{
+	{{.VarName}}Component,`
@@ -57,6 +57,9 @@ func getCtx(modelSchema *schema.ModelSchema, typesDir string) (*modelCtx, error)
 
 	for index, attrSchema := range modelSchema.Attributes() {
 		ctx.Attributes[index] = getAttrCtx(attrSchema.(*schema.AttributeSchema))
+		if ctx.Attributes[index].TypeUpper == "Time" {
+			ctx.ImportTime = true
+		}
 	}
 
 	return ctx, nil
@@ -99,6 +102,7 @@ func getModelCtx(schema *schema.ModelSchema, modFile string, typesDir string) *m
 		PluralKebabName:    strcase.ToKebab(schema.PluralName()),
 		SingularHumanName:  strcase.ToDelimited(schema.SingularName(), ' '),
 		PluralHumanName:    strcase.ToDelimited(schema.PluralName(), ' '),
+		DraftAndPublish:    schema.DraftAndPublish(),
 
 		ModFile:    modFile,
 		TypesDir:   typesDir,
@@ -123,6 +127,19 @@ func getAttrCtx(schema *schema.AttributeSchema) *attrCtx {
 	case "Boolean":
 		ctx.TypeLower = "bool"
 		ctx.TypeUpper = "Bool"
+	case "Date", "DateTime", "Timestamp":
+		ctx.TypeLower = "*time.Time"
+		ctx.TypeUpper = "Time"
+	case "Component":
+		if schema.Repeatable() {
+			ctx.TypeLower = "[]common.Component"
+		} else {
+			ctx.TypeLower = "common.Component"
+		}
+		ctx.TypeUpper = "Component"
+	case "DynamicZone":
+		ctx.TypeLower = "[]common.Component"
+		ctx.TypeUpper = "DynamicZone"
 	}
 
 	return ctx
@@ -174,9 +191,11 @@ type modelCtx struct {
 	PluralKebabName    string
 	SingularHumanName  string
 	PluralHumanName    string
+	DraftAndPublish    bool
 
 	ModFile    string
 	TypesDir   string
+	ImportTime bool
 	Attributes []*attrCtx
 }
 
@@ -192,7 +211,8 @@ type attrCtx struct {
 var modelTmpl = `package {{.PluralCamelName}}
 	
 import (
-	"github.com/cosys-io/cosys/common"
+	"github.com/cosys-io/cosys/common"{{if .ImportTime}}
+	"time"{{end}}
 )
 
 type {{.SingularPascalName}} struct {
@@ -226,7 +246,16 @@ var {{.SingularName}}Schema = schema.NewModelSchema(
 	"{{.SingularName}}",
 	"{{.PluralName}}",
 	"{{.Description}}",
-{{range .Attributes}}        schema.NewAttrSchema(
+` + attrsGoTmpl + `){{if or .DraftAndPublish .Versioned .Localized .SoftDelete}}.With({{if .DraftAndPublish}}
+	schema.DraftAndPublish,{{end}}{{if .Versioned}}
+	schema.Versioned,{{end}}{{if .Localized}}
+	schema.Localized,{{end}}{{if .SoftDelete}}
+	schema.SoftDelete,{{end}}
+){{end}}
+`
+
+// attrsGoTmpl is the template for creating the attribute schemas in a schema struct.
+var attrsGoTmpl = `{{range .Attributes}}        schema.NewAttrSchema(
 			"{{.Name}}",
 			"{{.SimplifiedDataType}}",
 			"{{.DetailedDataType}}",{{if not .ShownInTable}}
@@ -243,10 +272,13 @@ var {{.SingularName}}Schema = schema.NewModelSchema(
 			},{{end}}{{if .Default}}
 			schema.Default("{{.Default}}"),{{end}}{{if not .Nullable}}
 			schema.NotNullable,{{end}}{{if .Unique}}
-			schema.Unique,{{end}}
+			schema.Unique,{{end}}{{if .Component}}
+			schema.ComponentOf("{{.Component}}"),{{end}}{{if .Repeatable}}
+			schema.Repeatable,{{end}}{{if .Components}}
+			schema.Components({{range .Components}}"{{.}}", {{end}}),{{end}}{{if not .Localized}}
+			schema.NotLocalized,{{end}}
 		),
-{{end}})
-`
+{{end}}`
 
 // schemaYamlTmpl is the template for creating the yaml configuration of a new collection type.
 var schemaYamlTmpl = `modelType: {{.ModelType}}
@@ -254,9 +286,16 @@ collectionName: {{.CollectionName}}
 displayName: {{.DisplayName}}
 singularName: {{.SingularName}}
 pluralName: {{.PluralName}}
-description: {{.Description}}
+description: {{.Description}}{{if .DraftAndPublish}}
+draftAndPublish: true{{end}}{{if .Versioned}}
+versioned: true{{end}}{{if .Localized}}
+localized: true{{end}}{{if .SoftDelete}}
+softDelete: true{{end}}
 attributes:
-{{range .Attributes}}  - name: {{.Name}}
+` + attrsYamlTmpl
+
+// attrsYamlTmpl is the template for creating the attribute schemas in a yaml configuration.
+var attrsYamlTmpl = `{{range .Attributes}}  - name: {{.Name}}
     simplifiedDataType: {{.SimplifiedDataType}}
     detailedDataType: {{.DetailedDataType}}{{if not .ShownInTable}}
     shownInTable: false{{end}}{{if .Required}}
@@ -271,7 +310,12 @@ attributes:
 		- {{.}}{{end}}{{end}}{{if .Default}}
     default: {{.Default}}{{end}}{{if not .Nullable}}
     nullable: false{{end}}{{if .Unique}}
-    unique: true{{end}}
+    unique: true{{end}}{{if .Component}}
+    component: {{.Component}}{{end}}{{if .Repeatable}}
+    repeatable: true{{end}}{{if .Components}}
+    components:{{range .Components}}
+      - {{.}}{{end}}{{end}}{{if not .Localized}}
+    localized: false{{end}}
 {{end}}`
 
 // modelsImportTmpl is the template for adding the import for the model of a new collection type
@@ -303,14 +347,21 @@ var {{.PluralCamelName}}Controller, _ = common.NewController("{{.PluralCamelName
 	"findOne": routes.FindOne("api.{{.PluralCamelName}}"),
 	"create": routes.Create("api.{{.PluralCamelName}}"),
 	"update": routes.Update("api.{{.PluralCamelName}}"),
-	"delete": routes.Delete("api.{{.PluralCamelName}}"),
+	"delete": routes.Delete("api.{{.PluralCamelName}}"),{{if .DraftAndPublish}}
+	"publish": routes.Publish("api.{{.PluralCamelName}}"),
+	"unpublish": routes.Unpublish("api.{{.PluralCamelName}}"),{{end}}
 })`
 
 // routesTmpl is the template for adding the routes for a new collection type
 // to the routes slice in the routes.go file.
 var routesTmpl = `var Routes = []common.Route{
-	common.NewRoute("GET", ` + "`/api/{{.PluralKebabName}}`" + `, common.GetAction("{{.PluralCamelName}}.findMany")),
-	common.NewRoute("GET", ` + "`/api/{{.PluralKebabName}}/{id}`" + `, common.GetAction("{{.PluralCamelName}}.findOne")),
-	common.NewRoute("POST", ` + "`/api/{{.PluralKebabName}}`" + `, common.GetAction("{{.PluralCamelName}}.create")),
-	common.NewRoute("PUT", ` + "`/api/{{.PluralKebabName}}/{id}`" + `, common.GetAction("{{.PluralCamelName}}.update")),
-	common.NewRoute("DELETE", ` + "`/api/{{.PluralKebabName}}/{id}`" + `, common.GetAction("{{.PluralCamelName}}.delete")),`
+	common.NewRoute("GET", ` + "`/api/{{.PluralKebabName}}`" + `, common.GetAction("{{.PluralCamelName}}.findMany"),
+		common.Describe(common.RouteDoc{Summary: "Find {{.PluralHumanName}}", Tags: []string{"{{.PluralHumanName}}"}, Response: common.ModelListType("api.{{.PluralCamelName}}")})),
+	common.NewRoute("GET", ` + "`/api/{{.PluralKebabName}}/{id}`" + `, common.GetAction("{{.PluralCamelName}}.findOne"),
+		common.Describe(common.RouteDoc{Summary: "Find a {{.SingularHumanName}}", Tags: []string{"{{.PluralHumanName}}"}, Response: common.ModelType("api.{{.PluralCamelName}}")})),
+	common.NewRoute("POST", ` + "`/api/{{.PluralKebabName}}`" + `, common.GetAction("{{.PluralCamelName}}.create"),
+		common.Describe(common.RouteDoc{Summary: "Create a {{.SingularHumanName}}", Tags: []string{"{{.PluralHumanName}}"}, Request: common.ModelType("api.{{.PluralCamelName}}"), Response: common.ModelType("api.{{.PluralCamelName}}")})),
+	common.NewRoute("PUT", ` + "`/api/{{.PluralKebabName}}/{id}`" + `, common.GetAction("{{.PluralCamelName}}.update"),
+		common.Describe(common.RouteDoc{Summary: "Update a {{.SingularHumanName}}", Tags: []string{"{{.PluralHumanName}}"}, Request: common.ModelType("api.{{.PluralCamelName}}"), Response: common.ModelType("api.{{.PluralCamelName}}")})),
+	common.NewRoute("DELETE", ` + "`/api/{{.PluralKebabName}}/{id}`" + `, common.GetAction("{{.PluralCamelName}}.delete"),
+		common.Describe(common.RouteDoc{Summary: "Delete a {{.SingularHumanName}}", Tags: []string{"{{.PluralHumanName}}"}, Response: common.ModelType("api.{{.PluralCamelName}}")})),`
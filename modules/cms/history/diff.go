@@ -0,0 +1,102 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/cosys-io/cosys/common"
+	"github.com/cosys-io/cosys/modules/cms/schema"
+	"reflect"
+	"slices"
+)
+
+// FieldDiff is the difference in the value of an attribute between two versions.
+type FieldDiff struct {
+	Attribute string `json:"attribute"`
+	From      any    `json:"from"`
+	To        any    `json:"to"`
+}
+
+// Diff returns the differences between two json snapshots, sorted by attribute name.
+func Diff(from []byte, to []byte) ([]FieldDiff, error) {
+	var fromFields map[string]any
+	if err := json.Unmarshal(from, &fromFields); err != nil {
+		return nil, err
+	}
+
+	var toFields map[string]any
+	if err := json.Unmarshal(to, &toFields); err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for name := range fromFields {
+		names = append(names, name)
+	}
+	for name := range toFields {
+		if _, ok := fromFields[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	diffs := []FieldDiff{}
+	for _, name := range names {
+		if reflect.DeepEqual(fromFields[name], toFields[name]) {
+			continue
+		}
+
+		diffs = append(diffs, FieldDiff{
+			Attribute: name,
+			From:      fromFields[name],
+			To:        toFields[name],
+		})
+	}
+
+	return diffs, nil
+}
+
+// Restore updates an entity with the snapshot of the given version, and returns the entity after updating.
+// The restore is recorded as a new version, so it can be reverted.
+// The publishedAt attribute of draft and publish models and the deletedAt attribute of soft delete models are not restored.
+func Restore(cosys *common.Cosys, modelUid string, entityId int, version int) (common.Entity, error) {
+	model, err := cosys.Model(modelUid)
+	if err != nil {
+		return nil, err
+	}
+
+	database, err := cosys.Database()
+	if err != nil {
+		return nil, err
+	}
+
+	found, err := GetVersion(cosys, modelUid, entityId, version)
+	if err != nil {
+		return nil, err
+	}
+
+	entity := model.New_()
+	if err = json.Unmarshal([]byte(found.Snapshot), entity); err != nil {
+		return nil, fmt.Errorf("invalid snapshot: %w", err)
+	}
+
+	modelSchema, _ := model.Schema_().(*schema.ModelSchema)
+
+	columns := make([]common.Attribute, 0, len(model.Attributes_()))
+	for _, attr := range model.Attributes_()[1:] {
+		if modelSchema != nil && modelSchema.DraftAndPublish() && attr.CamelName() == schema.PublishedAtSchema.Name() {
+			continue
+		}
+		if attr.CamelName() == common.DeletedAtName {
+			continue
+		}
+
+		columns = append(columns, attr)
+	}
+
+	params := common.NewDBParamsBuilder().
+		Update(columns...).
+		Where(model.IdAttribute_().(common.IntAttribute).Eq(entityId)).
+		Build()
+
+	return database.Update(modelUid, entity, params)
+}
@@ -0,0 +1,305 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/cosys-io/cosys/common"
+	"github.com/cosys-io/cosys/modules/cms/schema"
+	"reflect"
+	"time"
+)
+
+// ModelUid is the uid of the history model.
+const ModelUid = "cms.histories"
+
+// Version is a snapshot of an entity before it was updated or deleted.
+type Version struct {
+	Id       int    `json:"id"`
+	ModelUid string `json:"modelUid"`
+	EntityId int    `json:"entityId"`
+	Version  int    `json:"version"`
+	// VersionKey is the unique key of the version of the entity, which prevents concurrent updates
+	// from recording the same version.
+	VersionKey string     `json:"versionKey"`
+	Event      string     `json:"event"`
+	Snapshot   string     `json:"snapshot"`
+	CreatedAt  *time.Time `json:"createdAt"`
+}
+
+// HistoriesModel is the model for entity snapshots.
+type HistoriesModel struct {
+	*common.ModelBase
+
+	Id         common.IntAttribute
+	ModelUid   common.StringAttribute
+	EntityId   common.IntAttribute
+	Version    common.IntAttribute
+	VersionKey common.StringAttribute
+	Event      common.StringAttribute
+	Snapshot   common.StringAttribute
+	CreatedAt  common.TimeAttribute
+}
+
+// historySchema is the schema for the history model.
+var historySchema = schema.NewModelSchema(
+	"cms_histories",
+	"Histories",
+	"cmsHistory",
+	"cmsHistories",
+	"Snapshots of versioned entities.",
+	&schema.IdSchema,
+	schema.NewAttrSchema("modelUid", "String", "String", schema.NotNullable, schema.NotEditable),
+	schema.NewAttrSchema("entityId", "Number", "Int", schema.NotNullable, schema.NotEditable),
+	schema.NewAttrSchema("version", "Number", "Int", schema.NotNullable, schema.NotEditable),
+	schema.NewAttrSchema("versionKey", "String", "String", schema.NotNullable, schema.NotEditable, schema.Unique),
+	schema.NewAttrSchema("event", "String", "String", schema.NotNullable, schema.NotEditable),
+	schema.NewAttrSchema("snapshot", "String", "String", schema.NotNullable, schema.NotEditable),
+	schema.NewAttrSchema("createdAt", "DateTime", "DateTime", schema.NotEditable),
+)
+
+// Histories is the history model.
+var Histories, _ = common.NewModel[Version, HistoriesModel](
+	"cms_histories",
+	"cmsHistory",
+	"cmsHistories",
+	historySchema,
+)
+
+// Enable registers the history model if it has not been registered,
+// and adds the lifecycle hooks recording snapshots to the given versioned models.
+func Enable(cosys *common.Cosys, models map[string]common.Model) error {
+	if _, err := cosys.Model(ModelUid); err != nil {
+		if err = cosys.AddModel(ModelUid, Histories); err != nil {
+			return err
+		}
+	}
+
+	for modelUid, model := range models {
+		if !IsVersioned(model) {
+			continue
+		}
+
+		if _, err := model.AddLifecycleHook_("beforeUpdate", captureHook(cosys, modelUid)); err != nil {
+			return err
+		}
+
+		if _, err := model.AddLifecycleHook_("afterUpdate", recordCapturedHook(cosys, modelUid)); err != nil {
+			return err
+		}
+
+		if _, err := model.AddLifecycleHook_("beforeDelete", recordHook(cosys, modelUid)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// IsVersioned returns whether the given model has versioning enabled.
+func IsVersioned(model common.Model) bool {
+	modelSchema, ok := model.Schema_().(*schema.ModelSchema)
+	return ok && modelSchema.Versioned()
+}
+
+// stateKey is the key under which the snapshot is kept in the lifecycle event state.
+const stateKey = "history"
+
+// captureHook returns the lifecycle hook that keeps the snapshot of the entity about to be updated
+// in the lifecycle event state.
+func captureHook(cosys *common.Cosys, modelUid string) common.LifecycleHook {
+	return func(query common.EventQuery) error {
+		entity, err := findCurrent(cosys, modelUid, query.Params)
+		if err != nil {
+			return err
+		}
+
+		statePtr, ok := query.State.(*any)
+		if !ok {
+			return fmt.Errorf("invalid lifecycle event state")
+		}
+
+		states, ok := (*statePtr).(map[string]any)
+		if !ok {
+			states = map[string]any{}
+			*statePtr = states
+		}
+		states[stateKey] = entity
+
+		return nil
+	}
+}
+
+// recordCapturedHook returns the lifecycle hook that records the snapshot
+// kept in the lifecycle event state after the entity is updated.
+func recordCapturedHook(cosys *common.Cosys, modelUid string) common.LifecycleHook {
+	return func(query common.EventQuery) error {
+		statePtr, ok := query.State.(*any)
+		if !ok {
+			return fmt.Errorf("invalid lifecycle event state")
+		}
+
+		states, ok := (*statePtr).(map[string]any)
+		if !ok {
+			return nil
+		}
+
+		entity, ok := states[stateKey]
+		if !ok {
+			return nil
+		}
+
+		return record(cosys, modelUid, "update", entity)
+	}
+}
+
+// recordHook returns the lifecycle hook that records the snapshot of the entity about to be deleted.
+func recordHook(cosys *common.Cosys, modelUid string) common.LifecycleHook {
+	return func(query common.EventQuery) error {
+		entity, err := findCurrent(cosys, modelUid, query.Params)
+		if err != nil {
+			return err
+		}
+
+		return record(cosys, modelUid, "delete", entity)
+	}
+}
+
+// findCurrent returns the entity matching the where conditions of the given params.
+func findCurrent(cosys *common.Cosys, modelUid string, params common.DBParams) (common.Entity, error) {
+	database, err := cosys.Database()
+	if err != nil {
+		return nil, err
+	}
+
+	findParams := common.NewDBParamsBuilder().
+		Where(params.Where...).
+		Build()
+	findParams.IncludeDeleted = params.IncludeDeleted
+	findParams.Context = params.Context
+
+	return database.FindOne(modelUid, findParams)
+}
+
+// maxRecordAttempts is the number of times recording a version is attempted,
+// when concurrent updates record the same version.
+const maxRecordAttempts = 5
+
+// record stores a snapshot of the given entity as the next version of the entity.
+// The version is unique for the entity, so that if a concurrent update records the same version,
+// the next version is computed again and recording is retried.
+func record(cosys *common.Cosys, modelUid string, event string, entity common.Entity) error {
+	database, err := cosys.Database()
+	if err != nil {
+		return err
+	}
+
+	entityId, err := getId(entity)
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := json.Marshal(entity)
+	if err != nil {
+		return err
+	}
+
+	for attempt := 1; ; attempt++ {
+		latest, err := Versions(cosys, modelUid, entityId)
+		if err != nil {
+			return err
+		}
+
+		version := 1
+		if len(latest) > 0 {
+			version = latest[0].Version + 1
+		}
+
+		now := time.Now().UTC()
+		_, err = database.Create(ModelUid, &Version{
+			ModelUid:   modelUid,
+			EntityId:   entityId,
+			Version:    version,
+			VersionKey: fmt.Sprintf("%s:%d:%d", modelUid, entityId, version),
+			Event:      event,
+			Snapshot:   string(snapshot),
+			CreatedAt:  &now,
+		}, common.NewDBParams())
+
+		if err == nil || common.ErrorCodeOf(err) != common.ConflictCode || attempt == maxRecordAttempts {
+			return err
+		}
+	}
+}
+
+// Versions returns all versions of an entity, latest first.
+func Versions(cosys *common.Cosys, modelUid string, entityId int) ([]*Version, error) {
+	database, err := cosys.Database()
+	if err != nil {
+		return nil, err
+	}
+
+	params := common.NewDBParamsBuilder().
+		Where(Histories.ModelUid.Eq(modelUid), Histories.EntityId.Eq(entityId)).
+		OrderBy(Histories.Version.Desc()).
+		Build()
+
+	entities, err := database.FindMany(ModelUid, params)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]*Version, len(entities))
+	for index, entity := range entities {
+		version, ok := entity.(*Version)
+		if !ok {
+			return nil, fmt.Errorf("invalid history entity")
+		}
+
+		versions[index] = version
+	}
+
+	return versions, nil
+}
+
+// GetVersion returns a version of an entity.
+func GetVersion(cosys *common.Cosys, modelUid string, entityId int, version int) (*Version, error) {
+	database, err := cosys.Database()
+	if err != nil {
+		return nil, err
+	}
+
+	params := common.NewDBParamsBuilder().
+		Where(
+			Histories.ModelUid.Eq(modelUid),
+			Histories.EntityId.Eq(entityId),
+			Histories.Version.Eq(version),
+		).
+		Build()
+
+	entity, err := database.FindOne(ModelUid, params)
+	if err != nil {
+		return nil, err
+	}
+
+	found, ok := entity.(*Version)
+	if !ok {
+		return nil, fmt.Errorf("invalid history entity")
+	}
+
+	return found, nil
+}
+
+// getId returns the value of the id field of an entity.
+func getId(entity common.Entity) (int, error) {
+	entityValue := reflect.Indirect(reflect.ValueOf(entity))
+	if entityValue.Kind() != reflect.Struct {
+		return 0, fmt.Errorf("entity is not a struct")
+	}
+
+	idValue := entityValue.FieldByName("Id")
+	if !idValue.IsValid() || !idValue.CanInt() {
+		return 0, fmt.Errorf("id not found")
+	}
+
+	return int(idValue.Int()), nil
+}
@@ -0,0 +1,93 @@
+package i18n
+
+import (
+	"fmt"
+	"github.com/cosys-io/cosys/common"
+	"github.com/cosys-io/cosys/modules/cms/schema"
+	"slices"
+	"sync"
+)
+
+// Config is the configuration for the locales of localized models.
+type Config struct {
+	DefaultLocale string              // DefaultLocale is the locale of entries created without a locale.
+	Locales       []string            // Locales are the allowed locales, all locales are allowed if empty.
+	Fallbacks     map[string][]string // Fallbacks are the locales tried in order when an entry has no translation in a locale.
+}
+
+var (
+	configMutex sync.RWMutex
+	config      = Config{
+		DefaultLocale: "en",
+		Locales:       nil,
+		Fallbacks:     map[string][]string{},
+	}
+)
+
+// Configure sets the configuration for the locales of localized models.
+// Safe for concurrent use.
+func Configure(newConfig Config) error {
+	if newConfig.DefaultLocale == "" {
+		return fmt.Errorf("default locale not specified")
+	}
+	if len(newConfig.Locales) > 0 && !slices.Contains(newConfig.Locales, newConfig.DefaultLocale) {
+		return fmt.Errorf("default locale not in locales: %s", newConfig.DefaultLocale)
+	}
+	if newConfig.Fallbacks == nil {
+		newConfig.Fallbacks = map[string][]string{}
+	}
+
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	config = newConfig
+	return nil
+}
+
+// DefaultLocale returns the locale of entries created without a locale.
+// Safe for concurrent use.
+func DefaultLocale() string {
+	configMutex.RLock()
+	defer configMutex.RUnlock()
+
+	return config.DefaultLocale
+}
+
+// CheckLocale returns an error if the given locale is not allowed.
+// Safe for concurrent use.
+func CheckLocale(locale string) error {
+	configMutex.RLock()
+	defer configMutex.RUnlock()
+
+	if locale == "" {
+		return fmt.Errorf("locale not specified")
+	}
+	if len(config.Locales) > 0 && !slices.Contains(config.Locales, locale) {
+		return fmt.Errorf("locale not allowed: %s", locale)
+	}
+
+	return nil
+}
+
+// FallbackChain returns the locales tried in order when looking up a translation in the given locale,
+// starting with the locale itself, followed by its fallbacks and then the default locale.
+// Safe for concurrent use.
+func FallbackChain(locale string) []string {
+	configMutex.RLock()
+	defer configMutex.RUnlock()
+
+	chain := []string{locale}
+	for _, fallback := range slices.Concat(config.Fallbacks[locale], []string{config.DefaultLocale}) {
+		if !slices.Contains(chain, fallback) {
+			chain = append(chain, fallback)
+		}
+	}
+
+	return chain
+}
+
+// IsLocalized returns whether the given model has localization enabled.
+func IsLocalized(model common.Model) bool {
+	modelSchema, ok := model.Schema_().(*schema.ModelSchema)
+	return ok && modelSchema.Localized()
+}
@@ -0,0 +1,384 @@
+package i18n
+
+import (
+	"fmt"
+	"github.com/cosys-io/cosys/common"
+	"github.com/cosys-io/cosys/modules/cms/schema"
+	"reflect"
+	"slices"
+)
+
+// Enable adds the lifecycle hooks that keep non-localized attributes in sync
+// between the translations of entries of the given localized models.
+func Enable(cosys *common.Cosys, models map[string]common.Model) error {
+	for modelUid, model := range models {
+		if !IsLocalized(model) {
+			continue
+		}
+
+		if _, err := model.AddLifecycleHook_("afterUpdate", syncHook(cosys, modelUid)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// syncHook returns the lifecycle hook that copies the non-localized attributes of an updated entry
+// to the other translations of the entry.
+func syncHook(cosys *common.Cosys, modelUid string) common.LifecycleHook {
+	return func(query common.EventQuery) error {
+		model, err := cosys.Model(modelUid)
+		if err != nil {
+			return err
+		}
+
+		shared := sharedAttributes(model)
+		if len(shared) == 0 || query.Result == nil {
+			return nil
+		}
+
+		database, err := cosys.Database()
+		if err != nil {
+			return err
+		}
+
+		id, err := getField[int](query.Result, "Id")
+		if err != nil {
+			return err
+		}
+
+		groupCondition, err := groupOf(model, query.Result)
+		if err != nil {
+			return err
+		}
+
+		params := common.NewDBParamsBuilder().
+			Update(shared...).
+			Where(groupCondition, model.IdAttribute_().(common.IntAttribute).NEq(id)).
+			Context(query.Context).
+			Build()
+
+		_, err = database.UpdateMany(modelUid, query.Result, params)
+		return err
+	}
+}
+
+// Translations returns all translations of the entry with the given id, including the entry itself.
+func Translations(cosys *common.Cosys, modelUid string, id int) ([]common.Entity, error) {
+	model, err := cosys.Model(modelUid)
+	if err != nil {
+		return nil, err
+	}
+
+	database, err := cosys.Database()
+	if err != nil {
+		return nil, err
+	}
+
+	entity, err := findById(database, modelUid, model, id)
+	if err != nil {
+		return nil, err
+	}
+
+	groupCondition, err := groupOf(model, entity)
+	if err != nil {
+		return nil, err
+	}
+
+	params := common.NewDBParamsBuilder().
+		Where(groupCondition).
+		OrderBy(model.IdAttribute_().Asc()).
+		Build()
+
+	return database.FindMany(modelUid, params)
+}
+
+// Translation returns the translation of the entry with the given id in the given locale,
+// or in the first of its fallback locales that the entry is translated to.
+// The where conditions of the given params are applied when looking up the translation.
+func Translation(cosys *common.Cosys, modelUid string, id int, locale string, params common.DBParams) (common.Entity, error) {
+	model, err := cosys.Model(modelUid)
+	if err != nil {
+		return nil, err
+	}
+
+	database, err := cosys.Database()
+	if err != nil {
+		return nil, err
+	}
+
+	entity, err := findById(database, modelUid, model, id)
+	if err != nil {
+		return nil, err
+	}
+
+	groupCondition, err := groupOf(model, entity)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, fallback := range FallbackChain(locale) {
+		fallbackParams := params
+		fallbackParams.Where = append(append([]common.Condition{}, params.Where...), groupCondition)
+		fallbackParams.Locale = fallback
+
+		translations, err := database.FindMany(modelUid, fallbackParams)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(translations) > 0 {
+			return translations[0], nil
+		}
+	}
+
+	return nil, common.NewNotFoundError("translation not found: " + locale)
+}
+
+// TranslatedMany returns the entries of the model with the given uid matching the given params,
+// each in the given locale or in the first of its fallback locales that the entry is translated to,
+// leaving out entries that are translated to none of them.
+// The limit and offset of the params apply to the entries after their translations are resolved.
+func TranslatedMany(cosys *common.Cosys, modelUid string, locale string, params common.DBParams) ([]common.Entity, error) {
+	model, err := cosys.Model(modelUid)
+	if err != nil {
+		return nil, err
+	}
+
+	database, err := cosys.Database()
+	if err != nil {
+		return nil, err
+	}
+
+	var localeAttr common.StringAttribute
+	found := false
+	for _, attr := range model.Attributes_() {
+		if attr.CamelName() == schema.LocaleSchema.Name() {
+			localeAttr, found = attr.(common.StringAttribute)
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("model is not localized: %s", model.PluralCamelName_())
+	}
+
+	chain := FallbackChain(locale)
+
+	localeCondition := localeAttr.Eq(chain[0])
+	for _, fallback := range chain[1:] {
+		localeCondition = localeCondition.Or(localeAttr.Eq(fallback))
+	}
+
+	findParams := params
+	findParams.Where = append(slices.Clone(params.Where), localeCondition)
+	findParams.Locale = ""
+	findParams.Limit = -1
+	findParams.Offset = 0
+	if len(params.Select) > 0 {
+		findParams.Select = slices.Clone(params.Select)
+		for _, attr := range model.Attributes_() {
+			switch attr.CamelName() {
+			case schema.IdSchema.Name(), schema.LocaleSchema.Name(), schema.LocalizationIdSchema.Name():
+				if !slices.Contains(findParams.Select, attr) {
+					findParams.Select = append(findParams.Select, attr)
+				}
+			}
+		}
+	}
+
+	entities, err := database.FindMany(modelUid, findParams)
+	if err != nil {
+		return nil, err
+	}
+
+	groupIds := make([]int, len(entities))
+	ranks := make([]int, len(entities))
+	best := make(map[int]int) // best are the indices of the translations in the first locale of the chain, by group id.
+	for index, entity := range entities {
+		if groupIds[index], err = getGroupId(entity); err != nil {
+			return nil, err
+		}
+
+		entityLocale, err := getField[string](entity, "Locale")
+		if err != nil {
+			return nil, err
+		}
+		ranks[index] = slices.Index(chain, entityLocale)
+
+		if bestIndex, ok := best[groupIds[index]]; !ok || ranks[index] < ranks[bestIndex] {
+			best[groupIds[index]] = index
+		}
+	}
+
+	translated := make([]common.Entity, 0, len(best))
+	for index, entity := range entities {
+		if best[groupIds[index]] == index {
+			translated = append(translated, entity)
+		}
+	}
+
+	if params.Offset > 0 {
+		translated = translated[min(params.Offset, int64(len(translated))):]
+	}
+	if params.Limit >= 0 {
+		translated = translated[:min(params.Limit, int64(len(translated)))]
+	}
+
+	return translated, nil
+}
+
+// CreateTranslation creates a translation of the entry with the given id in the given locale,
+// by copying the entry, and returns the new translation.
+// The publishedAt attribute of draft and publish models is not copied.
+func CreateTranslation(cosys *common.Cosys, modelUid string, id int, locale string) (common.Entity, error) {
+	if err := CheckLocale(locale); err != nil {
+		return nil, err
+	}
+
+	model, err := cosys.Model(modelUid)
+	if err != nil {
+		return nil, err
+	}
+
+	database, err := cosys.Database()
+	if err != nil {
+		return nil, err
+	}
+
+	entity, err := findById(database, modelUid, model, id)
+	if err != nil {
+		return nil, err
+	}
+
+	groupCondition, err := groupOf(model, entity)
+	if err != nil {
+		return nil, err
+	}
+
+	existingParams := common.NewDBParamsBuilder().
+		Where(groupCondition).
+		Locale(locale).
+		Build()
+
+	existing, err := database.FindMany(modelUid, existingParams)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		return nil, fmt.Errorf("translation already exists: %s", locale)
+	}
+
+	groupId, err := getGroupId(entity)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = common.SetEntityField(entity, "Locale", locale); err != nil {
+		return nil, err
+	}
+	if err = common.SetEntityField(entity, "LocalizationId", groupId); err != nil {
+		return nil, err
+	}
+
+	columns := make([]common.Attribute, 0, len(model.Attributes_()))
+	for _, attr := range model.Attributes_()[1:] {
+		if attr.CamelName() == schema.PublishedAtSchema.Name() {
+			continue
+		}
+
+		columns = append(columns, attr)
+	}
+
+	params := common.NewDBParamsBuilder().
+		Insert(columns...).
+		Build()
+
+	return database.Create(modelUid, entity, params)
+}
+
+// findById returns the entity with the given id.
+func findById(database common.Database, modelUid string, model common.Model, id int) (common.Entity, error) {
+	params := common.NewDBParamsBuilder().
+		Where(model.IdAttribute_().(common.IntAttribute).Eq(id)).
+		Build()
+
+	return database.FindOne(modelUid, params)
+}
+
+// sharedAttributes returns the editable attributes of the given model that are not localized,
+// which have the same value in all translations of an entry.
+func sharedAttributes(model common.Model) []common.Attribute {
+	modelSchema, ok := model.Schema_().(*schema.ModelSchema)
+	if !ok {
+		return nil
+	}
+
+	var shared []common.Attribute
+	for _, attr := range model.Attributes_() {
+		attrSchema, ok := modelSchema.Attribute(attr.CamelName())
+		if !ok || attrSchema.Localized() || !attrSchema.Editable() {
+			continue
+		}
+
+		shared = append(shared, attr)
+	}
+
+	return shared
+}
+
+// groupOf returns the where condition matching all translations of the given entity.
+func groupOf(model common.Model, entity common.Entity) (common.Condition, error) {
+	var localizationId common.IntAttribute
+	found := false
+	for _, attr := range model.Attributes_() {
+		if attr.CamelName() != schema.LocalizationIdSchema.Name() {
+			continue
+		}
+
+		localizationId, found = attr.(common.IntAttribute)
+	}
+	if !found {
+		return nil, fmt.Errorf("model is not localized: %s", model.PluralCamelName_())
+	}
+
+	groupId, err := getGroupId(entity)
+	if err != nil {
+		return nil, err
+	}
+
+	return model.IdAttribute_().(common.IntAttribute).Eq(groupId).Or(localizationId.Eq(groupId)), nil
+}
+
+// getGroupId returns the id of the original entry of the given entity,
+// which is the entity itself if it is not a translation.
+func getGroupId(entity common.Entity) (int, error) {
+	localizationId, err := getField[int](entity, "LocalizationId")
+	if err != nil {
+		return 0, err
+	}
+	if localizationId != 0 {
+		return localizationId, nil
+	}
+
+	return getField[int](entity, "Id")
+}
+
+// getField returns the value of a field of an entity.
+func getField[T any](entity common.Entity, name string) (T, error) {
+	entityValue := reflect.Indirect(reflect.ValueOf(entity))
+	if entityValue.Kind() != reflect.Struct {
+		return *new(T), fmt.Errorf("entity is not a struct")
+	}
+
+	field := entityValue.FieldByName(name)
+	if !field.IsValid() {
+		return *new(T), fmt.Errorf("field not found: %s", name)
+	}
+
+	value, ok := field.Interface().(T)
+	if !ok {
+		return *new(T), fmt.Errorf("invalid type for field: %s", name)
+	}
+
+	return value, nil
+}
@@ -19,6 +19,11 @@ var (
 	singularName string // singularName is bound to the singular flag.
 	pluralName   string // pluralName is bound to the plural flag.
 	about        string // about is bound to the about flag.
+
+	draftAndPublish bool // draftAndPublish is bound to the draft-and-publish flag.
+	versioned       bool // versioned is bound to the versioned flag.
+	localized       bool // localized is bound to the localized flag.
+	softDelete      bool // softDelete is bound to the soft-delete flag.
 )
 
 func init() {
@@ -27,6 +32,10 @@ func init() {
 	generateCollectionCmd.Flags().StringVarP(&singularName, "singular", "S", "", "singular name of the new content type")
 	generateCollectionCmd.Flags().StringVarP(&pluralName, "plural", "P", "", "plural name of the new content type")
 	generateCollectionCmd.Flags().StringVarP(&about, "about", "A", "", "description of the new content type")
+	generateCollectionCmd.Flags().BoolVar(&draftAndPublish, "draft-and-publish", false, "whether entries of the new content type are drafts until published")
+	generateCollectionCmd.Flags().BoolVar(&versioned, "versioned", false, "whether snapshots of entries of the new content type are recorded before updates and deletions")
+	generateCollectionCmd.Flags().BoolVar(&localized, "localized", false, "whether entries of the new content type have translations in multiple locales")
+	generateCollectionCmd.Flags().BoolVar(&softDelete, "soft-delete", false, "whether deleted entries of the new content type are kept until purged")
 	generateCollectionCmd.MarkFlagRequired("singular")
 	generateCollectionCmd.MarkFlagRequired("plural")
 
@@ -66,14 +75,32 @@ func getSchema(databaseName string, viewName string, singularName string, plural
 		}
 
 		if _, dup := attrsSet[attrSchema.Name()]; dup {
-			return nil, fmt.Errorf("duplicate attribute: %s", attrSchema.Name)
+			return nil, fmt.Errorf("duplicate attribute: %s", attrSchema.Name())
 		}
 		attrsSet[attrSchema.Name()] = true
 
 		attrs[index+1] = attrSchema
 	}
 
-	return getModelSchema(databaseName, viewName, singularName, pluralName, about, attrs), nil
+	modelSchema := getModelSchema(databaseName, viewName, singularName, pluralName, about, attrs)
+	if draftAndPublish {
+		modelSchema.With(schema.DraftAndPublish)
+	}
+	if versioned {
+		modelSchema.With(schema.Versioned)
+	}
+	if localized {
+		modelSchema.With(schema.Localized)
+	}
+	if softDelete {
+		modelSchema.With(schema.SoftDelete)
+	}
+
+	if err := schema.CheckComponents(modelSchema); err != nil {
+		return nil, err
+	}
+
+	return modelSchema, nil
 }
 
 // getType returns the simple and detailed type from the given attribute type string.
@@ -103,6 +130,12 @@ func getType(attrType string) (string, string, error) {
 	case "timestamp":
 		attrSimpleType = "Timestamp"
 		attrDetailedType = "Timestamp"
+	case "component":
+		attrSimpleType = "Component"
+		attrDetailedType = "Component"
+	case "dynamiczone":
+		attrSimpleType = "DynamicZone"
+		attrDetailedType = "DynamicZone"
 	default:
 		return "", "", fmt.Errorf("invalid type: %s", attrType)
 	}
@@ -153,6 +186,13 @@ func getAttrSchema(attrString string) (*schema.AttributeSchema, error) {
 		option(attrSchema)
 	}
 
+	if attrDetailedType == "Component" && attrSchema.Component() == "" {
+		return nil, fmt.Errorf("component not specified: %s", attrString)
+	}
+	if attrDetailedType == "DynamicZone" && len(attrSchema.Components()) == 0 {
+		return nil, fmt.Errorf("components not specified: %s", attrString)
+	}
+
 	return attrSchema, nil
 }
 
@@ -202,6 +242,29 @@ func getOption(option string) (schema.AttrOption, error) {
 		return schema.NotNullable, nil
 	case option == "unique":
 		return schema.Unique, nil
+	case regexp.MustCompile(`^component=([\w-]+\.[\w-]+)$`).MatchString(option):
+		matches := regexp.MustCompile(`^component=([\w-]+\.[\w-]+)$`).FindStringSubmatch(option)
+		uid, err := schema.ComponentUid(matches[1])
+		if err != nil {
+			return nil, err
+		}
+
+		return schema.ComponentOf(uid), nil
+	case option == "repeatable":
+		return schema.Repeatable, nil
+	case regexp.MustCompile(`^components=([\w.,-]+)$`).MatchString(option):
+		matches := regexp.MustCompile(`^components=([\w.,-]+)$`).FindStringSubmatch(option)
+		uids := strings.Split(matches[1], ",")
+		for index, uid := range uids {
+			var err error
+			if uids[index], err = schema.ComponentUid(uid); err != nil {
+				return nil, err
+			}
+		}
+
+		return schema.Components(uids...), nil
+	case option == "notlocalized":
+		return schema.NotLocalized, nil
 	default:
 		return nil, fmt.Errorf("invalid option: %s", option)
 	}
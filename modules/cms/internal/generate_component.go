@@ -0,0 +1,77 @@
+package internal
+
+import (
+	"fmt"
+	"github.com/cosys-io/cosys/modules/cms/generators"
+	"github.com/cosys-io/cosys/modules/cms/schema"
+	"log"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	generateComponentCmd.Flags().StringVarP(&viewName, "view", "V", "", "name displayed to users for the new component")
+	generateComponentCmd.Flags().StringVarP(&about, "about", "A", "", "description of the new component")
+
+	generateCmd.AddCommand(generateComponentCmd)
+}
+
+// generateComponentCmd is the command for generating a component.
+var generateComponentCmd = &cobra.Command{
+	Use:   "component category.name [attributes] [flags]",
+	Short: "Generate a component",
+	Long:  "Generate a component, a reusable group of attributes that can be embedded in content types.",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		componentSchema, err := getComponentSchema(args[0], viewName, about, args[1:])
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if err := generators.GenerateComponent(componentSchema); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+// getComponentSchema returns the component ModelSchema from the given uid, name, description and attribute strings.
+func getComponentSchema(uid string, viewName string, about string, attrStrings []string) (*schema.ModelSchema, error) {
+	uid, err := schema.ComponentUid(uid)
+	if err != nil {
+		return nil, err
+	}
+	category, name, _ := strings.Cut(uid, ".")
+
+	attrs := make([]*schema.AttributeSchema, len(attrStrings))
+	attrsSet := make(map[string]bool)
+
+	for index, attrString := range attrStrings {
+		attrSchema, err := getAttrSchema(attrString)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, dup := attrsSet[attrSchema.Name()]; dup {
+			return nil, fmt.Errorf("duplicate attribute: %s", attrSchema.Name())
+		}
+		attrsSet[attrSchema.Name()] = true
+
+		attrs[index] = attrSchema
+	}
+
+	var displayName string
+	if viewName != "" {
+		displayName = strcase.ToDelimited(viewName, ' ')
+	} else {
+		displayName = strcase.ToDelimited(name, ' ')
+	}
+
+	componentSchema := schema.NewComponentSchema(strcase.ToLowerCamel(category+"_"+name), displayName, category, name, about, attrs...)
+	if err := schema.CheckComponents(componentSchema); err != nil {
+		return nil, err
+	}
+
+	return componentSchema, nil
+}
@@ -33,6 +33,7 @@ var initCmd = &cobra.Command{
 		viper.Set("cms_controllers_path", filepath.Join(modulePath, "controllers"))
 		viper.Set("cms_middlewares_path", filepath.Join(modulePath, "middlewares"))
 		viper.Set("cms_policies_path", filepath.Join(modulePath, "policies"))
+		viper.Set("cms_components_path", filepath.Join(modulePath, "components"))
 		if err := viper.WriteConfig(); err != nil {
 			log.Fatal(err)
 		}
@@ -44,7 +45,7 @@ var initCmd = &cobra.Command{
 	},
 }
 
-// generateModule generates the code for the content types, controllers, middlewares, policies and routes packages.
+// generateModule generates the code for the content types, components, controllers, middlewares, policies and routes packages.
 func generateModule(moduleDir, moduleName, modFile string) error {
 	ctx := struct {
 		ModuleDir  string
@@ -61,6 +62,8 @@ func generateModule(moduleDir, moduleName, modFile string) error {
 		gen.NewFile(filepath.Join(moduleDir, "module.go"), moduleTmpl, ctx),
 		gen.NewDir(filepath.Join(moduleDir, "content_types"), gen.GenHeadOnly),
 		gen.NewFile(filepath.Join(moduleDir, "content_types", "models.go"), modelsTmpl, nil),
+		gen.NewDir(filepath.Join(moduleDir, "components"), gen.GenHeadOnly),
+		gen.NewFile(filepath.Join(moduleDir, "components", "components.go"), componentsTmpl, nil),
 		gen.NewDir(filepath.Join(moduleDir, "controllers"), gen.GenHeadOnly),
 		gen.NewFile(filepath.Join(moduleDir, "controllers", "controllers.go"), controllersTmpl, nil),
 		gen.NewDir(filepath.Join(moduleDir, "middlewares"), gen.GenHeadOnly),
@@ -84,16 +87,25 @@ var moduleTmpl = `package {{.ModuleName}}
 
 import (
 	"github.com/cosys-io/cosys/common"
+	"{{.ModFile}}/{{.ModuleDir}}/components"
 	"{{.ModFile}}/{{.ModuleDir}}/content_types"
 	"{{.ModFile}}/{{.ModuleDir}}/controllers"
 	"{{.ModFile}}/{{.ModuleDir}}/middlewares"
 	"{{.ModFile}}/{{.ModuleDir}}/policies"
 	"{{.ModFile}}/{{.ModuleDir}}/routes"
+	"github.com/cosys-io/cosys/modules/cms"
 	"github.com/cosys-io/cosys/modules/cms/admin"
+	"github.com/cosys-io/cosys/modules/cms/schema"
+	"github.com/cosys-io/cosys/modules/sqlite3"
 )
 
+// ModuleName is the name the module is registered with, which other modules can depend on.
+const ModuleName = "{{.ModFile}}/{{.ModuleDir}}"
+
+// init registers the module to register the routes, controllers, middlewares, policies, components and models.
+// It is registered after the sqlite3 and cms modules, whose database and commands its models are used with.
 func init() {
-	_ = common.RegisterModule(func(cosys *common.Cosys) error {
+	_ = common.RegisterNamedModule(ModuleName, func(cosys *common.Cosys) error {
 		if err := cosys.AddRoutes(routes.Routes...); err != nil {
 			return err
 		}
@@ -110,6 +122,10 @@ func init() {
 			return err
 		}
 
+		if err := schema.AddComponents(components.Components...); err != nil {
+			return err
+		}
+
 		if err := cosys.AddModels(models.Models); err != nil {
 			return err
 		}
@@ -122,8 +138,16 @@ func init() {
 			return err
 		}
 
+		if err := admin.AddHistoryRoutes(cosys, models.Models); err != nil {
+			return err
+		}
+
+		if err := admin.AddLocalizationRoutes(cosys, models.Models); err != nil {
+			return err
+		}
+
 		return nil
-	})
+	}, common.DependsOn(sqlite3.ModuleName, cms.ModuleName))
 }
 `
 
@@ -159,6 +183,14 @@ import "github.com/cosys-io/cosys/common"
 var Routes = []common.Route{
 }`
 
+// componentsTmpl is the template for creating the components.go file.
+var componentsTmpl = `package components
+
+import "github.com/cosys-io/cosys/modules/cms/schema"
+
+var Components = []*schema.ModelSchema{
+}`
+
 // modelsTmpl is the template for creating the models.go file.
 var modelsTmpl = `package models
 
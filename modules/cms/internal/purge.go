@@ -0,0 +1,74 @@
+package internal
+
+import (
+	"fmt"
+	"github.com/cosys-io/cosys/common"
+	"github.com/spf13/cobra"
+	"log"
+	"time"
+)
+
+// PurgeCmd is the command for permanently removing soft-deleted entries
+// that were deleted before the given duration.
+func PurgeCmd(cosys *common.Cosys) *cobra.Command {
+	var olderThan time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "purge [flags]",
+		Short: "Permanently remove soft-deleted entries",
+		Long:  "Permanently remove soft-deleted entries.",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := cosys.Bootstrap(); err != nil {
+				log.Fatal(err)
+			}
+
+			purged, err := purge(cosys, time.Now().UTC().Add(-olderThan))
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			fmt.Printf("Purged %d entries.\n", purged)
+
+			if err = cosys.Cleanup(); err != nil {
+				log.Fatal(err)
+			}
+		},
+	}
+
+	cmd.Flags().DurationVar(&olderThan, "older-than", 30*24*time.Hour, "minimum time since the entries were deleted")
+
+	return cmd
+}
+
+// purge permanently removes entries of soft delete models that were deleted before the given time,
+// and returns the number of removed entries.
+func purge(cosys *common.Cosys, before time.Time) (int, error) {
+	database, err := cosys.Database()
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for modelUid, model := range cosys.Models() {
+		deletedAt, ok := common.SoftDeleteAttribute(model)
+		if !ok {
+			continue
+		}
+
+		params := common.NewDBParamsBuilder().
+			Where(deletedAt.Lt(before)).
+			IncludeDeleted().
+			Permanent().
+			Build()
+
+		entities, err := database.DeleteMany(modelUid, params)
+		if err != nil {
+			return purged, err
+		}
+
+		purged += len(entities)
+	}
+
+	return purged, nil
+}
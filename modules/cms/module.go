@@ -3,12 +3,21 @@ package cms
 import (
 	"github.com/cosys-io/cosys/common"
 	"github.com/cosys-io/cosys/modules/cms/internal"
+	"github.com/cosys-io/cosys/modules/sqlite3"
 	"github.com/spf13/cobra"
 )
 
-// init registers the module to register the cli commands for the cms.
+// ModuleName is the name the module is registered with, which other modules can depend on.
+const ModuleName = "cms"
+
+// init registers the module to register the cli commands for the cms,
+// and the command for purging soft-deleted entries.
+// It depends on the sqlite3 module, as its commands use the database.
 func init() {
-	_ = common.RegisterModule(func(cosys *common.Cosys) error {
-		return cosys.AddCommands(func(*common.Cosys) *cobra.Command { return internal.RootCmd })
-	})
+	_ = common.RegisterNamedModule(ModuleName, func(cosys *common.Cosys) error {
+		return cosys.AddCommands(
+			func(*common.Cosys) *cobra.Command { return internal.RootCmd },
+			internal.PurgeCmd,
+		)
+	}, common.DependsOn(sqlite3.ModuleName))
 }
@@ -0,0 +1,60 @@
+package routes
+
+import (
+	"github.com/cosys-io/cosys/common"
+)
+
+// FindManyQuery are the query parameters of the find many actions.
+var FindManyQuery = []common.ParamDoc{
+	{Name: "page", Description: "The page number, starting from 1.", Type: "integer"},
+	{Name: "pageSize", Description: "The number of entries per page, by default 20.", Type: "integer"},
+	{Name: "sort", Description: "The comma-separated attributes to sort by, prefixed with - for descending order."},
+	{Name: "fields", Description: "The comma-separated attributes to return."},
+	{Name: "populate", Description: "The comma-separated component and dynamic zone attributes to populate, or * for all."},
+	{Name: "locale", Description: "The locale of the entries of localized models."},
+}
+
+// FindOneQuery are the query parameters of the find one actions.
+var FindOneQuery = []common.ParamDoc{
+	{Name: "fields", Description: "The comma-separated attributes to return."},
+	{Name: "populate", Description: "The comma-separated component and dynamic zone attributes to populate, or * for all."},
+	{Name: "locale", Description: "The locale of the translation of localized models."},
+}
+
+// Doc returns the documentation of the cms action with the given name, one of findMany, findOne,
+// create, update, delete, publish, unpublish and restore, for the model of the given uid.
+func Doc(action string, modelUid string, model common.Model) common.RouteDoc {
+	singular := model.SingularHumanName_()
+	plural := model.PluralHumanName_()
+
+	doc := common.RouteDoc{
+		Tags:     []string{plural},
+		Response: common.ModelType(modelUid),
+	}
+
+	switch action {
+	case "findMany":
+		doc.Summary = "Find " + plural
+		doc.Query = FindManyQuery
+		doc.Response = common.ModelListType(modelUid)
+	case "findOne":
+		doc.Summary = "Find a " + singular
+		doc.Query = FindOneQuery
+	case "create":
+		doc.Summary = "Create a " + singular
+		doc.Request = common.ModelType(modelUid)
+	case "update":
+		doc.Summary = "Update a " + singular
+		doc.Request = common.ModelType(modelUid)
+	case "delete":
+		doc.Summary = "Delete a " + singular
+	case "publish":
+		doc.Summary = "Publish a " + singular
+	case "unpublish":
+		doc.Summary = "Unpublish a " + singular
+	case "restore":
+		doc.Summary = "Restore a deleted " + singular
+	}
+
+	return doc
+}
@@ -0,0 +1,98 @@
+package routes
+
+import (
+	"fmt"
+	"github.com/cosys-io/cosys/common"
+	"github.com/cosys-io/cosys/modules/server/response"
+	"net/http"
+	"time"
+)
+
+// Publish returns the publish ActionFunc for the model of the given uid,
+// which sets the publishedAt attribute of a draft entity.
+func Publish(modelUid string) common.ActionFunc {
+	return setPublishedAt(modelUid, "Publish", "publish")
+}
+
+// Unpublish returns the unpublish ActionFunc for the model of the given uid,
+// which clears the publishedAt attribute of a published entity, reverting it to a draft.
+func Unpublish(modelUid string) common.ActionFunc {
+	return setPublishedAt(modelUid, "Unpublish", "unpublish")
+}
+
+// setPublishedAt returns the ActionFunc for publishing or unpublishing entities of the model of the given uid,
+// calling the before and after lifecycle hooks for the given event.
+func setPublishedAt(modelUid string, event string, verb string) common.ActionFunc {
+	return func(cosys *common.Cosys) (http.HandlerFunc, error) {
+		model, err := cosys.Model(modelUid)
+		if err != nil {
+			return nil, err
+		}
+
+		publishedAt, ok := getPublishedAt(model)
+		if !ok {
+			return nil, fmt.Errorf("model does not have draft and publish enabled: %s", modelUid)
+		}
+
+		database, err := cosys.Database()
+		if err != nil {
+			return nil, err
+		}
+
+		return func(w http.ResponseWriter, r *http.Request) {
+			id, err := getId(r)
+			if err != nil {
+				respondError(w, err, "Could not "+verb+" "+model.SingularHumanName_())
+				return
+			}
+
+			entity := model.New_()
+
+			var value *time.Time
+			if event == "Publish" {
+				now := time.Now().UTC()
+				value = &now
+			}
+
+			if err = common.SetEntityField(entity, publishedAt.PascalName(), value); err != nil {
+				response.RespondError(w, "Could not "+verb+" "+model.SingularHumanName_(), http.StatusInternalServerError)
+				return
+			}
+
+			dbParams := common.NewDBParamsBuilder().
+				Update(publishedAt).
+				Where(model.IdAttribute_().(common.IntAttribute).Eq(id)).
+				Context(r.Context()).
+				Build()
+
+			var state any
+			if err = model.CallLifecycle_("before"+event, common.EventQuery{
+				Params:  dbParams,
+				Result:  nil,
+				State:   &state,
+				Context: r.Context(),
+			}); err != nil {
+				respondError(w, err, "Could not "+verb+" "+model.SingularHumanName_())
+				return
+			}
+
+			newEntity, err := database.Update(modelUid, entity, dbParams)
+			if err != nil {
+				respondError(w, err, "Could not "+verb+" "+model.SingularHumanName_())
+				return
+			}
+
+			if err = model.CallLifecycle_("after"+event, common.EventQuery{
+				Params:  dbParams,
+				Result:  newEntity,
+				State:   &state,
+				Context: r.Context(),
+			}); err != nil {
+				respondError(w, err, "Could not "+verb+" "+model.SingularHumanName_())
+				return
+			}
+
+			response.RespondOne(w, newEntity, http.StatusOK)
+		}, nil
+	}
+}
@@ -0,0 +1,75 @@
+package routes
+
+import (
+	"fmt"
+	"github.com/cosys-io/cosys/common"
+	"github.com/cosys-io/cosys/modules/server/response"
+	"net/http"
+)
+
+// Restore returns the restore ActionFunc for the model of the given uid,
+// which clears the deletedAt attribute of a soft-deleted entity.
+func Restore(modelUid string) common.ActionFunc {
+	return func(cosys *common.Cosys) (http.HandlerFunc, error) {
+		model, err := cosys.Model(modelUid)
+		if err != nil {
+			return nil, err
+		}
+
+		deletedAt, ok := common.SoftDeleteAttribute(model)
+		if !ok {
+			return nil, fmt.Errorf("model does not have soft delete enabled: %s", modelUid)
+		}
+
+		database, err := cosys.Database()
+		if err != nil {
+			return nil, err
+		}
+
+		return func(w http.ResponseWriter, r *http.Request) {
+			id, err := getId(r)
+			if err != nil {
+				respondError(w, err, "Could not restore "+model.SingularHumanName_())
+				return
+			}
+
+			entity := model.New_()
+
+			dbParams := common.NewDBParamsBuilder().
+				Update(deletedAt).
+				Where(model.IdAttribute_().(common.IntAttribute).Eq(id), deletedAt.NotNull()).
+				IncludeDeleted().
+				Context(r.Context()).
+				Build()
+
+			var state any
+			if err = model.CallLifecycle_("beforeRestore", common.EventQuery{
+				Params:  dbParams,
+				Result:  nil,
+				State:   &state,
+				Context: r.Context(),
+			}); err != nil {
+				respondError(w, err, "Could not restore "+model.SingularHumanName_())
+				return
+			}
+
+			newEntity, err := database.Update(modelUid, entity, dbParams)
+			if err != nil {
+				respondError(w, err, "Could not restore "+model.SingularHumanName_())
+				return
+			}
+
+			if err = model.CallLifecycle_("afterRestore", common.EventQuery{
+				Params:  dbParams,
+				Result:  newEntity,
+				State:   &state,
+				Context: r.Context(),
+			}); err != nil {
+				respondError(w, err, "Could not restore "+model.SingularHumanName_())
+				return
+			}
+
+			response.RespondOne(w, newEntity, http.StatusOK)
+		}, nil
+	}
+}
@@ -3,18 +3,51 @@ package routes
 import (
 	"encoding/json"
 	"github.com/cosys-io/cosys/common"
+	"github.com/cosys-io/cosys/modules/cms/i18n"
 	"github.com/cosys-io/cosys/modules/server/response"
 	"net/http"
 )
 
+// actionOptions are configurations for the cms actions.
+type actionOptions struct {
+	drafts bool
+}
+
+// ActionOption is a configuration for the cms actions.
+type ActionOption func(*actionOptions)
+
+// WithDrafts specifies that find actions return draft entities of draft and publish models,
+// which are otherwise only returned once published.
+var WithDrafts ActionOption = func(opts *actionOptions) {
+	opts.drafts = true
+}
+
+// getActionOptions returns the configuration from the given action options.
+func getActionOptions(opts []ActionOption) actionOptions {
+	options := actionOptions{
+		drafts: false,
+	}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return options
+}
+
 // FindMany returns the find many ActionFunc for the model of the given uid.
-func FindMany(modelUid string) common.ActionFunc {
+func FindMany(modelUid string, opts ...ActionOption) common.ActionFunc {
+	options := getActionOptions(opts)
+
 	return func(cosys *common.Cosys) (http.HandlerFunc, error) {
 		model, err := cosys.Model(modelUid)
 		if err != nil {
 			return nil, err
 		}
 
+		publishedAt, draftAndPublish := getPublishedAt(model)
+		localized := i18n.IsLocalized(model)
+
 		database, err := cosys.Database()
 		if err != nil {
 			return nil, err
@@ -23,19 +56,35 @@ func FindMany(modelUid string) common.ActionFunc {
 		return func(w http.ResponseWriter, r *http.Request) {
 			page, err := getPage(r)
 			if err != nil {
-				response.RespondError(w, "Could not find "+model.PluralHumanName_(), http.StatusBadRequest)
+				respondError(w, err, "Could not find "+model.PluralHumanName_())
 				return
 			}
 
 			dbParams, err := getParams(r, model.Attributes_())
 			if err != nil {
-				response.RespondError(w, "Could not find "+model.PluralHumanName_(), http.StatusBadRequest)
+				respondError(w, err, "Could not find "+model.PluralHumanName_())
 				return
 			}
 
-			entities, err := database.FindMany(modelUid, dbParams)
+			if draftAndPublish && !options.drafts {
+				dbParams.Where = append(dbParams.Where, publishedAt.NotNull())
+			}
+
+			var entities []common.Entity
+			if localized {
+				var locale string
+				locale, _, err = getLocale(r)
+				if err != nil {
+					respondError(w, err, "Could not find "+model.PluralHumanName_())
+					return
+				}
+
+				entities, err = i18n.TranslatedMany(cosys, modelUid, locale, dbParams)
+			} else {
+				entities, err = database.FindMany(modelUid, dbParams)
+			}
 			if err != nil {
-				response.RespondError(w, "Could not find "+model.PluralHumanName_(), http.StatusBadRequest)
+				respondError(w, err, "Could not find "+model.PluralHumanName_())
 				return
 			}
 
@@ -45,13 +94,18 @@ func FindMany(modelUid string) common.ActionFunc {
 }
 
 // FindOne returns the find one ActionFunc for the model of the given uid.
-func FindOne(modelUid string) common.ActionFunc {
+func FindOne(modelUid string, opts ...ActionOption) common.ActionFunc {
+	options := getActionOptions(opts)
+
 	return func(cosys *common.Cosys) (http.HandlerFunc, error) {
 		model, err := cosys.Model(modelUid)
 		if err != nil {
 			return nil, err
 		}
 
+		publishedAt, draftAndPublish := getPublishedAt(model)
+		localized := i18n.IsLocalized(model)
+
 		database, err := cosys.Database()
 		if err != nil {
 			return nil, err
@@ -60,17 +114,47 @@ func FindOne(modelUid string) common.ActionFunc {
 		return func(w http.ResponseWriter, r *http.Request) {
 			id, err := getId(r)
 			if err != nil {
-				response.RespondError(w, "Could not find "+model.SingularHumanName_(), http.StatusBadRequest)
+				respondError(w, err, "Could not find "+model.SingularHumanName_())
+				return
+			}
+
+			populate, err := getPopulate(r, model.Attributes_())
+			if err != nil {
+				respondError(w, err, "Could not find "+model.SingularHumanName_())
+				return
+			}
+
+			fields, err := getFields(r, model.Attributes_(), populate)
+			if err != nil {
+				respondError(w, err, "Could not find "+model.SingularHumanName_())
 				return
 			}
 
 			dbParams := common.NewDBParamsBuilder().
-				Where(model.IdAttribute_().(common.IntAttribute).Eq(id)).
+				Select(fields...).
+				Populate(populate...).
+				Context(r.Context()).
 				Build()
 
-			entity, err := database.FindOne(modelUid, dbParams)
+			if draftAndPublish && !options.drafts {
+				dbParams.Where = append(dbParams.Where, publishedAt.NotNull())
+			}
+
+			locale, hasLocale, err := getLocale(r)
+			if localized && err != nil {
+				respondError(w, err, "Could not find "+model.SingularHumanName_())
+				return
+			}
+
+			var entity common.Entity
+			if localized && hasLocale {
+				entity, err = i18n.Translation(cosys, modelUid, id, locale, dbParams)
+			} else {
+				dbParams.Where = append(dbParams.Where, model.IdAttribute_().(common.IntAttribute).Eq(id))
+				entity, err = database.FindOne(modelUid, dbParams)
+			}
 			if err != nil {
-				response.RespondError(w, "Could not find "+model.SingularHumanName_(), http.StatusBadRequest)
+				respondError(w, err, "Could not find "+model.SingularHumanName_())
 				return
 			}
 
@@ -96,13 +180,29 @@ func Create(modelUid string) common.ActionFunc {
 			entity := model.New_()
 
 			if err := json.NewDecoder(r.Body).Decode(entity); err != nil {
-				response.RespondError(w, "Could not create "+model.SingularHumanName_(), http.StatusBadRequest)
+				respondError(w, err, "Could not create "+model.SingularHumanName_())
 				return
 			}
 
-			newEntity, err := database.Create(modelUid, entity, common.NewDBParams())
+			columns := getColumns(model)
+
+			if i18n.IsLocalized(model) {
+				if err := setLocalization(r, model, entity); err != nil {
+					respondError(w, err, "Could not create "+model.SingularHumanName_())
+					return
+				}
+
+				columns = append(columns, getLocalizationColumns(model)...)
+			}
+
+			dbParams := common.NewDBParamsBuilder().
+				Insert(columns...).
+				Context(r.Context()).
+				Build()
+
+			newEntity, err := database.Create(modelUid, entity, dbParams)
 			if err != nil {
-				response.RespondError(w, "Could not create "+model.SingularHumanName_(), http.StatusBadRequest)
+				respondError(w, err, "Could not create "+model.SingularHumanName_())
 				return
 			}
 
@@ -127,24 +227,26 @@ func Update(modelUid string) common.ActionFunc {
 		return func(w http.ResponseWriter, r *http.Request) {
 			id, err := getId(r)
 			if err != nil {
-				response.RespondError(w, "Could not update "+model.SingularHumanName_(), http.StatusBadRequest)
+				respondError(w, err, "Could not update "+model.SingularHumanName_())
 				return
 			}
 
 			entity := model.New_()
 
 			if err := json.NewDecoder(r.Body).Decode(entity); err != nil {
-				response.RespondError(w, "Could not update "+model.SingularHumanName_(), http.StatusBadRequest)
+				respondError(w, err, "Could not update "+model.SingularHumanName_())
 				return
 			}
 
 			dbParams := common.NewDBParamsBuilder().
+				Update(getColumns(model)...).
 				Where(model.IdAttribute_().(common.IntAttribute).Eq(id)).
+				Context(r.Context()).
 				Build()
 
 			newEntity, err := database.Update(modelUid, entity, dbParams)
 			if err != nil {
-				response.RespondError(w, "Could not update "+model.SingularHumanName_(), http.StatusBadRequest)
+				respondError(w, err, "Could not update "+model.SingularHumanName_())
 				return
 			}
 
@@ -169,17 +271,18 @@ func Delete(modelUid string) common.ActionFunc {
 		return func(w http.ResponseWriter, r *http.Request) {
 			id, err := getId(r)
 			if err != nil {
-				response.RespondError(w, "Could not delete "+model.SingularHumanName_(), http.StatusBadRequest)
+				respondError(w, err, "Could not delete "+model.SingularHumanName_())
 				return
 			}
 
 			dbParams := common.NewDBParamsBuilder().
 				Where(model.IdAttribute_().(common.IntAttribute).Eq(id)).
+				Context(r.Context()).
 				Build()
 
 			entity, err := database.Delete(modelUid, dbParams)
 			if err != nil {
-				response.RespondError(w, "Could not delete "+model.SingularHumanName_(), http.StatusBadRequest)
+				respondError(w, err, "Could not delete "+model.SingularHumanName_())
 				return
 			}
 
@@ -3,11 +3,28 @@ package routes
 import (
 	"fmt"
 	"github.com/cosys-io/cosys/common"
+	"github.com/cosys-io/cosys/modules/cms/i18n"
+	"github.com/cosys-io/cosys/modules/cms/schema"
+	"github.com/cosys-io/cosys/modules/server/response"
 	"net/http"
+	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 )
 
+// respondError responds with the given error if it is a typed error,
+// so that typed errors returned by the database or lifecycle hooks are mapped to their status and error code,
+// or with the given message and 400 Bad Request otherwise.
+func respondError(w http.ResponseWriter, err error, message string) {
+	if _, ok := common.AsTypedError(err); ok {
+		response.RespondErr(w, err)
+		return
+	}
+
+	response.RespondError(w, message, http.StatusBadRequest)
+}
+
 // getParams returns the DBParams from the query string.
 func getParams(r *http.Request, attrs []common.Attribute) (common.DBParams, error) {
 	pageSize, err := getPageSize(r)
@@ -27,12 +44,12 @@ func getParams(r *http.Request, attrs []common.Attribute) (common.DBParams, erro
 
 	var filter []common.Condition
 
-	fields, err := getFields(r, attrs)
+	populate, err := getPopulate(r, attrs)
 	if err != nil {
 		return common.DBParams{}, err
 	}
 
-	populate, err := getPopulate(r, attrs)
+	fields, err := getFields(r, attrs, populate)
 	if err != nil {
 		return common.DBParams{}, err
 	}
@@ -44,12 +61,13 @@ func getParams(r *http.Request, attrs []common.Attribute) (common.DBParams, erro
 		Where(filter...).
 		Select(fields...).
 		Populate(populate...).
+		Context(r.Context()).
 		Build(), nil
 }
 
 // getPageSize returns the page size value from the query string.
 func getPageSize(r *http.Request) (int64, error) {
-	pageSizeString := r.PathValue("pageSize")
+	pageSizeString := r.URL.Query().Get("pageSize")
 	if pageSizeString != "" {
 		pageSize, err := strconv.ParseInt(pageSizeString, 10, 64)
 		if err != nil {
@@ -64,7 +82,7 @@ func getPageSize(r *http.Request) (int64, error) {
 
 // getPage returns the page number value from the query string.
 func getPage(r *http.Request) (int, error) {
-	pageString := r.PathValue("page")
+	pageString := r.URL.Query().Get("page")
 	if pageString != "" {
 		page, err := strconv.Atoi(pageString)
 		if err != nil {
@@ -79,7 +97,7 @@ func getPage(r *http.Request) (int, error) {
 
 // getSort returns the order conditions from the query string.
 func getSort(r *http.Request, attrs []common.Attribute) ([]*common.Order, error) {
-	sortSliceString := r.PathValue("sort")
+	sortSliceString := r.URL.Query().Get("sort")
 	if sortSliceString != "" {
 		sortStrings := strings.Split(sortSliceString, ",")
 		sort := make([]*common.Order, len(sortStrings))
@@ -121,8 +139,10 @@ func getSort(r *http.Request, attrs []common.Attribute) ([]*common.Order, error)
 }
 
 // getFields returns the return fields from the query string.
-func getFields(r *http.Request, attrs []common.Attribute) ([]common.Attribute, error) {
-	fieldSliceString := r.PathValue("fields")
+// If no fields are specified, all attributes are returned,
+// except for component and dynamic zone attributes that are not populated.
+func getFields(r *http.Request, attrs []common.Attribute, populate []common.Attribute) ([]common.Attribute, error) {
+	fieldSliceString := r.URL.Query().Get("fields")
 	if fieldSliceString != "" {
 		fieldStrings := strings.Split(fieldSliceString, ",")
 		fields := make([]common.Attribute, len(fieldStrings))
@@ -144,16 +164,35 @@ func getFields(r *http.Request, attrs []common.Attribute) ([]common.Attribute, e
 
 		return fields, nil
 	} else {
-		return attrs, nil
+		fields := make([]common.Attribute, 0, len(attrs))
+		for _, attr := range attrs {
+			if common.IsEmbedded(attr) && !slices.Contains(populate, attr) {
+				continue
+			}
+
+			fields = append(fields, attr)
+		}
+
+		return fields, nil
 	}
 }
 
 // getPopulate returns the fields to populate from the query string.
+// Populating "*" populates all component and dynamic zone attributes.
 func getPopulate(r *http.Request, attrs []common.Attribute) ([]common.Attribute, error) {
-	populateSliceString := r.PathValue("populate")
-	if populateSliceString != "" {
+	populateSliceString := r.URL.Query().Get("populate")
+	if populateSliceString == "*" {
+		var populate []common.Attribute
+		for _, attr := range attrs {
+			if common.IsEmbedded(attr) {
+				populate = append(populate, attr)
+			}
+		}
+
+		return populate, nil
+	} else if populateSliceString != "" {
 		populateStrings := strings.Split(populateSliceString, ",")
-		populate := make([]common.Attribute, len(populateStrings))
+		populate := make([]common.Attribute, 0, len(populateStrings))
 
 		for _, populateString := range populateStrings {
 			var populateAttr common.Attribute
@@ -191,3 +230,114 @@ func getId(r *http.Request) (int, error) {
 
 	return id, nil
 }
+
+// getPublishedAt returns the publishedAt attribute of the given model,
+// and whether the model has draft and publish enabled.
+func getPublishedAt(model common.Model) (common.Attribute, bool) {
+	modelSchema, ok := model.Schema_().(*schema.ModelSchema)
+	if !ok || !modelSchema.DraftAndPublish() {
+		return nil, false
+	}
+
+	for _, attr := range model.Attributes_() {
+		if attr.CamelName() == schema.PublishedAtSchema.Name() {
+			return attr, true
+		}
+	}
+
+	return nil, false
+}
+
+// getLocale returns the locale from the query string for localized models,
+// or the default locale if no locale is specified,
+// and whether the locale was specified.
+func getLocale(r *http.Request) (string, bool, error) {
+	locale := r.URL.Query().Get("locale")
+	if locale == "" {
+		return i18n.DefaultLocale(), false, nil
+	}
+
+	if err := i18n.CheckLocale(locale); err != nil {
+		return "", false, err
+	}
+
+	return locale, true, nil
+}
+
+// setLocalization sets the locale of a new entry of a localized model to the locale from the query string,
+// the locale in the request body, or the default locale, and marks the entry as an original entry.
+func setLocalization(r *http.Request, model common.Model, entity common.Entity) error {
+	locale, hasLocale, err := getLocale(r)
+	if err != nil {
+		return err
+	}
+
+	for _, attr := range getLocalizationColumns(model) {
+		switch attr.CamelName() {
+		case schema.LocaleSchema.Name():
+			if !hasLocale {
+				if bodyLocale := reflect.Indirect(reflect.ValueOf(entity)).FieldByName(attr.PascalName()); bodyLocale.IsValid() && bodyLocale.String() != "" {
+					locale = bodyLocale.String()
+				}
+			}
+
+			if err = i18n.CheckLocale(locale); err != nil {
+				return err
+			}
+
+			if err = common.SetEntityField(entity, attr.PascalName(), locale); err != nil {
+				return err
+			}
+		case schema.LocalizationIdSchema.Name():
+			if err = common.SetEntityField(entity, attr.PascalName(), 0); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// getColumns returns the attributes of the given model that are set by create and update queries,
+// excluding the publishedAt attribute which can only be set by publishing and unpublishing,
+// the locale and localizationId attributes which can only be set when creating entries,
+// and the deletedAt attribute which can only be set by deleting and restoring.
+func getColumns(model common.Model) []common.Attribute {
+	publishedAt, draftAndPublish := getPublishedAt(model)
+	localized := i18n.IsLocalized(model)
+	deletedAt, softDelete := common.SoftDeleteAttribute(model)
+
+	columns := make([]common.Attribute, 0, len(model.Attributes_()))
+	for _, attr := range model.Attributes_()[1:] {
+		if draftAndPublish && attr == publishedAt {
+			continue
+		}
+		if localized && isLocalizationAttribute(attr) {
+			continue
+		}
+		if softDelete && attr == deletedAt {
+			continue
+		}
+
+		columns = append(columns, attr)
+	}
+
+	return columns
+}
+
+// isLocalizationAttribute returns whether the given attribute is the locale or localizationId attribute.
+func isLocalizationAttribute(attr common.Attribute) bool {
+	return attr.CamelName() == schema.LocaleSchema.Name() || attr.CamelName() == schema.LocalizationIdSchema.Name()
+}
+
+// getLocalizationColumns returns the locale and localizationId attributes of the given model.
+func getLocalizationColumns(model common.Model) []common.Attribute {
+	var columns []common.Attribute
+	for _, attr := range model.Attributes_() {
+		if isLocalizationAttribute(attr) {
+			columns = append(columns, attr)
+		}
+	}
+
+	return columns
+}
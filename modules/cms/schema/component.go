@@ -0,0 +1,116 @@
+package schema
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"sync"
+
+	"github.com/iancoleman/strcase"
+)
+
+var (
+	componentsMutex sync.RWMutex
+	components      = map[string]*ModelSchema{} // components are the registered component schemas by uid.
+)
+
+// componentUidRegexp matches component uids in the form category.name.
+var componentUidRegexp = regexp.MustCompile(`^([\w-]+)\.([\w-]+)$`)
+
+// ComponentUid returns the given component uid in the form category.name, with the category and name in lower camel case,
+// so that the uids given on component and dynamic zone attributes match the uids of the generated components.
+// Returns an error if the uid is not in the form category.name.
+func ComponentUid(uid string) (string, error) {
+	matches := componentUidRegexp.FindStringSubmatch(uid)
+	if matches == nil {
+		return "", fmt.Errorf("invalid component uid: %s", uid)
+	}
+
+	return strcase.ToLowerCamel(matches[1]) + "." + strcase.ToLowerCamel(matches[2]), nil
+}
+
+// AddComponents registers component schemas under their uids,
+// and returns an error if a schema is not a component schema or if any uid is already registered.
+// The operation is atomic, either all or no schemas will be registered.
+// Safe for concurrent use.
+func AddComponents(schemas ...*ModelSchema) error {
+	newComponents := make(map[string]*ModelSchema, len(schemas))
+	for _, schema := range schemas {
+		if schema == nil {
+			return fmt.Errorf("component is nil")
+		}
+
+		if schema.modelType != "component" {
+			return fmt.Errorf("schema is not a component schema: %s", schema.singularName)
+		}
+
+		uid := schema.Uid()
+		if _, dup := newComponents[uid]; dup {
+			return fmt.Errorf("duplicate component: %s", uid)
+		}
+
+		newComponents[uid] = schema
+	}
+
+	componentsMutex.Lock()
+	defer componentsMutex.Unlock()
+
+	for uid := range newComponents {
+		if _, dup := components[uid]; dup {
+			return fmt.Errorf("duplicate component: %s", uid)
+		}
+	}
+
+	maps.Copy(components, newComponents)
+
+	return nil
+}
+
+// Component returns the component schema with the given uid.
+// Safe for concurrent use.
+func Component(uid string) (*ModelSchema, error) {
+	componentsMutex.RLock()
+	defer componentsMutex.RUnlock()
+
+	component, ok := components[uid]
+	if !ok {
+		return nil, fmt.Errorf("component not found: %s", uid)
+	}
+
+	return component, nil
+}
+
+// AllComponents returns a map of all registered component schemas by uid.
+// Safe for concurrent use, returns a copy of the underlying map.
+func AllComponents() map[string]*ModelSchema {
+	componentsMutex.RLock()
+	defer componentsMutex.RUnlock()
+
+	return maps.Clone(components)
+}
+
+// CheckComponents returns an error if any component or dynamic zone attribute of the given schema
+// refers to a component that has not been registered.
+func CheckComponents(schema *ModelSchema) error {
+	for _, attr := range schema.attributes {
+		attrCMS, ok := attr.(*AttributeSchema)
+		if !ok {
+			continue
+		}
+
+		switch attrCMS.detailedDataType {
+		case "Component":
+			if _, err := Component(attrCMS.component); err != nil {
+				return fmt.Errorf("%s: %w", attrCMS.name, err)
+			}
+		case "DynamicZone":
+			for _, uid := range attrCMS.components {
+				if _, err := Component(uid); err != nil {
+					return fmt.Errorf("%s: %w", attrCMS.name, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/cosys-io/cosys/common"
 	"io"
+	"slices"
 )
 
 // ParseSchema parses json from a reader and scans it into a ModelSchema.
@@ -29,12 +30,18 @@ func ParseSchema(schema *ModelSchema, reader io.Reader) error {
 // which are then replaced with their respective default values.
 type modelParseable struct {
 	ModelType      string           `yaml:"modelType" json:"modelType"`
+	Category       string           `yaml:"category" json:"category"`
 	CollectionName string           `yaml:"collectionName" json:"collectionName"`
 	DisplayName    string           `yaml:"displayName" json:"displayName"`
 	SingularName   string           `yaml:"singularName" json:"singularName"`
 	PluralName     string           `yaml:"pluralName" json:"pluralName"`
 	Description    string           `yaml:"description" json:"description"`
 	Attributes     []*attrParseable `yaml:"attributes" json:"attributes"`
+
+	DraftAndPublish bool `yaml:"draftAndPublish" json:"draftAndPublish"`
+	Versioned       bool `yaml:"versioned" json:"versioned"`
+	Localized       bool `yaml:"localized" json:"localized"`
+	SoftDelete      bool `yaml:"softDelete" json:"softDelete"`
 }
 
 // Schema returns the corresponding ModelSchema with default values.
@@ -54,6 +61,15 @@ func (m modelParseable) Schema() (*ModelSchema, error) {
 	if m.PluralName == "" {
 		return nil, fmt.Errorf("model has no plural name: %s", m.DisplayName)
 	}
+	if m.ModelType == "component" && m.Category == "" {
+		return nil, fmt.Errorf("component has no category: %s", m.DisplayName)
+	}
+	if m.ModelType == "component" {
+		uid := m.Category + "." + m.SingularName
+		if normalized, err := ComponentUid(uid); err != nil || normalized != uid {
+			return nil, fmt.Errorf("invalid component uid, must be in the form category.name in lower camel case: %s", uid)
+		}
+	}
 
 	var attrs []common.AttributeSchema
 	var index int
@@ -64,7 +80,7 @@ func (m modelParseable) Schema() (*ModelSchema, error) {
 			hasId = true
 		}
 	}
-	if hasId {
+	if hasId || m.ModelType == "component" {
 		attrs = make([]common.AttributeSchema, len(m.Attributes))
 		index = 0
 	} else {
@@ -82,15 +98,31 @@ func (m modelParseable) Schema() (*ModelSchema, error) {
 		index += 1
 	}
 
-	return &ModelSchema{
+	schema := &ModelSchema{
 		modelType:      m.ModelType,
+		category:       m.Category,
 		collectionName: m.CollectionName,
 		displayName:    m.DisplayName,
 		singularName:   m.SingularName,
 		pluralName:     m.PluralName,
 		description:    m.Description,
 		attributes:     attrs,
-	}, nil
+	}
+
+	if m.DraftAndPublish {
+		schema.With(DraftAndPublish)
+	}
+	if m.Versioned {
+		schema.With(Versioned)
+	}
+	if m.Localized {
+		schema.With(Localized)
+	}
+	if m.SoftDelete {
+		schema.With(SoftDelete)
+	}
+
+	return schema, nil
 }
 
 // attrParseable is used to parse attribute schema json with default values.
@@ -114,6 +146,12 @@ type attrParseable struct {
 	Default  *string `yaml:"default" json:"default"`
 	Nullable *bool   `yaml:"nullable" json:"nullable"`
 	Unique   *bool   `yaml:"unique" json:"unique"`
+
+	Component  *string   `yaml:"component" json:"component"`
+	Repeatable *bool     `yaml:"repeatable" json:"repeatable"`
+	Components *[]string `yaml:"components" json:"components"`
+
+	Localized *bool `yaml:"localized" json:"localized"`
 }
 
 // Schema returns the corresponding AttributeSchema with default values.
@@ -127,6 +165,28 @@ func (a attrParseable) Schema() (*AttributeSchema, error) {
 	if a.DetailedDataType == "" {
 		return nil, fmt.Errorf("attribute has no detailed type: %s", a.Name)
 	}
+	if a.DetailedDataType == "Component" && checkDefault("", a.Component) == "" {
+		return nil, fmt.Errorf("component attribute has no component: %s", a.Name)
+	}
+	if a.DetailedDataType == "DynamicZone" && len(checkDefault([]string{}, a.Components)) == 0 {
+		return nil, fmt.Errorf("dynamic zone attribute has no components: %s", a.Name)
+	}
+
+	component := checkDefault("", a.Component)
+	if component != "" {
+		var err error
+		if component, err = ComponentUid(component); err != nil {
+			return nil, fmt.Errorf("%s: %w", a.Name, err)
+		}
+	}
+
+	components := slices.Clone(checkDefault([]string{}, a.Components))
+	for index, uid := range components {
+		var err error
+		if components[index], err = ComponentUid(uid); err != nil {
+			return nil, fmt.Errorf("%s: %w", a.Name, err)
+		}
+	}
 
 	return &AttributeSchema{
 		name:               a.Name,
@@ -146,6 +206,12 @@ func (a attrParseable) Schema() (*AttributeSchema, error) {
 		defaultValue: checkDefault("", a.Default),
 		nullable:     checkDefault(true, a.Nullable),
 		unique:       checkDefault(false, a.Unique),
+
+		component:  component,
+		repeatable: checkDefault(false, a.Repeatable),
+		components: components,
+
+		localized: checkDefault(true, a.Localized),
 	}, nil
 }
 
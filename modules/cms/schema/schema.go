@@ -6,18 +6,34 @@ import "github.com/cosys-io/cosys/common"
 // with added getter methods for display name and description.
 type ModelSchema struct {
 	modelType      string
+	category       string
 	collectionName string
 	displayName    string
 	singularName   string
 	pluralName     string
 	description    string
 	attributes     []common.AttributeSchema
+
+	draftAndPublish bool
+	versioned       bool
+	localized       bool
+	softDelete      bool
 }
 
 func (m ModelSchema) ModelType() string {
 	return m.modelType
 }
 
+// Category returns the category of a component schema, or an empty string for collection types.
+func (m ModelSchema) Category() string {
+	return m.category
+}
+
+// Uid returns the uid of a component schema, in the form category.singularName.
+func (m ModelSchema) Uid() string {
+	return m.category + "." + m.singularName
+}
+
 func (m ModelSchema) CollectionName() string {
 	return m.collectionName
 }
@@ -42,6 +58,46 @@ func (m ModelSchema) Attributes() []common.AttributeSchema {
 	return m.attributes
 }
 
+// DraftAndPublish returns whether entries of the model are drafts until published.
+func (m ModelSchema) DraftAndPublish() bool {
+	return m.draftAndPublish
+}
+
+// Versioned returns whether snapshots of entries of the model are recorded before they are updated or deleted.
+func (m ModelSchema) Versioned() bool {
+	return m.versioned
+}
+
+// Localized returns whether entries of the model have translations in multiple locales.
+func (m ModelSchema) Localized() bool {
+	return m.localized
+}
+
+// SoftDelete returns whether deleted entries of the model are kept until purged.
+func (m ModelSchema) SoftDelete() bool {
+	return m.softDelete
+}
+
+// Attribute returns the attribute schema with the given name.
+func (m ModelSchema) Attribute(name string) (*AttributeSchema, bool) {
+	for _, attr := range m.attributes {
+		if attrCMS, ok := attr.(*AttributeSchema); ok && attrCMS.name == name {
+			return attrCMS, true
+		}
+	}
+
+	return nil, false
+}
+
+// With applies the given configurations to the model schema and returns it.
+func (m *ModelSchema) With(opts ...ModelOption) *ModelSchema {
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
 // AttributeSchema is an implementation of the AttributeSchema common interface,
 // with the added getter method for whether the attribute is shown in table.
 type AttributeSchema struct {
@@ -62,6 +118,12 @@ type AttributeSchema struct {
 	defaultValue string
 	nullable     bool
 	unique       bool
+
+	component  string
+	repeatable bool
+	components []string
+
+	localized bool
 }
 
 func (a AttributeSchema) Name() string {
@@ -124,6 +186,26 @@ func (a AttributeSchema) Unique() bool {
 	return a.unique
 }
 
+// Component returns the uid of the component held by a component attribute.
+func (a AttributeSchema) Component() string {
+	return a.component
+}
+
+// Repeatable returns whether a component attribute holds a list of components.
+func (a AttributeSchema) Repeatable() bool {
+	return a.repeatable
+}
+
+// Components returns the uids of the components allowed in a dynamic zone attribute.
+func (a AttributeSchema) Components() []string {
+	return a.components
+}
+
+// Localized returns whether the value of the attribute differs between translations of an entry of a localized model.
+func (a AttributeSchema) Localized() bool {
+	return a.localized
+}
+
 // NewModelSchema returns a new ModelSchema from the given names, descriptions and attribute schemas.
 func NewModelSchema(collection, display, singular, plural, description string, attrs ...*AttributeSchema) *ModelSchema {
 	commonAttrs := make([]common.AttributeSchema, len(attrs))
@@ -142,6 +224,26 @@ func NewModelSchema(collection, display, singular, plural, description string, a
 	}
 }
 
+// NewComponentSchema returns a new component ModelSchema from the given names, descriptions and attribute schemas.
+// Components are not stored in their own collection, the collection name is only used for generated code.
+func NewComponentSchema(collection, display, category, name, description string, attrs ...*AttributeSchema) *ModelSchema {
+	commonAttrs := make([]common.AttributeSchema, len(attrs))
+	for i, attr := range attrs {
+		commonAttrs[i] = attr
+	}
+
+	return &ModelSchema{
+		modelType:      "component",
+		category:       category,
+		collectionName: collection,
+		displayName:    display,
+		singularName:   name,
+		pluralName:     name,
+		description:    description,
+		attributes:     commonAttrs,
+	}
+}
+
 // NewAttrSchema returns a new AttributeSchema from the given names, types and configurations.
 func NewAttrSchema(attrName, simpleType, detailedType string, opts ...AttrOption) *AttributeSchema {
 	schema := &AttributeSchema{
@@ -160,6 +262,10 @@ func NewAttrSchema(attrName, simpleType, detailedType string, opts ...AttrOption
 		defaultValue:       "",
 		nullable:           true,
 		unique:             false,
+		component:          "",
+		repeatable:         false,
+		components:         nil,
+		localized:          true,
 	}
 
 	for _, opt := range opts {
@@ -169,6 +275,51 @@ func NewAttrSchema(attrName, simpleType, detailedType string, opts ...AttrOption
 	return schema
 }
 
+// ModelOption is a configuration for a model schema.
+type ModelOption func(*ModelSchema)
+
+// DraftAndPublish specifies that entries of the model are drafts until published,
+// and adds the publishedAt attribute to the model schema if it does not exist.
+var DraftAndPublish ModelOption = func(schema *ModelSchema) {
+	schema.draftAndPublish = true
+
+	if _, ok := schema.Attribute(PublishedAtSchema.name); !ok {
+		publishedAt := PublishedAtSchema
+		schema.attributes = append(schema.attributes, &publishedAt)
+	}
+}
+
+// Versioned specifies that snapshots of entries of the model are recorded before they are updated or deleted.
+var Versioned ModelOption = func(schema *ModelSchema) {
+	schema.versioned = true
+}
+
+// Localized specifies that entries of the model have translations in multiple locales,
+// and adds the locale and localizationId attributes to the model schema if they do not exist.
+var Localized ModelOption = func(schema *ModelSchema) {
+	schema.localized = true
+
+	if _, ok := schema.Attribute(LocaleSchema.name); !ok {
+		locale := LocaleSchema
+		schema.attributes = append(schema.attributes, &locale)
+	}
+	if _, ok := schema.Attribute(LocalizationIdSchema.name); !ok {
+		localizationId := LocalizationIdSchema
+		schema.attributes = append(schema.attributes, &localizationId)
+	}
+}
+
+// SoftDelete specifies that deleted entries of the model are kept until purged,
+// and adds the deletedAt attribute to the model schema if it does not exist.
+var SoftDelete ModelOption = func(schema *ModelSchema) {
+	schema.softDelete = true
+
+	if _, ok := schema.Attribute(DeletedAtSchema.name); !ok {
+		deletedAt := DeletedAtSchema
+		schema.attributes = append(schema.attributes, &deletedAt)
+	}
+}
+
 // AttrOption is a configuration for an attribute schema.
 type AttrOption func(*AttributeSchema)
 
@@ -244,6 +395,30 @@ var Unique AttrOption = func(schema *AttributeSchema) {
 	schema.unique = true
 }
 
+// ComponentOf specifies that a component attribute holds the component with the given uid.
+func ComponentOf(uid string) AttrOption {
+	return func(schema *AttributeSchema) {
+		schema.component = uid
+	}
+}
+
+// Repeatable specifies that a component attribute holds a list of components.
+var Repeatable AttrOption = func(schema *AttributeSchema) {
+	schema.repeatable = true
+}
+
+// Components specifies that a dynamic zone attribute can hold the components with the given uids.
+func Components(uids ...string) AttrOption {
+	return func(schema *AttributeSchema) {
+		schema.components = uids
+	}
+}
+
+// NotLocalized specifies that the value of an attribute is shared between translations of an entry.
+var NotLocalized AttrOption = func(schema *AttributeSchema) {
+	schema.localized = false
+}
+
 // IdSchema is the schema for the id attribute.
 var IdSchema = AttributeSchema{
 	name:               "id",
@@ -263,6 +438,8 @@ var IdSchema = AttributeSchema{
 	defaultValue: "",
 	nullable:     false,
 	unique:       true,
+
+	localized: true,
 }
 
 // UuidSchema is the schema for the uuid attribute.
@@ -284,4 +461,99 @@ var UuidSchema = AttributeSchema{
 	defaultValue: "",
 	nullable:     false,
 	unique:       true,
+
+	localized: true,
+}
+
+// PublishedAtSchema is the schema for the publishedAt attribute of draft and publish models.
+var PublishedAtSchema = AttributeSchema{
+	name:               "publishedAt",
+	simplifiedDataType: "DateTime",
+	detailedDataType:   "DateTime",
+
+	shownInTable: false,
+	required:     false,
+	max:          2147483647,
+	min:          -2147483648,
+	maxLength:    -1,
+	minLength:    -1,
+	private:      false,
+	editable:     false,
+	enum:         nil,
+
+	defaultValue: "",
+	nullable:     true,
+	unique:       false,
+
+	localized: true,
+}
+
+// LocaleSchema is the schema for the locale attribute of localized models.
+var LocaleSchema = AttributeSchema{
+	name:               "locale",
+	simplifiedDataType: "String",
+	detailedDataType:   "String",
+
+	shownInTable: true,
+	required:     false,
+	max:          2147483647,
+	min:          -2147483648,
+	maxLength:    -1,
+	minLength:    -1,
+	private:      false,
+	editable:     false,
+	enum:         nil,
+
+	defaultValue: "",
+	nullable:     false,
+	unique:       false,
+
+	localized: true,
+}
+
+// LocalizationIdSchema is the schema for the localizationId attribute of localized models,
+// which holds the id of the original entry of a translation, or 0 for original entries.
+var LocalizationIdSchema = AttributeSchema{
+	name:               "localizationId",
+	simplifiedDataType: "Number",
+	detailedDataType:   "Int",
+
+	shownInTable: false,
+	required:     false,
+	max:          2147483647,
+	min:          -2147483648,
+	maxLength:    -1,
+	minLength:    -1,
+	private:      false,
+	editable:     false,
+	enum:         nil,
+
+	defaultValue: "0",
+	nullable:     false,
+	unique:       false,
+
+	localized: true,
+}
+
+// DeletedAtSchema is the schema for the deletedAt attribute of soft delete models.
+var DeletedAtSchema = AttributeSchema{
+	name:               common.DeletedAtName,
+	simplifiedDataType: "DateTime",
+	detailedDataType:   "DateTime",
+
+	shownInTable: false,
+	required:     false,
+	max:          2147483647,
+	min:          -2147483648,
+	maxLength:    -1,
+	minLength:    -1,
+	private:      false,
+	editable:     false,
+	enum:         nil,
+
+	defaultValue: "",
+	nullable:     true,
+	unique:       false,
+
+	localized: true,
 }
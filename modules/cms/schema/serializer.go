@@ -23,12 +23,18 @@ func ToModelSerializable(schema common.ModelSchema) (ModelSerializable, error) {
 
 	return ModelSerializable{
 		ModelType:      schemaCMS.modelType,
+		Category:       schemaCMS.category,
 		CollectionName: schemaCMS.collectionName,
 		DisplayName:    schemaCMS.displayName,
 		SingularName:   schemaCMS.singularName,
 		PluralName:     schemaCMS.pluralName,
 		Description:    schemaCMS.description,
 		Attributes:     attrsSerializable,
+
+		DraftAndPublish: schemaCMS.draftAndPublish,
+		Versioned:       schemaCMS.versioned,
+		Localized:       schemaCMS.localized,
+		SoftDelete:      schemaCMS.softDelete,
 	}, nil
 }
 
@@ -52,18 +58,30 @@ func ToAttrSerializable(schema *AttributeSchema) *AttrSerializable {
 		DefaultValue: schema.defaultValue,
 		Nullable:     schema.nullable,
 		Unique:       schema.unique,
+
+		Component:  schema.component,
+		Repeatable: schema.repeatable,
+		Components: schema.components,
+
+		Localized: schema.localized,
 	}
 }
 
 // ModelSerializable is used to serialize model schema into json.
 type ModelSerializable struct {
 	ModelType      string              `json:"modelType"`
+	Category       string              `json:"category,omitempty"`
 	CollectionName string              `json:"collectionName"`
 	DisplayName    string              `json:"displayName"`
 	SingularName   string              `json:"singularName"`
 	PluralName     string              `json:"pluralName"`
 	Description    string              `json:"description"`
 	Attributes     []*AttrSerializable `json:"attributes"`
+
+	DraftAndPublish bool `json:"draftAndPublish"`
+	Versioned       bool `json:"versioned"`
+	Localized       bool `json:"localized"`
+	SoftDelete      bool `json:"softDelete"`
 }
 
 // AttrSerializable is used to serialize attribute schema into json.
@@ -85,4 +103,10 @@ type AttrSerializable struct {
 	DefaultValue string `json:"default"`
 	Nullable     bool   `json:"nullable"`
 	Unique       bool   `json:"unique"`
+
+	Component  string   `json:"component,omitempty"`
+	Repeatable bool     `json:"repeatable,omitempty"`
+	Components []string `json:"components,omitempty"`
+
+	Localized bool `json:"localized"`
 }
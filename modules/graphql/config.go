@@ -0,0 +1,49 @@
+package graphql
+
+import (
+	"github.com/cosys-io/cosys/common"
+	"github.com/spf13/viper"
+)
+
+// Keys of the project configurations of the graphql module.
+const (
+	PathKey        = "graphql_path"
+	PageSizeKey    = "graphql_page_size"
+	MaxPageSizeKey = "graphql_max_page_size"
+	MaxDepthKey    = "graphql_max_depth"
+	MutationsKey   = "graphql_mutations"
+	PoliciesKey    = "graphql_policies"
+	MiddlewaresKey = "graphql_middlewares"
+)
+
+// withProjectConfigs returns the given configuration,
+// overridden by the configurations set in the project configurations.
+func withProjectConfigs(config Config) (Config, error) {
+	if err := common.ReadConfigs(); err != nil {
+		return Config{}, err
+	}
+
+	if viper.IsSet(PathKey) {
+		config.Path = viper.GetString(PathKey)
+	}
+	if viper.IsSet(PageSizeKey) {
+		config.DefaultPageSize = viper.GetInt(PageSizeKey)
+	}
+	if viper.IsSet(MaxPageSizeKey) {
+		config.MaxPageSize = viper.GetInt(MaxPageSizeKey)
+	}
+	if viper.IsSet(MaxDepthKey) {
+		config.MaxDepth = viper.GetInt(MaxDepthKey)
+	}
+	if viper.IsSet(MutationsKey) {
+		config.Mutations = viper.GetBool(MutationsKey)
+	}
+	if viper.IsSet(PoliciesKey) {
+		config.Policies = viper.GetStringSlice(PoliciesKey)
+	}
+	if viper.IsSet(MiddlewaresKey) {
+		config.Middlewares = viper.GetStringSlice(MiddlewaresKey)
+	}
+
+	return config, nil
+}
@@ -0,0 +1,38 @@
+package internal
+
+import (
+	"fmt"
+)
+
+// Error is a graphql error, as reported in the errors of a response.
+type Error struct {
+	Message    string         `json:"message"`
+	Locations  []Location     `json:"locations,omitempty"`
+	Path       []any          `json:"path,omitempty"`
+	Extensions map[string]any `json:"extensions,omitempty"`
+
+	err error
+}
+
+// Error returns the error message.
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the error returned by a resolver, if any.
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// newError returns a new graphql error with the given message and locations.
+func newError(message string, locations ...Location) *Error {
+	return &Error{
+		Message:   message,
+		Locations: locations,
+	}
+}
+
+// errorf returns a new graphql error at the given location with the formatted message.
+func errorf(location Location, format string, args ...any) *Error {
+	return newError(fmt.Sprintf(format, args...), location)
+}
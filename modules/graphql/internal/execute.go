@@ -0,0 +1,491 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Request is a graphql request.
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"` // Variables are json values, with numbers as json.Number.
+}
+
+// Response is a graphql response.
+type Response struct {
+	Errors []*Error        `json:"errors,omitempty"`
+	Data   json.RawMessage `json:"data,omitempty"` // Data is omitted if the request failed before execution.
+}
+
+// ErrorFormatter converts an error returned by a resolver to a graphql error with a message and extensions.
+type ErrorFormatter func(err error) *Error
+
+// ExecuteParams are the inputs of Execute.
+type ExecuteParams struct {
+	Context     context.Context
+	Schema      *Schema
+	Document    *Document
+	Request     Request
+	FormatError ErrorFormatter // FormatError formats resolver errors, or uses their messages if nil.
+	AllowedType string         // AllowedType restricts the operation to queries or mutations, or any if empty.
+}
+
+// Execute executes the operation of the request in the given validated document.
+func Execute(params ExecuteParams) Response {
+	operation, err := getOperation(params.Document, params.Request.OperationName)
+	if err != nil {
+		return Response{Errors: []*Error{err}}
+	}
+
+	if params.AllowedType != "" && operation.Type != params.AllowedType {
+		return Response{Errors: []*Error{
+			errorf(operation.Location, "%s operations are not allowed", operation.Type),
+		}}
+	}
+
+	variables, errs := coerceVariables(params.Schema, operation, params.Request.Variables)
+	if len(errs) > 0 {
+		return Response{Errors: errs}
+	}
+
+	e := &executor{
+		ctx:         params.Context,
+		schema:      params.Schema,
+		document:    params.Document,
+		variables:   variables,
+		formatError: params.FormatError,
+	}
+
+	root := params.Schema.Query
+	if operation.Type == "mutation" {
+		root = params.Schema.Mutation
+	}
+
+	data, ok := e.executeSelectionSet(operation.SelectionSet, root, nil, nil)
+
+	response := Response{
+		Errors: e.errors,
+		Data:   json.RawMessage("null"),
+	}
+
+	if ok {
+		encoded, err := json.Marshal(data)
+		if err != nil {
+			response.Errors = append(response.Errors, newError(err.Error()))
+			return response
+		}
+
+		response.Data = encoded
+	}
+
+	return response
+}
+
+// getOperation returns the operation of the document with the given name,
+// or its only operation if the name is empty.
+func getOperation(document *Document, name string) (*Operation, *Error) {
+	if name == "" {
+		if len(document.Operations) != 1 {
+			return nil, newError("must provide operation name if query contains multiple operations")
+		}
+
+		return document.Operations[0], nil
+	}
+
+	for _, operation := range document.Operations {
+		if operation.Name == name {
+			return operation, nil
+		}
+	}
+
+	return nil, newError(fmt.Sprintf("unknown operation named %s", name))
+}
+
+// coerceVariables coerces the given variable values to the variable types of the operation.
+func coerceVariables(schema *Schema, operation *Operation, values map[string]any) (map[string]any, []*Error) {
+	var errs []*Error
+	variables := map[string]any{}
+
+	for _, definition := range operation.Variables {
+		variableType, err := schema.TypeOf(definition.Type)
+		if err != nil {
+			errs = append(errs, errorf(definition.Location, "variable $%s: %s", definition.Name, err))
+			continue
+		}
+
+		value, ok := values[definition.Name]
+		if !ok {
+			if definition.Default != nil {
+				if variables[definition.Name], err = coerceLiteral(definition.Default, variableType, map[string]any{}); err != nil {
+					errs = append(errs, errorf(definition.Location, "variable $%s got invalid default value: %s", definition.Name, err))
+				}
+			} else if _, nonNull := variableType.(*NonNull); nonNull {
+				errs = append(errs, errorf(definition.Location, "variable $%s of required type %s was not provided", definition.Name, variableType))
+			}
+
+			continue
+		}
+
+		coerced, err := coerceValue(value, variableType)
+		if err != nil {
+			errs = append(errs, errorf(definition.Location, "variable $%s got invalid value: %s", definition.Name, err))
+			continue
+		}
+
+		variables[definition.Name] = coerced
+	}
+
+	return variables, errs
+}
+
+// executor executes an operation.
+type executor struct {
+	ctx         context.Context
+	schema      *Schema
+	document    *Document
+	variables   map[string]any
+	formatError ErrorFormatter
+	errors      []*Error
+}
+
+// resolverError adds the error returned by the resolver of the field at the given path.
+func (e *executor) resolverError(err error, field *Field, path []any) {
+	var graphqlError *Error
+	if e.formatError != nil {
+		graphqlError = e.formatError(err)
+	} else {
+		graphqlError = newError(err.Error())
+	}
+
+	graphqlError.err = err
+	graphqlError.Locations = []Location{field.Location}
+	graphqlError.Path = append([]any{}, path...)
+	e.errors = append(e.errors, graphqlError)
+}
+
+// fieldError adds an error of the field at the given path, such as an invalid argument or a null non-null value.
+func (e *executor) fieldError(err error, field *Field, path []any) {
+	graphqlError := newError(err.Error(), field.Location)
+	graphqlError.Path = append([]any{}, path...)
+	e.errors = append(e.errors, graphqlError)
+}
+
+// executeSelectionSet executes the selection set on the given object,
+// and returns false if a non-null field is null.
+func (e *executor) executeSelectionSet(selectionSet []Selection, object *Object, source any, path []any) (*OrderedMap, bool) {
+	result := NewOrderedMap()
+
+	keys, fields := e.collectFields(object, selectionSet, map[string]bool{}, nil, map[string][]*Field{})
+	for _, key := range keys {
+		fieldNodes := fields[key]
+		fieldPath := append(append([]any{}, path...), key)
+
+		if fieldNodes[0].Name == "__typename" {
+			result.Set(key, object.Name())
+			continue
+		}
+
+		definition := e.schema.FieldDefinition(object, fieldNodes[0].Name)
+		value, ok := e.executeField(object, source, definition, fieldNodes, fieldPath)
+		if !ok {
+			if _, nonNull := definition.Type.(*NonNull); nonNull {
+				return nil, false
+			}
+
+			value = nil
+		}
+
+		result.Set(key, value)
+	}
+
+	return result, true
+}
+
+// collectFields returns the response keys and fields of a selection set on the given object,
+// with fragments expanded and skipped selections removed.
+func (e *executor) collectFields(object *Object, selectionSet []Selection, visited map[string]bool, keys []string, fields map[string][]*Field) ([]string, map[string][]*Field) {
+	for _, selection := range selectionSet {
+		switch selection := selection.(type) {
+		case *Field:
+			if !e.included(selection.Directives) {
+				continue
+			}
+
+			key := selection.ResponseKey()
+			if _, ok := fields[key]; !ok {
+				keys = append(keys, key)
+			}
+			fields[key] = append(fields[key], selection)
+		case *FragmentSpread:
+			if visited[selection.Name] || !e.included(selection.Directives) {
+				continue
+			}
+			visited[selection.Name] = true
+
+			fragment, ok := e.document.Fragments[selection.Name]
+			if !ok || fragment.TypeCondition != object.Name() {
+				continue
+			}
+
+			keys, fields = e.collectFields(object, fragment.SelectionSet, visited, keys, fields)
+		case *InlineFragment:
+			if !e.included(selection.Directives) {
+				continue
+			}
+
+			if selection.TypeCondition != "" && selection.TypeCondition != object.Name() {
+				continue
+			}
+
+			keys, fields = e.collectFields(object, selection.SelectionSet, visited, keys, fields)
+		}
+	}
+
+	return keys, fields
+}
+
+// included returns whether a selection with the given directives is included, based on @skip and @include.
+func (e *executor) included(directives []*Directive) bool {
+	for _, directive := range directives {
+		args, err := coerceArguments(ifArguments, directive.Arguments, e.variables)
+		if err != nil {
+			continue
+		}
+
+		condition, _ := args["if"].(bool)
+		if directive.Name == "skip" && condition {
+			return false
+		}
+		if directive.Name == "include" && !condition {
+			return false
+		}
+	}
+
+	return true
+}
+
+// executeField resolves and completes a field, and returns false if it is null because of an error.
+func (e *executor) executeField(object *Object, source any, definition *FieldDefinition, fields []*Field, path []any) (any, bool) {
+	field := fields[0]
+
+	args, err := coerceArguments(definition.Arguments, field.Arguments, e.variables)
+	if err != nil {
+		e.fieldError(err, field, path)
+		return nil, false
+	}
+
+	var selection []string
+	if fieldObject, ok := NamedType(definition.Type).(*Object); ok {
+		keys, subFields := e.collectFields(fieldObject, subSelections(fields), map[string]bool{}, nil, map[string][]*Field{})
+		for _, key := range keys {
+			selection = append(selection, subFields[key][0].Name)
+		}
+	}
+
+	var resolved any
+	if definition.Resolve != nil {
+		resolved, err = definition.Resolve(ResolveParams{
+			Context:   e.ctx,
+			Source:    source,
+			Args:      args,
+			Selection: selection,
+			Schema:    e.schema,
+		})
+		if err != nil {
+			e.resolverError(err, field, path)
+			return nil, false
+		}
+	} else if sourceMap, ok := source.(map[string]any); ok {
+		resolved = sourceMap[definition.Name]
+	}
+
+	return e.completeValue(definition.Type, fields, resolved, path)
+}
+
+// subSelections returns the selections of all given fields with the same response key.
+func subSelections(fields []*Field) []Selection {
+	var selections []Selection
+	for _, field := range fields {
+		selections = append(selections, field.SelectionSet...)
+	}
+
+	return selections
+}
+
+// completeValue converts a resolved value to its response value of the given type,
+// and returns false if it is null because of an error or a null non-null value.
+func (e *executor) completeValue(t Type, fields []*Field, value any, path []any) (any, bool) {
+	if nonNull, ok := t.(*NonNull); ok {
+		completed, ok := e.completeValue(nonNull.OfType, fields, value, path)
+		if !ok {
+			return nil, false
+		}
+
+		if completed == nil {
+			e.fieldError(fmt.Errorf("cannot return null for non-nullable field"), fields[0], path)
+			return nil, false
+		}
+
+		return completed, true
+	}
+
+	if isNil(value) {
+		return nil, true
+	}
+
+	switch t := t.(type) {
+	case *List:
+		list := reflect.ValueOf(value)
+		if list.Kind() != reflect.Slice && list.Kind() != reflect.Array {
+			e.fieldError(fmt.Errorf("expected list value for field of type %s", t), fields[0], path)
+			return nil, false
+		}
+
+		items := make([]any, list.Len())
+		for index := range list.Len() {
+			itemPath := append(append([]any{}, path...), index)
+
+			item, ok := e.completeValue(t.OfType, fields, list.Index(index).Interface(), itemPath)
+			if !ok {
+				if _, nonNull := t.OfType.(*NonNull); nonNull {
+					return nil, false
+				}
+
+				item = nil
+			}
+
+			items[index] = item
+		}
+
+		return items, true
+	case *Scalar:
+		serialized, err := t.Serialize(value)
+		if err != nil {
+			e.fieldError(err, fields[0], path)
+			return nil, false
+		}
+
+		return serialized, true
+	case *Enum:
+		name := fmt.Sprint(value)
+		if t.Value(name) == nil {
+			e.fieldError(fmt.Errorf("enum %s cannot represent value: %s", t, name), fields[0], path)
+			return nil, false
+		}
+
+		return name, true
+	case *Object:
+		return e.executeSelectionSet(subSelections(fields), t, value, path)
+	default:
+		e.fieldError(fmt.Errorf("cannot complete value of type %s", t), fields[0], path)
+		return nil, false
+	}
+}
+
+// isNil returns whether the given value is nil, including typed nil pointers, maps and slices.
+func isNil(value any) bool {
+	if value == nil {
+		return true
+	}
+
+	switch reflected := reflect.ValueOf(value); reflected.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+		return reflected.IsNil()
+	default:
+		return false
+	}
+}
+
+// coerceArguments coerces the given arguments to the argument definitions of a field or directive.
+func coerceArguments(definitions []*InputValue, arguments []*Argument, variables map[string]any) (map[string]any, error) {
+	values := map[string]*Value{}
+	for _, argument := range arguments {
+		values[argument.Name] = argument.Value
+	}
+
+	args := map[string]any{}
+	for _, definition := range definitions {
+		value, ok := values[definition.Name]
+		if ok && value.Kind == VariableLiteral {
+			_, ok = variables[value.Raw]
+		}
+
+		if !ok {
+			if err := setDefault(args, definition, nil); err != nil {
+				return nil, fmt.Errorf("argument %s of required type %s was not provided", definition.Name, definition.Type)
+			}
+
+			continue
+		}
+
+		coerced, err := coerceLiteral(value, definition.Type, variables)
+		if err != nil {
+			return nil, fmt.Errorf("argument %s has invalid value %s: %w", definition.Name, printLiteral(value), err)
+		}
+
+		args[definition.Name] = coerced
+	}
+
+	return args, nil
+}
+
+// OrderedMap is a json object that keeps the order of its keys.
+type OrderedMap struct {
+	keys   []string
+	values map[string]any
+}
+
+// NewOrderedMap returns a new empty ordered map.
+func NewOrderedMap() *OrderedMap {
+	return &OrderedMap{
+		values: map[string]any{},
+	}
+}
+
+// Set sets the value of the given key, adding the key at the end if it is new.
+func (m *OrderedMap) Set(key string, value any) {
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+
+	m.values[key] = value
+}
+
+// Get returns the value of the given key, and whether it was set.
+func (m *OrderedMap) Get(key string) (any, bool) {
+	value, ok := m.values[key]
+	return value, ok
+}
+
+// MarshalJSON encodes the map as a json object with its keys in order.
+func (m *OrderedMap) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteByte('{')
+
+	for index, key := range m.keys {
+		if index > 0 {
+			buffer.WriteByte(',')
+		}
+
+		encodedKey, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+
+		encodedValue, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+
+		buffer.Write(encodedKey)
+		buffer.WriteByte(':')
+		buffer.Write(encodedValue)
+	}
+
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,150 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/cosys-io/cosys/common"
+)
+
+// maxBodySize is the maximum size of the body of graphql requests.
+const maxBodySize = 1 << 20
+
+// HandlerOptions are configurations of the graphql handler.
+type HandlerOptions struct {
+	Options
+	MaxDepth int // MaxDepth is the maximum depth of selection sets, or unlimited if not positive.
+}
+
+// Handler returns the ActionFunc serving the graphql api of the models of the cosys app.
+// The schema is built when the server starts, after all models are registered.
+// Queries are served over GET and POST, and mutations only over POST.
+func Handler(options HandlerOptions) common.ActionFunc {
+	return func(cosys *common.Cosys) (http.HandlerFunc, error) {
+		schema, err := BuildSchema(cosys, options.Options)
+		if err != nil {
+			return nil, err
+		}
+
+		formatError := errorFormatter(cosys)
+
+		return func(w http.ResponseWriter, r *http.Request) {
+			request, err := readRequest(w, r)
+			if err != nil {
+				respond(w, Response{Errors: []*Error{newError(err.Error())}}, http.StatusBadRequest)
+				return
+			}
+
+			if request.Query == "" {
+				respond(w, Response{Errors: []*Error{newError("query not found")}}, http.StatusBadRequest)
+				return
+			}
+
+			document, err := Parse(request.Query)
+			if err != nil {
+				var syntaxErr *Error
+				if !errors.As(err, &syntaxErr) {
+					syntaxErr = newError(err.Error())
+				}
+
+				respond(w, Response{Errors: []*Error{syntaxErr}}, http.StatusBadRequest)
+				return
+			}
+
+			if errs := Validate(schema, document, options.MaxDepth); len(errs) > 0 {
+				respond(w, Response{Errors: errs}, http.StatusBadRequest)
+				return
+			}
+
+			allowedType := ""
+			if r.Method == http.MethodGet {
+				allowedType = "query"
+			}
+
+			response := Execute(ExecuteParams{
+				Context:     r.Context(),
+				Schema:      schema,
+				Document:    document,
+				Request:     request,
+				FormatError: formatError,
+				AllowedType: allowedType,
+			})
+
+			status := http.StatusOK
+			if response.Data == nil {
+				status = http.StatusBadRequest
+			}
+
+			respond(w, response, status)
+		}, nil
+	}
+}
+
+// readRequest returns the graphql request from the query string of GET requests,
+// or from the json body of other requests.
+func readRequest(w http.ResponseWriter, r *http.Request) (Request, error) {
+	var request Request
+
+	if r.Method == http.MethodGet {
+		query := r.URL.Query()
+		request.Query = query.Get("query")
+		request.OperationName = query.Get("operationName")
+
+		if variables := query.Get("variables"); variables != "" {
+			decoder := json.NewDecoder(strings.NewReader(variables))
+			decoder.UseNumber()
+
+			if err := decoder.Decode(&request.Variables); err != nil {
+				return Request{}, errors.New("invalid variables")
+			}
+		}
+
+		return request, nil
+	}
+
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&request); err != nil {
+		return Request{}, errors.New("invalid request body")
+	}
+
+	return request, nil
+}
+
+// errorFormatter returns the ErrorFormatter reporting typed errors with their messages and error codes,
+// and logging other errors, which are reported as internal server errors so as not to leak details.
+func errorFormatter(cosys *common.Cosys) ErrorFormatter {
+	return func(err error) *Error {
+		if typedErr, ok := common.AsTypedError(err); ok {
+			return &Error{
+				Message: typedErr.Message,
+				Extensions: map[string]any{
+					"code": string(typedErr.Code),
+				},
+			}
+		}
+
+		cosys.LogError("graphql resolver failed", err)
+
+		return &Error{
+			Message: http.StatusText(http.StatusInternalServerError),
+			Extensions: map[string]any{
+				"code": string(common.InternalCode),
+			},
+		}
+	}
+}
+
+// respond responds with the given graphql response and status code.
+func respond(w http.ResponseWriter, response Response, code int) {
+	w.Header().Set("Content-Type", "application/graphql-response+json")
+	w.WriteHeader(code)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Println(err)
+	}
+}
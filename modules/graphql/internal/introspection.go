@@ -0,0 +1,467 @@
+package internal
+
+// directive is a directive supported by the executor.
+type directive struct {
+	name        string
+	description string
+	locations   []string
+	args        []*InputValue
+}
+
+// directives are the directives supported by the executor.
+var directives = []*directive{
+	{
+		name:        "include",
+		description: "Directs the executor to include this field or fragment only when the `if` argument is true.",
+		locations:   []string{"FIELD", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"},
+		args:        ifArguments,
+	},
+	{
+		name:        "skip",
+		description: "Directs the executor to skip this field or fragment when the `if` argument is true.",
+		locations:   []string{"FIELD", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"},
+		args:        ifArguments,
+	},
+}
+
+// Introspection types.
+var (
+	schemaType     = &Object{TypeName: "__Schema"}
+	typeType       = &Object{TypeName: "__Type"}
+	fieldType      = &Object{TypeName: "__Field"}
+	inputValueType = &Object{TypeName: "__InputValue"}
+	enumValueType  = &Object{TypeName: "__EnumValue"}
+	directiveType  = &Object{TypeName: "__Directive"}
+
+	typeKindType = &Enum{
+		TypeName: "__TypeKind",
+		Values: enumValues(
+			"SCALAR", "OBJECT", "INTERFACE", "UNION", "ENUM", "INPUT_OBJECT", "LIST", "NON_NULL",
+		),
+	}
+
+	directiveLocationType = &Enum{
+		TypeName: "__DirectiveLocation",
+		Values: enumValues(
+			"QUERY", "MUTATION", "SUBSCRIPTION", "FIELD", "FRAGMENT_DEFINITION", "FRAGMENT_SPREAD",
+			"INLINE_FRAGMENT", "VARIABLE_DEFINITION", "SCHEMA", "SCALAR", "OBJECT", "FIELD_DEFINITION",
+			"ARGUMENT_DEFINITION", "INTERFACE", "UNION", "ENUM", "ENUM_VALUE", "INPUT_OBJECT",
+			"INPUT_FIELD_DEFINITION",
+		),
+	}
+)
+
+// Meta fields.
+var (
+	typenameField = &FieldDefinition{
+		Name:        "__typename",
+		Description: "The name of the current object type.",
+		Type:        &NonNull{OfType: StringType},
+	}
+
+	schemaField = &FieldDefinition{
+		Name:        "__schema",
+		Description: "Access the current type schema of this server.",
+		Type:        &NonNull{OfType: schemaType},
+		Resolve: func(params ResolveParams) (any, error) {
+			return params.Schema, nil
+		},
+	}
+
+	typeField = &FieldDefinition{
+		Name:        "__type",
+		Description: "Request the type information of a single type.",
+		Arguments: []*InputValue{
+			{Name: "name", Type: &NonNull{OfType: StringType}},
+		},
+		Type: typeType,
+		Resolve: func(params ResolveParams) (any, error) {
+			return params.Schema.Type(params.Args["name"].(string)), nil
+		},
+	}
+)
+
+// FieldDefinition returns the field of the given object type with the given name, including meta fields,
+// or nil if not found.
+func (s *Schema) FieldDefinition(parent *Object, name string) *FieldDefinition {
+	switch {
+	case name == typenameField.Name:
+		return typenameField
+	case parent == s.Query && name == schemaField.Name:
+		return schemaField
+	case parent == s.Query && name == typeField.Name:
+		return typeField
+	default:
+		return parent.Field(name)
+	}
+}
+
+// enumValues returns the enum values with the given names.
+func enumValues(names ...string) []*EnumValue {
+	values := make([]*EnumValue, len(names))
+	for index, name := range names {
+		values[index] = &EnumValue{Name: name}
+	}
+
+	return values
+}
+
+// includeDeprecated is the includeDeprecated argument of introspection fields.
+// Deprecation is not supported, so it has no effect.
+var includeDeprecated = []*InputValue{
+	{Name: "includeDeprecated", Type: BooleanType, Default: false},
+}
+
+// nonNullList returns the type of non-null lists of non-null items of the given type.
+func nonNullList(t Type) Type {
+	return &NonNull{OfType: &List{OfType: &NonNull{OfType: t}}}
+}
+
+func init() {
+	schemaType.Description = "A GraphQL Schema defines the capabilities of a GraphQL server."
+	schemaType.Fields = []*FieldDefinition{
+		{
+			Name: "description",
+			Type: StringType,
+			Resolve: func(params ResolveParams) (any, error) {
+				return nil, nil
+			},
+		},
+		{
+			Name: "types",
+			Type: nonNullList(typeType),
+			Resolve: func(params ResolveParams) (any, error) {
+				return params.Source.(*Schema).Types(), nil
+			},
+		},
+		{
+			Name: "queryType",
+			Type: &NonNull{OfType: typeType},
+			Resolve: func(params ResolveParams) (any, error) {
+				return params.Source.(*Schema).Query, nil
+			},
+		},
+		{
+			Name: "mutationType",
+			Type: typeType,
+			Resolve: func(params ResolveParams) (any, error) {
+				if mutation := params.Source.(*Schema).Mutation; mutation != nil {
+					return mutation, nil
+				}
+
+				return nil, nil
+			},
+		},
+		{
+			Name: "subscriptionType",
+			Type: typeType,
+			Resolve: func(params ResolveParams) (any, error) {
+				return nil, nil
+			},
+		},
+		{
+			Name: "directives",
+			Type: nonNullList(directiveType),
+			Resolve: func(params ResolveParams) (any, error) {
+				return directives, nil
+			},
+		},
+	}
+
+	typeType.Description = "The fundamental unit of any GraphQL Schema is the type."
+	typeType.Fields = []*FieldDefinition{
+		{
+			Name: "kind",
+			Type: &NonNull{OfType: typeKindType},
+			Resolve: func(params ResolveParams) (any, error) {
+				return string(params.Source.(Type).Kind()), nil
+			},
+		},
+		{
+			Name: "name",
+			Type: StringType,
+			Resolve: func(params ResolveParams) (any, error) {
+				if name := params.Source.(Type).Name(); name != "" {
+					return name, nil
+				}
+
+				return nil, nil
+			},
+		},
+		{
+			Name: "description",
+			Type: StringType,
+			Resolve: func(params ResolveParams) (any, error) {
+				return description(params.Source.(Type)), nil
+			},
+		},
+		{
+			Name: "specifiedByURL",
+			Type: StringType,
+			Resolve: func(params ResolveParams) (any, error) {
+				return nil, nil
+			},
+		},
+		{
+			Name:      "fields",
+			Arguments: includeDeprecated,
+			Type:      &List{OfType: &NonNull{OfType: fieldType}},
+			Resolve: func(params ResolveParams) (any, error) {
+				if object, ok := params.Source.(*Object); ok {
+					return object.Fields, nil
+				}
+
+				return nil, nil
+			},
+		},
+		{
+			Name: "interfaces",
+			Type: &List{OfType: &NonNull{OfType: typeType}},
+			Resolve: func(params ResolveParams) (any, error) {
+				if _, ok := params.Source.(*Object); ok {
+					return []Type{}, nil
+				}
+
+				return nil, nil
+			},
+		},
+		{
+			Name: "possibleTypes",
+			Type: &List{OfType: &NonNull{OfType: typeType}},
+			Resolve: func(params ResolveParams) (any, error) {
+				return nil, nil
+			},
+		},
+		{
+			Name:      "enumValues",
+			Arguments: includeDeprecated,
+			Type:      &List{OfType: &NonNull{OfType: enumValueType}},
+			Resolve: func(params ResolveParams) (any, error) {
+				if enum, ok := params.Source.(*Enum); ok {
+					return enum.Values, nil
+				}
+
+				return nil, nil
+			},
+		},
+		{
+			Name:      "inputFields",
+			Arguments: includeDeprecated,
+			Type:      &List{OfType: &NonNull{OfType: inputValueType}},
+			Resolve: func(params ResolveParams) (any, error) {
+				if inputObject, ok := params.Source.(*InputObject); ok {
+					return inputObject.Fields, nil
+				}
+
+				return nil, nil
+			},
+		},
+		{
+			Name: "ofType",
+			Type: typeType,
+			Resolve: func(params ResolveParams) (any, error) {
+				switch t := params.Source.(type) {
+				case *List:
+					return t.OfType, nil
+				case *NonNull:
+					return t.OfType, nil
+				default:
+					return nil, nil
+				}
+			},
+		},
+		{
+			Name: "isOneOf",
+			Type: BooleanType,
+			Resolve: func(params ResolveParams) (any, error) {
+				if _, ok := params.Source.(*InputObject); ok {
+					return false, nil
+				}
+
+				return nil, nil
+			},
+		},
+	}
+
+	fieldType.Description = "Object and Interface types are described by a list of Fields, each of which has a name, potentially a list of arguments, and a return type."
+	fieldType.Fields = []*FieldDefinition{
+		{
+			Name: "name",
+			Type: &NonNull{OfType: StringType},
+			Resolve: func(params ResolveParams) (any, error) {
+				return params.Source.(*FieldDefinition).Name, nil
+			},
+		},
+		{
+			Name: "description",
+			Type: StringType,
+			Resolve: func(params ResolveParams) (any, error) {
+				return optionalString(params.Source.(*FieldDefinition).Description), nil
+			},
+		},
+		{
+			Name:      "args",
+			Arguments: includeDeprecated,
+			Type:      nonNullList(inputValueType),
+			Resolve: func(params ResolveParams) (any, error) {
+				if args := params.Source.(*FieldDefinition).Arguments; args != nil {
+					return args, nil
+				}
+
+				return []*InputValue{}, nil
+			},
+		},
+		{
+			Name: "type",
+			Type: &NonNull{OfType: typeType},
+			Resolve: func(params ResolveParams) (any, error) {
+				return params.Source.(*FieldDefinition).Type, nil
+			},
+		},
+		notDeprecatedField,
+		deprecationReasonField,
+	}
+
+	inputValueType.Description = "Arguments provided to Fields or Directives and the input fields of an InputObject are represented as Input Values which describe their type and optionally a default value."
+	inputValueType.Fields = []*FieldDefinition{
+		{
+			Name: "name",
+			Type: &NonNull{OfType: StringType},
+			Resolve: func(params ResolveParams) (any, error) {
+				return params.Source.(*InputValue).Name, nil
+			},
+		},
+		{
+			Name: "description",
+			Type: StringType,
+			Resolve: func(params ResolveParams) (any, error) {
+				return optionalString(params.Source.(*InputValue).Description), nil
+			},
+		},
+		{
+			Name: "type",
+			Type: &NonNull{OfType: typeType},
+			Resolve: func(params ResolveParams) (any, error) {
+				return params.Source.(*InputValue).Type, nil
+			},
+		},
+		{
+			Name:        "defaultValue",
+			Description: "A GraphQL-formatted string representing the default value for this input value.",
+			Type:        StringType,
+			Resolve: func(params ResolveParams) (any, error) {
+				inputValue := params.Source.(*InputValue)
+				if inputValue.Default == nil {
+					return nil, nil
+				}
+
+				return printValue(inputValue.Default, inputValue.Type), nil
+			},
+		},
+		notDeprecatedField,
+		deprecationReasonField,
+	}
+
+	enumValueType.Description = "One possible value for a given Enum."
+	enumValueType.Fields = []*FieldDefinition{
+		{
+			Name: "name",
+			Type: &NonNull{OfType: StringType},
+			Resolve: func(params ResolveParams) (any, error) {
+				return params.Source.(*EnumValue).Name, nil
+			},
+		},
+		{
+			Name: "description",
+			Type: StringType,
+			Resolve: func(params ResolveParams) (any, error) {
+				return optionalString(params.Source.(*EnumValue).Description), nil
+			},
+		},
+		notDeprecatedField,
+		deprecationReasonField,
+	}
+
+	directiveType.Description = "A Directive provides a way to describe alternate runtime execution and type validation behavior in a GraphQL document."
+	directiveType.Fields = []*FieldDefinition{
+		{
+			Name: "name",
+			Type: &NonNull{OfType: StringType},
+			Resolve: func(params ResolveParams) (any, error) {
+				return params.Source.(*directive).name, nil
+			},
+		},
+		{
+			Name: "description",
+			Type: StringType,
+			Resolve: func(params ResolveParams) (any, error) {
+				return optionalString(params.Source.(*directive).description), nil
+			},
+		},
+		{
+			Name: "isRepeatable",
+			Type: &NonNull{OfType: BooleanType},
+			Resolve: func(params ResolveParams) (any, error) {
+				return false, nil
+			},
+		},
+		{
+			Name: "locations",
+			Type: nonNullList(directiveLocationType),
+			Resolve: func(params ResolveParams) (any, error) {
+				return params.Source.(*directive).locations, nil
+			},
+		},
+		{
+			Name:      "args",
+			Arguments: includeDeprecated,
+			Type:      nonNullList(inputValueType),
+			Resolve: func(params ResolveParams) (any, error) {
+				return params.Source.(*directive).args, nil
+			},
+		},
+	}
+}
+
+// notDeprecatedField is the isDeprecated field of introspection types, which is always false.
+var notDeprecatedField = &FieldDefinition{
+	Name: "isDeprecated",
+	Type: &NonNull{OfType: BooleanType},
+	Resolve: func(params ResolveParams) (any, error) {
+		return false, nil
+	},
+}
+
+// deprecationReasonField is the deprecationReason field of introspection types, which is always null.
+var deprecationReasonField = &FieldDefinition{
+	Name: "deprecationReason",
+	Type: StringType,
+	Resolve: func(params ResolveParams) (any, error) {
+		return nil, nil
+	},
+}
+
+// description returns the description of the given named type, or nil if none.
+func description(t Type) any {
+	switch t := t.(type) {
+	case *Scalar:
+		return optionalString(t.Description)
+	case *Enum:
+		return optionalString(t.Description)
+	case *Object:
+		return optionalString(t.Description)
+	case *InputObject:
+		return optionalString(t.Description)
+	default:
+		return nil
+	}
+}
+
+// optionalString returns the given string, or nil if empty.
+func optionalString(s string) any {
+	if s == "" {
+		return nil
+	}
+
+	return s
+}
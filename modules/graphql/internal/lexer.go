@@ -0,0 +1,298 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// tokenKind is the kind of a lexical token of a graphql document.
+type tokenKind int
+
+const (
+	eofToken tokenKind = iota
+	punctuatorToken
+	nameToken
+	intToken
+	floatToken
+	stringToken
+)
+
+// token is a lexical token of a graphql document.
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+// Location is a line and column in a graphql document, starting from 1.
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// lexer splits a graphql document into tokens.
+type lexer struct {
+	source     string
+	pos        int
+	lineStarts []int
+}
+
+// location returns the location of the given position in the source.
+func (l *lexer) location(pos int) Location {
+	if l.lineStarts == nil {
+		l.lineStarts = []int{0}
+		for i := 0; i < len(l.source); i++ {
+			if l.source[i] == '\n' {
+				l.lineStarts = append(l.lineStarts, i+1)
+			}
+		}
+	}
+
+	line := sort.SearchInts(l.lineStarts, pos+1)
+	return Location{Line: line, Column: pos - l.lineStarts[line-1] + 1}
+}
+
+// errorf returns a syntax error at the given position.
+func (l *lexer) errorf(pos int, format string, args ...any) error {
+	return &Error{
+		Message:   "syntax error: " + fmt.Sprintf(format, args...),
+		Locations: []Location{l.location(pos)},
+	}
+}
+
+// next returns the next token, skipping whitespace, commas and comments.
+func (l *lexer) next() (token, error) {
+	l.skipIgnored()
+
+	if l.pos >= len(l.source) {
+		return token{kind: eofToken, pos: l.pos}, nil
+	}
+
+	start := l.pos
+	c := l.source[l.pos]
+
+	switch {
+	case strings.HasPrefix(l.source[l.pos:], "..."):
+		l.pos += 3
+		return token{kind: punctuatorToken, value: "...", pos: start}, nil
+	case strings.IndexByte("!$&()/:=@[]{}|", c) >= 0:
+		l.pos++
+		return token{kind: punctuatorToken, value: string(c), pos: start}, nil
+	case c == '_' || isLetter(c):
+		for l.pos < len(l.source) && (l.source[l.pos] == '_' || isLetter(l.source[l.pos]) || isDigit(l.source[l.pos])) {
+			l.pos++
+		}
+		return token{kind: nameToken, value: l.source[start:l.pos], pos: start}, nil
+	case c == '-' || isDigit(c):
+		return l.number()
+	case c == '"':
+		if strings.HasPrefix(l.source[l.pos:], `"""`) {
+			return l.blockString()
+		}
+		return l.string()
+	default:
+		return token{}, l.errorf(start, "unexpected character %q", c)
+	}
+}
+
+// skipIgnored skips whitespace, commas, byte order marks and comments.
+func (l *lexer) skipIgnored() {
+	for l.pos < len(l.source) {
+		switch c := l.source[l.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			l.pos++
+		case c == '#':
+			for l.pos < len(l.source) && l.source[l.pos] != '\n' && l.source[l.pos] != '\r' {
+				l.pos++
+			}
+		case strings.HasPrefix(l.source[l.pos:], "\uFEFF"):
+			l.pos += len("\uFEFF")
+		default:
+			return
+		}
+	}
+}
+
+// number returns the next int or float token.
+func (l *lexer) number() (token, error) {
+	start := l.pos
+	kind := intToken
+
+	if l.source[l.pos] == '-' {
+		l.pos++
+	}
+
+	integerStart := l.pos
+	if !l.digits() {
+		return token{}, l.errorf(start, "invalid number")
+	}
+
+	// Integer parts cannot have leading zeros.
+	if l.source[integerStart] == '0' && l.pos-integerStart > 1 {
+		return token{}, l.errorf(start, "invalid number")
+	}
+
+	if l.pos < len(l.source) && l.source[l.pos] == '.' {
+		kind = floatToken
+		l.pos++
+		if !l.digits() {
+			return token{}, l.errorf(start, "invalid number")
+		}
+	}
+
+	if l.pos < len(l.source) && (l.source[l.pos] == 'e' || l.source[l.pos] == 'E') {
+		kind = floatToken
+		l.pos++
+		if l.pos < len(l.source) && (l.source[l.pos] == '+' || l.source[l.pos] == '-') {
+			l.pos++
+		}
+		if !l.digits() {
+			return token{}, l.errorf(start, "invalid number")
+		}
+	}
+
+	if l.pos < len(l.source) && (l.source[l.pos] == '_' || isLetter(l.source[l.pos]) || l.source[l.pos] == '.') {
+		return token{}, l.errorf(start, "invalid number")
+	}
+
+	return token{kind: kind, value: l.source[start:l.pos], pos: start}, nil
+}
+
+// digits skips a sequence of digits, and returns whether there was at least one.
+func (l *lexer) digits() bool {
+	start := l.pos
+	for l.pos < len(l.source) && isDigit(l.source[l.pos]) {
+		l.pos++
+	}
+
+	return l.pos > start
+}
+
+// string returns the next string token, with its escape sequences resolved.
+func (l *lexer) string() (token, error) {
+	start := l.pos
+	l.pos++
+
+	var value strings.Builder
+	for l.pos < len(l.source) {
+		c := l.source[l.pos]
+		switch {
+		case c == '"':
+			l.pos++
+			return token{kind: stringToken, value: value.String(), pos: start}, nil
+		case c == '\n' || c == '\r':
+			return token{}, l.errorf(start, "unterminated string")
+		case c == '\\':
+			if l.pos+1 >= len(l.source) {
+				return token{}, l.errorf(start, "unterminated string")
+			}
+
+			escaped := l.source[l.pos+1]
+			l.pos += 2
+
+			switch escaped {
+			case '"', '\\', '/':
+				value.WriteByte(escaped)
+			case 'b':
+				value.WriteByte('\b')
+			case 'f':
+				value.WriteByte('\f')
+			case 'n':
+				value.WriteByte('\n')
+			case 'r':
+				value.WriteByte('\r')
+			case 't':
+				value.WriteByte('\t')
+			case 'u':
+				if l.pos+4 > len(l.source) {
+					return token{}, l.errorf(start, "invalid unicode escape")
+				}
+
+				var r rune
+				if _, err := fmt.Sscanf(l.source[l.pos:l.pos+4], "%04x", &r); err != nil {
+					return token{}, l.errorf(start, "invalid unicode escape")
+				}
+				l.pos += 4
+
+				value.WriteRune(r)
+			default:
+				return token{}, l.errorf(start, "invalid escape sequence \\%c", escaped)
+			}
+		default:
+			r, size := utf8.DecodeRuneInString(l.source[l.pos:])
+			value.WriteRune(r)
+			l.pos += size
+		}
+	}
+
+	return token{}, l.errorf(start, "unterminated string")
+}
+
+// blockString returns the next block string token, with its common indentation removed.
+func (l *lexer) blockString() (token, error) {
+	start := l.pos
+	l.pos += 3
+
+	end := strings.Index(l.source[l.pos:], `"""`)
+	for end > 0 && l.source[l.pos+end-1] == '\\' {
+		next := strings.Index(l.source[l.pos+end+3:], `"""`)
+		if next < 0 {
+			end = -1
+			break
+		}
+		end += 3 + next
+	}
+	if end < 0 {
+		return token{}, l.errorf(start, "unterminated string")
+	}
+
+	raw := strings.ReplaceAll(l.source[l.pos:l.pos+end], `\"""`, `"""`)
+	l.pos += end + 3
+
+	return token{kind: stringToken, value: blockStringValue(raw), pos: start}, nil
+}
+
+// blockStringValue removes the common indentation and leading and trailing blank lines of a block string.
+func blockStringValue(raw string) string {
+	lines := strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n")
+
+	indent := -1
+	for _, line := range lines[1:] {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed == "" {
+			continue
+		}
+		if lineIndent := len(line) - len(trimmed); indent < 0 || lineIndent < indent {
+			indent = lineIndent
+		}
+	}
+
+	if indent > 0 {
+		for i := 1; i < len(lines); i++ {
+			if len(lines[i]) >= indent {
+				lines[i] = lines[i][indent:]
+			} else {
+				lines[i] = strings.TrimLeft(lines[i], " \t")
+			}
+		}
+	}
+
+	for len(lines) > 0 && strings.TrimLeft(lines[0], " \t") == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimLeft(lines[len(lines)-1], " \t") == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
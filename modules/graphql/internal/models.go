@@ -0,0 +1,336 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/cosys-io/cosys/common"
+)
+
+// DateTimeType is the scalar of date-times, serialized as RFC 3339 strings.
+var DateTimeType = &Scalar{
+	TypeName:    "DateTime",
+	Description: "The `DateTime` scalar type represents a date-time, serialized as an RFC 3339 string.",
+	Serialize: func(value any) (any, error) {
+		switch value := value.(type) {
+		case time.Time:
+			return value.Format(time.RFC3339Nano), nil
+		case *time.Time:
+			return value.Format(time.RFC3339Nano), nil
+		case string:
+			return value, nil
+		default:
+			return nil, fmt.Errorf("DateTime cannot represent value: %v", value)
+		}
+	},
+	Parse: func(value any) (any, error) {
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("DateTime cannot represent non string value: %v", value)
+		}
+
+		parsed, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return nil, fmt.Errorf("DateTime cannot represent non RFC 3339 value: %s", s)
+		}
+
+		return parsed, nil
+	},
+}
+
+// JSONType is the scalar of arbitrary json values, used for components and dynamic zones.
+var JSONType = &Scalar{
+	TypeName:    "JSON",
+	Description: "The `JSON` scalar type represents an arbitrary JSON value.",
+	Serialize: func(value any) (any, error) {
+		return value, nil
+	},
+	Parse: func(value any) (any, error) {
+		return value, nil
+	},
+}
+
+// filterTypes are the filter input types of the scalars of filterable attributes.
+var filterTypes = map[*Scalar]*InputObject{
+	IntType:      comparisonFilter(IntType, true),
+	StringType:   comparisonFilter(StringType, false),
+	BooleanType:  comparisonFilter(BooleanType, false),
+	DateTimeType: comparisonFilter(DateTimeType, true),
+}
+
+// comparisonFilter returns the filter input type of attributes of the given scalar,
+// with ordering operators if the scalar is ordered.
+func comparisonFilter(scalar *Scalar, ordered bool) *InputObject {
+	filter := &InputObject{
+		TypeName:    scalar.TypeName + "Filter",
+		Description: "Conditions on " + scalar.TypeName + " attributes, all of which must be met.",
+		Fields: []*InputValue{
+			{Name: "eq", Description: "Equal to the given value.", Type: scalar},
+			{Name: "ne", Description: "Not equal to the given value.", Type: scalar},
+		},
+	}
+
+	if ordered {
+		filter.Fields = append(filter.Fields,
+			&InputValue{Name: "lt", Description: "Less than the given value.", Type: scalar},
+			&InputValue{Name: "lte", Description: "Less than or equal to the given value.", Type: scalar},
+			&InputValue{Name: "gt", Description: "Greater than the given value.", Type: scalar},
+			&InputValue{Name: "gte", Description: "Greater than or equal to the given value.", Type: scalar},
+		)
+	}
+
+	filter.Fields = append(filter.Fields,
+		&InputValue{Name: "null", Description: "Null if true, or not null if false.", Type: BooleanType},
+	)
+
+	return filter
+}
+
+// filterOperations are the condition operations of the fields of filter input types.
+var filterOperations = map[string]common.ExpressionOperation{
+	"eq":  common.Eq,
+	"ne":  common.Neq,
+	"lt":  common.Lt,
+	"lte": common.Lte,
+	"gt":  common.Gt,
+	"gte": common.Gte,
+}
+
+// namePattern is the pattern of valid graphql names.
+var namePattern = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+// Options are configurations of the schema generated from models.
+type Options struct {
+	DefaultPageSize int // DefaultPageSize is the number of entities returned by list queries without a pageSize.
+	MaxPageSize     int // MaxPageSize is the maximum pageSize of list queries, or unlimited if not positive.
+	// Mutations specifies whether the create, update and delete mutations are generated.
+	Mutations bool
+}
+
+// modelType is the graphql representation of a model.
+type modelType struct {
+	uid        string
+	model      common.Model
+	object     *Object
+	filter     *InputObject
+	input      *InputObject
+	attributes map[string]common.Attribute // attributes are the public attributes by name.
+}
+
+// BuildSchema returns the graphql schema of the models of the cosys app,
+// with queries, and mutations if enabled, resolved through the database core service.
+func BuildSchema(cosys *common.Cosys, options Options) (*Schema, error) {
+	models := cosys.Models()
+
+	uids := make([]string, 0, len(models))
+	for uid := range models {
+		uids = append(uids, uid)
+	}
+	sort.Strings(uids)
+
+	query := &Object{
+		TypeName: "Query",
+	}
+	mutation := &Object{
+		TypeName: "Mutation",
+	}
+
+	for _, uid := range uids {
+		modelType, err := newModelType(uid, models[uid])
+		if err != nil {
+			return nil, err
+		}
+
+		resolver := &resolver{
+			cosys:     cosys,
+			modelType: modelType,
+			options:   options,
+		}
+
+		query.Fields = append(query.Fields, resolver.queryFields()...)
+		if options.Mutations {
+			mutation.Fields = append(mutation.Fields, resolver.mutationFields()...)
+		}
+	}
+
+	if len(query.Fields) == 0 {
+		query.Fields = append(query.Fields, &FieldDefinition{
+			Name:        "_empty",
+			Description: "Placeholder field of schemas without models.",
+			Type:        BooleanType,
+			Resolve: func(params ResolveParams) (any, error) {
+				return nil, nil
+			},
+		})
+	}
+
+	if len(mutation.Fields) == 0 {
+		mutation = nil
+	}
+
+	return NewSchema(query, mutation)
+}
+
+// newModelType returns the object, filter and input types of the model with the given uid.
+// Private attributes are left out of the object and filter types,
+// and attributes that are not editable are left out of the input type.
+func newModelType(uid string, model common.Model) (*modelType, error) {
+	name := model.SingularPascalName_()
+	if !namePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid graphql type name for model %s: %s", uid, name)
+	}
+
+	attrSchemas := map[string]common.AttributeSchema{}
+	if model.Schema_() != nil {
+		for _, attrSchema := range model.Schema_().Attributes() {
+			attrSchemas[attrSchema.Name()] = attrSchema
+		}
+	}
+
+	modelType := &modelType{
+		uid:   uid,
+		model: model,
+		object: &Object{
+			TypeName: name,
+		},
+		filter: &InputObject{
+			TypeName:    name + "Filter",
+			Description: "Conditions on " + model.PluralHumanName_() + ", all of which must be met.",
+		},
+		input: &InputObject{
+			TypeName:    name + "Input",
+			Description: "The attributes of a " + model.SingularHumanName_() + " to create or update.",
+		},
+		attributes: map[string]common.Attribute{},
+	}
+
+	if described, ok := model.Schema_().(interface{ Description() string }); ok {
+		modelType.object.Description = described.Description()
+	}
+
+	for _, attr := range model.Attributes_() {
+		scalar := attributeScalar(attr)
+		if scalar == nil {
+			continue
+		}
+
+		attrName := attr.CamelName()
+		attrSchema, hasSchema := attrSchemas[attrName]
+
+		if !hasSchema || attrSchema.Editable() {
+			if attr != model.IdAttribute_() {
+				modelType.input.Fields = append(modelType.input.Fields, &InputValue{
+					Name: attrName,
+					Type: scalar,
+				})
+			}
+		}
+
+		if hasSchema && attrSchema.Private() {
+			continue
+		}
+
+		var fieldType Type = scalar
+		if attr == model.IdAttribute_() || (hasSchema && !attrSchema.Nullable()) {
+			fieldType = &NonNull{OfType: scalar}
+		}
+
+		modelType.object.Fields = append(modelType.object.Fields, &FieldDefinition{
+			Name:    attrName,
+			Type:    fieldType,
+			Resolve: resolveAttribute(attr),
+		})
+
+		modelType.attributes[attrName] = attr
+
+		if filterType, ok := filterTypes[scalar]; ok {
+			modelType.filter.Fields = append(modelType.filter.Fields, &InputValue{
+				Name: attrName,
+				Type: filterType,
+			})
+		}
+	}
+
+	modelType.filter.Fields = append(modelType.filter.Fields,
+		&InputValue{Name: "and", Description: "Conditions that must all be met.", Type: &List{OfType: &NonNull{OfType: modelType.filter}}},
+		&InputValue{Name: "or", Description: "Conditions of which at least one must be met.", Type: &List{OfType: &NonNull{OfType: modelType.filter}}},
+		&InputValue{Name: "not", Description: "Conditions that must not be met.", Type: modelType.filter},
+	)
+
+	return modelType, nil
+}
+
+// attributeScalar returns the scalar of the values of the given attribute, or nil if not supported.
+func attributeScalar(attr common.Attribute) *Scalar {
+	switch attr.(type) {
+	case common.IntAttribute:
+		return IntType
+	case common.StringAttribute:
+		return StringType
+	case common.BoolAttribute:
+		return BooleanType
+	case common.TimeAttribute:
+		return DateTimeType
+	case common.ComponentAttribute, common.DynamicZoneAttribute:
+		return JSONType
+	default:
+		return nil
+	}
+}
+
+// resolveAttribute returns the ResolveFunc reading the value of the given attribute from an entity.
+func resolveAttribute(attr common.Attribute) ResolveFunc {
+	return func(params ResolveParams) (any, error) {
+		entity := reflect.Indirect(reflect.ValueOf(params.Source))
+		if entity.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("entity is not a struct")
+		}
+
+		field := entity.FieldByName(attr.PascalName())
+		if !field.IsValid() {
+			return nil, nil
+		}
+
+		return field.Interface(), nil
+	}
+}
+
+// newEntity returns a new entity of the model with the given input values,
+// and the attributes that were given.
+func (m *modelType) newEntity(data map[string]any) (common.Entity, []common.Attribute, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entity := m.model.New_()
+	if err = json.Unmarshal(encoded, entity); err != nil {
+		return nil, nil, common.NewValidationError("invalid " + m.model.SingularHumanName_() + ": " + err.Error())
+	}
+
+	var columns []common.Attribute
+	for _, attr := range m.model.Attributes_() {
+		if _, ok := data[attr.CamelName()]; ok {
+			columns = append(columns, attr)
+		}
+	}
+
+	return entity, columns, nil
+}
+
+// inputColumns returns the attributes of the input type, which are all set when creating entities,
+// with zero values for the attributes that were not given.
+func (m *modelType) inputColumns() []common.Attribute {
+	columns := make([]common.Attribute, 0, len(m.input.Fields))
+	for _, attr := range m.model.Attributes_() {
+		if m.input.Field(attr.CamelName()) != nil {
+			columns = append(columns, attr)
+		}
+	}
+
+	return columns
+}
@@ -0,0 +1,723 @@
+package internal
+
+import (
+	"fmt"
+)
+
+// Document is a parsed graphql executable document.
+type Document struct {
+	Operations []*Operation
+	Fragments  map[string]*Fragment
+}
+
+// Operation is a query or mutation of a document.
+type Operation struct {
+	Type         string // Type is query, mutation or subscription.
+	Name         string
+	Variables    []*VariableDefinition
+	Directives   []*Directive
+	SelectionSet []Selection
+	Location     Location
+}
+
+// VariableDefinition is a variable of an operation.
+type VariableDefinition struct {
+	Name     string
+	Type     *TypeRef
+	Default  *Value
+	Location Location
+}
+
+// TypeRef is a reference to a type in a document, e.g. [String!]!.
+type TypeRef struct {
+	Name    string   // Name is the name of a named type.
+	List    *TypeRef // List is the item type of a list type.
+	NonNull bool
+}
+
+// String returns the type reference as written in documents.
+func (t *TypeRef) String() string {
+	var s string
+	if t.List != nil {
+		s = "[" + t.List.String() + "]"
+	} else {
+		s = t.Name
+	}
+
+	if t.NonNull {
+		s += "!"
+	}
+
+	return s
+}
+
+// Selection is a field, fragment spread or inline fragment of a selection set.
+type Selection interface {
+	selection()
+}
+
+// Field is a field selection.
+type Field struct {
+	Alias        string
+	Name         string
+	Arguments    []*Argument
+	Directives   []*Directive
+	SelectionSet []Selection
+	Location     Location
+}
+
+// ResponseKey returns the key of the field in the response, its alias or its name.
+func (f *Field) ResponseKey() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+
+	return f.Name
+}
+
+// FragmentSpread is a fragment spread selection, e.g. ...postFields.
+type FragmentSpread struct {
+	Name       string
+	Directives []*Directive
+	Location   Location
+}
+
+// InlineFragment is an inline fragment selection, e.g. ... on Post { title }.
+type InlineFragment struct {
+	TypeCondition string
+	Directives    []*Directive
+	SelectionSet  []Selection
+	Location      Location
+}
+
+func (*Field) selection()          {}
+func (*FragmentSpread) selection() {}
+func (*InlineFragment) selection() {}
+
+// Fragment is a named fragment definition.
+type Fragment struct {
+	Name          string
+	TypeCondition string
+	Directives    []*Directive
+	SelectionSet  []Selection
+	Location      Location
+}
+
+// Directive is a directive on a selection or operation, e.g. @skip(if: true).
+type Directive struct {
+	Name      string
+	Arguments []*Argument
+	Location  Location
+}
+
+// Argument is an argument of a field or directive.
+type Argument struct {
+	Name     string
+	Value    *Value
+	Location Location
+}
+
+// ValueKind is the kind of a value literal.
+type ValueKind int
+
+const (
+	VariableLiteral ValueKind = iota
+	IntLiteral
+	FloatLiteral
+	StringLiteral
+	BooleanLiteral
+	NullLiteral
+	EnumLiteral
+	ListLiteral
+	ObjectLiteral
+)
+
+// Value is a value literal in a document.
+type Value struct {
+	Kind     ValueKind
+	Raw      string         // Raw is the name of variables and enum values, or the literal of scalars.
+	List     []*Value       // List are the items of list values.
+	Fields   []*ObjectField // Fields are the fields of object values.
+	Location Location
+}
+
+// ObjectField is a field of an object value.
+type ObjectField struct {
+	Name  string
+	Value *Value
+}
+
+// parser parses graphql executable documents.
+type parser struct {
+	lexer *lexer
+	token token
+}
+
+// Parse parses the given graphql executable document.
+func Parse(source string) (*Document, error) {
+	p := &parser{lexer: newLexer(source)}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	document := &Document{
+		Fragments: map[string]*Fragment{},
+	}
+
+	for p.token.kind != eofToken {
+		switch {
+		case p.peek(punctuatorToken, "{"):
+			location := p.location(p.token.pos)
+
+			selectionSet, err := p.selectionSet()
+			if err != nil {
+				return nil, err
+			}
+
+			document.Operations = append(document.Operations, &Operation{
+				Type:         "query",
+				SelectionSet: selectionSet,
+				Location:     location,
+			})
+		case p.peek(nameToken, "fragment"):
+			fragment, err := p.fragment()
+			if err != nil {
+				return nil, err
+			}
+
+			if _, ok := document.Fragments[fragment.Name]; ok {
+				return nil, newError(fmt.Sprintf("there can be only one fragment named %s", fragment.Name), fragment.Location)
+			}
+			document.Fragments[fragment.Name] = fragment
+		case p.peek(nameToken, "query"), p.peek(nameToken, "mutation"), p.peek(nameToken, "subscription"):
+			operation, err := p.operation()
+			if err != nil {
+				return nil, err
+			}
+
+			document.Operations = append(document.Operations, operation)
+		default:
+			return nil, p.unexpected()
+		}
+	}
+
+	if len(document.Operations) == 0 {
+		return nil, newError("document does not contain any operations")
+	}
+
+	return document, nil
+}
+
+// newLexer returns a lexer for the given source.
+func newLexer(source string) *lexer {
+	return &lexer{source: source}
+}
+
+// location returns the location of the given position in the document.
+func (p *parser) location(pos int) Location {
+	return p.lexer.location(pos)
+}
+
+// advance moves to the next token.
+func (p *parser) advance() error {
+	next, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+
+	p.token = next
+	return nil
+}
+
+// peek returns whether the current token is of the given kind and value.
+func (p *parser) peek(kind tokenKind, value string) bool {
+	return p.token.kind == kind && p.token.value == value
+}
+
+// skip moves to the next token if the current token is of the given kind and value,
+// and returns whether it did.
+func (p *parser) skip(kind tokenKind, value string) (bool, error) {
+	if !p.peek(kind, value) {
+		return false, nil
+	}
+
+	return true, p.advance()
+}
+
+// expect moves to the next token, or throws an error if the current token is not the given punctuator.
+func (p *parser) expect(value string) error {
+	if !p.peek(punctuatorToken, value) {
+		return p.lexer.errorf(p.token.pos, "expected %q, found %s", value, p.describe())
+	}
+
+	return p.advance()
+}
+
+// name returns the current name token and moves to the next token,
+// or throws an error if the current token is not a name.
+func (p *parser) name() (string, error) {
+	if p.token.kind != nameToken {
+		return "", p.lexer.errorf(p.token.pos, "expected name, found %s", p.describe())
+	}
+
+	name := p.token.value
+	return name, p.advance()
+}
+
+// unexpected returns the error for an unexpected current token.
+func (p *parser) unexpected() error {
+	return p.lexer.errorf(p.token.pos, "unexpected %s", p.describe())
+}
+
+// describe returns the description of the current token for error messages.
+func (p *parser) describe() string {
+	switch p.token.kind {
+	case eofToken:
+		return "end of document"
+	case stringToken:
+		return "string"
+	default:
+		return fmt.Sprintf("%q", p.token.value)
+	}
+}
+
+// operation parses an operation definition.
+func (p *parser) operation() (*Operation, error) {
+	operation := &Operation{
+		Type:     p.token.value,
+		Location: p.location(p.token.pos),
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if p.token.kind == nameToken {
+		if operation.Name, err = p.name(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.peek(punctuatorToken, "(") {
+		if operation.Variables, err = p.variableDefinitions(); err != nil {
+			return nil, err
+		}
+	}
+
+	if operation.Directives, err = p.directives(); err != nil {
+		return nil, err
+	}
+
+	if operation.SelectionSet, err = p.selectionSet(); err != nil {
+		return nil, err
+	}
+
+	return operation, nil
+}
+
+// variableDefinitions parses the variable definitions of an operation.
+func (p *parser) variableDefinitions() ([]*VariableDefinition, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	var definitions []*VariableDefinition
+	for {
+		if ok, err := p.skip(punctuatorToken, ")"); err != nil || ok {
+			return definitions, err
+		}
+
+		definition := &VariableDefinition{
+			Location: p.location(p.token.pos),
+		}
+
+		if err := p.expect("$"); err != nil {
+			return nil, err
+		}
+
+		var err error
+		if definition.Name, err = p.name(); err != nil {
+			return nil, err
+		}
+
+		if err = p.expect(":"); err != nil {
+			return nil, err
+		}
+
+		if definition.Type, err = p.typeRef(); err != nil {
+			return nil, err
+		}
+
+		if ok, err := p.skip(punctuatorToken, "="); err != nil {
+			return nil, err
+		} else if ok {
+			if definition.Default, err = p.value(true); err != nil {
+				return nil, err
+			}
+		}
+
+		if _, err = p.directives(); err != nil {
+			return nil, err
+		}
+
+		definitions = append(definitions, definition)
+	}
+}
+
+// typeRef parses a type reference.
+func (p *parser) typeRef() (*TypeRef, error) {
+	typeRef := &TypeRef{}
+
+	if ok, err := p.skip(punctuatorToken, "["); err != nil {
+		return nil, err
+	} else if ok {
+		if typeRef.List, err = p.typeRef(); err != nil {
+			return nil, err
+		}
+
+		if err = p.expect("]"); err != nil {
+			return nil, err
+		}
+	} else {
+		if typeRef.Name, err = p.name(); err != nil {
+			return nil, err
+		}
+	}
+
+	var err error
+	typeRef.NonNull, err = p.skip(punctuatorToken, "!")
+	return typeRef, err
+}
+
+// fragment parses a fragment definition.
+func (p *parser) fragment() (*Fragment, error) {
+	fragment := &Fragment{
+		Location: p.location(p.token.pos),
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	namePos := p.token.pos
+
+	var err error
+	if fragment.Name, err = p.name(); err != nil {
+		return nil, err
+	}
+	if fragment.Name == "on" {
+		return nil, p.lexer.errorf(namePos, "fragments cannot be named on")
+	}
+
+	if !p.peek(nameToken, "on") {
+		return nil, p.lexer.errorf(p.token.pos, "expected \"on\", found %s", p.describe())
+	}
+	if err = p.advance(); err != nil {
+		return nil, err
+	}
+
+	if fragment.TypeCondition, err = p.name(); err != nil {
+		return nil, err
+	}
+
+	if fragment.Directives, err = p.directives(); err != nil {
+		return nil, err
+	}
+
+	if fragment.SelectionSet, err = p.selectionSet(); err != nil {
+		return nil, err
+	}
+
+	return fragment, nil
+}
+
+// selectionSet parses a selection set.
+func (p *parser) selectionSet() ([]Selection, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	var selections []Selection
+	for {
+		closePos := p.token.pos
+		if ok, err := p.skip(punctuatorToken, "}"); err != nil {
+			return nil, err
+		} else if ok {
+			if len(selections) == 0 {
+				return nil, p.lexer.errorf(closePos, "selection sets cannot be empty")
+			}
+
+			return selections, nil
+		}
+
+		selection, err := p.selection()
+		if err != nil {
+			return nil, err
+		}
+
+		selections = append(selections, selection)
+	}
+}
+
+// selection parses a field, fragment spread or inline fragment.
+func (p *parser) selection() (Selection, error) {
+	location := p.location(p.token.pos)
+
+	if ok, err := p.skip(punctuatorToken, "..."); err != nil {
+		return nil, err
+	} else if !ok {
+		return p.field()
+	}
+
+	if p.token.kind == nameToken && p.token.value != "on" {
+		spread := &FragmentSpread{
+			Location: location,
+		}
+
+		var err error
+		if spread.Name, err = p.name(); err != nil {
+			return nil, err
+		}
+
+		if spread.Directives, err = p.directives(); err != nil {
+			return nil, err
+		}
+
+		return spread, nil
+	}
+
+	fragment := &InlineFragment{
+		Location: location,
+	}
+
+	var err error
+	if ok, err := p.skip(nameToken, "on"); err != nil {
+		return nil, err
+	} else if ok {
+		if fragment.TypeCondition, err = p.name(); err != nil {
+			return nil, err
+		}
+	}
+
+	if fragment.Directives, err = p.directives(); err != nil {
+		return nil, err
+	}
+
+	if fragment.SelectionSet, err = p.selectionSet(); err != nil {
+		return nil, err
+	}
+
+	return fragment, nil
+}
+
+// field parses a field selection.
+func (p *parser) field() (*Field, error) {
+	field := &Field{
+		Location: p.location(p.token.pos),
+	}
+
+	var err error
+	if field.Name, err = p.name(); err != nil {
+		return nil, err
+	}
+
+	if ok, err := p.skip(punctuatorToken, ":"); err != nil {
+		return nil, err
+	} else if ok {
+		field.Alias = field.Name
+		if field.Name, err = p.name(); err != nil {
+			return nil, err
+		}
+	}
+
+	if field.Arguments, err = p.arguments(false); err != nil {
+		return nil, err
+	}
+
+	if field.Directives, err = p.directives(); err != nil {
+		return nil, err
+	}
+
+	if p.peek(punctuatorToken, "{") {
+		if field.SelectionSet, err = p.selectionSet(); err != nil {
+			return nil, err
+		}
+	}
+
+	return field, nil
+}
+
+// arguments parses the arguments of a field or directive, if any.
+func (p *parser) arguments(constant bool) ([]*Argument, error) {
+	if ok, err := p.skip(punctuatorToken, "("); err != nil || !ok {
+		return nil, err
+	}
+
+	var arguments []*Argument
+	for {
+		closePos := p.token.pos
+		if ok, err := p.skip(punctuatorToken, ")"); err != nil {
+			return nil, err
+		} else if ok {
+			if len(arguments) == 0 {
+				return nil, p.lexer.errorf(closePos, "argument lists cannot be empty")
+			}
+
+			return arguments, nil
+		}
+
+		argument := &Argument{
+			Location: p.location(p.token.pos),
+		}
+
+		var err error
+		if argument.Name, err = p.name(); err != nil {
+			return nil, err
+		}
+
+		if err = p.expect(":"); err != nil {
+			return nil, err
+		}
+
+		if argument.Value, err = p.value(constant); err != nil {
+			return nil, err
+		}
+
+		arguments = append(arguments, argument)
+	}
+}
+
+// directives parses the directives of a selection or definition, if any.
+func (p *parser) directives() ([]*Directive, error) {
+	var directives []*Directive
+	for p.peek(punctuatorToken, "@") {
+		directive := &Directive{
+			Location: p.location(p.token.pos),
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+
+		var err error
+		if directive.Name, err = p.name(); err != nil {
+			return nil, err
+		}
+
+		if directive.Arguments, err = p.arguments(false); err != nil {
+			return nil, err
+		}
+
+		directives = append(directives, directive)
+	}
+
+	return directives, nil
+}
+
+// value parses a value literal. Variables are not allowed in constant values.
+func (p *parser) value(constant bool) (*Value, error) {
+	value := &Value{
+		Raw:      p.token.value,
+		Location: p.location(p.token.pos),
+	}
+
+	switch p.token.kind {
+	case intToken:
+		value.Kind = IntLiteral
+	case floatToken:
+		value.Kind = FloatLiteral
+	case stringToken:
+		value.Kind = StringLiteral
+	case nameToken:
+		switch p.token.value {
+		case "true", "false":
+			value.Kind = BooleanLiteral
+		case "null":
+			value.Kind = NullLiteral
+		default:
+			value.Kind = EnumLiteral
+		}
+	case punctuatorToken:
+		switch p.token.value {
+		case "$":
+			if constant {
+				return nil, p.unexpected()
+			}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+
+			value.Kind = VariableLiteral
+
+			var err error
+			value.Raw, err = p.name()
+			return value, err
+		case "[":
+			return p.listValue(value, constant)
+		case "{":
+			return p.objectValue(value, constant)
+		default:
+			return nil, p.unexpected()
+		}
+	default:
+		return nil, p.unexpected()
+	}
+
+	return value, p.advance()
+}
+
+// listValue parses the items of a list value.
+func (p *parser) listValue(value *Value, constant bool) (*Value, error) {
+	value.Kind = ListLiteral
+	value.List = []*Value{}
+
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	for {
+		if ok, err := p.skip(punctuatorToken, "]"); err != nil || ok {
+			return value, err
+		}
+
+		item, err := p.value(constant)
+		if err != nil {
+			return nil, err
+		}
+
+		value.List = append(value.List, item)
+	}
+}
+
+// objectValue parses the fields of an object value.
+func (p *parser) objectValue(value *Value, constant bool) (*Value, error) {
+	value.Kind = ObjectLiteral
+	value.Fields = []*ObjectField{}
+
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	for {
+		if ok, err := p.skip(punctuatorToken, "}"); err != nil || ok {
+			return value, err
+		}
+
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+
+		if err = p.expect(":"); err != nil {
+			return nil, err
+		}
+
+		fieldValue, err := p.value(constant)
+		if err != nil {
+			return nil, err
+		}
+
+		value.Fields = append(value.Fields, &ObjectField{
+			Name:  name,
+			Value: fieldValue,
+		})
+	}
+}
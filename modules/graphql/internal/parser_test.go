@@ -0,0 +1,195 @@
+package internal
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseOperation(t *testing.T) {
+	document, err := Parse(`
+		# Posts with many views.
+		query popular($views: Int = 10, $sort: [String!]!) @skip(if: false) {
+			top: posts(where: {views: {gte: $views}}, sort: $sort, pageSize: 5) {
+				id
+				...postFields
+				... on Post { views }
+			}
+		}
+
+		fragment postFields on Post {
+			title
+		}
+	`)
+	if err != nil {
+		t.Fatalf("could not parse document: %v", err)
+	}
+
+	if len(document.Operations) != 1 {
+		t.Fatalf("operations = %d, want 1", len(document.Operations))
+	}
+
+	operation := document.Operations[0]
+	if operation.Type != "query" || operation.Name != "popular" {
+		t.Errorf("operation = %s %s, want query popular", operation.Type, operation.Name)
+	}
+	if operation.Location != (Location{Line: 3, Column: 3}) {
+		t.Errorf("operation location = %+v, want 3:3", operation.Location)
+	}
+	if len(operation.Directives) != 1 || operation.Directives[0].Name != "skip" {
+		t.Errorf("operation directives = %+v, want @skip", operation.Directives)
+	}
+
+	if len(operation.Variables) != 2 {
+		t.Fatalf("variables = %d, want 2", len(operation.Variables))
+	}
+	views := operation.Variables[0]
+	if views.Name != "views" || views.Type.String() != "Int" || views.Default == nil || views.Default.Raw != "10" {
+		t.Errorf("views variable = %s: %s = %+v", views.Name, views.Type, views.Default)
+	}
+	if sort := operation.Variables[1]; sort.Type.String() != "[String!]!" {
+		t.Errorf("sort variable type = %s, want [String!]!", sort.Type)
+	}
+
+	if len(operation.SelectionSet) != 1 {
+		t.Fatalf("selections = %d, want 1", len(operation.SelectionSet))
+	}
+	field, ok := operation.SelectionSet[0].(*Field)
+	if !ok {
+		t.Fatalf("selection is %T, want *Field", operation.SelectionSet[0])
+	}
+	if field.Name != "posts" || field.Alias != "top" || field.ResponseKey() != "top" {
+		t.Errorf("field = %s: %s, want top: posts", field.Alias, field.Name)
+	}
+
+	if len(field.Arguments) != 3 {
+		t.Fatalf("arguments = %d, want 3", len(field.Arguments))
+	}
+	where := field.Arguments[0].Value
+	if where.Kind != ObjectLiteral || where.Fields[0].Name != "views" {
+		t.Fatalf("where = %+v, want an object on views", where)
+	}
+	gte := where.Fields[0].Value.Fields[0]
+	if gte.Name != "gte" || gte.Value.Kind != VariableLiteral || gte.Value.Raw != "views" {
+		t.Errorf("where.views = %s: %+v, want gte: $views", gte.Name, gte.Value)
+	}
+	if pageSize := field.Arguments[2].Value; pageSize.Kind != IntLiteral || pageSize.Raw != "5" {
+		t.Errorf("pageSize = %+v, want 5", pageSize)
+	}
+
+	if len(field.SelectionSet) != 3 {
+		t.Fatalf("subselections = %d, want 3", len(field.SelectionSet))
+	}
+	if spread, ok := field.SelectionSet[1].(*FragmentSpread); !ok || spread.Name != "postFields" {
+		t.Errorf("second subselection = %+v, want ...postFields", field.SelectionSet[1])
+	}
+	if inline, ok := field.SelectionSet[2].(*InlineFragment); !ok || inline.TypeCondition != "Post" {
+		t.Errorf("third subselection = %+v, want ... on Post", field.SelectionSet[2])
+	}
+
+	fragment, ok := document.Fragments["postFields"]
+	if !ok || fragment.TypeCondition != "Post" || len(fragment.SelectionSet) != 1 {
+		t.Errorf("fragment = %+v, want postFields on Post", fragment)
+	}
+}
+
+func TestParseShorthandAndMutation(t *testing.T) {
+	document, err := Parse(`{ posts { id } } mutation remove { deletePost(id: 1) { id } }`)
+	if err != nil {
+		t.Fatalf("could not parse document: %v", err)
+	}
+
+	if len(document.Operations) != 2 {
+		t.Fatalf("operations = %d, want 2", len(document.Operations))
+	}
+	if operation := document.Operations[0]; operation.Type != "query" || operation.Name != "" {
+		t.Errorf("shorthand operation = %s %q, want an anonymous query", operation.Type, operation.Name)
+	}
+	if operation := document.Operations[1]; operation.Type != "mutation" || operation.Name != "remove" {
+		t.Errorf("second operation = %s %s, want mutation remove", operation.Type, operation.Name)
+	}
+}
+
+func TestParseValues(t *testing.T) {
+	document, err := Parse(`{ f(a: -1.5e3, b: "a\"é", c: true, d: null, e: ENUM, f: [1, [2]], g: """block "quoted" string""") }`)
+	if err != nil {
+		t.Fatalf("could not parse document: %v", err)
+	}
+
+	arguments := document.Operations[0].SelectionSet[0].(*Field).Arguments
+	want := []struct {
+		kind ValueKind
+		raw  string
+	}{
+		{FloatLiteral, "-1.5e3"},
+		{StringLiteral, `a"é`},
+		{BooleanLiteral, "true"},
+		{NullLiteral, ""},
+		{EnumLiteral, "ENUM"},
+		{ListLiteral, ""},
+		{StringLiteral, `block "quoted" string`},
+	}
+
+	if len(arguments) != len(want) {
+		t.Fatalf("arguments = %d, want %d", len(arguments), len(want))
+	}
+	for i, argument := range arguments {
+		if argument.Value.Kind != want[i].kind {
+			t.Errorf("argument %s kind = %d, want %d", argument.Name, argument.Value.Kind, want[i].kind)
+		}
+		if want[i].raw != "" && argument.Value.Raw != want[i].raw {
+			t.Errorf("argument %s = %q, want %q", argument.Name, argument.Value.Raw, want[i].raw)
+		}
+	}
+
+	list := arguments[5].Value.List
+	if len(list) != 2 || list[0].Raw != "1" || list[1].Kind != ListLiteral || list[1].List[0].Raw != "2" {
+		t.Errorf("list = %+v, want [1, [2]]", list)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		source   string
+		message  string
+		location Location
+	}{
+		{name: "empty document", source: ``, message: "document does not contain any operations"},
+		{name: "empty selection set", source: `{ }`, message: "syntax error: selection sets cannot be empty", location: Location{Line: 1, Column: 3}},
+		{name: "unclosed selection set", source: `{ posts { id }`, message: "syntax error: expected name, found end of document", location: Location{Line: 1, Column: 15}},
+		{name: "empty arguments", source: `{ posts() { id } }`, message: "syntax error: argument lists cannot be empty", location: Location{Line: 1, Column: 9}},
+		{name: "unexpected character", source: "{\n  posts ? }", message: `syntax error: unexpected character '?'`, location: Location{Line: 2, Column: 9}},
+		{name: "unterminated string", source: `{ f(a: "abc) }`, message: "syntax error: unterminated string", location: Location{Line: 1, Column: 8}},
+		{name: "leading zero", source: `{ f(a: 01) }`, message: "syntax error: invalid number", location: Location{Line: 1, Column: 8}},
+		{name: "invalid exponent", source: `{ f(a: 1e) }`, message: "syntax error: invalid number", location: Location{Line: 1, Column: 8}},
+		{name: "variable in constant", source: `query($a: Int = $b) { f }`, location: Location{Line: 1, Column: 17}},
+		{name: "fragment named on", source: `fragment on on Post { id }`, message: "syntax error: fragments cannot be named on", location: Location{Line: 1, Column: 10}},
+		{name: "duplicate fragment", source: `{ ...a } fragment a on Post { id } fragment a on Post { id }`, message: "there can be only one fragment named a", location: Location{Line: 1, Column: 36}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Parse(test.source)
+			if err == nil {
+				t.Fatal("expected a syntax error")
+			}
+
+			var graphqlErr *Error
+			if !errors.As(err, &graphqlErr) {
+				t.Fatalf("error is %T, want *Error", err)
+			}
+
+			if test.message != "" && graphqlErr.Message != test.message {
+				t.Errorf("message = %q, want %q", graphqlErr.Message, test.message)
+			}
+			if test.message == "" && !strings.HasPrefix(graphqlErr.Message, "syntax error: ") {
+				t.Errorf("message = %q, want a syntax error", graphqlErr.Message)
+			}
+
+			if test.location != (Location{}) {
+				if len(graphqlErr.Locations) != 1 || graphqlErr.Locations[0] != test.location {
+					t.Errorf("locations = %+v, want %+v", graphqlErr.Locations, test.location)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,528 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/cosys-io/cosys/common"
+	"github.com/cosys-io/cosys/modules/cms/i18n"
+	"github.com/cosys-io/cosys/modules/cms/schema"
+)
+
+// resolver resolves the queries and mutations of a model through the database core service,
+// so that lifecycle hooks and database wrappers apply as they do to the rest api.
+type resolver struct {
+	cosys     *common.Cosys
+	modelType *modelType
+	options   Options
+}
+
+// queryFields returns the find one and find many query fields of the model.
+func (r *resolver) queryFields() []*FieldDefinition {
+	model := r.modelType.model
+
+	singularName := model.SingularCamelName_()
+	pluralName := model.PluralCamelName_()
+	if pluralName == singularName {
+		pluralName += "List"
+	}
+
+	findOne := &FieldDefinition{
+		Name:        singularName,
+		Description: "Returns the " + model.SingularHumanName_() + " with the given id, or null if not found.",
+		Arguments: []*InputValue{
+			{Name: "id", Type: &NonNull{OfType: IntType}},
+		},
+		Type:    r.modelType.object,
+		Resolve: r.findOne,
+	}
+
+	findMany := &FieldDefinition{
+		Name:        pluralName,
+		Description: "Returns the " + model.PluralHumanName_() + " meeting the given conditions.",
+		Arguments: []*InputValue{
+			{Name: "where", Description: "Conditions the " + model.PluralHumanName_() + " must meet.", Type: r.modelType.filter},
+			{Name: "sort", Description: "Attributes to sort by, in descending order if prefixed with \"-\".", Type: &List{OfType: &NonNull{OfType: StringType}}},
+			{Name: "page", Description: "The page to return, starting from 1.", Type: IntType, Default: json.Number("1")},
+			{Name: "pageSize", Description: "The number of entities per page.", Type: IntType},
+		},
+		Type:    &NonNull{OfType: &List{OfType: &NonNull{OfType: r.modelType.object}}},
+		Resolve: r.findMany,
+	}
+
+	if i18n.IsLocalized(model) {
+		findOne.Arguments = append(findOne.Arguments, &InputValue{
+			Name:        "locale",
+			Description: "The locale of the translation to return.",
+			Type:        StringType,
+		})
+		findMany.Arguments = append(findMany.Arguments, &InputValue{
+			Name:        "locale",
+			Description: "The locale of the entities to return, or the default locale if null.",
+			Type:        StringType,
+		})
+	}
+
+	return []*FieldDefinition{findOne, findMany}
+}
+
+// mutationFields returns the create, update and delete mutation fields of the model.
+func (r *resolver) mutationFields() []*FieldDefinition {
+	model := r.modelType.model
+	typeName := r.modelType.object.TypeName
+
+	create := &FieldDefinition{
+		Name:        "create" + typeName,
+		Description: "Creates a " + model.SingularHumanName_() + " and returns it.",
+		Arguments: []*InputValue{
+			{Name: "data", Type: &NonNull{OfType: r.modelType.input}},
+		},
+		Type:    r.modelType.object,
+		Resolve: r.create,
+	}
+
+	if i18n.IsLocalized(model) {
+		create.Arguments = append(create.Arguments, &InputValue{
+			Name:        "locale",
+			Description: "The locale of the entity, or the default locale if null.",
+			Type:        StringType,
+		})
+	}
+
+	update := &FieldDefinition{
+		Name:        "update" + typeName,
+		Description: "Updates the given attributes of the " + model.SingularHumanName_() + " with the given id and returns it.",
+		Arguments: []*InputValue{
+			{Name: "id", Type: &NonNull{OfType: IntType}},
+			{Name: "data", Type: &NonNull{OfType: r.modelType.input}},
+		},
+		Type:    r.modelType.object,
+		Resolve: r.update,
+	}
+
+	remove := &FieldDefinition{
+		Name:        "delete" + typeName,
+		Description: "Deletes the " + model.SingularHumanName_() + " with the given id and returns it.",
+		Arguments: []*InputValue{
+			{Name: "id", Type: &NonNull{OfType: IntType}},
+		},
+		Type:    r.modelType.object,
+		Resolve: r.delete,
+	}
+
+	return []*FieldDefinition{create, update, remove}
+}
+
+// findOne resolves the find one query.
+func (r *resolver) findOne(params ResolveParams) (any, error) {
+	database, err := r.cosys.Database()
+	if err != nil {
+		return nil, err
+	}
+
+	model := r.modelType.model
+	id := params.Args["id"].(int)
+
+	dbParams := r.selectParams(params)
+
+	if publishedAt, ok := getPublishedAt(model); ok {
+		dbParams.Where = append(dbParams.Where, publishedAt.NotNull())
+	}
+
+	var entity common.Entity
+	if locale, ok := params.Args["locale"].(string); ok && i18n.IsLocalized(model) {
+		if err = i18n.CheckLocale(locale); err != nil {
+			return nil, common.NewValidationError(err.Error())
+		}
+
+		entity, err = i18n.Translation(r.cosys, r.modelType.uid, id, locale, dbParams)
+	} else {
+		dbParams.Where = append(dbParams.Where, model.IdAttribute_().(common.IntAttribute).Eq(id))
+		entity, err = database.FindOne(r.modelType.uid, dbParams)
+	}
+	if err != nil {
+		if common.IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return entity, nil
+}
+
+// findMany resolves the find many query.
+func (r *resolver) findMany(params ResolveParams) (any, error) {
+	database, err := r.cosys.Database()
+	if err != nil {
+		return nil, err
+	}
+
+	model := r.modelType.model
+	dbParams := r.selectParams(params)
+
+	if where, ok := params.Args["where"].(map[string]any); ok {
+		condition, err := r.modelType.condition(where)
+		if err != nil {
+			return nil, err
+		}
+
+		if condition != nil {
+			dbParams.Where = append(dbParams.Where, condition)
+		}
+	}
+
+	if publishedAt, ok := getPublishedAt(model); ok {
+		dbParams.Where = append(dbParams.Where, publishedAt.NotNull())
+	}
+
+	if sort, ok := params.Args["sort"].([]any); ok {
+		for _, key := range sort {
+			order, err := r.modelType.order(key.(string))
+			if err != nil {
+				return nil, err
+			}
+
+			dbParams.OrderBy = append(dbParams.OrderBy, order)
+		}
+	}
+
+	page := 1
+	if value, ok := params.Args["page"].(int); ok {
+		page = value
+	}
+	if page < 1 {
+		return nil, common.NewValidationError(fmt.Sprintf("invalid page: %d", page))
+	}
+
+	pageSize := r.options.DefaultPageSize
+	if value, ok := params.Args["pageSize"].(int); ok {
+		pageSize = value
+	}
+	if pageSize < 1 {
+		return nil, common.NewValidationError(fmt.Sprintf("invalid pageSize: %d", pageSize))
+	}
+	if r.options.MaxPageSize > 0 {
+		pageSize = min(pageSize, r.options.MaxPageSize)
+	}
+
+	dbParams.Limit = int64(pageSize)
+	dbParams.Offset = int64(pageSize) * int64(page-1)
+
+	if i18n.IsLocalized(model) {
+		dbParams.Locale = i18n.DefaultLocale()
+
+		if locale, ok := params.Args["locale"].(string); ok {
+			if err = i18n.CheckLocale(locale); err != nil {
+				return nil, common.NewValidationError(err.Error())
+			}
+
+			dbParams.Locale = locale
+		}
+	}
+
+	return database.FindMany(r.modelType.uid, dbParams)
+}
+
+// create resolves the create mutation.
+func (r *resolver) create(params ResolveParams) (any, error) {
+	database, err := r.cosys.Database()
+	if err != nil {
+		return nil, err
+	}
+
+	model := r.modelType.model
+
+	entity, _, err := r.modelType.newEntity(params.Args["data"].(map[string]any))
+	if err != nil {
+		return nil, err
+	}
+
+	columns := r.modelType.inputColumns()
+
+	if i18n.IsLocalized(model) {
+		locale := i18n.DefaultLocale()
+		if value, ok := params.Args["locale"].(string); ok {
+			locale = value
+		}
+
+		if err = i18n.CheckLocale(locale); err != nil {
+			return nil, common.NewValidationError(err.Error())
+		}
+
+		for _, attr := range model.Attributes_() {
+			var value any
+			switch attr.CamelName() {
+			case schema.LocaleSchema.Name():
+				value = locale
+			case schema.LocalizationIdSchema.Name():
+				value = 0
+			default:
+				continue
+			}
+
+			if err = setField(entity, attr, value); err != nil {
+				return nil, err
+			}
+
+			columns = append(columns, attr)
+		}
+	}
+
+	dbParams := common.NewDBParamsBuilder().
+		Insert(columns...).
+		Context(params.Context).
+		Build()
+
+	return database.Create(r.modelType.uid, entity, dbParams)
+}
+
+// update resolves the update mutation.
+func (r *resolver) update(params ResolveParams) (any, error) {
+	database, err := r.cosys.Database()
+	if err != nil {
+		return nil, err
+	}
+
+	model := r.modelType.model
+	id := params.Args["id"].(int)
+
+	entity, columns, err := r.modelType.newEntity(params.Args["data"].(map[string]any))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(columns) == 0 {
+		return nil, common.NewValidationError("no attributes to update")
+	}
+
+	dbParams := common.NewDBParamsBuilder().
+		Update(columns...).
+		Where(model.IdAttribute_().(common.IntAttribute).Eq(id)).
+		Context(params.Context).
+		Build()
+
+	return database.Update(r.modelType.uid, entity, dbParams)
+}
+
+// delete resolves the delete mutation.
+func (r *resolver) delete(params ResolveParams) (any, error) {
+	database, err := r.cosys.Database()
+	if err != nil {
+		return nil, err
+	}
+
+	model := r.modelType.model
+	id := params.Args["id"].(int)
+
+	dbParams := common.NewDBParamsBuilder().
+		Where(model.IdAttribute_().(common.IntAttribute).Eq(id)).
+		Context(params.Context).
+		Build()
+
+	return database.Delete(r.modelType.uid, dbParams)
+}
+
+// selectParams returns the DBParams selecting the id and the attributes selected by the query,
+// and populating the selected component and dynamic zone attributes.
+func (r *resolver) selectParams(params ResolveParams) common.DBParams {
+	model := r.modelType.model
+
+	selects := []common.Attribute{model.IdAttribute_()}
+	var populate []common.Attribute
+
+	for _, name := range params.Selection {
+		attr, ok := r.modelType.attributes[name]
+		if !ok || attr == model.IdAttribute_() {
+			continue
+		}
+
+		selects = append(selects, attr)
+		if common.IsEmbedded(attr) {
+			populate = append(populate, attr)
+		}
+	}
+
+	return common.NewDBParamsBuilder().
+		Select(selects...).
+		Populate(populate...).
+		Context(params.Context).
+		Build()
+}
+
+// condition returns the where condition of the given filter input,
+// or nil if the filter has no conditions.
+// Operators with null values are ignored.
+func (m *modelType) condition(filter map[string]any) (common.Condition, error) {
+	keys := make([]string, 0, len(filter))
+	for key := range filter {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var conditions []common.Condition
+
+	for _, key := range keys {
+		if filter[key] == nil {
+			continue
+		}
+
+		switch key {
+		case "and", "or":
+			var nested common.Condition
+			for _, item := range filter[key].([]any) {
+				condition, err := m.condition(item.(map[string]any))
+				if err != nil {
+					return nil, err
+				}
+
+				if condition == nil {
+					continue
+				}
+
+				if nested == nil {
+					nested = condition
+				} else if key == "and" {
+					nested = nested.And(condition)
+				} else {
+					nested = nested.Or(condition)
+				}
+			}
+
+			if nested != nil {
+				conditions = append(conditions, nested)
+			}
+		case "not":
+			condition, err := m.condition(filter[key].(map[string]any))
+			if err != nil {
+				return nil, err
+			}
+
+			if condition != nil {
+				conditions = append(conditions, condition.Not())
+			}
+		default:
+			attr, ok := m.attributes[key]
+			if !ok {
+				return nil, common.NewValidationError("attribute not found: " + key)
+			}
+
+			attrConditions, err := attributeConditions(attr, filter[key].(map[string]any))
+			if err != nil {
+				return nil, err
+			}
+
+			conditions = append(conditions, attrConditions...)
+		}
+	}
+
+	if len(conditions) == 0 {
+		return nil, nil
+	}
+
+	condition := conditions[0]
+	for _, right := range conditions[1:] {
+		condition = condition.And(right)
+	}
+
+	return condition, nil
+}
+
+// attributeConditions returns the where conditions on the given attribute of the given attribute filter input.
+func attributeConditions(attr common.Attribute, filter map[string]any) ([]common.Condition, error) {
+	operators := make([]string, 0, len(filter))
+	for operator := range filter {
+		operators = append(operators, operator)
+	}
+	sort.Strings(operators)
+
+	var conditions []common.Condition
+
+	for _, operator := range operators {
+		value := filter[operator]
+		if value == nil {
+			continue
+		}
+
+		if operator == "null" {
+			if value.(bool) {
+				conditions = append(conditions, attr.Null())
+			} else {
+				conditions = append(conditions, attr.NotNull())
+			}
+
+			continue
+		}
+
+		op, ok := filterOperations[operator]
+		if !ok {
+			return nil, common.NewValidationError("invalid operator: " + operator)
+		}
+
+		conditions = append(conditions, &common.ExpressionCondition{
+			Op:    op,
+			Left:  attr,
+			Right: value,
+		})
+	}
+
+	return conditions, nil
+}
+
+// order returns the order-by condition of the given sort key,
+// an attribute name in descending order if prefixed with "-".
+func (m *modelType) order(key string) (*common.Order, error) {
+	name, desc := strings.CutPrefix(key, "-")
+
+	attr, ok := m.attributes[name]
+	if !ok || common.IsEmbedded(attr) {
+		return nil, common.NewValidationError("invalid sort attribute: " + name)
+	}
+
+	if desc {
+		return attr.Desc(), nil
+	}
+
+	return attr.Asc(), nil
+}
+
+// getPublishedAt returns the publishedAt attribute of the given model,
+// and whether the model has draft and publish enabled.
+func getPublishedAt(model common.Model) (common.Attribute, bool) {
+	modelSchema, ok := model.Schema_().(*schema.ModelSchema)
+	if !ok || !modelSchema.DraftAndPublish() {
+		return nil, false
+	}
+
+	for _, attr := range model.Attributes_() {
+		if attr.CamelName() == schema.PublishedAtSchema.Name() {
+			return attr, true
+		}
+	}
+
+	return nil, false
+}
+
+// setField sets the field of the given entity holding the given attribute to the given value.
+func setField(entity common.Entity, attr common.Attribute, value any) error {
+	entityValue := reflect.Indirect(reflect.ValueOf(entity))
+	if entityValue.Kind() != reflect.Struct {
+		return fmt.Errorf("entity is not a struct")
+	}
+
+	field := entityValue.FieldByName(attr.PascalName())
+	if !field.IsValid() {
+		return fmt.Errorf("attribute not found: %s", attr.PascalName())
+	}
+
+	newValue := reflect.ValueOf(value)
+	if !newValue.Type().AssignableTo(field.Type()) {
+		return fmt.Errorf("invalid type for attribute: %s", attr.PascalName())
+	}
+
+	field.Set(newValue)
+	return nil
+}
@@ -0,0 +1,450 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/cosys-io/cosys/common"
+	"github.com/cosys-io/cosys/modules/cms/schema"
+)
+
+// Post is the entity of the test model.
+type Post struct {
+	Id     int    `json:"id"`
+	Title  string `json:"title"`
+	Views  int    `json:"views"`
+	Secret string `json:"secret"`
+}
+
+// PostsModel is the test model.
+type PostsModel struct {
+	*common.ModelBase
+
+	Id     common.IntAttribute
+	Title  common.StringAttribute
+	Views  common.IntAttribute
+	Secret common.StringAttribute
+}
+
+// postsUid is the uid of the test model.
+const postsUid = "api.posts"
+
+// newPostsModel returns the test model, with a non-nullable title and a private secret.
+func newPostsModel(t *testing.T) PostsModel {
+	t.Helper()
+
+	model, err := common.NewModel[Post, PostsModel]("posts", "post", "posts", schema.NewModelSchema(
+		"posts",
+		"Posts",
+		"post",
+		"posts",
+		"Blog posts.",
+		&schema.IdSchema,
+		schema.NewAttrSchema("title", "String", "String", schema.NotNullable),
+		schema.NewAttrSchema("views", "Number", "Int"),
+		schema.NewAttrSchema("secret", "String", "String", schema.Private),
+	))
+	if err != nil {
+		t.Fatalf("could not create model: %v", err)
+	}
+
+	return model
+}
+
+// call is a call of the test database.
+type call struct {
+	method string
+	data   common.Entity
+	params common.DBParams
+}
+
+// testDatabase is a database holding posts in memory, which records its calls.
+// Conditions are not applied, except equality on ids.
+type testDatabase struct {
+	posts []*Post
+	calls []call
+}
+
+func (d *testDatabase) record(method string, data common.Entity, params common.DBParams) {
+	d.calls = append(d.calls, call{method: method, data: data, params: params})
+}
+
+// lastCall returns the last call of the database.
+func (d *testDatabase) lastCall(t *testing.T) call {
+	t.Helper()
+
+	if len(d.calls) == 0 {
+		t.Fatal("database was not called")
+	}
+
+	return d.calls[len(d.calls)-1]
+}
+
+// find returns the post with the id of the equality condition of the given params.
+func (d *testDatabase) find(params common.DBParams) (*Post, error) {
+	for _, condition := range params.Where {
+		expression, ok := condition.(*common.ExpressionCondition)
+		if !ok || expression.Left.CamelName() != "id" {
+			continue
+		}
+
+		for _, post := range d.posts {
+			if post.Id == expression.Right {
+				return post, nil
+			}
+		}
+	}
+
+	return nil, common.NewNotFoundError("post not found")
+}
+
+func (d *testDatabase) FindOne(uid string, params common.DBParams) (common.Entity, error) {
+	d.record("FindOne", nil, params)
+	return d.find(params)
+}
+
+func (d *testDatabase) FindMany(uid string, params common.DBParams) ([]common.Entity, error) {
+	d.record("FindMany", nil, params)
+
+	entities := make([]common.Entity, 0, len(d.posts))
+	for _, post := range d.posts {
+		entities = append(entities, post)
+	}
+
+	return entities, nil
+}
+
+func (d *testDatabase) Create(uid string, data common.Entity, params common.DBParams) (common.Entity, error) {
+	d.record("Create", data, params)
+
+	post := data.(*Post)
+	post.Id = len(d.posts) + 1
+	d.posts = append(d.posts, post)
+
+	return post, nil
+}
+
+func (d *testDatabase) CreateMany(uid string, data []common.Entity, params common.DBParams) ([]common.Entity, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (d *testDatabase) Update(uid string, data common.Entity, params common.DBParams) (common.Entity, error) {
+	d.record("Update", data, params)
+
+	post, err := d.find(params)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, column := range params.Columns {
+		if err = common.SetEntityField(post, column.PascalName(), readField(data, column.PascalName())); err != nil {
+			return nil, err
+		}
+	}
+
+	return post, nil
+}
+
+func (d *testDatabase) UpdateMany(uid string, data common.Entity, params common.DBParams) ([]common.Entity, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (d *testDatabase) Delete(uid string, params common.DBParams) (common.Entity, error) {
+	d.record("Delete", nil, params)
+	return d.find(params)
+}
+
+func (d *testDatabase) DeleteMany(uid string, params common.DBParams) ([]common.Entity, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (d *testDatabase) Close() error {
+	return nil
+}
+
+// readField returns the value of the field with the given name of a post.
+func readField(entity common.Entity, name string) any {
+	post := entity.(*Post)
+	switch name {
+	case "Title":
+		return post.Title
+	case "Views":
+		return post.Views
+	case "Secret":
+		return post.Secret
+	default:
+		return nil
+	}
+}
+
+// database is the database registered by the test module on the cosys apps created by newTestCosys.
+var database *testDatabase
+
+func init() {
+	_ = common.RegisterNamedModule("graphqlTest", func(cosys *common.Cosys) error {
+		return cosys.UseDatabase(database)
+	})
+}
+
+// newTestCosys returns a bootstrapped cosys app with the test model, and its database holding the given posts.
+func newTestCosys(t *testing.T, posts ...*Post) (*common.Cosys, *testDatabase) {
+	t.Helper()
+
+	database = &testDatabase{posts: posts}
+
+	cosys, err := common.New()
+	if err != nil {
+		t.Fatalf("could not create cosys app: %v", err)
+	}
+
+	if err = cosys.AddModel(postsUid, newPostsModel(t)); err != nil {
+		t.Fatalf("could not add model: %v", err)
+	}
+
+	if err = cosys.Bootstrap(); err != nil {
+		t.Fatalf("could not bootstrap cosys app: %v", err)
+	}
+
+	return cosys, database
+}
+
+// newTestSchema returns the schema of a cosys app with the test model, and its database holding the given posts.
+func newTestSchema(t *testing.T, options Options, posts ...*Post) (*Schema, *testDatabase) {
+	t.Helper()
+
+	cosys, database := newTestCosys(t, posts...)
+
+	schema, err := BuildSchema(cosys, options)
+	if err != nil {
+		t.Fatalf("could not build schema: %v", err)
+	}
+
+	return schema, database
+}
+
+// execute parses, validates and executes the given query against the schema,
+// and returns its data as json, and its errors.
+func execute(t *testing.T, schema *Schema, query string, variables map[string]any) (string, []*Error) {
+	t.Helper()
+
+	document, err := Parse(query)
+	if err != nil {
+		t.Fatalf("could not parse query: %v", err)
+	}
+
+	if errs := Validate(schema, document, 0); len(errs) > 0 {
+		return "", errs
+	}
+
+	response := Execute(ExecuteParams{
+		Context:  context.Background(),
+		Schema:   schema,
+		Document: document,
+		Request:  Request{Query: query, Variables: variables},
+	})
+
+	return string(response.Data), response.Errors
+}
+
+// defaultOptions are the options of the schema in tests, as configured by default.
+var defaultOptions = Options{DefaultPageSize: 20, MaxPageSize: 100}
+
+func TestResolveFindOne(t *testing.T) {
+	schema, database := newTestSchema(t, defaultOptions, &Post{Id: 1, Title: "Hello", Views: 3, Secret: "s"})
+
+	data, errs := execute(t, schema, `query($id: Int!) { post(id: $id) { id title } }`, map[string]any{"id": json.Number("1")})
+	if len(errs) > 0 {
+		t.Fatalf("errors = %v", errs)
+	}
+	if data != `{"post":{"id":1,"title":"Hello"}}` {
+		t.Errorf("data = %s", data)
+	}
+
+	params := database.lastCall(t).params
+	if len(params.Select) != 2 || params.Select[0].CamelName() != "id" || params.Select[1].CamelName() != "title" {
+		t.Errorf("selected attributes = %v, want id and title", params.Select)
+	}
+
+	data, errs = execute(t, schema, `{ post(id: 2) { id } }`, nil)
+	if len(errs) > 0 {
+		t.Fatalf("errors = %v", errs)
+	}
+	if data != `{"post":null}` {
+		t.Errorf("data of missing post = %s, want null", data)
+	}
+}
+
+func TestResolveFindMany(t *testing.T) {
+	schema, database := newTestSchema(t, defaultOptions, &Post{Id: 1, Title: "a"}, &Post{Id: 2, Title: "b"})
+
+	data, errs := execute(t, schema, `{ posts(where: {views: {gte: 10}, or: [{title: {eq: "a"}}, {title: {eq: "b"}}]}, sort: ["-views", "title"], page: 3, pageSize: 500) { title } }`, nil)
+	if len(errs) > 0 {
+		t.Fatalf("errors = %v", errs)
+	}
+	if data != `{"posts":[{"title":"a"},{"title":"b"}]}` {
+		t.Errorf("data = %s", data)
+	}
+
+	params := database.lastCall(t).params
+	if params.Limit != 100 || params.Offset != 200 {
+		t.Errorf("limit, offset = %d, %d, want 100, 200 as the page size is capped", params.Limit, params.Offset)
+	}
+	if len(params.Where) != 1 {
+		t.Errorf("conditions = %d, want 1", len(params.Where))
+	}
+	if len(params.OrderBy) != 2 {
+		t.Errorf("orders = %d, want 2", len(params.OrderBy))
+	}
+
+	if _, errs = execute(t, schema, `{ posts { id } }`, nil); len(errs) > 0 {
+		t.Fatalf("errors = %v", errs)
+	}
+	if params = database.lastCall(t).params; params.Limit != 20 || params.Offset != 0 {
+		t.Errorf("default limit, offset = %d, %d, want 20, 0", params.Limit, params.Offset)
+	}
+
+	for _, query := range []string{
+		`{ posts(page: 0) { id } }`,
+		`{ posts(pageSize: 0) { id } }`,
+		`{ posts(sort: ["unknown"]) { id } }`,
+	} {
+		data, errs = execute(t, schema, query, nil)
+		if len(errs) != 1 || common.ErrorCodeOf(errs[0].Unwrap()) != common.ValidationCode {
+			t.Errorf("%s: errors = %v, want a validation error", query, errs)
+		}
+		if data != `{"posts":null}` && data != `null` {
+			t.Errorf("%s: data = %s, want null", query, data)
+		}
+	}
+}
+
+func TestResolveMutations(t *testing.T) {
+	schema, database := newTestSchema(t, Options{DefaultPageSize: 20, Mutations: true})
+
+	data, errs := execute(t, schema, `mutation { createPost(data: {title: "New", secret: "s"}) { id title views } }`, nil)
+	if len(errs) > 0 {
+		t.Fatalf("errors = %v", errs)
+	}
+	if data != `{"createPost":{"id":1,"title":"New","views":0}}` {
+		t.Errorf("data = %s", data)
+	}
+
+	created := database.lastCall(t)
+	if post := created.data.(*Post); post.Title != "New" || post.Secret != "s" {
+		t.Errorf("created post = %+v", post)
+	}
+	var columns []string
+	for _, column := range created.params.Columns {
+		columns = append(columns, column.CamelName())
+	}
+	if strings.Join(columns, ",") != "title,views,secret" {
+		t.Errorf("inserted columns = %v, want all editable attributes", columns)
+	}
+
+	data, errs = execute(t, schema, `mutation { updatePost(id: 1, data: {views: 5}) { title views } }`, nil)
+	if len(errs) > 0 {
+		t.Fatalf("errors = %v", errs)
+	}
+	if data != `{"updatePost":{"title":"New","views":5}}` {
+		t.Errorf("data = %s", data)
+	}
+	if columns := database.lastCall(t).params.Columns; len(columns) != 1 || columns[0].CamelName() != "views" {
+		t.Errorf("updated columns = %v, want only views", columns)
+	}
+
+	_, errs = execute(t, schema, `mutation { updatePost(id: 1, data: {}) { id } }`, nil)
+	if len(errs) != 1 || common.ErrorCodeOf(errs[0].Unwrap()) != common.ValidationCode {
+		t.Errorf("errors of empty update = %v, want a validation error", errs)
+	}
+
+	data, errs = execute(t, schema, `mutation { deletePost(id: 1) { id } }`, nil)
+	if len(errs) > 0 {
+		t.Fatalf("errors = %v", errs)
+	}
+	if data != `{"deletePost":{"id":1}}` || database.lastCall(t).method != "Delete" {
+		t.Errorf("data = %s, last call = %s", data, database.lastCall(t).method)
+	}
+}
+
+func TestMutationsDisabledByDefault(t *testing.T) {
+	schema, database := newTestSchema(t, defaultOptions)
+
+	if schema.Mutation != nil {
+		t.Fatal("schema has mutations although they are not enabled")
+	}
+
+	_, errs := execute(t, schema, `mutation { createPost(data: {title: "New"}) { id } }`, nil)
+	if len(errs) != 1 || errs[0].Message != "schema does not support mutations" {
+		t.Errorf("errors = %v, want schema does not support mutations", errs)
+	}
+	if len(database.calls) != 0 {
+		t.Errorf("database was called: %v", database.calls)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	cosys, database := newTestCosys(t, &Post{Id: 1, Title: "Hello"})
+
+	handler, err := Handler(HandlerOptions{Options: Options{DefaultPageSize: 20, Mutations: true}, MaxDepth: 3})(cosys)
+	if err != nil {
+		t.Fatalf("could not create handler: %v", err)
+	}
+
+	serve := func(method string, body string, query string) (int, Response) {
+		var r *http.Request
+		if method == http.MethodGet {
+			r = httptest.NewRequest(method, "/graphql?query="+url.QueryEscape(query), nil)
+		} else {
+			r = httptest.NewRequest(method, "/graphql", strings.NewReader(body))
+		}
+
+		w := httptest.NewRecorder()
+		handler(w, r)
+
+		var response Response
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("could not decode response %s: %v", w.Body.String(), err)
+		}
+
+		return w.Code, response
+	}
+
+	code, response := serve(http.MethodGet, "", `{ post(id: 1) { title } }`)
+	if code != http.StatusOK || string(response.Data) != `{"post":{"title":"Hello"}}` {
+		t.Errorf("GET query = %d %s %v", code, response.Data, response.Errors)
+	}
+
+	code, response = serve(http.MethodGet, "", `mutation { deletePost(id: 1) { id } }`)
+	if code != http.StatusBadRequest || len(response.Errors) == 0 {
+		t.Errorf("GET mutation = %d %s, want a bad request", code, response.Data)
+	}
+	if database.lastCall(t).method == "Delete" {
+		t.Error("mutation was executed over GET")
+	}
+
+	code, response = serve(http.MethodPost, `{"query": "{ post(id: 1) { id title } }"}`, "")
+	if code != http.StatusOK || string(response.Data) != `{"post":{"id":1,"title":"Hello"}}` {
+		t.Errorf("POST query = %d %s %v", code, response.Data, response.Errors)
+	}
+
+	code, response = serve(http.MethodPost, `{"query": "{ post(id: 1) { secret } }"}`, "")
+	if code != http.StatusBadRequest || len(response.Errors) != 1 || response.Errors[0].Message != "cannot query field secret on type Post" {
+		t.Errorf("private attribute = %d %v, want a validation error", code, response.Errors)
+	}
+
+	code, _ = serve(http.MethodPost, `{"query": "{ post(id: 1) { ...a } } fragment a on Post { ...b } fragment b on Post { id }"}`, "")
+	if code != http.StatusOK {
+		t.Errorf("fragments within the maximum depth = %d, want %d", code, http.StatusOK)
+	}
+
+	code, _ = serve(http.MethodPost, `not json`, "")
+	if code != http.StatusBadRequest {
+		t.Errorf("invalid body = %d, want %d", code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,446 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// Kind is the kind of a graphql type.
+type Kind string
+
+const (
+	ScalarKind      Kind = "SCALAR"
+	ObjectKind      Kind = "OBJECT"
+	InputObjectKind Kind = "INPUT_OBJECT"
+	EnumKind        Kind = "ENUM"
+	ListKind        Kind = "LIST"
+	NonNullKind     Kind = "NON_NULL"
+)
+
+// Type is a graphql type.
+type Type interface {
+	Kind() Kind
+	Name() string   // Name is the name of named types, or empty for list and non-null types.
+	String() string // String returns the type as written in documents, e.g. [Post!]!.
+}
+
+// Scalar is a scalar type.
+type Scalar struct {
+	TypeName    string
+	Description string
+	Serialize   func(any) (any, error) // Serialize converts a resolved value to its json value.
+	Parse       func(any) (any, error) // Parse converts an input json value, with numbers as json.Number, to its go value.
+}
+
+// Enum is an enum type.
+type Enum struct {
+	TypeName    string
+	Description string
+	Values      []*EnumValue
+}
+
+// EnumValue is a value of an enum type.
+type EnumValue struct {
+	Name        string
+	Description string
+}
+
+// Object is an object type.
+type Object struct {
+	TypeName    string
+	Description string
+	Fields      []*FieldDefinition
+}
+
+// FieldDefinition is a field of an object type.
+type FieldDefinition struct {
+	Name        string
+	Description string
+	Arguments   []*InputValue
+	Type        Type
+	Resolve     ResolveFunc // Resolve resolves the field, or reads the field from a map source if nil.
+}
+
+// InputObject is an input object type.
+type InputObject struct {
+	TypeName    string
+	Description string
+	Fields      []*InputValue
+}
+
+// InputValue is an argument of a field, or a field of an input object type.
+type InputValue struct {
+	Name        string
+	Description string
+	Type        Type
+	Default     any // Default is the default json value, or nil if none.
+}
+
+// List is a list type.
+type List struct {
+	OfType Type
+}
+
+// NonNull is a non-null type.
+type NonNull struct {
+	OfType Type
+}
+
+func (*Scalar) Kind() Kind      { return ScalarKind }
+func (*Enum) Kind() Kind        { return EnumKind }
+func (*Object) Kind() Kind      { return ObjectKind }
+func (*InputObject) Kind() Kind { return InputObjectKind }
+func (*List) Kind() Kind        { return ListKind }
+func (*NonNull) Kind() Kind     { return NonNullKind }
+
+func (s *Scalar) Name() string      { return s.TypeName }
+func (e *Enum) Name() string        { return e.TypeName }
+func (o *Object) Name() string      { return o.TypeName }
+func (i *InputObject) Name() string { return i.TypeName }
+func (*List) Name() string          { return "" }
+func (*NonNull) Name() string       { return "" }
+
+func (s *Scalar) String() string      { return s.TypeName }
+func (e *Enum) String() string        { return e.TypeName }
+func (o *Object) String() string      { return o.TypeName }
+func (i *InputObject) String() string { return i.TypeName }
+func (l *List) String() string        { return "[" + l.OfType.String() + "]" }
+func (n *NonNull) String() string     { return n.OfType.String() + "!" }
+
+// Field returns the field of the object type with the given name, or nil if not found.
+func (o *Object) Field(name string) *FieldDefinition {
+	for _, field := range o.Fields {
+		if field.Name == name {
+			return field
+		}
+	}
+
+	return nil
+}
+
+// Field returns the field of the input object type with the given name, or nil if not found.
+func (i *InputObject) Field(name string) *InputValue {
+	for _, field := range i.Fields {
+		if field.Name == name {
+			return field
+		}
+	}
+
+	return nil
+}
+
+// Value returns the value of the enum type with the given name, or nil if not found.
+func (e *Enum) Value(name string) *EnumValue {
+	for _, value := range e.Values {
+		if value.Name == name {
+			return value
+		}
+	}
+
+	return nil
+}
+
+// ResolveFunc resolves the value of a field.
+type ResolveFunc func(params ResolveParams) (any, error)
+
+// ResolveParams are the inputs of a ResolveFunc.
+type ResolveParams struct {
+	Context   context.Context
+	Source    any            // Source is the resolved value of the parent object.
+	Args      map[string]any // Args are the coerced arguments, including defaults.
+	Selection []string       // Selection are the names of the fields selected on the resolved objects, if any.
+	Schema    *Schema
+}
+
+// NamedType returns the named type wrapped by the given list and non-null types.
+func NamedType(t Type) Type {
+	for {
+		switch wrapped := t.(type) {
+		case *List:
+			t = wrapped.OfType
+		case *NonNull:
+			t = wrapped.OfType
+		default:
+			return t
+		}
+	}
+}
+
+// IsInputType returns whether values of the given type can be inputs.
+func IsInputType(t Type) bool {
+	switch NamedType(t).(type) {
+	case *Scalar, *Enum, *InputObject:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsLeafType returns whether the given type is a scalar or enum type.
+func IsLeafType(t Type) bool {
+	switch NamedType(t).(type) {
+	case *Scalar, *Enum:
+		return true
+	default:
+		return false
+	}
+}
+
+// Schema is a graphql schema.
+type Schema struct {
+	Query    *Object
+	Mutation *Object
+	types    map[string]Type
+}
+
+// NewSchema returns the schema with the given root types, and all named types reachable from them,
+// the built-in scalars and the introspection types.
+// Throws an error if different types have the same name.
+func NewSchema(query *Object, mutation *Object) (*Schema, error) {
+	schema := &Schema{
+		Query:    query,
+		Mutation: mutation,
+		types:    map[string]Type{},
+	}
+
+	roots := []Type{query, IntType, FloatType, StringType, BooleanType, IDType, schemaType}
+	if mutation != nil {
+		roots = append(roots, mutation)
+	}
+
+	for _, root := range roots {
+		if err := schema.collect(root); err != nil {
+			return nil, err
+		}
+	}
+
+	return schema, nil
+}
+
+// collect adds the given type and all named types reachable from it to the schema.
+func (s *Schema) collect(t Type) error {
+	t = NamedType(t)
+
+	if existing, ok := s.types[t.Name()]; ok {
+		if existing != t {
+			return fmt.Errorf("duplicate type: %s", t.Name())
+		}
+
+		return nil
+	}
+	s.types[t.Name()] = t
+
+	switch t := t.(type) {
+	case *Object:
+		for _, field := range t.Fields {
+			if err := s.collect(field.Type); err != nil {
+				return err
+			}
+
+			for _, argument := range field.Arguments {
+				if err := s.collect(argument.Type); err != nil {
+					return err
+				}
+			}
+		}
+	case *InputObject:
+		for _, field := range t.Fields {
+			if err := s.collect(field.Type); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Type returns the named type of the schema with the given name, or nil if not found.
+func (s *Schema) Type(name string) Type {
+	return s.types[name]
+}
+
+// Types returns all named types of the schema, sorted by name.
+func (s *Schema) Types() []Type {
+	names := make([]string, 0, len(s.types))
+	for name := range s.types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	types := make([]Type, len(names))
+	for index, name := range names {
+		types[index] = s.types[name]
+	}
+
+	return types
+}
+
+// Built-in scalars
+
+// IntType is the built-in Int scalar of signed 32-bit integers.
+var IntType = &Scalar{
+	TypeName:    "Int",
+	Description: "The `Int` scalar type represents non-fractional signed whole numeric values between -2^31 and 2^31-1.",
+	Serialize: func(value any) (any, error) {
+		i, err := toInt(value)
+		if err != nil {
+			return nil, err
+		}
+
+		if i < math.MinInt32 || i > math.MaxInt32 {
+			return nil, fmt.Errorf("Int cannot represent non 32-bit signed integer value: %d", i)
+		}
+
+		return i, nil
+	},
+	Parse: func(value any) (any, error) {
+		number, ok := value.(json.Number)
+		if !ok {
+			return nil, fmt.Errorf("Int cannot represent non-integer value: %v", value)
+		}
+
+		i, err := strconv.ParseInt(string(number), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("Int cannot represent non 32-bit signed integer value: %s", number)
+		}
+
+		return int(i), nil
+	},
+}
+
+// FloatType is the built-in Float scalar of double-precision numbers.
+var FloatType = &Scalar{
+	TypeName:    "Float",
+	Description: "The `Float` scalar type represents signed double-precision fractional values.",
+	Serialize: func(value any) (any, error) {
+		switch value := value.(type) {
+		case float32:
+			return float64(value), nil
+		case float64:
+			return value, nil
+		default:
+			i, err := toInt(value)
+			return float64(i), err
+		}
+	},
+	Parse: func(value any) (any, error) {
+		number, ok := value.(json.Number)
+		if !ok {
+			return nil, fmt.Errorf("Float cannot represent non-numeric value: %v", value)
+		}
+
+		return number.Float64()
+	},
+}
+
+// StringType is the built-in String scalar of utf-8 strings.
+var StringType = &Scalar{
+	TypeName:    "String",
+	Description: "The `String` scalar type represents textual data, represented as UTF-8 character sequences.",
+	Serialize: func(value any) (any, error) {
+		switch value := value.(type) {
+		case string:
+			return value, nil
+		case fmt.Stringer:
+			return value.String(), nil
+		default:
+			return nil, fmt.Errorf("String cannot represent value: %v", value)
+		}
+	},
+	Parse: func(value any) (any, error) {
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("String cannot represent a non string value: %v", value)
+		}
+
+		return s, nil
+	},
+}
+
+// BooleanType is the built-in Boolean scalar.
+var BooleanType = &Scalar{
+	TypeName:    "Boolean",
+	Description: "The `Boolean` scalar type represents `true` or `false`.",
+	Serialize: func(value any) (any, error) {
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("Boolean cannot represent a non boolean value: %v", value)
+		}
+
+		return b, nil
+	},
+	Parse: func(value any) (any, error) {
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("Boolean cannot represent a non boolean value: %v", value)
+		}
+
+		return b, nil
+	},
+}
+
+// IDType is the built-in ID scalar of unique identifiers, serialized as strings.
+var IDType = &Scalar{
+	TypeName:    "ID",
+	Description: "The `ID` scalar type represents a unique identifier, serialized as a string.",
+	Serialize: func(value any) (any, error) {
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+
+		i, err := toInt(value)
+		if err != nil {
+			return nil, fmt.Errorf("ID cannot represent value: %v", value)
+		}
+
+		return strconv.FormatInt(i, 10), nil
+	},
+	Parse: func(value any) (any, error) {
+		switch value := value.(type) {
+		case string:
+			return value, nil
+		case json.Number:
+			if _, err := value.Int64(); err != nil {
+				return nil, fmt.Errorf("ID cannot represent value: %s", value)
+			}
+
+			return string(value), nil
+		default:
+			return nil, fmt.Errorf("ID cannot represent value: %v", value)
+		}
+	},
+}
+
+// toInt converts a go integer value to an int64.
+func toInt(value any) (int64, error) {
+	switch value := value.(type) {
+	case int:
+		return int64(value), nil
+	case int8:
+		return int64(value), nil
+	case int16:
+		return int64(value), nil
+	case int32:
+		return int64(value), nil
+	case int64:
+		return value, nil
+	case uint8:
+		return int64(value), nil
+	case uint16:
+		return int64(value), nil
+	case uint32:
+		return int64(value), nil
+	case float64:
+		if value != math.Trunc(value) {
+			return 0, fmt.Errorf("Int cannot represent non-integer value: %v", value)
+		}
+
+		return int64(value), nil
+	default:
+		return 0, fmt.Errorf("Int cannot represent value: %v", value)
+	}
+}
@@ -0,0 +1,523 @@
+package internal
+
+import (
+	"fmt"
+)
+
+// variableUsage is a use of a variable in an argument or input field of the given type.
+type variableUsage struct {
+	value        *Value
+	locationType Type // locationType is nil for variables in scalar literals, which can be of any type.
+	hasDefault   bool // hasDefault specifies that the argument or input field has a default value.
+}
+
+// validator validates documents against a schema before execution.
+type validator struct {
+	schema   *Schema
+	document *Document
+	maxDepth int
+	errors   []*Error
+
+	fragmentUsages map[string][]variableUsage
+}
+
+// Validate validates the given document against the schema,
+// and returns the errors that prevent it from being executed.
+// Selection sets may be nested up to the given maximum depth, or to any depth if not positive.
+func Validate(schema *Schema, document *Document, maxDepth int) []*Error {
+	v := &validator{
+		schema:         schema,
+		document:       document,
+		maxDepth:       maxDepth,
+		fragmentUsages: map[string][]variableUsage{},
+	}
+
+	v.validateOperationNames()
+
+	cyclic := v.validateFragmentCycles()
+
+	for _, fragment := range document.Fragments {
+		parent, ok := schema.Type(fragment.TypeCondition).(*Object)
+		if !ok {
+			v.errorf(fragment.Location, "fragment %s cannot condition on non object type %s", fragment.Name, fragment.TypeCondition)
+			continue
+		}
+
+		var usages []variableUsage
+		v.validateDirectives(fragment.Directives, &usages)
+		v.validateSelectionSet(fragment.SelectionSet, parent, &usages)
+		v.fragmentUsages[fragment.Name] = usages
+	}
+
+	usedFragments := map[string]bool{}
+	for _, operation := range document.Operations {
+		v.validateOperation(operation, usedFragments, cyclic)
+	}
+
+	for name, fragment := range document.Fragments {
+		if !usedFragments[name] {
+			v.errorf(fragment.Location, "fragment %s is never used", name)
+		}
+	}
+
+	return v.errors
+}
+
+// errorf adds a validation error at the given location.
+func (v *validator) errorf(location Location, format string, args ...any) {
+	v.errors = append(v.errors, errorf(location, format, args...))
+}
+
+// validateOperationNames checks that operation names are unique,
+// and that anonymous operations are the only operation of the document.
+func (v *validator) validateOperationNames() {
+	names := map[string]bool{}
+	for _, operation := range v.document.Operations {
+		if operation.Name == "" {
+			if len(v.document.Operations) > 1 {
+				v.errorf(operation.Location, "this anonymous operation must be the only defined operation")
+			}
+
+			continue
+		}
+
+		if names[operation.Name] {
+			v.errorf(operation.Location, "there can be only one operation named %s", operation.Name)
+		}
+		names[operation.Name] = true
+	}
+}
+
+// validateFragmentCycles checks that fragments do not spread themselves,
+// and returns whether any fragment does.
+func (v *validator) validateFragmentCycles() bool {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	cyclic := false
+	states := map[string]int{}
+
+	var visit func(fragment *Fragment)
+	visit = func(fragment *Fragment) {
+		states[fragment.Name] = visiting
+
+		for _, spread := range fragmentSpreads(fragment.SelectionSet) {
+			next, ok := v.document.Fragments[spread.Name]
+			if !ok {
+				continue
+			}
+
+			switch states[spread.Name] {
+			case visiting:
+				cyclic = true
+				v.errorf(spread.Location, "cannot spread fragment %s within itself", spread.Name)
+			case unvisited:
+				visit(next)
+			}
+		}
+
+		states[fragment.Name] = visited
+	}
+
+	for _, fragment := range v.document.Fragments {
+		if states[fragment.Name] == unvisited {
+			visit(fragment)
+		}
+	}
+
+	return cyclic
+}
+
+// fragmentSpreads returns the fragment spreads in the given selection set, including in inline fragments.
+func fragmentSpreads(selectionSet []Selection) []*FragmentSpread {
+	var spreads []*FragmentSpread
+	for _, selection := range selectionSet {
+		switch selection := selection.(type) {
+		case *Field:
+			spreads = append(spreads, fragmentSpreads(selection.SelectionSet)...)
+		case *FragmentSpread:
+			spreads = append(spreads, selection)
+		case *InlineFragment:
+			spreads = append(spreads, fragmentSpreads(selection.SelectionSet)...)
+		}
+	}
+
+	return spreads
+}
+
+// validateOperation validates an operation, and marks the fragments it uses.
+func (v *validator) validateOperation(operation *Operation, usedFragments map[string]bool, cyclic bool) {
+	var root *Object
+	switch operation.Type {
+	case "query":
+		root = v.schema.Query
+	case "mutation":
+		root = v.schema.Mutation
+		if root == nil {
+			v.errorf(operation.Location, "schema does not support mutations")
+			return
+		}
+	default:
+		v.errorf(operation.Location, "schema does not support %ss", operation.Type)
+		return
+	}
+
+	defined := map[string]bool{}
+	variables := map[string]Type{}
+	variableDefaults := map[string]bool{}
+	for _, definition := range operation.Variables {
+		if defined[definition.Name] {
+			v.errorf(definition.Location, "there can be only one variable named $%s", definition.Name)
+			continue
+		}
+		defined[definition.Name] = true
+
+		variableType, err := v.schema.TypeOf(definition.Type)
+		if err != nil {
+			v.errorf(definition.Location, "variable $%s: %s", definition.Name, err)
+			continue
+		}
+
+		if !IsInputType(variableType) {
+			v.errorf(definition.Location, "variable $%s cannot be non-input type %s", definition.Name, variableType)
+			continue
+		}
+
+		if definition.Default != nil {
+			if _, err = coerceLiteral(definition.Default, variableType, nil); err != nil {
+				v.errorf(definition.Default.Location, "variable $%s has invalid default value: %s", definition.Name, err)
+			}
+		}
+
+		variables[definition.Name] = variableType
+		variableDefaults[definition.Name] = definition.Default != nil && definition.Default.Kind != NullLiteral
+	}
+
+	var usages []variableUsage
+	v.validateDirectives(operation.Directives, &usages)
+	v.validateSelectionSet(operation.SelectionSet, root, &usages)
+
+	fragments := map[string]bool{}
+	v.usedFragments(operation.SelectionSet, fragments)
+	for name := range fragments {
+		usedFragments[name] = true
+		usages = append(usages, v.fragmentUsages[name]...)
+	}
+
+	used := map[string]bool{}
+	for _, usage := range usages {
+		name := usage.value.Raw
+		used[name] = true
+
+		if !defined[name] {
+			v.errorf(usage.value.Location, "variable $%s is not defined%s", name, operationName(operation))
+			continue
+		}
+
+		variableType, ok := variables[name]
+		if !ok || usage.locationType == nil {
+			continue
+		}
+
+		locationType := usage.locationType
+		if nonNull, ok := locationType.(*NonNull); ok && (usage.hasDefault || variableDefaults[name]) {
+			if _, ok := variableType.(*NonNull); !ok {
+				locationType = nonNull.OfType
+			}
+		}
+
+		if !isSubTypeOf(variableType, locationType) {
+			v.errorf(usage.value.Location, "variable $%s of type %s used in position expecting type %s", name, variableType, usage.locationType)
+		}
+	}
+
+	for _, definition := range operation.Variables {
+		if !used[definition.Name] {
+			v.errorf(definition.Location, "variable $%s is never used%s", definition.Name, operationName(operation))
+		}
+	}
+
+	if v.maxDepth > 0 && !cyclic {
+		if depth := v.depth(operation.SelectionSet); depth > v.maxDepth {
+			v.errorf(operation.Location, "operation exceeds the maximum depth of %d", v.maxDepth)
+		}
+	}
+}
+
+// operationName returns the name of the operation for error messages, e.g. " in operation getPosts".
+func operationName(operation *Operation) string {
+	if operation.Name == "" {
+		return ""
+	}
+
+	return " in operation " + operation.Name
+}
+
+// usedFragments adds the names of the fragments spread in the given selection set, directly or indirectly.
+func (v *validator) usedFragments(selectionSet []Selection, used map[string]bool) {
+	for _, spread := range fragmentSpreads(selectionSet) {
+		if used[spread.Name] {
+			continue
+		}
+		used[spread.Name] = true
+
+		if fragment, ok := v.document.Fragments[spread.Name]; ok {
+			v.usedFragments(fragment.SelectionSet, used)
+		}
+	}
+}
+
+// depth returns the maximum depth of nested fields in the given selection set.
+func (v *validator) depth(selectionSet []Selection) int {
+	maxDepth := 0
+	for _, selection := range selectionSet {
+		var depth int
+		switch selection := selection.(type) {
+		case *Field:
+			if selection.SelectionSet != nil {
+				depth = 1 + v.depth(selection.SelectionSet)
+			}
+		case *FragmentSpread:
+			if fragment, ok := v.document.Fragments[selection.Name]; ok {
+				depth = v.depth(fragment.SelectionSet)
+			}
+		case *InlineFragment:
+			depth = v.depth(selection.SelectionSet)
+		}
+
+		maxDepth = max(maxDepth, depth)
+	}
+
+	return maxDepth
+}
+
+// validateSelectionSet validates the selections of a selection set on the given object type.
+func (v *validator) validateSelectionSet(selectionSet []Selection, parent *Object, usages *[]variableUsage) {
+	for _, selection := range selectionSet {
+		switch selection := selection.(type) {
+		case *Field:
+			v.validateField(selection, parent, usages)
+		case *FragmentSpread:
+			v.validateDirectives(selection.Directives, usages)
+
+			fragment, ok := v.document.Fragments[selection.Name]
+			if !ok {
+				v.errorf(selection.Location, "unknown fragment %s", selection.Name)
+				continue
+			}
+
+			if fragment.TypeCondition != parent.Name() && v.schema.Type(fragment.TypeCondition) != nil {
+				v.errorf(selection.Location, "fragment %s cannot be spread here as objects of type %s can never be of type %s", selection.Name, parent, fragment.TypeCondition)
+			}
+		case *InlineFragment:
+			v.validateDirectives(selection.Directives, usages)
+
+			if selection.TypeCondition != "" && selection.TypeCondition != parent.Name() {
+				if _, ok := v.schema.Type(selection.TypeCondition).(*Object); !ok {
+					v.errorf(selection.Location, "fragment cannot condition on non object type %s", selection.TypeCondition)
+				} else {
+					v.errorf(selection.Location, "fragment cannot be spread here as objects of type %s can never be of type %s", parent, selection.TypeCondition)
+				}
+				continue
+			}
+
+			v.validateSelectionSet(selection.SelectionSet, parent, usages)
+		}
+	}
+}
+
+// validateField validates a field selection on the given object type.
+func (v *validator) validateField(field *Field, parent *Object, usages *[]variableUsage) {
+	v.validateDirectives(field.Directives, usages)
+
+	definition := v.schema.FieldDefinition(parent, field.Name)
+	if definition == nil {
+		v.errorf(field.Location, "cannot query field %s on type %s", field.Name, parent)
+		return
+	}
+
+	v.validateArguments(field.Arguments, definition.Arguments, field.Location, "field "+field.Name, usages)
+
+	fieldType := NamedType(definition.Type)
+	if IsLeafType(fieldType) {
+		if field.SelectionSet != nil {
+			v.errorf(field.Location, "field %s must not have a selection since type %s has no subfields", field.Name, definition.Type)
+		}
+
+		return
+	}
+
+	if field.SelectionSet == nil {
+		v.errorf(field.Location, "field %s of type %s must have a selection of subfields", field.Name, definition.Type)
+		return
+	}
+
+	v.validateSelectionSet(field.SelectionSet, fieldType.(*Object), usages)
+}
+
+// validateDirectives validates the @skip and @include directives of a selection.
+func (v *validator) validateDirectives(directives []*Directive, usages *[]variableUsage) {
+	seen := map[string]bool{}
+	for _, directive := range directives {
+		if directive.Name != "skip" && directive.Name != "include" {
+			v.errorf(directive.Location, "unknown directive @%s", directive.Name)
+			continue
+		}
+
+		if seen[directive.Name] {
+			v.errorf(directive.Location, "the directive @%s can only be used once at this location", directive.Name)
+		}
+		seen[directive.Name] = true
+
+		v.validateArguments(directive.Arguments, ifArguments, directive.Location, "directive @"+directive.Name, usages)
+	}
+}
+
+// ifArguments are the arguments of the @skip and @include directives.
+var ifArguments = []*InputValue{
+	{
+		Name: "if",
+		Type: &NonNull{OfType: BooleanType},
+	},
+}
+
+// validateArguments validates the given arguments against the argument definitions of a field or directive.
+func (v *validator) validateArguments(arguments []*Argument, definitions []*InputValue, location Location, owner string, usages *[]variableUsage) {
+	provided := map[string]bool{}
+	for _, argument := range arguments {
+		if provided[argument.Name] {
+			v.errorf(argument.Location, "there can be only one argument named %s", argument.Name)
+			continue
+		}
+		provided[argument.Name] = true
+
+		var definition *InputValue
+		for _, candidate := range definitions {
+			if candidate.Name == argument.Name {
+				definition = candidate
+			}
+		}
+
+		if definition == nil {
+			v.errorf(argument.Location, "unknown argument %s on %s", argument.Name, owner)
+			continue
+		}
+
+		if _, err := coerceLiteral(argument.Value, definition.Type, nil); err != nil {
+			v.errorf(argument.Value.Location, "argument %s has invalid value %s: %s", argument.Name, printLiteral(argument.Value), err)
+		}
+
+		collectVariableUsages(argument.Value, definition.Type, definition.Default != nil, usages)
+	}
+
+	for _, definition := range definitions {
+		if _, nonNull := definition.Type.(*NonNull); nonNull && definition.Default == nil && !provided[definition.Name] {
+			v.errorf(location, "%s argument %s of type %s is required, but it was not provided", owner, definition.Name, definition.Type)
+		}
+	}
+}
+
+// collectVariableUsages adds the variables used in the given value of the given type.
+func collectVariableUsages(value *Value, t Type, hasDefault bool, usages *[]variableUsage) {
+	if _, scalar := NamedType(t).(*Scalar); scalar && (value.Kind == ListLiteral || value.Kind == ObjectLiteral) {
+		t = nil
+	}
+
+	switch value.Kind {
+	case VariableLiteral:
+		*usages = append(*usages, variableUsage{
+			value:        value,
+			locationType: t,
+			hasDefault:   hasDefault,
+		})
+	case ListLiteral:
+		itemType := t
+		if nonNull, ok := itemType.(*NonNull); ok {
+			itemType = nonNull.OfType
+		}
+		if list, ok := itemType.(*List); ok {
+			itemType = list.OfType
+		}
+		if t == nil {
+			itemType = nil
+		}
+
+		for _, item := range value.List {
+			collectVariableUsages(item, itemType, false, usages)
+		}
+	case ObjectLiteral:
+		var inputObject *InputObject
+		if t != nil {
+			inputObject, _ = NamedType(t).(*InputObject)
+		}
+
+		for _, field := range value.Fields {
+			if inputObject == nil || inputObject.Field(field.Name) == nil {
+				collectVariableUsages(field.Value, nil, false, usages)
+				continue
+			}
+
+			definition := inputObject.Field(field.Name)
+			collectVariableUsages(field.Value, definition.Type, definition.Default != nil, usages)
+		}
+	}
+}
+
+// isSubTypeOf returns whether values of the given variable type can be used where the given type is expected.
+func isSubTypeOf(variableType Type, locationType Type) bool {
+	if locationNonNull, ok := locationType.(*NonNull); ok {
+		variableNonNull, ok := variableType.(*NonNull)
+		if !ok {
+			return false
+		}
+
+		return isSubTypeOf(variableNonNull.OfType, locationNonNull.OfType)
+	}
+
+	if variableNonNull, ok := variableType.(*NonNull); ok {
+		return isSubTypeOf(variableNonNull.OfType, locationType)
+	}
+
+	if locationList, ok := locationType.(*List); ok {
+		variableList, ok := variableType.(*List)
+		if !ok {
+			return false
+		}
+
+		return isSubTypeOf(variableList.OfType, locationList.OfType)
+	}
+
+	if _, ok := variableType.(*List); ok {
+		return false
+	}
+
+	return variableType == locationType
+}
+
+// TypeOf returns the type referenced by the given type reference.
+// Throws an error if the named type does not exist.
+func (s *Schema) TypeOf(ref *TypeRef) (Type, error) {
+	var t Type
+	if ref.List != nil {
+		itemType, err := s.TypeOf(ref.List)
+		if err != nil {
+			return nil, err
+		}
+
+		t = &List{OfType: itemType}
+	} else {
+		t = s.Type(ref.Name)
+		if t == nil {
+			return nil, fmt.Errorf("unknown type %s", ref.Name)
+		}
+	}
+
+	if ref.NonNull {
+		t = &NonNull{OfType: t}
+	}
+
+	return t, nil
+}
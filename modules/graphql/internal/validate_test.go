@@ -0,0 +1,65 @@
+package internal
+
+import "testing"
+
+func TestValidate(t *testing.T) {
+	schema, _ := newTestSchema(t, Options{DefaultPageSize: 20, Mutations: true})
+
+	for _, test := range []struct {
+		name     string
+		query    string
+		maxDepth int
+		message  string
+		location Location
+	}{
+		{name: "valid query", query: `query($id: Int!) { post(id: $id) { ...fields } } fragment fields on Post { id title }`},
+		{name: "valid mutation", query: `mutation { updatePost(id: 1, data: {views: 2}) { id } }`},
+		{name: "unknown field", query: `{ post(id: 1) { body } }`, message: "cannot query field body on type Post", location: Location{Line: 1, Column: 17}},
+		{name: "private field", query: `{ post(id: 1) { secret } }`, message: "cannot query field secret on type Post", location: Location{Line: 1, Column: 17}},
+		{name: "missing selection", query: `{ post(id: 1) }`, message: "field post of type Post must have a selection of subfields", location: Location{Line: 1, Column: 3}},
+		{name: "selection on scalar", query: `{ post(id: 1) { id { value } } }`, message: "field id must not have a selection since type Int! has no subfields", location: Location{Line: 1, Column: 17}},
+		{name: "missing argument", query: `{ post { id } }`, message: "field post argument id of type Int! is required, but it was not provided", location: Location{Line: 1, Column: 3}},
+		{name: "unknown argument", query: `{ post(id: 1, slug: "a") { id } }`, message: "unknown argument slug on field post", location: Location{Line: 1, Column: 15}},
+		{name: "invalid argument", query: `{ post(id: "1") { id } }`, message: `argument id has invalid value "1": Int cannot represent non-integer value: 1`, location: Location{Line: 1, Column: 12}},
+		{name: "invalid filter", query: `{ posts(where: {views: {like: 1}}) { id } }`, message: "argument where has invalid value {views: {like: 1}}: in field views: field like is not defined by type IntFilter", location: Location{Line: 1, Column: 16}},
+		{name: "undefined variable", query: `{ post(id: $id) { id } }`, message: "variable $id is not defined", location: Location{Line: 1, Column: 12}},
+		{name: "unused variable", query: `query($id: Int) { posts { id } }`, message: "variable $id is never used", location: Location{Line: 1, Column: 7}},
+		{name: "variable type mismatch", query: `query($id: String!) { post(id: $id) { id } }`, message: "variable $id of type String! used in position expecting type Int!", location: Location{Line: 1, Column: 32}},
+		{name: "unknown fragment", query: `{ post(id: 1) { ...fields } }`, message: "unknown fragment fields", location: Location{Line: 1, Column: 17}},
+		{name: "unused fragment", query: `{ posts { id } } fragment fields on Post { id }`, message: "fragment fields is never used", location: Location{Line: 1, Column: 18}},
+		{name: "fragment cycle", query: `{ post(id: 1) { ...a } } fragment a on Post { ...b } fragment b on Post { ...a }`, message: "cannot spread fragment a within itself", location: Location{Line: 1, Column: 75}},
+		{name: "anonymous operation with others", query: `{ posts { id } } query named { posts { id } }`, message: "this anonymous operation must be the only defined operation", location: Location{Line: 1, Column: 1}},
+		{name: "duplicate operation", query: `query a { posts { id } } query a { posts { id } }`, message: "there can be only one operation named a", location: Location{Line: 1, Column: 26}},
+		{name: "subscription", query: `subscription { posts { id } }`, message: "schema does not support subscriptions", location: Location{Line: 1, Column: 1}},
+		{name: "within maximum depth", query: `{ posts { id } }`, maxDepth: 2},
+		{name: "maximum depth", query: `{ __schema { types { fields { type { name } } } } }`, maxDepth: 3, message: "operation exceeds the maximum depth of 3", location: Location{Line: 1, Column: 1}},
+		{name: "maximum depth through fragments", query: `{ __schema { ...types } } fragment types on __Schema { types { fields { type { name } } } }`, maxDepth: 3, message: "operation exceeds the maximum depth of 3", location: Location{Line: 1, Column: 1}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			document, err := Parse(test.query)
+			if err != nil {
+				t.Fatalf("could not parse query: %v", err)
+			}
+
+			errs := Validate(schema, document, test.maxDepth)
+
+			if test.message == "" {
+				if len(errs) > 0 {
+					t.Errorf("errors = %v, want none", errs)
+				}
+				return
+			}
+
+			if len(errs) == 0 {
+				t.Fatal("expected a validation error")
+			}
+
+			if errs[0].Message != test.message {
+				t.Errorf("message = %q, want %q", errs[0].Message, test.message)
+			}
+			if len(errs[0].Locations) != 1 || errs[0].Locations[0] != test.location {
+				t.Errorf("locations = %+v, want %+v", errs[0].Locations, test.location)
+			}
+		})
+	}
+}
@@ -0,0 +1,341 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// coerceLiteral coerces a value literal to a go value of the given input type,
+// with variables replaced by their coerced values.
+// If variables is nil, variables are not replaced, which is used to validate literals before execution.
+func coerceLiteral(value *Value, t Type, variables map[string]any) (any, error) {
+	if value.Kind == VariableLiteral {
+		if variables == nil {
+			return nil, nil
+		}
+
+		variable, ok := variables[value.Raw]
+		if !ok {
+			variable = nil
+		}
+
+		if _, nonNull := t.(*NonNull); nonNull && variable == nil {
+			return nil, fmt.Errorf("expected non-null value of type %s, found null variable $%s", t, value.Raw)
+		}
+
+		return variable, nil
+	}
+
+	if nonNull, ok := t.(*NonNull); ok {
+		if value.Kind == NullLiteral {
+			return nil, fmt.Errorf("expected non-null value of type %s, found null", t)
+		}
+
+		return coerceLiteral(value, nonNull.OfType, variables)
+	}
+
+	if value.Kind == NullLiteral {
+		return nil, nil
+	}
+
+	switch t := t.(type) {
+	case *List:
+		if value.Kind != ListLiteral {
+			item, err := coerceLiteral(value, t.OfType, variables)
+			if err != nil {
+				return nil, err
+			}
+
+			return []any{item}, nil
+		}
+
+		items := make([]any, len(value.List))
+		for index, itemValue := range value.List {
+			item, err := coerceLiteral(itemValue, t.OfType, variables)
+			if err != nil {
+				return nil, fmt.Errorf("at index %d: %w", index, err)
+			}
+
+			items[index] = item
+		}
+
+		return items, nil
+	case *InputObject:
+		if value.Kind != ObjectLiteral {
+			return nil, fmt.Errorf("expected value of type %s, found %s", t, printLiteral(value))
+		}
+
+		fields := map[string]*Value{}
+		for _, field := range value.Fields {
+			if t.Field(field.Name) == nil {
+				return nil, fmt.Errorf("field %s is not defined by type %s", field.Name, t)
+			}
+
+			fields[field.Name] = field.Value
+		}
+
+		object := map[string]any{}
+		for _, field := range t.Fields {
+			fieldValue, ok := fields[field.Name]
+			if ok && fieldValue.Kind == VariableLiteral && variables != nil {
+				_, ok = variables[fieldValue.Raw]
+			}
+
+			if !ok {
+				if err := setDefault(object, field, t); err != nil {
+					return nil, err
+				}
+
+				continue
+			}
+
+			coerced, err := coerceLiteral(fieldValue, field.Type, variables)
+			if err != nil {
+				return nil, fmt.Errorf("in field %s: %w", field.Name, err)
+			}
+
+			object[field.Name] = coerced
+		}
+
+		return object, nil
+	case *Enum:
+		if value.Kind != EnumLiteral || t.Value(value.Raw) == nil {
+			return nil, fmt.Errorf("value %s does not exist in enum %s", printLiteral(value), t)
+		}
+
+		return value.Raw, nil
+	case *Scalar:
+		jsonValue, err := literalToJSON(value, variables)
+		if err != nil {
+			return nil, err
+		}
+
+		return t.Parse(jsonValue)
+	default:
+		return nil, fmt.Errorf("type %s is not an input type", t)
+	}
+}
+
+// coerceValue coerces a json value, with numbers as json.Number, to a go value of the given input type.
+func coerceValue(value any, t Type) (any, error) {
+	if nonNull, ok := t.(*NonNull); ok {
+		if value == nil {
+			return nil, fmt.Errorf("expected non-null value of type %s, found null", t)
+		}
+
+		return coerceValue(value, nonNull.OfType)
+	}
+
+	if value == nil {
+		return nil, nil
+	}
+
+	switch t := t.(type) {
+	case *List:
+		list, ok := value.([]any)
+		if !ok {
+			item, err := coerceValue(value, t.OfType)
+			if err != nil {
+				return nil, err
+			}
+
+			return []any{item}, nil
+		}
+
+		items := make([]any, len(list))
+		for index, itemValue := range list {
+			item, err := coerceValue(itemValue, t.OfType)
+			if err != nil {
+				return nil, fmt.Errorf("at index %d: %w", index, err)
+			}
+
+			items[index] = item
+		}
+
+		return items, nil
+	case *InputObject:
+		fields, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("expected value of type %s, found %v", t, value)
+		}
+
+		for name := range fields {
+			if t.Field(name) == nil {
+				return nil, fmt.Errorf("field %s is not defined by type %s", name, t)
+			}
+		}
+
+		object := map[string]any{}
+		for _, field := range t.Fields {
+			fieldValue, ok := fields[field.Name]
+			if !ok {
+				if err := setDefault(object, field, t); err != nil {
+					return nil, err
+				}
+
+				continue
+			}
+
+			coerced, err := coerceValue(fieldValue, field.Type)
+			if err != nil {
+				return nil, fmt.Errorf("in field %s: %w", field.Name, err)
+			}
+
+			object[field.Name] = coerced
+		}
+
+		return object, nil
+	case *Enum:
+		name, ok := value.(string)
+		if !ok || t.Value(name) == nil {
+			return nil, fmt.Errorf("value %v does not exist in enum %s", value, t)
+		}
+
+		return name, nil
+	case *Scalar:
+		return t.Parse(value)
+	default:
+		return nil, fmt.Errorf("type %s is not an input type", t)
+	}
+}
+
+// setDefault sets the default value of the given omitted input field or argument in the given object,
+// or throws an error if the field is required.
+func setDefault(object map[string]any, field *InputValue, parent Type) error {
+	if field.Default != nil {
+		coerced, err := coerceValue(field.Default, field.Type)
+		if err != nil {
+			return err
+		}
+
+		object[field.Name] = coerced
+		return nil
+	}
+
+	if _, nonNull := field.Type.(*NonNull); nonNull {
+		return fmt.Errorf("field %s of required type %s was not provided in %s", field.Name, field.Type, parent)
+	}
+
+	return nil
+}
+
+// literalToJSON converts a value literal to a json value, with numbers as json.Number.
+func literalToJSON(value *Value, variables map[string]any) (any, error) {
+	switch value.Kind {
+	case VariableLiteral:
+		return variables[value.Raw], nil
+	case IntLiteral, FloatLiteral:
+		return json.Number(value.Raw), nil
+	case StringLiteral:
+		return value.Raw, nil
+	case BooleanLiteral:
+		return value.Raw == "true", nil
+	case NullLiteral:
+		return nil, nil
+	case ListLiteral:
+		items := make([]any, len(value.List))
+		for index, item := range value.List {
+			jsonItem, err := literalToJSON(item, variables)
+			if err != nil {
+				return nil, err
+			}
+
+			items[index] = jsonItem
+		}
+
+		return items, nil
+	case ObjectLiteral:
+		object := map[string]any{}
+		for _, field := range value.Fields {
+			jsonField, err := literalToJSON(field.Value, variables)
+			if err != nil {
+				return nil, err
+			}
+
+			object[field.Name] = jsonField
+		}
+
+		return object, nil
+	default:
+		return nil, fmt.Errorf("unexpected value %s", printLiteral(value))
+	}
+}
+
+// printLiteral returns the value literal as written in documents.
+func printLiteral(value *Value) string {
+	switch value.Kind {
+	case VariableLiteral:
+		return "$" + value.Raw
+	case StringLiteral:
+		return strconv.Quote(value.Raw)
+	case ListLiteral:
+		items := make([]string, len(value.List))
+		for index, item := range value.List {
+			items[index] = printLiteral(item)
+		}
+
+		return "[" + strings.Join(items, ", ") + "]"
+	case ObjectLiteral:
+		fields := make([]string, len(value.Fields))
+		for index, field := range value.Fields {
+			fields[index] = field.Name + ": " + printLiteral(field.Value)
+		}
+
+		return "{" + strings.Join(fields, ", ") + "}"
+	default:
+		return value.Raw
+	}
+}
+
+// printValue returns the json value of the given input type as a value literal, e.g. for default values.
+func printValue(value any, t Type) string {
+	if nonNull, ok := t.(*NonNull); ok {
+		t = nonNull.OfType
+	}
+
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case []any:
+		itemType := t
+		if list, ok := t.(*List); ok {
+			itemType = list.OfType
+		}
+
+		items := make([]string, len(value))
+		for index, item := range value {
+			items[index] = printValue(item, itemType)
+		}
+
+		return "[" + strings.Join(items, ", ") + "]"
+	case map[string]any:
+		names := make([]string, 0, len(value))
+		for name := range value {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		fields := make([]string, len(names))
+		for index, name := range names {
+			var fieldType Type = t
+			if inputObject, ok := t.(*InputObject); ok && inputObject.Field(name) != nil {
+				fieldType = inputObject.Field(name).Type
+			}
+
+			fields[index] = name + ": " + printValue(value[name], fieldType)
+		}
+
+		return "{" + strings.Join(fields, ", ") + "}"
+	case string:
+		if _, ok := t.(*Enum); ok {
+			return value
+		}
+
+		return strconv.Quote(value)
+	default:
+		return fmt.Sprint(value)
+	}
+}
@@ -0,0 +1,111 @@
+package graphql
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/cosys-io/cosys/common"
+	"github.com/cosys-io/cosys/modules/cms"
+	"github.com/cosys-io/cosys/modules/graphql/internal"
+	"github.com/cosys-io/cosys/modules/sqlite3"
+)
+
+// ModuleName is the name the module is registered with, which other modules can depend on.
+const ModuleName = "graphql"
+
+// Config is the configuration of the graphql api registered by the module.
+// Configurations set in the project configurations override those set with Configure.
+type Config struct {
+	Path            string   // Path is the path the graphql api is served on.
+	DefaultPageSize int      // DefaultPageSize is the number of entities returned by list queries without a pageSize.
+	MaxPageSize     int      // MaxPageSize is the maximum pageSize of list queries, or unlimited if not positive.
+	MaxDepth        int      // MaxDepth is the maximum depth of selection sets, or unlimited if not positive.
+	Policies        []string // Policies are the uids of the policies applied to the graphql routes.
+	Middlewares     []string // Middlewares are the uids of the middlewares applied to the graphql routes.
+	// Mutations specifies whether the create, update and delete mutations are served,
+	// which should only be enabled along with policies restricting who can change entities.
+	Mutations bool
+}
+
+// DefaultConfig returns the default configuration of the graphql api,
+// served on /graphql with pages of 20 entities, of up to 100 entities, without mutations.
+func DefaultConfig() Config {
+	return Config{
+		Path:            "/graphql",
+		DefaultPageSize: 20,
+		MaxPageSize:     100,
+		MaxDepth:        10,
+	}
+}
+
+var (
+	configMutex sync.RWMutex
+	config      = DefaultConfig()
+)
+
+// Configure sets the configuration of the graphql api registered by the module.
+// Must be called before the cosys app is created.
+// Safe for concurrent use.
+func Configure(newConfig Config) {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	config = newConfig
+}
+
+// init registers the module to register the routes serving the graphql api,
+// whose schema is generated from the models of the cosys app when the server starts.
+// It depends on the cms and sqlite3 modules, whose models and database it uses,
+// and is registered after the logger module, if registered, which it logs to.
+func init() {
+	_ = common.RegisterNamedModule(ModuleName, func(cosys *common.Cosys) error {
+		configMutex.RLock()
+		moduleConfig := config
+		configMutex.RUnlock()
+
+		moduleConfig, err := withProjectConfigs(moduleConfig)
+		if err != nil {
+			return err
+		}
+
+		return cosys.AddRoutes(moduleConfig.routes()...)
+	}, common.DependsOn(cms.ModuleName, sqlite3.ModuleName), common.OptionallyDependsOn("logger"))
+}
+
+// routes returns the GET and POST routes serving the graphql api with the configuration.
+func (c Config) routes() []common.Route {
+	action := internal.Handler(internal.HandlerOptions{
+		Options: internal.Options{
+			DefaultPageSize: c.DefaultPageSize,
+			MaxPageSize:     c.MaxPageSize,
+			Mutations:       c.Mutations,
+		},
+		MaxDepth: c.MaxDepth,
+	})
+
+	route := func(method string, doc common.RouteDoc) common.Route {
+		return common.NewRoute(method, c.Path, action,
+			common.GetPolicies(c.Policies...),
+			common.GetMiddlewares(c.Middlewares...),
+			common.Describe(doc))
+	}
+
+	return []common.Route{
+		route(http.MethodGet, common.RouteDoc{
+			Summary:     "Execute a GraphQL query",
+			Description: "Executes the query operation in the query parameter, with the operationName and json variables parameters.",
+			Tags:        []string{"graphql"},
+			Query: []common.ParamDoc{
+				{Name: "query", Description: "The GraphQL document."},
+				{Name: "operationName", Description: "The name of the operation to execute."},
+				{Name: "variables", Description: "The json object of variables."},
+			},
+		}),
+		route(http.MethodPost, common.RouteDoc{
+			Summary:     "Execute a GraphQL operation",
+			Description: "Executes the query or mutation operation in the json request body.",
+			Tags:        []string{"graphql"},
+			Request:     common.TypeOf(internal.Request{}),
+		}),
+	}
+}
@@ -0,0 +1,68 @@
+package grpc
+
+import (
+	"github.com/cosys-io/cosys/common"
+	"github.com/spf13/viper"
+)
+
+// Keys of the project configurations of the grpc module.
+const (
+	PortKey         = "grpc_port"
+	ReflectionKey   = "grpc_reflection"
+	ContentTypesKey = "grpc_content_types"
+	PackageKey      = "grpc_package"
+	PageSizeKey     = "grpc_page_size"
+	MaxPageSizeKey  = "grpc_max_page_size"
+	PoliciesKey     = "grpc_policies"
+	MiddlewaresKey  = "grpc_middlewares"
+
+	TLSCertFileKey     = "grpc_tls_cert_file"
+	TLSKeyFileKey      = "grpc_tls_key_file"
+	TLSMinVersionKey   = "grpc_tls_min_version"
+	TLSClientCAFileKey = "grpc_tls_client_ca_file"
+	TLSClientAuthKey   = "grpc_tls_client_auth"
+)
+
+// withProjectConfigs returns the given configuration,
+// overridden by the configurations set in the project configurations.
+func withProjectConfigs(config Config) (Config, error) {
+	if err := common.ReadConfigs(); err != nil {
+		return Config{}, err
+	}
+
+	setString(PortKey, &config.Port)
+	if viper.IsSet(ReflectionKey) {
+		config.Reflection = viper.GetBool(ReflectionKey)
+	}
+	if viper.IsSet(ContentTypesKey) {
+		config.ContentTypes = viper.GetBool(ContentTypesKey)
+	}
+	setString(PackageKey, &config.Package)
+	if viper.IsSet(PageSizeKey) {
+		config.Options.DefaultPageSize = viper.GetInt(PageSizeKey)
+	}
+	if viper.IsSet(MaxPageSizeKey) {
+		config.Options.MaxPageSize = viper.GetInt(MaxPageSizeKey)
+	}
+	if viper.IsSet(PoliciesKey) {
+		config.Policies = viper.GetStringSlice(PoliciesKey)
+	}
+	if viper.IsSet(MiddlewaresKey) {
+		config.Middlewares = viper.GetStringSlice(MiddlewaresKey)
+	}
+
+	setString(TLSCertFileKey, &config.TLS.CertFile)
+	setString(TLSKeyFileKey, &config.TLS.KeyFile)
+	setString(TLSMinVersionKey, &config.TLS.MinVersion)
+	setString(TLSClientCAFileKey, &config.TLS.ClientCAFile)
+	setString(TLSClientAuthKey, &config.TLS.ClientAuth)
+
+	return config, nil
+}
+
+// setString sets the value to the project configuration with the given key, if it is set.
+func setString(key string, value *string) {
+	if viper.IsSet(key) {
+		*value = viper.GetString(key)
+	}
+}
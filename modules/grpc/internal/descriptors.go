@@ -0,0 +1,261 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/cosys-io/cosys/common"
+	"github.com/cosys-io/cosys/modules/cms/i18n"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Well-known types the fields of entities are mapped to.
+var (
+	timestampName = string((&timestamppb.Timestamp{}).ProtoReflect().Descriptor().FullName())
+	valueName     = string((&structpb.Value{}).ProtoReflect().Descriptor().FullName())
+)
+
+// contentType is the gRPC representation of a model.
+type contentType struct {
+	uid   string
+	model common.Model
+
+	typeName   string // typeName is the singular pascal name of the model, e.g. Post.
+	pluralName string // pluralName is the plural pascal name of the model, e.g. Posts.
+	localized  bool
+
+	output []common.Attribute // output are the public attributes, in the order of their field numbers.
+	input  []common.Attribute // input are the editable attributes, in the order of their field numbers.
+
+	service protoreflect.ServiceDescriptor
+}
+
+// buildFile returns the proto file, in the given package, declaring the messages and CRUD services of the given models,
+// and the content types of the models, with their service descriptors set.
+// Throws an error if the names of the models are not valid proto identifiers, or collide.
+func buildFile(pkg string, models map[string]common.Model) (protoreflect.FileDescriptor, []*contentType, error) {
+	uids := make([]string, 0, len(models))
+	for uid := range models {
+		uids = append(uids, uid)
+	}
+	sort.Strings(uids)
+
+	file := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String(pkg + ".proto"),
+		Package:    proto.String(pkg),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/timestamp.proto", "google/protobuf/struct.proto"},
+	}
+
+	contentTypes := make([]*contentType, 0, len(uids))
+	for _, uid := range uids {
+		contentType := newContentType(uid, models[uid])
+		contentType.declare(file)
+		contentTypes = append(contentTypes, contentType)
+	}
+
+	descriptor, err := protodesc.NewFile(file, protoregistry.GlobalFiles)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid grpc content type services: %w", err)
+	}
+
+	for _, contentType := range contentTypes {
+		contentType.service = descriptor.Services().ByName(protoreflect.Name(contentType.typeName + "Service"))
+	}
+
+	return descriptor, contentTypes, nil
+}
+
+// newContentType returns the content type of the model with the given uid.
+// Private attributes are left out of entity messages,
+// and attributes that are not editable are left out of input messages.
+func newContentType(uid string, model common.Model) *contentType {
+	attrSchemas := map[string]common.AttributeSchema{}
+	if model.Schema_() != nil {
+		for _, attrSchema := range model.Schema_().Attributes() {
+			attrSchemas[attrSchema.Name()] = attrSchema
+		}
+	}
+
+	contentType := &contentType{
+		uid:        uid,
+		model:      model,
+		typeName:   model.SingularPascalName_(),
+		pluralName: model.PluralPascalName_(),
+		localized:  i18n.IsLocalized(model),
+	}
+
+	if contentType.pluralName == contentType.typeName {
+		contentType.pluralName += "List"
+	}
+
+	for _, attr := range model.Attributes_() {
+		if fieldType(attr) == nil {
+			continue
+		}
+
+		attrSchema, hasSchema := attrSchemas[attr.CamelName()]
+
+		if attr != model.IdAttribute_() && (!hasSchema || attrSchema.Editable()) {
+			contentType.input = append(contentType.input, attr)
+		}
+
+		if !hasSchema || !attrSchema.Private() {
+			contentType.output = append(contentType.output, attr)
+		}
+	}
+
+	return contentType
+}
+
+// fieldType returns the type of the proto field of the given attribute, and the name of its message type, if any,
+// or nil if the attribute is not supported.
+func fieldType(attr common.Attribute) *descriptorpb.FieldDescriptorProto {
+	switch attr.(type) {
+	case common.IntAttribute:
+		return &descriptorpb.FieldDescriptorProto{Type: descriptorpb.FieldDescriptorProto_TYPE_INT64.Enum()}
+	case common.StringAttribute:
+		return &descriptorpb.FieldDescriptorProto{Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()}
+	case common.BoolAttribute:
+		return &descriptorpb.FieldDescriptorProto{Type: descriptorpb.FieldDescriptorProto_TYPE_BOOL.Enum()}
+	case common.TimeAttribute:
+		return &descriptorpb.FieldDescriptorProto{
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+			TypeName: proto.String("." + timestampName),
+		}
+	case common.ComponentAttribute, common.DynamicZoneAttribute:
+		return &descriptorpb.FieldDescriptorProto{
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+			TypeName: proto.String("." + valueName),
+		}
+	default:
+		return nil
+	}
+}
+
+// declare adds the messages and the service of the content type to the given file:
+//
+//	service PostService {
+//	  rpc GetPost(GetPostRequest) returns (Post);
+//	  rpc ListPosts(ListPostsRequest) returns (ListPostsResponse);
+//	  rpc CreatePost(CreatePostRequest) returns (Post);
+//	  rpc UpdatePost(UpdatePostRequest) returns (Post);
+//	  rpc DeletePost(DeletePostRequest) returns (Post);
+//	}
+func (c *contentType) declare(file *descriptorpb.FileDescriptorProto) {
+	pkg := "." + file.GetPackage() + "."
+	name := c.typeName
+	plural := c.pluralName
+
+	entity := &descriptorpb.DescriptorProto{Name: proto.String(name)}
+	for index, attr := range c.output {
+		field := fieldType(attr)
+		field.Name = proto.String(attr.SnakeName())
+		field.JsonName = proto.String(attr.CamelName())
+		field.Number = proto.Int32(int32(index + 1))
+		field.Label = descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()
+		entity.Field = append(entity.Field, field)
+	}
+
+	// Fields of inputs are proto3 optional fields, so that updates only set the given attributes.
+	input := &descriptorpb.DescriptorProto{Name: proto.String(name + "Input")}
+	for index, attr := range c.input {
+		field := fieldType(attr)
+		field.Name = proto.String(attr.SnakeName())
+		field.JsonName = proto.String(attr.CamelName())
+		field.Number = proto.Int32(int32(index + 1))
+		field.Label = descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()
+		field.Proto3Optional = proto.Bool(true)
+		field.OneofIndex = proto.Int32(int32(index))
+		input.Field = append(input.Field, field)
+		input.OneofDecl = append(input.OneofDecl, &descriptorpb.OneofDescriptorProto{
+			Name: proto.String("_" + attr.SnakeName()),
+		})
+	}
+
+	getRequest := message(name+"Request", "Get",
+		scalarField("id", 1, descriptorpb.FieldDescriptorProto_TYPE_INT64))
+	listRequest := message(plural+"Request", "List",
+		scalarField("page", 1, descriptorpb.FieldDescriptorProto_TYPE_INT32),
+		scalarField("page_size", 2, descriptorpb.FieldDescriptorProto_TYPE_INT32),
+		repeated(scalarField("sort", 3, descriptorpb.FieldDescriptorProto_TYPE_STRING)))
+	listResponse := message(plural+"Response", "List",
+		repeated(messageField("items", 1, pkg+name)))
+	createRequest := message(name+"Request", "Create",
+		messageField("data", 1, pkg+name+"Input"))
+	updateRequest := message(name+"Request", "Update",
+		scalarField("id", 1, descriptorpb.FieldDescriptorProto_TYPE_INT64),
+		messageField("data", 2, pkg+name+"Input"))
+	deleteRequest := message(name+"Request", "Delete",
+		scalarField("id", 1, descriptorpb.FieldDescriptorProto_TYPE_INT64))
+
+	if c.localized {
+		getRequest.Field = append(getRequest.Field, scalarField("locale", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING))
+		listRequest.Field = append(listRequest.Field, scalarField("locale", 4, descriptorpb.FieldDescriptorProto_TYPE_STRING))
+		createRequest.Field = append(createRequest.Field, scalarField("locale", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING))
+	}
+
+	file.MessageType = append(file.MessageType,
+		entity, input, getRequest, listRequest, listResponse, createRequest, updateRequest, deleteRequest)
+
+	file.Service = append(file.Service, &descriptorpb.ServiceDescriptorProto{
+		Name: proto.String(name + "Service"),
+		Method: []*descriptorpb.MethodDescriptorProto{
+			method("Get"+name, pkg+getRequest.GetName(), pkg+name),
+			method("List"+plural, pkg+listRequest.GetName(), pkg+listResponse.GetName()),
+			method("Create"+name, pkg+createRequest.GetName(), pkg+name),
+			method("Update"+name, pkg+updateRequest.GetName(), pkg+name),
+			method("Delete"+name, pkg+deleteRequest.GetName(), pkg+name),
+		},
+	})
+}
+
+// message returns a message with the given name, prefixed with the given verb, and fields.
+func message(name string, verb string, fields ...*descriptorpb.FieldDescriptorProto) *descriptorpb.DescriptorProto {
+	return &descriptorpb.DescriptorProto{
+		Name:  proto.String(verb + name),
+		Field: fields,
+	}
+}
+
+// scalarField returns a scalar field with the given name, number and type.
+func scalarField(name string, number int32, fieldType descriptorpb.FieldDescriptorProto_Type) *descriptorpb.FieldDescriptorProto {
+	return &descriptorpb.FieldDescriptorProto{
+		Name:   proto.String(name),
+		Number: proto.Int32(number),
+		Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		Type:   fieldType.Enum(),
+	}
+}
+
+// messageField returns a field with the given name and number, of the message type with the given full name.
+func messageField(name string, number int32, typeName string) *descriptorpb.FieldDescriptorProto {
+	return &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String(name),
+		Number:   proto.Int32(number),
+		Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+		TypeName: proto.String(typeName),
+	}
+}
+
+// repeated returns the given field as a repeated field.
+func repeated(field *descriptorpb.FieldDescriptorProto) *descriptorpb.FieldDescriptorProto {
+	field.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	return field
+}
+
+// method returns a unary method with the given name, input and output types.
+func method(name string, input string, output string) *descriptorpb.MethodDescriptorProto {
+	return &descriptorpb.MethodDescriptorProto{
+		Name:       proto.String(name),
+		InputType:  proto.String(input),
+		OutputType: proto.String(output),
+	}
+}
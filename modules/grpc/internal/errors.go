@@ -0,0 +1,73 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/cosys-io/cosys/common"
+	"github.com/cosys-io/cosys/modules/server/response"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorCodeKey is the trailer holding the error code of typed errors returned by gRPC methods.
+const ErrorCodeKey = "cosys-error-code"
+
+// statusCodes are the gRPC status codes of http response statuses.
+var statusCodes = map[int]codes.Code{
+	http.StatusBadRequest:          codes.InvalidArgument,
+	http.StatusUnauthorized:        codes.Unauthenticated,
+	http.StatusForbidden:           codes.PermissionDenied,
+	http.StatusNotFound:            codes.NotFound,
+	http.StatusConflict:            codes.AlreadyExists,
+	http.StatusRequestTimeout:      codes.DeadlineExceeded,
+	http.StatusTooManyRequests:     codes.ResourceExhausted,
+	http.StatusNotImplemented:      codes.Unimplemented,
+	http.StatusServiceUnavailable:  codes.Unavailable,
+	http.StatusGatewayTimeout:      codes.DeadlineExceeded,
+	http.StatusInternalServerError: codes.Internal,
+}
+
+// StatusCode returns the gRPC status code of the given http response status,
+// which is Unknown for unmapped client errors and Internal for other unmapped statuses.
+func StatusCode(httpStatus int) codes.Code {
+	if code, ok := statusCodes[httpStatus]; ok {
+		return code
+	}
+
+	if httpStatus >= 400 && httpStatus < 500 {
+		return codes.Unknown
+	}
+
+	return codes.Internal
+}
+
+// statusError returns the gRPC status error of the given error returned by a gRPC method.
+// Status errors are returned as is, and typed errors with their messages and the status codes of their error codes,
+// which are also set in the ErrorCodeKey trailer.
+// Other errors are logged and returned as internal errors, so as not to leak details.
+func statusError(ctx context.Context, cosys *common.Cosys, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+
+	if typedErr, ok := common.AsTypedError(err); ok {
+		_ = grpc.SetTrailer(ctx, metadata.Pairs(ErrorCodeKey, string(typedErr.Code)))
+		return status.Error(StatusCode(response.ErrorStatus(typedErr.Code)), typedErr.Message)
+	}
+
+	cosys.LogError("grpc method failed", err)
+
+	return status.Error(codes.Internal, http.StatusText(http.StatusInternalServerError))
+}
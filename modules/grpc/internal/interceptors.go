@@ -0,0 +1,288 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"runtime/debug"
+	"strings"
+
+	"github.com/cosys-io/cosys/common"
+	"github.com/cosys-io/cosys/modules/server/response"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// chain wraps the calls of the methods of a gRPC service in its middlewares and policies.
+type chain func(next http.HandlerFunc) http.HandlerFunc
+
+// newChain returns the chain of the given middlewares and policies,
+// with the policies applied within the middlewares, as they are for routes.
+// Returns nil if there are no middlewares and policies.
+func newChain(cosys *common.Cosys, middlewareFuncs []common.MiddlewareFunc, policyFuncs []common.PolicyFunc) (chain, error) {
+	if len(middlewareFuncs) == 0 && len(policyFuncs) == 0 {
+		return nil, nil
+	}
+
+	var wrappers []chain
+
+	for _, middlewareFunc := range middlewareFuncs {
+		middleware, err := middlewareFunc(cosys)
+		if err != nil {
+			return nil, err
+		}
+
+		wrappers = append(wrappers, middleware)
+	}
+
+	for _, policyFunc := range policyFuncs {
+		policy, err := policyFunc(cosys)
+		if err != nil {
+			return nil, err
+		}
+
+		wrappers = append(wrappers, func(next http.HandlerFunc) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				if !policy(r) {
+					response.RespondErr(w, common.NewForbiddenError("Forbidden"))
+					return
+				}
+
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		for i := len(wrappers) - 1; i >= 0; i-- {
+			next = wrappers[i](next)
+		}
+
+		return next
+	}, nil
+}
+
+// unaryInterceptor intercepts unary calls, see intercept.
+func (s *Server) unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	var resp any
+
+	err := s.intercept(ctx, info.FullMethod, func(ctx context.Context) error {
+		var err error
+		resp, err = handler(ctx, req)
+		return err
+	})
+
+	return resp, err
+}
+
+// streamInterceptor intercepts streaming calls, see intercept.
+func (s *Server) streamInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return s.intercept(stream.Context(), info.FullMethod, func(ctx context.Context) error {
+		return handler(srv, &contextStream{
+			ServerStream: stream,
+			ctx:          ctx,
+		})
+	})
+}
+
+// contextStream is a server stream with the context of the intercepted call.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context of the intercepted call.
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// intercept calls the method with the given full name in a request scope and a span, if a tracer is registered,
+// and through the middlewares and policies of its service, recovering from panics.
+// Middlewares and policies are called with an http request built from the call,
+// and if they respond instead of calling the method, the response status is returned as a status error.
+// Headers set by middlewares are sent as response metadata.
+// Errors returned by the method are returned as status errors, see statusError.
+func (s *Server) intercept(ctx context.Context, fullMethod string, call func(context.Context) error) (err error) {
+	service, method := splitMethod(fullMethod)
+	header := http.Header{}
+
+	ctx = common.ContextWithRequestScope(common.ContextWithRoute(ctx, common.Route{
+		Method: http.MethodPost,
+		Path:   fullMethod,
+	}))
+
+	if _, tErr := s.cosys.Tracer(); tErr == nil {
+		var span common.Span
+		ctx, span = s.cosys.StartSpan(common.ExtractTraceparent(ctx, incomingHeader(ctx)), fullMethod)
+		defer func() {
+			code := status.Code(err)
+			span.SetAttribute("rpc.grpc.status_code", int(code))
+			if isServerError(code) {
+				span.RecordError(err)
+			}
+			span.End()
+		}()
+
+		span.SetAttribute("rpc.system", "grpc")
+		span.SetAttribute("rpc.service", service)
+		span.SetAttribute("rpc.method", method)
+
+		common.InjectTraceparent(ctx, header)
+	}
+
+	defer func() {
+		recovered := recover()
+		if recovered == nil {
+			return
+		}
+
+		recoveredErr, ok := recovered.(error)
+		if !ok {
+			recoveredErr = fmt.Errorf("%v", recovered)
+		}
+
+		s.cosys.LogError("panic recovered", recoveredErr, common.F("method", fullMethod), common.F("stack", string(debug.Stack())))
+
+		err = status.Error(codes.Internal, http.StatusText(http.StatusInternalServerError))
+	}()
+
+	methodChain := s.chains[service]
+	if methodChain == nil {
+		sendHeader(ctx, header)
+		return statusError(ctx, s.cosys, call(ctx))
+	}
+
+	buffer := response.NewBuffer(header)
+
+	called := false
+	methodChain(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		sendHeader(r.Context(), w.Header())
+		err = statusError(r.Context(), s.cosys, call(r.Context()))
+	})(buffer, newRequest(ctx, fullMethod))
+
+	if !called {
+		sendHeader(ctx, header)
+		return bufferStatusError(ctx, buffer)
+	}
+
+	return err
+}
+
+// splitMethod returns the service and method names of the given full method name, e.g. /package.Service/Method.
+func splitMethod(fullMethod string) (string, string) {
+	service, method, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	return service, method
+}
+
+// isServerError returns whether the given status code is the code of a server error.
+func isServerError(code codes.Code) bool {
+	switch code {
+	case codes.Unknown, codes.DeadlineExceeded, codes.Unimplemented, codes.Internal, codes.Unavailable, codes.DataLoss:
+		return true
+	default:
+		return false
+	}
+}
+
+// incomingHeader returns the incoming metadata of the call with the given context as http headers.
+// Pseudo-headers and binary metadata are left out.
+func incomingHeader(ctx context.Context) http.Header {
+	header := http.Header{}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	for key, values := range md {
+		if strings.HasPrefix(key, ":") || strings.HasSuffix(key, "-bin") {
+			continue
+		}
+
+		for _, value := range values {
+			header.Add(key, value)
+		}
+	}
+
+	return header
+}
+
+// newRequest returns the http request of the call of the method with the given full name and context,
+// a POST request to the full method name, with the incoming metadata as headers,
+// and the address and tls connection state of the peer.
+func newRequest(ctx context.Context, fullMethod string) *http.Request {
+	r := &http.Request{
+		Method:     http.MethodPost,
+		URL:        &url.URL{Path: fullMethod},
+		Proto:      "HTTP/2.0",
+		ProtoMajor: 2,
+		Header:     incomingHeader(ctx),
+		Body:       http.NoBody,
+		RequestURI: fullMethod,
+	}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if authority := md.Get(":authority"); len(authority) > 0 {
+			r.Host = authority[0]
+		}
+	}
+
+	if p, ok := peer.FromContext(ctx); ok {
+		if p.Addr != nil {
+			r.RemoteAddr = p.Addr.String()
+		}
+
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			state := tlsInfo.State
+			r.TLS = &state
+		}
+	}
+
+	return r.WithContext(ctx)
+}
+
+// sendHeader sets the given http headers as the response metadata of the call with the given context.
+// Content headers and reserved gRPC headers are left out.
+func sendHeader(ctx context.Context, header http.Header) {
+	md := metadata.MD{}
+	for key, values := range header {
+		key = strings.ToLower(key)
+		if key == "content-type" || key == "content-length" || strings.HasPrefix(key, "grpc-") {
+			continue
+		}
+
+		md.Append(key, values...)
+	}
+
+	if md.Len() > 0 {
+		_ = grpc.SetHeader(ctx, md)
+	}
+}
+
+// bufferStatusError returns the status error of the buffered response,
+// with the code of the response status and the error message of the response, if any.
+// The error code of the response, if any, is set in the ErrorCodeKey trailer.
+// Responses that are not errors are returned as internal errors, as the method was not called.
+func bufferStatusError(ctx context.Context, buffer *response.Buffer) error {
+	if buffer.Status() < 400 {
+		return status.Error(codes.Internal, "method not called")
+	}
+
+	message := http.StatusText(buffer.Status())
+
+	var resp response.Response
+	if err := json.Unmarshal(buffer.Body(), &resp); err == nil {
+		if resp.Meta.Error != "" {
+			message = resp.Meta.Error
+		}
+
+		if resp.Meta.ErrorCode != "" {
+			_ = grpc.SetTrailer(ctx, metadata.Pairs(ErrorCodeKey, resp.Meta.ErrorCode))
+		}
+	}
+
+	return status.Error(StatusCode(buffer.Status()), message)
+}
@@ -0,0 +1,230 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"reflect"
+	"sync"
+
+	"github.com/cosys-io/cosys/common"
+	"github.com/cosys-io/cosys/modules/server/tlsconfig"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/reflection"
+	v1reflectiongrpc "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	v1alphareflectiongrpc "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// Config is the configuration of the gRPC server.
+type Config struct {
+	Port string // Port is the port the server listens on.
+	TLS  tlsconfig.Config
+	// Reflection specifies whether the server reflection service is registered, for clients such as grpcurl.
+	Reflection bool
+	// ContentTypes specifies whether CRUD services are generated for the models of the cosys app.
+	ContentTypes bool
+	// Package is the proto package of the generated CRUD services, e.g. cosys.api.
+	Package     string
+	Options     Options
+	Policies    []string // Policies are the uids of the policies applied to all services.
+	Middlewares []string // Middlewares are the uids of the middlewares applied to all services.
+}
+
+// Server is an implementation of the GrpcServer core service using the grpc package.
+type Server struct {
+	config Config
+	cosys  *common.Cosys
+	chains map[string]chain // chains are the chains of middlewares and policies of the services by name.
+
+	grpcServerMutex sync.Mutex
+	grpcServer      *grpc.Server
+	shutdown        bool
+	listening       chan struct{} // listening is closed once the server is listening.
+}
+
+// NewServer returns a new Server.
+func NewServer(config Config, cosys *common.Cosys) *Server {
+	return &Server{
+		config:    config,
+		cosys:     cosys,
+		listening: make(chan struct{}),
+	}
+}
+
+// resolveServices creates the grpc server from the registered services and the CRUD services of content types,
+// with the chains of middlewares and policies of the services, and the server reflection service, if configured.
+// Throws an error if a service is invalid or registered twice.
+func (s *Server) resolveServices() (*grpc.Server, error) {
+	services := s.cosys.GrpcServices()
+	resolver := descriptorResolver{protoregistry.GlobalFiles}
+
+	if s.config.ContentTypes && len(s.cosys.Models()) > 0 {
+		file, contentTypes, err := buildFile(s.config.Package, s.cosys.Models())
+		if err != nil {
+			return nil, err
+		}
+
+		files := new(protoregistry.Files)
+		if err = files.RegisterFile(file); err != nil {
+			return nil, err
+		}
+		resolver = append(descriptorResolver{files}, resolver...)
+
+		for _, contentType := range contentTypes {
+			services = append(services, newContentTypeService(s.cosys, contentType, s.config.Options).grpcService())
+		}
+	}
+
+	route := common.NewRoute("POST", "/", nil,
+		common.GetMiddlewares(s.config.Middlewares...),
+		common.GetPolicies(s.config.Policies...))
+
+	options := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(s.unaryInterceptor),
+		grpc.ChainStreamInterceptor(s.streamInterceptor),
+	}
+
+	if s.config.TLS.Enabled() {
+		tlsConfig, err := s.config.TLS.Build()
+		if err != nil {
+			return nil, err
+		}
+
+		options = append(options, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	grpcServer := grpc.NewServer(options...)
+	chains := map[string]chain{}
+
+	for _, service := range services {
+		if service.Desc == nil {
+			return nil, fmt.Errorf("grpc service description not found")
+		}
+
+		name := service.Desc.ServiceName
+		if _, ok := chains[name]; ok {
+			return nil, fmt.Errorf("grpc service already registered: %s", name)
+		}
+
+		if service.Desc.HandlerType != nil {
+			handlerType := reflect.TypeOf(service.Desc.HandlerType).Elem()
+			if implType := reflect.TypeOf(service.Impl); implType == nil || !implType.Implements(handlerType) {
+				return nil, fmt.Errorf("grpc service %s does not implement %s", name, handlerType)
+			}
+		}
+
+		serviceChain, err := newChain(s.cosys,
+			append(append([]common.MiddlewareFunc{}, route.Middlewares...), service.Middlewares...),
+			append(append([]common.PolicyFunc{}, route.Policies...), service.Policies...))
+		if err != nil {
+			return nil, err
+		}
+
+		chains[name] = serviceChain
+		grpcServer.RegisterService(service.Desc, service.Impl)
+	}
+
+	if s.config.Reflection {
+		reflectionServer := reflection.NewServerV1(reflection.ServerOptions{
+			Services:           grpcServer,
+			DescriptorResolver: resolver,
+		})
+
+		v1reflectiongrpc.RegisterServerReflectionServer(grpcServer, reflectionServer)
+		v1alphareflectiongrpc.RegisterServerReflectionServer(grpcServer, reflection.NewServer(reflection.ServerOptions{
+			Services:           grpcServer,
+			DescriptorResolver: resolver,
+		}))
+	}
+
+	s.chains = chains
+	return grpcServer, nil
+}
+
+// Start resolves the services and starts the server, over tls if configured.
+// Returns nil once the server is shut down.
+func (s *Server) Start() error {
+	grpcServer, err := s.resolveServices()
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", ":"+s.config.Port)
+	if err != nil {
+		return err
+	}
+
+	s.grpcServerMutex.Lock()
+	if s.shutdown {
+		s.grpcServerMutex.Unlock()
+		_ = listener.Close()
+		return nil
+	}
+	s.grpcServer = grpcServer
+	s.grpcServerMutex.Unlock()
+	close(s.listening)
+
+	return grpcServer.Serve(listener)
+}
+
+// Listening returns a channel that is closed once the server is listening.
+func (s *Server) Listening() <-chan struct{} {
+	return s.listening
+}
+
+// Shutdown stops the server from accepting new connections and RPCs,
+// and waits for in-flight RPCs to complete until the context is done, after which they are cancelled.
+// Safe for concurrent use.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.grpcServerMutex.Lock()
+	grpcServer := s.grpcServer
+	s.shutdown = true
+	s.grpcServerMutex.Unlock()
+
+	if grpcServer == nil {
+		return nil
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		grpcServer.Stop()
+		<-stopped
+		return ctx.Err()
+	}
+}
+
+// descriptorResolver resolves descriptors from the first of its resolvers that has them,
+// so that the server reflection service describes both generated and registered services.
+type descriptorResolver []protodesc.Resolver
+
+func (r descriptorResolver) FindFileByPath(path string) (protoreflect.FileDescriptor, error) {
+	for _, resolver := range r {
+		if file, err := resolver.FindFileByPath(path); err == nil {
+			return file, nil
+		}
+	}
+
+	return nil, protoregistry.NotFound
+}
+
+func (r descriptorResolver) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	for _, resolver := range r {
+		if descriptor, err := resolver.FindDescriptorByName(name); err == nil {
+			return descriptor, nil
+		}
+	}
+
+	return nil, protoregistry.NotFound
+}
@@ -0,0 +1,559 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/cosys-io/cosys/common"
+	"github.com/cosys-io/cosys/modules/cms/i18n"
+	"github.com/cosys-io/cosys/modules/cms/schema"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Options are configurations of the CRUD services of content types.
+type Options struct {
+	DefaultPageSize int // DefaultPageSize is the number of entities returned by list methods without a page_size.
+	MaxPageSize     int // MaxPageSize is the maximum page_size of list methods, or unlimited if not positive.
+}
+
+// methodFunc is the implementation of a unary method of a content type service.
+type methodFunc func(ctx context.Context, request *dynamicpb.Message) (proto.Message, error)
+
+// contentTypeService serves the CRUD methods of a content type through the database core service,
+// so that lifecycle hooks and database wrappers apply as they do to the rest api.
+type contentTypeService struct {
+	cosys *common.Cosys
+	*contentType
+	options Options
+
+	attributes map[string]common.Attribute // attributes are the public attributes by camel and snake case name.
+}
+
+// newContentTypeService returns the service of the given content type.
+func newContentTypeService(cosys *common.Cosys, contentType *contentType, options Options) *contentTypeService {
+	attributes := map[string]common.Attribute{}
+	for _, attr := range contentType.output {
+		attributes[attr.CamelName()] = attr
+		attributes[attr.SnakeName()] = attr
+	}
+
+	return &contentTypeService{
+		cosys:       cosys,
+		contentType: contentType,
+		options:     options,
+		attributes:  attributes,
+	}
+}
+
+// grpcService returns the gRPC service of the content type, whose messages are decoded as dynamic messages.
+func (s *contentTypeService) grpcService() common.GrpcService {
+	methodFuncs := map[string]methodFunc{
+		"Get" + s.typeName:    s.get,
+		"List" + s.pluralName: s.list,
+		"Create" + s.typeName: s.create,
+		"Update" + s.typeName: s.update,
+		"Delete" + s.typeName: s.delete,
+	}
+
+	desc := &grpc.ServiceDesc{
+		ServiceName: string(s.service.FullName()),
+		HandlerType: (*any)(nil),
+		Metadata:    s.service.ParentFile().Path(),
+	}
+
+	methods := s.service.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		method := methods.Get(i)
+		desc.Methods = append(desc.Methods, grpc.MethodDesc{
+			MethodName: string(method.Name()),
+			Handler:    unaryHandler(method, methodFuncs[string(method.Name())]),
+		})
+	}
+
+	return common.GrpcService{
+		Desc: desc,
+		Impl: s,
+	}
+}
+
+// unaryHandler returns the handler of the given method,
+// which decodes requests into dynamic messages and calls the method through the interceptor, if any.
+func unaryHandler(method protoreflect.MethodDescriptor, call methodFunc) func(any, context.Context, func(any) error, grpc.UnaryServerInterceptor) (any, error) {
+	fullMethod := "/" + string(method.Parent().FullName()) + "/" + string(method.Name())
+
+	return func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+		request := dynamicpb.NewMessage(method.Input())
+		if err := dec(request); err != nil {
+			return nil, err
+		}
+
+		if interceptor == nil {
+			return call(ctx, request)
+		}
+
+		info := &grpc.UnaryServerInfo{
+			Server:     srv,
+			FullMethod: fullMethod,
+		}
+
+		return interceptor(ctx, request, info, func(ctx context.Context, req any) (any, error) {
+			return call(ctx, req.(*dynamicpb.Message))
+		})
+	}
+}
+
+// get serves the get method, returning the published entity with the given id, or its translation in the given locale.
+func (s *contentTypeService) get(ctx context.Context, request *dynamicpb.Message) (proto.Message, error) {
+	database, err := s.cosys.Database()
+	if err != nil {
+		return nil, err
+	}
+
+	id := int(getField(request, "id").Int())
+
+	dbParams := s.selectParams(ctx)
+
+	if publishedAt, ok := getPublishedAt(s.model); ok {
+		dbParams.Where = append(dbParams.Where, publishedAt.NotNull())
+	}
+
+	var locale string
+	if s.localized {
+		locale = getField(request, "locale").String()
+	}
+
+	var entity common.Entity
+	if locale != "" {
+		if err = i18n.CheckLocale(locale); err != nil {
+			return nil, common.NewValidationError(err.Error())
+		}
+
+		entity, err = i18n.Translation(s.cosys, s.uid, id, locale, dbParams)
+	} else {
+		dbParams.Where = append(dbParams.Where, s.model.IdAttribute_().(common.IntAttribute).Eq(id))
+		entity, err = database.FindOne(s.uid, dbParams)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.message(entity)
+}
+
+// list serves the list method, returning a page of the published entities in the given locale.
+func (s *contentTypeService) list(ctx context.Context, request *dynamicpb.Message) (proto.Message, error) {
+	database, err := s.cosys.Database()
+	if err != nil {
+		return nil, err
+	}
+
+	dbParams := s.selectParams(ctx)
+
+	if publishedAt, ok := getPublishedAt(s.model); ok {
+		dbParams.Where = append(dbParams.Where, publishedAt.NotNull())
+	}
+
+	sortList := getField(request, "sort").List()
+	for i := 0; i < sortList.Len(); i++ {
+		order, err := s.order(sortList.Get(i).String())
+		if err != nil {
+			return nil, err
+		}
+
+		dbParams.OrderBy = append(dbParams.OrderBy, order)
+	}
+
+	page := int(getField(request, "page").Int())
+	if page == 0 {
+		page = 1
+	}
+	if page < 1 {
+		return nil, common.NewValidationError(fmt.Sprintf("invalid page: %d", page))
+	}
+
+	pageSize := int(getField(request, "page_size").Int())
+	if pageSize == 0 {
+		pageSize = s.options.DefaultPageSize
+	}
+	if pageSize < 1 {
+		return nil, common.NewValidationError(fmt.Sprintf("invalid page_size: %d", pageSize))
+	}
+	if s.options.MaxPageSize > 0 {
+		pageSize = min(pageSize, s.options.MaxPageSize)
+	}
+
+	dbParams.Limit = int64(pageSize)
+	dbParams.Offset = int64(pageSize) * int64(page-1)
+
+	if s.localized {
+		dbParams.Locale = i18n.DefaultLocale()
+
+		if locale := getField(request, "locale").String(); locale != "" {
+			if err = i18n.CheckLocale(locale); err != nil {
+				return nil, common.NewValidationError(err.Error())
+			}
+
+			dbParams.Locale = locale
+		}
+	}
+
+	entities, err := database.FindMany(s.uid, dbParams)
+	if err != nil {
+		return nil, err
+	}
+
+	method := s.service.Methods().ByName(protoreflect.Name("List" + s.pluralName))
+	response := dynamicpb.NewMessage(method.Output())
+	items := response.Mutable(response.Descriptor().Fields().ByName("items")).List()
+
+	for _, entity := range entities {
+		item, err := s.message(entity)
+		if err != nil {
+			return nil, err
+		}
+
+		items.Append(protoreflect.ValueOfMessage(item))
+	}
+
+	return response, nil
+}
+
+// create serves the create method, creating an entity in the given locale,
+// with zero values for the attributes that are not given.
+func (s *contentTypeService) create(ctx context.Context, request *dynamicpb.Message) (proto.Message, error) {
+	database, err := s.cosys.Database()
+	if err != nil {
+		return nil, err
+	}
+
+	entity, _, err := s.entity(request)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := append([]common.Attribute{}, s.input...)
+
+	if s.localized {
+		locale := i18n.DefaultLocale()
+		if value := getField(request, "locale").String(); value != "" {
+			locale = value
+		}
+
+		if err = i18n.CheckLocale(locale); err != nil {
+			return nil, common.NewValidationError(err.Error())
+		}
+
+		for _, attr := range s.model.Attributes_() {
+			var value any
+			switch attr.CamelName() {
+			case schema.LocaleSchema.Name():
+				value = locale
+			case schema.LocalizationIdSchema.Name():
+				value = 0
+			default:
+				continue
+			}
+
+			if err = setField(entity, attr, value); err != nil {
+				return nil, err
+			}
+
+			columns = append(columns, attr)
+		}
+	}
+
+	dbParams := common.NewDBParamsBuilder().
+		Insert(columns...).
+		Context(ctx).
+		Build()
+
+	created, err := database.Create(s.uid, entity, dbParams)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.message(created)
+}
+
+// update serves the update method, updating the given attributes of the entity with the given id.
+func (s *contentTypeService) update(ctx context.Context, request *dynamicpb.Message) (proto.Message, error) {
+	database, err := s.cosys.Database()
+	if err != nil {
+		return nil, err
+	}
+
+	id := int(getField(request, "id").Int())
+
+	entity, columns, err := s.entity(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(columns) == 0 {
+		return nil, common.NewValidationError("no attributes to update")
+	}
+
+	dbParams := common.NewDBParamsBuilder().
+		Update(columns...).
+		Where(s.model.IdAttribute_().(common.IntAttribute).Eq(id)).
+		Context(ctx).
+		Build()
+
+	updated, err := database.Update(s.uid, entity, dbParams)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.message(updated)
+}
+
+// delete serves the delete method, deleting the entity with the given id.
+func (s *contentTypeService) delete(ctx context.Context, request *dynamicpb.Message) (proto.Message, error) {
+	database, err := s.cosys.Database()
+	if err != nil {
+		return nil, err
+	}
+
+	id := int(getField(request, "id").Int())
+
+	dbParams := common.NewDBParamsBuilder().
+		Where(s.model.IdAttribute_().(common.IntAttribute).Eq(id)).
+		Context(ctx).
+		Build()
+
+	deleted, err := database.Delete(s.uid, dbParams)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.message(deleted)
+}
+
+// selectParams returns the DBParams selecting the public attributes,
+// and populating the public component and dynamic zone attributes.
+func (s *contentTypeService) selectParams(ctx context.Context) common.DBParams {
+	var populate []common.Attribute
+	for _, attr := range s.output {
+		if common.IsEmbedded(attr) {
+			populate = append(populate, attr)
+		}
+	}
+
+	return common.NewDBParamsBuilder().
+		Select(s.output...).
+		Populate(populate...).
+		Context(ctx).
+		Build()
+}
+
+// order returns the order-by condition of the given sort key,
+// an attribute name in descending order if prefixed with "-".
+func (s *contentTypeService) order(key string) (*common.Order, error) {
+	name, desc := strings.CutPrefix(key, "-")
+
+	attr, ok := s.attributes[name]
+	if !ok || common.IsEmbedded(attr) {
+		return nil, common.NewValidationError("invalid sort attribute: " + name)
+	}
+
+	if desc {
+		return attr.Desc(), nil
+	}
+
+	return attr.Asc(), nil
+}
+
+// message returns the entity message of the given entity, with the values of its public attributes.
+// Nil times, components and dynamic zones are left unset.
+func (s *contentTypeService) message(entity common.Entity) (*dynamicpb.Message, error) {
+	entityValue := reflect.Indirect(reflect.ValueOf(entity))
+	if entityValue.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("entity is not a struct")
+	}
+
+	message := dynamicpb.NewMessage(s.service.ParentFile().Messages().ByName(protoreflect.Name(s.typeName)))
+	fields := message.Descriptor().Fields()
+
+	for _, attr := range s.output {
+		field := entityValue.FieldByName(attr.PascalName())
+		if !field.IsValid() {
+			continue
+		}
+
+		fieldDesc := fields.ByName(protoreflect.Name(attr.SnakeName()))
+
+		value, ok, err := protoValue(fieldDesc, field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of attribute %s: %w", attr.CamelName(), err)
+		}
+
+		if ok {
+			message.Set(fieldDesc, value)
+		}
+	}
+
+	return message, nil
+}
+
+// protoValue returns the value of the given proto field of the given entity field, and whether it is set.
+func protoValue(fieldDesc protoreflect.FieldDescriptor, field reflect.Value) (protoreflect.Value, bool, error) {
+	switch value := field.Interface().(type) {
+	case *time.Time:
+		if value == nil {
+			return protoreflect.Value{}, false, nil
+		}
+
+		return protoreflect.ValueOfMessage(timestamppb.New(*value).ProtoReflect()), true, nil
+	case time.Time:
+		return protoreflect.ValueOfMessage(timestamppb.New(value).ProtoReflect()), true, nil
+	}
+
+	switch fieldDesc.Kind() {
+	case protoreflect.Int64Kind:
+		if !field.CanInt() {
+			return protoreflect.Value{}, false, fmt.Errorf("not an integer")
+		}
+
+		return protoreflect.ValueOfInt64(field.Int()), true, nil
+	case protoreflect.StringKind:
+		if field.Kind() != reflect.String {
+			return protoreflect.Value{}, false, fmt.Errorf("not a string")
+		}
+
+		return protoreflect.ValueOfString(field.String()), true, nil
+	case protoreflect.BoolKind:
+		if field.Kind() != reflect.Bool {
+			return protoreflect.Value{}, false, fmt.Errorf("not a bool")
+		}
+
+		return protoreflect.ValueOfBool(field.Bool()), true, nil
+	case protoreflect.MessageKind:
+		if (field.Kind() == reflect.Map || field.Kind() == reflect.Slice || field.Kind() == reflect.Pointer) && field.IsNil() {
+			return protoreflect.Value{}, false, nil
+		}
+
+		encoded, err := json.Marshal(field.Interface())
+		if err != nil {
+			return protoreflect.Value{}, false, err
+		}
+
+		var decoded any
+		if err = json.Unmarshal(encoded, &decoded); err != nil {
+			return protoreflect.Value{}, false, err
+		}
+
+		value, err := structpb.NewValue(decoded)
+		if err != nil {
+			return protoreflect.Value{}, false, err
+		}
+
+		return protoreflect.ValueOfMessage(value.ProtoReflect()), true, nil
+	default:
+		return protoreflect.Value{}, false, fmt.Errorf("unsupported field kind: %s", fieldDesc.Kind())
+	}
+}
+
+// entity returns a new entity with the values of the data input of the given request,
+// and the attributes that were given.
+func (s *contentTypeService) entity(request *dynamicpb.Message) (common.Entity, []common.Attribute, error) {
+	data := getField(request, "data").Message()
+	fields := data.Descriptor().Fields()
+
+	values := map[string]any{}
+	var columns []common.Attribute
+
+	for _, attr := range s.input {
+		fieldDesc := fields.ByName(protoreflect.Name(attr.SnakeName()))
+		if !data.Has(fieldDesc) {
+			continue
+		}
+
+		value := data.Get(fieldDesc)
+
+		switch fieldDesc.Kind() {
+		case protoreflect.MessageKind:
+			// Timestamps are encoded as RFC 3339 strings and values as json values, as expected by entities.
+			encoded, err := protojson.Marshal(value.Message().Interface())
+			if err != nil {
+				return nil, nil, common.NewValidationError("invalid " + attr.SnakeName() + ": " + err.Error())
+			}
+
+			values[attr.CamelName()] = json.RawMessage(encoded)
+		default:
+			values[attr.CamelName()] = value.Interface()
+		}
+
+		columns = append(columns, attr)
+	}
+
+	encoded, err := json.Marshal(values)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entity := s.model.New_()
+	if err = json.Unmarshal(encoded, entity); err != nil {
+		return nil, nil, common.NewValidationError("invalid " + s.model.SingularHumanName_() + ": " + err.Error())
+	}
+
+	return entity, columns, nil
+}
+
+// getField returns the value of the field of the given message with the given name,
+// or an invalid value if the message has no such field, e.g. the locale fields of models that are not localized.
+func getField(message protoreflect.Message, name string) protoreflect.Value {
+	fieldDesc := message.Descriptor().Fields().ByName(protoreflect.Name(name))
+	if fieldDesc == nil {
+		return protoreflect.Value{}
+	}
+
+	return message.Get(fieldDesc)
+}
+
+// getPublishedAt returns the publishedAt attribute of the given model,
+// and whether the model has draft and publish enabled.
+func getPublishedAt(model common.Model) (common.Attribute, bool) {
+	modelSchema, ok := model.Schema_().(*schema.ModelSchema)
+	if !ok || !modelSchema.DraftAndPublish() {
+		return nil, false
+	}
+
+	for _, attr := range model.Attributes_() {
+		if attr.CamelName() == schema.PublishedAtSchema.Name() {
+			return attr, true
+		}
+	}
+
+	return nil, false
+}
+
+// setField sets the field of the given entity holding the given attribute to the given value.
+func setField(entity common.Entity, attr common.Attribute, value any) error {
+	entityValue := reflect.Indirect(reflect.ValueOf(entity))
+	if entityValue.Kind() != reflect.Struct {
+		return fmt.Errorf("entity is not a struct")
+	}
+
+	field := entityValue.FieldByName(attr.PascalName())
+	if !field.IsValid() {
+		return fmt.Errorf("attribute not found: %s", attr.PascalName())
+	}
+
+	newValue := reflect.ValueOf(value)
+	if !newValue.Type().AssignableTo(field.Type()) {
+		return fmt.Errorf("invalid type for attribute: %s", attr.PascalName())
+	}
+
+	field.Set(newValue)
+	return nil
+}
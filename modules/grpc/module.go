@@ -0,0 +1,70 @@
+package grpc
+
+import (
+	"sync"
+
+	"github.com/cosys-io/cosys/common"
+	"github.com/cosys-io/cosys/modules/cms"
+	"github.com/cosys-io/cosys/modules/grpc/internal"
+	"github.com/cosys-io/cosys/modules/sqlite3"
+)
+
+// ModuleName is the name the module is registered with, which other modules can depend on.
+const ModuleName = "grpc"
+
+type (
+	Config  = internal.Config  // Config is the configuration of the gRPC server.
+	Options = internal.Options // Options are configurations of the CRUD services of content types.
+)
+
+// ErrorCodeKey is the trailer holding the error code of typed errors returned by gRPC methods.
+const ErrorCodeKey = internal.ErrorCodeKey
+
+// DefaultConfig returns the default configuration of the gRPC server,
+// which serves the registered services in plaintext on port 50051, without server reflection.
+// CRUD services for the models of the cosys app are not served unless enabled,
+// and are then generated in the cosys.api package, with pages of 20 entities, of up to 100 entities.
+func DefaultConfig() Config {
+	return Config{
+		Port:    "50051",
+		Package: "cosys.api",
+		Options: Options{
+			DefaultPageSize: 20,
+			MaxPageSize:     100,
+		},
+	}
+}
+
+var (
+	configMutex sync.RWMutex
+	config      = DefaultConfig()
+)
+
+// Configure sets the configuration of the gRPC server.
+// Must be called before the cosys app is created.
+// Safe for concurrent use.
+func Configure(newConfig Config) {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	config = newConfig
+}
+
+// init registers the module to register the GrpcServer core service,
+// which serves the registered gRPC services and the CRUD services of content types, if configured.
+// It depends on the cms and sqlite3 modules, whose models and database it uses,
+// and is registered after the logger and tracing modules, if registered, whose logger and tracer it uses.
+func init() {
+	_ = common.RegisterNamedModule(ModuleName, func(cosys *common.Cosys) error {
+		configMutex.RLock()
+		moduleConfig := config
+		configMutex.RUnlock()
+
+		moduleConfig, err := withProjectConfigs(moduleConfig)
+		if err != nil {
+			return err
+		}
+
+		return cosys.UseGrpcServer(internal.NewServer(moduleConfig, cosys))
+	}, common.DependsOn(cms.ModuleName, sqlite3.ModuleName), common.OptionallyDependsOn("logger", "tracing"))
+}
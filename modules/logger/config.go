@@ -0,0 +1,44 @@
+package logger
+
+import (
+	"github.com/cosys-io/cosys/common"
+	"github.com/spf13/viper"
+)
+
+// Keys of the project configurations of the logger module.
+const (
+	LevelKey  = "log.level"
+	FormatKey = "log.format"
+	SinksKey  = "log.sinks"
+)
+
+// withProjectConfigs returns the given configuration,
+// overridden by the configurations set in the project configurations.
+// Throws an error if the overridden configuration is invalid.
+func withProjectConfigs(config Config) (Config, error) {
+	if err := common.ReadConfigs(); err != nil {
+		return Config{}, err
+	}
+
+	if viper.IsSet(LevelKey) {
+		level, err := common.ParseLogLevel(viper.GetString(LevelKey))
+		if err != nil {
+			return Config{}, err
+		}
+
+		config.Level = level
+	}
+	if viper.IsSet(FormatKey) {
+		config.Format = viper.GetString(FormatKey)
+	}
+	if viper.IsSet(SinksKey) {
+		var sinks []Sink
+		if err := viper.UnmarshalKey(SinksKey, &sinks); err != nil {
+			return Config{}, err
+		}
+
+		config.Sinks = sinks
+	}
+
+	return validateConfig(config)
+}
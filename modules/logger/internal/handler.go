@@ -0,0 +1,67 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// MultiHandler is a slog handler that sends each record to all of its handlers,
+// e.g. to write logs to multiple sinks.
+type MultiHandler struct {
+	handlers []slog.Handler
+}
+
+// NewMultiHandler returns a new MultiHandler sending records to the given handlers.
+func NewMultiHandler(handlers ...slog.Handler) *MultiHandler {
+	return &MultiHandler{
+		handlers: handlers,
+	}
+}
+
+// Enabled returns whether any of the handlers handles records at the given level.
+func (h *MultiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, handler := range h.handlers {
+		if handler.Enabled(ctx, level) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Handle sends the record to all handlers that handle records at its level.
+func (h *MultiHandler) Handle(ctx context.Context, record slog.Record) error {
+	var errs []error
+	for _, handler := range h.handlers {
+		if !handler.Enabled(ctx, record.Level) {
+			continue
+		}
+
+		if err := handler.Handle(ctx, record.Clone()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// WithAttrs returns a MultiHandler whose handlers add the given attributes to all records.
+func (h *MultiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make([]slog.Handler, len(h.handlers))
+	for index, handler := range h.handlers {
+		handlers[index] = handler.WithAttrs(attrs)
+	}
+
+	return NewMultiHandler(handlers...)
+}
+
+// WithGroup returns a MultiHandler whose handlers qualify all following attributes with the given group.
+func (h *MultiHandler) WithGroup(name string) slog.Handler {
+	handlers := make([]slog.Handler, len(h.handlers))
+	for index, handler := range h.handlers {
+		handlers[index] = handler.WithGroup(name)
+	}
+
+	return NewMultiHandler(handlers...)
+}
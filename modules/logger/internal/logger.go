@@ -1,46 +1,86 @@
 package internal
 
 import (
-	"fmt"
+	"context"
 	"github.com/cosys-io/cosys/common"
-	"log"
+	"log/slog"
 )
 
-// Logger is an implementation of the Logger core service using the native log package.
-type Logger struct{}
+// Logger is an implementation of the Logger core service using the log/slog package.
+type Logger struct {
+	logger *slog.Logger
+}
+
+// NewLogger returns a new Logger writing to the given handler.
+func NewLogger(handler slog.Handler) *Logger {
+	return &Logger{
+		logger: slog.New(handler),
+	}
+}
+
+// Log logs a message with the given fields at the given log level.
+func (l *Logger) Log(logLevel common.LogLevel, msg string, fields ...common.Field) {
+	l.logger.LogAttrs(context.Background(), SlogLevel(logLevel), msg, attrs(fields)...)
+}
+
+// Debug logs a message with the given fields at the debug level.
+func (l *Logger) Debug(msg string, fields ...common.Field) {
+	l.Log(common.Debug, msg, fields...)
+}
+
+// Info logs a message with the given fields at the info level.
+func (l *Logger) Info(msg string, fields ...common.Field) {
+	l.Log(common.Info, msg, fields...)
+}
+
+// Warn logs a message with the given fields at the warn level.
+func (l *Logger) Warn(msg string, fields ...common.Field) {
+	l.Log(common.Warn, msg, fields...)
+}
 
-// Log logs a message at the given log level.
-func (l Logger) Log(stringer fmt.Stringer, logLevel common.LogLevel) {
+// Error logs a message with the given fields at the error level.
+func (l *Logger) Error(msg string, fields ...common.Field) {
+	l.Log(common.Error, msg, fields...)
+}
+
+// With returns a child logger that adds the given fields to all of its messages.
+func (l *Logger) With(fields ...common.Field) common.Logger {
+	return &Logger{
+		logger: slog.New(l.logger.Handler().WithAttrs(attrs(fields))),
+	}
+}
+
+// Enabled returns whether messages at the given log level are logged.
+func (l *Logger) Enabled(logLevel common.LogLevel) bool {
+	return l.logger.Enabled(context.Background(), SlogLevel(logLevel))
+}
+
+// SlogLevel returns the slog level corresponding to the given log level.
+func SlogLevel(logLevel common.LogLevel) slog.Level {
 	switch logLevel {
 	case common.Debug:
-		log.Println("DEBUG: ", stringer.String())
-	case common.Info:
-		log.Println("INFO: ", stringer.String())
+		return slog.LevelDebug
 	case common.Warn:
-		log.Println("WARN: ", stringer.String())
+		return slog.LevelWarn
 	case common.Error:
-		log.Println("ERROR: ", stringer.String())
+		return slog.LevelError
 	default:
-		log.Println(stringer.String())
+		return slog.LevelInfo
 	}
 }
 
-// Info logs a message at the info level.
-func (l Logger) Info(stringer fmt.Stringer) {
-	l.Log(stringer, common.Info)
-}
-
-// Debug logs a message at the debug level.
-func (l Logger) Debug(stringer fmt.Stringer) {
-	l.Log(stringer, common.Debug)
-}
+// attrs returns the slog attributes of the given fields.
+// Error values are logged by their message.
+func attrs(fields []common.Field) []slog.Attr {
+	attrs := make([]slog.Attr, 0, len(fields))
+	for _, field := range fields {
+		if err, ok := field.Value.(error); ok && err != nil {
+			attrs = append(attrs, slog.String(field.Key, err.Error()))
+			continue
+		}
 
-// Warn logs a message at the warn level.
-func (l Logger) Warn(stringer fmt.Stringer) {
-	l.Log(stringer, common.Warn)
-}
+		attrs = append(attrs, slog.Any(field.Key, field.Value))
+	}
 
-// Error logs a message at the error level.
-func (l Logger) Error(stringer fmt.Stringer) {
-	l.Log(stringer, common.Error)
+	return attrs
 }
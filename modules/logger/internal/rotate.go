@@ -0,0 +1,143 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// RotatingFile is a log file that is rotated when it reaches a maximum size.
+// The rotated files are named with increasing suffixes, e.g. app.log.1 is the most recent,
+// and only a maximum number of them are kept.
+type RotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	mutex sync.Mutex
+	file  *os.File
+	size  int64
+}
+
+// NewRotatingFile returns a new RotatingFile writing to the given path,
+// rotated when it reaches maxSize bytes, keeping at most maxBackups rotated files.
+// The file is rotated only if maxSize is positive.
+func NewRotatingFile(path string, maxSize int64, maxBackups int) (*RotatingFile, error) {
+	file := &RotatingFile{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+
+	if err := file.open(); err != nil {
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// Write writes to the file, rotating the file first if the write would exceed the maximum size.
+// If the file cannot be rotated, it is written to without being rotated, and the error of the rotation is returned.
+// Safe for concurrent use.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.file == nil {
+		return 0, fmt.Errorf("log file is closed: %s", f.path)
+	}
+
+	var rotateErr error
+	if f.maxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		rotateErr = f.rotate()
+		if f.file == nil {
+			return 0, rotateErr
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+
+	return n, errors.Join(rotateErr, err)
+}
+
+// Close closes the file.
+// Safe for concurrent use.
+func (f *RotatingFile) Close() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+
+	err := f.file.Close()
+	f.file = nil
+
+	return err
+}
+
+// open opens the file for appending, creating it and its directory if they do not exist.
+func (f *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+// rotate closes the file, shifts the rotated files, removing the oldest,
+// and opens a new file.
+// If the rotated files cannot be shifted, the file is opened again for appending, so that logs are not lost.
+func (f *RotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	f.file = nil
+
+	if err := f.shift(); err != nil {
+		return errors.Join(fmt.Errorf("could not rotate log file %s: %w", f.path, err), f.open())
+	}
+
+	return f.open()
+}
+
+// shift shifts the rotated files and moves the file to the most recent rotated file, removing the oldest,
+// or removes the file if no rotated files are kept.
+func (f *RotatingFile) shift() error {
+	if f.maxBackups <= 0 {
+		if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		return nil
+	}
+
+	for index := f.maxBackups - 1; index >= 1; index-- {
+		if err := os.Rename(backupPath(f.path, index), backupPath(f.path, index+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return os.Rename(f.path, backupPath(f.path, 1))
+}
+
+// backupPath returns the path of the rotated file with the given index.
+func backupPath(path string, index int) string {
+	return fmt.Sprintf("%s.%d", path, index)
+}
@@ -0,0 +1,80 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// readFile returns the content of the file at the given path.
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("could not read %s: %v", path, err)
+	}
+
+	return string(content)
+}
+
+func TestRotatingFileRotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "app.log")
+
+	file, err := NewRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatalf("could not open log file: %v", err)
+	}
+	defer file.Close()
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err = file.Write([]byte(line)); err != nil {
+			t.Fatalf("could not write %q: %v", line, err)
+		}
+	}
+
+	if content := readFile(t, path); content != "fourth\n" {
+		t.Errorf("log file = %q, want %q", content, "fourth\n")
+	}
+	if content := readFile(t, backupPath(path, 1)); content != "third\n" {
+		t.Errorf("first backup = %q, want %q", content, "third\n")
+	}
+	if content := readFile(t, backupPath(path, 2)); content != "second\n" {
+		t.Errorf("second backup = %q, want %q", content, "second\n")
+	}
+	if _, err = os.Stat(backupPath(path, 3)); !os.IsNotExist(err) {
+		t.Errorf("more than 2 backups kept")
+	}
+}
+
+func TestRotatingFileRotationFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	// The file cannot be moved to its first backup, which is a non-empty directory.
+	if err := os.MkdirAll(filepath.Join(backupPath(path, 1), "blocked"), 0o755); err != nil {
+		t.Fatalf("could not create directory: %v", err)
+	}
+
+	file, err := NewRotatingFile(path, 10, 1)
+	if err != nil {
+		t.Fatalf("could not open log file: %v", err)
+	}
+	defer file.Close()
+
+	if _, err = file.Write([]byte("first\n")); err != nil {
+		t.Fatalf("could not write: %v", err)
+	}
+
+	if _, err = file.Write([]byte("second\n")); err == nil {
+		t.Error("expected the rotation error")
+	}
+
+	// The file is opened again, so that logs are still written.
+	if _, err = file.Write([]byte("third\n")); err == nil {
+		t.Error("expected the rotation error")
+	}
+
+	if content := readFile(t, path); content != "first\nsecond\nthird\n" {
+		t.Errorf("log file = %q, want all lines", content)
+	}
+}
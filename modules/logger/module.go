@@ -1,13 +1,222 @@
 package logger
 
 import (
+	"errors"
+	"fmt"
 	"github.com/cosys-io/cosys/common"
 	"github.com/cosys-io/cosys/modules/logger/internal"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
 )
 
-// init registers the module to register the Logger core service.
+// ModuleName is the name the module is registered with, which other modules can depend on.
+const ModuleName = "logger"
+
+// Formats of log sinks.
+const (
+	TextFormat = "text" // TextFormat writes logs as key=value pairs.
+	JSONFormat = "json" // JSONFormat writes logs as JSON objects.
+)
+
+// Types of log sinks.
+const (
+	StdoutSink = "stdout" // StdoutSink writes logs to standard output.
+	StderrSink = "stderr" // StderrSink writes logs to standard error.
+	FileSink   = "file"   // FileSink writes logs to a rotating file.
+)
+
+// Config is the configuration of the logger module.
+type Config struct {
+	Level  common.LogLevel // Level is the minimum level of logged messages.
+	Format string          // Format is the format of sinks without a format.
+	Sinks  []Sink          // Sinks are the outputs that logs are written to.
+}
+
+// Sink is the configuration of an output that logs are written to.
+type Sink struct {
+	Type       string `mapstructure:"type"`        // Type is the type of the sink.
+	Format     string `mapstructure:"format"`      // Format is the format of the sink, defaults to the format of the config.
+	Path       string `mapstructure:"path"`        // Path is the path of the log file for file sinks.
+	MaxSize    int64  `mapstructure:"max_size"`    // MaxSize is the size in bytes at which the log file is rotated, the file is not rotated if 0.
+	MaxBackups int    `mapstructure:"max_backups"` // MaxBackups is the number of rotated log files kept.
+}
+
+var (
+	configMutex sync.RWMutex
+	config      = defaultConfig()
+
+	CleanupHookKey string // CleanupHookKey can be used to update or remove the cleanup hook.
+)
+
+// hookPriority is the priority of the cleanup hook,
+// so that the log files are closed after the hooks of other modules, which may log.
+const hookPriority = 1000
+
+// defaultConfig returns the default configuration from the COSYS_LOG_LEVEL, COSYS_LOG_FORMAT
+// and COSYS_LOG_FILE environment variables, defaulting to text logs at the info level on standard output.
+// Configurations set with Configure and in the project configurations override it.
+func defaultConfig() Config {
+	defaultConfig := Config{
+		Level:  common.Info,
+		Format: TextFormat,
+		Sinks: []Sink{
+			{Type: StdoutSink},
+		},
+	}
+
+	if level, err := common.ParseLogLevel(os.Getenv("COSYS_LOG_LEVEL")); err == nil {
+		defaultConfig.Level = level
+	}
+
+	if format := os.Getenv("COSYS_LOG_FORMAT"); format == JSONFormat {
+		defaultConfig.Format = JSONFormat
+	}
+
+	if path := os.Getenv("COSYS_LOG_FILE"); path != "" {
+		defaultConfig.Sinks = append(defaultConfig.Sinks, Sink{
+			Type:       FileSink,
+			Path:       path,
+			MaxSize:    100 << 20,
+			MaxBackups: 5,
+		})
+	}
+
+	return defaultConfig
+}
+
+// Configure sets the configuration of the logger module, which the project configurations override.
+// Must be called before the cosys app is created.
+// Throws an error if the level, a format or a sink is not found, or if a file sink has no path.
+// Safe for concurrent use.
+func Configure(newConfig Config) error {
+	newConfig, err := validateConfig(newConfig)
+	if err != nil {
+		return err
+	}
+
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	config = newConfig
+	return nil
+}
+
+// validateConfig returns the given configuration, with the text format if it has no format.
+// Throws an error if the level, a format or a sink is not found, or if a file sink has no path.
+func validateConfig(config Config) (Config, error) {
+	if _, err := common.ParseLogLevel(string(config.Level)); err != nil {
+		return Config{}, err
+	}
+
+	if config.Format == "" {
+		config.Format = TextFormat
+	}
+
+	for _, sink := range append([]Sink{{Format: config.Format}}, config.Sinks...) {
+		if sink.Format != "" && sink.Format != TextFormat && sink.Format != JSONFormat {
+			return Config{}, fmt.Errorf("log format not found: %s", sink.Format)
+		}
+	}
+
+	for _, sink := range config.Sinks {
+		switch sink.Type {
+		case StdoutSink, StderrSink:
+		case FileSink:
+			if sink.Path == "" {
+				return Config{}, fmt.Errorf("log file path not specified")
+			}
+		default:
+			return Config{}, fmt.Errorf("log sink not found: %s", sink.Type)
+		}
+	}
+
+	return config, nil
+}
+
+// init registers the module to register the Logger core service,
+// and the cleanup hook closing the log files.
 func init() {
-	_ = common.RegisterModule(func(cosys *common.Cosys) error {
-		return cosys.UseLogger(internal.Logger{})
+	_ = common.RegisterNamedModule(ModuleName, func(cosys *common.Cosys) error {
+		configMutex.RLock()
+		moduleConfig := config
+		configMutex.RUnlock()
+
+		moduleConfig, err := withProjectConfigs(moduleConfig)
+		if err != nil {
+			return err
+		}
+
+		handler, files, err := newHandler(moduleConfig)
+		if err != nil {
+			return err
+		}
+
+		if err = cosys.UseLogger(internal.NewLogger(handler)); err != nil {
+			return errors.Join(err, closeFiles(files))
+		}
+
+		CleanupHookKey, err = cosys.AddCleanupHook(func(cosys *common.Cosys) error {
+			return closeFiles(files)
+		}, common.HookPriority(hookPriority))
+		if err != nil {
+			return errors.Join(err, closeFiles(files))
+		}
+
+		return nil
 	})
 }
+
+// newHandler returns the slog handler writing to all sinks of the given config,
+// and the files of its file sinks, which must be closed once the handler is no longer used.
+func newHandler(config Config) (slog.Handler, []*internal.RotatingFile, error) {
+	var files []*internal.RotatingFile
+
+	options := &slog.HandlerOptions{
+		Level: internal.SlogLevel(config.Level),
+	}
+
+	handlers := make([]slog.Handler, 0, len(config.Sinks))
+	for _, sink := range config.Sinks {
+		var writer io.Writer
+		switch sink.Type {
+		case StdoutSink:
+			writer = os.Stdout
+		case StderrSink:
+			writer = os.Stderr
+		case FileSink:
+			file, err := internal.NewRotatingFile(sink.Path, sink.MaxSize, sink.MaxBackups)
+			if err != nil {
+				return nil, nil, errors.Join(err, closeFiles(files))
+			}
+			files = append(files, file)
+			writer = file
+		default:
+			return nil, nil, errors.Join(fmt.Errorf("log sink not found: %s", sink.Type), closeFiles(files))
+		}
+
+		format := sink.Format
+		if format == "" {
+			format = config.Format
+		}
+
+		if format == JSONFormat {
+			handlers = append(handlers, slog.NewJSONHandler(writer, options))
+		} else {
+			handlers = append(handlers, slog.NewTextHandler(writer, options))
+		}
+	}
+
+	return internal.NewMultiHandler(handlers...), files, nil
+}
+
+// closeFiles closes the given files, and returns the errors of all files that could not be closed.
+func closeFiles(files []*internal.RotatingFile) error {
+	var errs []error
+	for _, file := range files {
+		errs = append(errs, file.Close())
+	}
+
+	return errors.Join(errs...)
+}
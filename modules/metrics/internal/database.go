@@ -0,0 +1,102 @@
+package internal
+
+import (
+	"github.com/cosys-io/cosys/common"
+	"time"
+)
+
+// Database is a database core service that records the count and duration of all calls
+// to the database it wraps, by model uid and operation.
+type Database struct {
+	database common.Database
+}
+
+// WrapDatabase is the DatabaseWrapper that instruments the database core service.
+func WrapDatabase(database common.Database) common.Database {
+	return &Database{
+		database: database,
+	}
+}
+
+// observe records a call to the wrapped database.
+func observe(uid string, operation string, start time.Time, err error) {
+	dbQueriesTotal.Inc(uid, operation, status(err))
+	dbQueryDuration.Observe(seconds(start), uid, operation)
+}
+
+// FindOne returns the first entity that satisfy the query conditions.
+func (d *Database) FindOne(uid string, params common.DBParams) (common.Entity, error) {
+	start := time.Now()
+	entity, err := d.database.FindOne(uid, params)
+	observe(uid, "findOne", start, err)
+
+	return entity, err
+}
+
+// FindMany returns all entities that satisfy the query conditions.
+func (d *Database) FindMany(uid string, params common.DBParams) ([]common.Entity, error) {
+	start := time.Now()
+	entities, err := d.database.FindMany(uid, params)
+	observe(uid, "findMany", start, err)
+
+	return entities, err
+}
+
+// Create creates an entity and returns it.
+func (d *Database) Create(uid string, data common.Entity, params common.DBParams) (common.Entity, error) {
+	start := time.Now()
+	entity, err := d.database.Create(uid, data, params)
+	observe(uid, "create", start, err)
+
+	return entity, err
+}
+
+// CreateMany creates entities and returns them.
+func (d *Database) CreateMany(uid string, data []common.Entity, params common.DBParams) ([]common.Entity, error) {
+	start := time.Now()
+	entities, err := d.database.CreateMany(uid, data, params)
+	observe(uid, "createMany", start, err)
+
+	return entities, err
+}
+
+// Update updates the first entity that satisfy the query conditions and returns it.
+func (d *Database) Update(uid string, data common.Entity, params common.DBParams) (common.Entity, error) {
+	start := time.Now()
+	entity, err := d.database.Update(uid, data, params)
+	observe(uid, "update", start, err)
+
+	return entity, err
+}
+
+// UpdateMany updates all entities that satisfy the query conditions and returns them.
+func (d *Database) UpdateMany(uid string, data common.Entity, params common.DBParams) ([]common.Entity, error) {
+	start := time.Now()
+	entities, err := d.database.UpdateMany(uid, data, params)
+	observe(uid, "updateMany", start, err)
+
+	return entities, err
+}
+
+// Delete deletes the first entity that satisfy the query conditions and returns it.
+func (d *Database) Delete(uid string, params common.DBParams) (common.Entity, error) {
+	start := time.Now()
+	entity, err := d.database.Delete(uid, params)
+	observe(uid, "delete", start, err)
+
+	return entity, err
+}
+
+// DeleteMany deletes all entities that satisfy the query conditions and returns them.
+func (d *Database) DeleteMany(uid string, params common.DBParams) ([]common.Entity, error) {
+	start := time.Now()
+	entities, err := d.database.DeleteMany(uid, params)
+	observe(uid, "deleteMany", start, err)
+
+	return entities, err
+}
+
+// Close closes the wrapped database.
+func (d *Database) Close() error {
+	return d.database.Close()
+}
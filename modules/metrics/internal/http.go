@@ -0,0 +1,68 @@
+package internal
+
+import (
+	"github.com/cosys-io/cosys/common"
+	"github.com/cosys-io/cosys/modules/server/response"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// InstrumentRoutes is the MiddlewareFunc that records the count, duration and status codes
+// of requests by the route they matched.
+func InstrumentRoutes(cosys *common.Cosys) (func(http.HandlerFunc) http.HandlerFunc, error) {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			recorder := response.NewRecorder(w)
+
+			next(recorder, r)
+
+			route := "unmatched"
+			if matched, ok := common.RouteFromContext(r.Context()); ok {
+				route = matched.String()
+			}
+
+			httpRequestsTotal.Inc(route, strconv.Itoa(recorder.Status()))
+			httpRequestDuration.Observe(seconds(start), route)
+		}
+	}, nil
+}
+
+// InstrumentModels is the BootstrapHook that adds the lifecycle observers to every model.
+func InstrumentModels(cosys *common.Cosys) error {
+	for uid, model := range cosys.Models() {
+		if _, err := model.AddLifecycleObserver_(observeLifecycle(uid)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// observeLifecycle returns the LifecycleObserver that records the duration and errors
+// of the lifecycle events of the model with the given uid.
+func observeLifecycle(uid string) common.LifecycleObserver {
+	return func(event string, duration time.Duration, err error) {
+		lifecycleDuration.Observe(duration.Seconds(), uid, event)
+		if err != nil {
+			lifecycleErrorsTotal.Inc(uid, event)
+		}
+	}
+}
+
+// MetricsRoute returns the route exposing all metrics in the Prometheus text format.
+func MetricsRoute() common.Route {
+	return common.NewRoute("GET", "/metrics", metrics,
+		common.Describe(common.RouteDoc{Hidden: true}))
+}
+
+// metrics is the ActionFunc for exposing all metrics in the Prometheus text format.
+func metrics(cosys *common.Cosys) (http.HandlerFunc, error) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+
+		_ = registry.Write(w)
+	}, nil
+}
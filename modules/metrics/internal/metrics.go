@@ -0,0 +1,60 @@
+package internal
+
+import (
+	"time"
+)
+
+// registry is the registry of all metrics exposed by the metrics module.
+var registry = NewRegistry()
+
+var (
+	httpRequestsTotal = registry.NewCounterVec(
+		"cosys_http_requests_total",
+		"Total number of HTTP requests by route and status code.",
+		"route", "status",
+	)
+	httpRequestDuration = registry.NewHistogramVec(
+		"cosys_http_request_duration_seconds",
+		"Duration of HTTP requests in seconds by route.",
+		DefaultBuckets,
+		"route",
+	)
+
+	dbQueriesTotal = registry.NewCounterVec(
+		"cosys_db_queries_total",
+		"Total number of database queries by model, operation and status.",
+		"model", "operation", "status",
+	)
+	dbQueryDuration = registry.NewHistogramVec(
+		"cosys_db_query_duration_seconds",
+		"Duration of database queries in seconds by model and operation.",
+		DefaultBuckets,
+		"model", "operation",
+	)
+
+	lifecycleErrorsTotal = registry.NewCounterVec(
+		"cosys_lifecycle_errors_total",
+		"Total number of lifecycle events whose hooks returned an error, by model and event.",
+		"model", "event",
+	)
+	lifecycleDuration = registry.NewHistogramVec(
+		"cosys_lifecycle_duration_seconds",
+		"Duration of calling the hooks of lifecycle events in seconds by model and event.",
+		DefaultBuckets,
+		"model", "event",
+	)
+)
+
+// status returns the status label for the given error.
+func status(err error) string {
+	if err != nil {
+		return "error"
+	}
+
+	return "ok"
+}
+
+// seconds returns the duration since the given start time in seconds.
+func seconds(start time.Time) float64 {
+	return time.Since(start).Seconds()
+}
@@ -0,0 +1,289 @@
+package internal
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are the default upper bounds of histogram buckets, in seconds.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// collector is a metric that can be written in the Prometheus text format.
+type collector interface {
+	write(w io.Writer) error
+}
+
+// Registry is a collection of metrics.
+type Registry struct {
+	mutex      sync.RWMutex
+	collectors []collector
+}
+
+// NewRegistry returns a new empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		collectors: []collector{},
+	}
+}
+
+// NewCounterVec returns a new counter partitioned by the given labels, and adds it to the registry.
+// Safe for concurrent use.
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	counter := &CounterVec{
+		name:   name,
+		help:   help,
+		labels: labels,
+		series: map[string]*counterSeries{},
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.collectors = append(r.collectors, counter)
+	return counter
+}
+
+// NewHistogramVec returns a new histogram with the given buckets partitioned by the given labels,
+// and adds it to the registry.
+// Safe for concurrent use.
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	sortedBuckets := slices.Clone(buckets)
+	slices.Sort(sortedBuckets)
+
+	histogram := &HistogramVec{
+		name:    name,
+		help:    help,
+		labels:  labels,
+		buckets: sortedBuckets,
+		series:  map[string]*histogramSeries{},
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.collectors = append(r.collectors, histogram)
+	return histogram
+}
+
+// Write writes all metrics in the registry in the Prometheus text format.
+// Safe for concurrent use.
+func (r *Registry) Write(w io.Writer) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, c := range r.collectors {
+		if err := c.write(w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CounterVec is a counter partitioned by label values.
+type CounterVec struct {
+	name   string
+	help   string
+	labels []string
+
+	mutex  sync.Mutex
+	series map[string]*counterSeries
+}
+
+// counterSeries is the value of a counter for a set of label values.
+type counterSeries struct {
+	labelValues []string
+	value       float64
+}
+
+// Inc increments the counter for the given label values.
+// Safe for concurrent use.
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds the given value to the counter for the given label values.
+// Safe for concurrent use.
+func (c *CounterVec) Add(value float64, labelValues ...string) {
+	key := seriesKey(labelValues)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	series, ok := c.series[key]
+	if !ok {
+		series = &counterSeries{
+			labelValues: labelValues,
+		}
+		c.series[key] = series
+	}
+
+	series.value += value
+}
+
+// write writes the counter in the Prometheus text format.
+func (c *CounterVec) write(w io.Writer) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", c.name, escapeHelp(c.help), c.name); err != nil {
+		return err
+	}
+
+	for _, key := range sortedKeys(c.series) {
+		series := c.series[key]
+		if _, err := fmt.Fprintf(w, "%s%s %s\n",
+			c.name, formatLabels(c.labels, series.labelValues, "", ""), formatValue(series.value)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// HistogramVec is a histogram partitioned by label values.
+type HistogramVec struct {
+	name    string
+	help    string
+	labels  []string
+	buckets []float64
+
+	mutex  sync.Mutex
+	series map[string]*histogramSeries
+}
+
+// histogramSeries is the value of a histogram for a set of label values.
+type histogramSeries struct {
+	labelValues []string
+	counts      []uint64
+	count       uint64
+	sum         float64
+}
+
+// Observe adds an observation to the histogram for the given label values.
+// Safe for concurrent use.
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	key := seriesKey(labelValues)
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	series, ok := h.series[key]
+	if !ok {
+		series = &histogramSeries{
+			labelValues: labelValues,
+			counts:      make([]uint64, len(h.buckets)),
+		}
+		h.series[key] = series
+	}
+
+	for index, bucket := range h.buckets {
+		if value <= bucket {
+			series.counts[index]++
+		}
+	}
+	series.count++
+	series.sum += value
+}
+
+// write writes the histogram in the Prometheus text format.
+func (h *HistogramVec) write(w io.Writer) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", h.name, escapeHelp(h.help), h.name); err != nil {
+		return err
+	}
+
+	for _, key := range sortedKeys(h.series) {
+		series := h.series[key]
+
+		for index, bucket := range h.buckets {
+			if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n",
+				h.name, formatLabels(h.labels, series.labelValues, "le", formatValue(bucket)), series.counts[index]); err != nil {
+				return err
+			}
+		}
+
+		if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n",
+			h.name, formatLabels(h.labels, series.labelValues, "le", "+Inf"), series.count); err != nil {
+			return err
+		}
+
+		if _, err := fmt.Fprintf(w, "%s_sum%s %s\n%s_count%s %d\n",
+			h.name, formatLabels(h.labels, series.labelValues, "", ""), formatValue(series.sum),
+			h.name, formatLabels(h.labels, series.labelValues, "", ""), series.count); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// seriesKey returns the key of the series with the given label values.
+func seriesKey(labelValues []string) string {
+	return strings.Join(labelValues, "\xff")
+}
+
+// sortedKeys returns the keys of the given series map in order, so that output is stable.
+func sortedKeys[T any](series map[string]T) []string {
+	keys := make([]string, 0, len(series))
+	for key := range series {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
+
+// formatLabels returns the label set of a series, with an extra label if extraName is not empty.
+func formatLabels(names []string, values []string, extraName string, extraValue string) string {
+	pairs := make([]string, 0, len(names)+1)
+	for index, name := range names {
+		value := ""
+		if index < len(values) {
+			value = values[index]
+		}
+
+		pairs = append(pairs, name+`="`+escapeLabel(value)+`"`)
+	}
+
+	if extraName != "" {
+		pairs = append(pairs, extraName+`="`+escapeLabel(extraValue)+`"`)
+	}
+
+	if len(pairs) == 0 {
+		return ""
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// formatValue returns the Prometheus text representation of a sample value.
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
+
+// escapeLabel escapes backslashes, double quotes and line feeds in a label value.
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// escapeHelp escapes backslashes and line feeds in a help text.
+func escapeHelp(help string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+}
@@ -0,0 +1,28 @@
+package metrics
+
+import (
+	"github.com/cosys-io/cosys/common"
+	"github.com/cosys-io/cosys/modules/metrics/internal"
+)
+
+// ModuleName is the name the module is registered with, which other modules can depend on.
+const ModuleName = "metrics"
+
+// init registers the module to instrument the routes, database and lifecycle hooks,
+// and to register the route exposing the metrics.
+// It is registered after the cms and sqlite3 modules, if registered, whose models and database it instruments.
+func init() {
+	_ = common.RegisterNamedModule(ModuleName, func(cosys *common.Cosys) error {
+		if _, err := cosys.AddDatabaseWrapper(internal.WrapDatabase); err != nil {
+			return err
+		}
+
+		if _, err := cosys.AddBootstrapHook(internal.InstrumentModels); err != nil {
+			return err
+		}
+
+		cosys.Use(common.UseMiddlewares(internal.InstrumentRoutes))
+
+		return cosys.AddRoutes(internal.MetricsRoute())
+	}, common.OptionallyDependsOn("cms", "sqlite3"))
+}
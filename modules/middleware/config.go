@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"github.com/cosys-io/cosys/common"
+	"github.com/spf13/viper"
+)
+
+// Keys of the project configurations of the middleware module.
+const (
+	GlobalKey = "middleware_global"
+
+	CORSAllowedOriginsKey   = "cors_allowed_origins"
+	CORSAllowedMethodsKey   = "cors_allowed_methods"
+	CORSAllowedHeadersKey   = "cors_allowed_headers"
+	CORSExposedHeadersKey   = "cors_exposed_headers"
+	CORSAllowCredentialsKey = "cors_allow_credentials"
+	CORSMaxAgeKey           = "cors_max_age"
+
+	HSTSMaxAgeKey            = "security_hsts_max_age"
+	HSTSIncludeSubdomainsKey = "security_hsts_include_subdomains"
+	HSTSPreloadKey           = "security_hsts_preload"
+	ContentSecurityPolicyKey = "security_content_security_policy"
+	FrameOptionsKey          = "security_frame_options"
+	ReferrerPolicyKey        = "security_referrer_policy"
+
+	CSRFCookieNameKey   = "csrf_cookie_name"
+	CSRFHeaderNameKey   = "csrf_header_name"
+	CSRFFormFieldKey    = "csrf_form_field"
+	CSRFCookieSecureKey = "csrf_cookie_secure"
+)
+
+// withProjectConfigs returns the given configuration,
+// overridden by the configurations set in the project configurations.
+func withProjectConfigs(config Config) (Config, error) {
+	if err := common.ReadConfigs(); err != nil {
+		return Config{}, err
+	}
+
+	setStrings(GlobalKey, &config.Global)
+
+	setStrings(CORSAllowedOriginsKey, &config.CORS.AllowedOrigins)
+	setStrings(CORSAllowedMethodsKey, &config.CORS.AllowedMethods)
+	setStrings(CORSAllowedHeadersKey, &config.CORS.AllowedHeaders)
+	setStrings(CORSExposedHeadersKey, &config.CORS.ExposedHeaders)
+	if viper.IsSet(CORSAllowCredentialsKey) {
+		config.CORS.AllowCredentials = viper.GetBool(CORSAllowCredentialsKey)
+	}
+	if viper.IsSet(CORSMaxAgeKey) {
+		config.CORS.MaxAge = viper.GetDuration(CORSMaxAgeKey)
+	}
+	if err := config.CORS.Validate(); err != nil {
+		return Config{}, err
+	}
+
+	if viper.IsSet(HSTSMaxAgeKey) {
+		config.SecurityHeaders.HSTSMaxAge = viper.GetDuration(HSTSMaxAgeKey)
+	}
+	if viper.IsSet(HSTSIncludeSubdomainsKey) {
+		config.SecurityHeaders.HSTSIncludeSubdomains = viper.GetBool(HSTSIncludeSubdomainsKey)
+	}
+	if viper.IsSet(HSTSPreloadKey) {
+		config.SecurityHeaders.HSTSPreload = viper.GetBool(HSTSPreloadKey)
+	}
+	setString(ContentSecurityPolicyKey, &config.SecurityHeaders.ContentSecurityPolicy)
+	setString(FrameOptionsKey, &config.SecurityHeaders.FrameOptions)
+	setString(ReferrerPolicyKey, &config.SecurityHeaders.ReferrerPolicy)
+
+	setString(CSRFCookieNameKey, &config.CSRF.CookieName)
+	setString(CSRFHeaderNameKey, &config.CSRF.HeaderName)
+	setString(CSRFFormFieldKey, &config.CSRF.FormField)
+	if viper.IsSet(CSRFCookieSecureKey) {
+		config.CSRF.CookieSecure = viper.GetBool(CSRFCookieSecureKey)
+	}
+
+	return config, nil
+}
+
+// setString sets the value to the project configuration with the given key, if it is set.
+func setString(key string, value *string) {
+	if viper.IsSet(key) {
+		*value = viper.GetString(key)
+	}
+}
+
+// setStrings sets the value to the project configuration with the given key, if it is set.
+func setStrings(key string, value *[]string) {
+	if viper.IsSet(key) {
+		*value = viper.GetStringSlice(key)
+	}
+}
@@ -0,0 +1,59 @@
+package internal
+
+import (
+	"github.com/cosys-io/cosys/common"
+	"github.com/cosys-io/cosys/modules/server/response"
+	"net/http"
+	"time"
+)
+
+// AccessLogMiddleware is the MiddlewareFunc that logs the method, path, route, status,
+// response size and latency of requests using the logger core service,
+// or the standard logger if no logger is registered.
+// Responses with client error status codes are logged at the warn level,
+// and with server error status codes at the error level.
+func AccessLogMiddleware(cosys *common.Cosys) (func(http.HandlerFunc) http.HandlerFunc, error) {
+	logger, err := cosys.Logger()
+	if err != nil {
+		logger = common.StandardLogger()
+	}
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			recorder := response.NewRecorder(w)
+
+			next(recorder, r)
+
+			fields := []common.Field{
+				common.F("method", r.Method),
+				common.F("path", r.URL.Path),
+				common.F("status", recorder.Status()),
+				common.F("bytes", recorder.Bytes()),
+				common.F("latency", time.Since(start).String()),
+			}
+
+			if route, ok := common.RouteFromContext(r.Context()); ok {
+				fields = append(fields, common.F("route", route.String()))
+			}
+
+			if requestId := RequestId(r.Context()); requestId != "" {
+				fields = append(fields, common.F("requestId", requestId))
+			}
+
+			if spanContext, ok := common.SpanContextFromContext(r.Context()); ok {
+				fields = append(fields, common.F("traceId", spanContext.TraceId.String()))
+			}
+
+			level := common.Info
+			switch {
+			case recorder.Status() >= http.StatusInternalServerError:
+				level = common.Error
+			case recorder.Status() >= http.StatusBadRequest:
+				level = common.Warn
+			}
+
+			logger.Log(level, "request", fields...)
+		}
+	}, nil
+}
@@ -0,0 +1,149 @@
+package internal
+
+import (
+	"fmt"
+	"github.com/cosys-io/cosys/common"
+	"github.com/cosys-io/cosys/modules/server/response"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSConfig is the configuration of the cors middleware.
+type CORSConfig struct {
+	AllowedOrigins   []string      // AllowedOrigins are the allowed origins, "*" allows all origins and "https://*.example.com" allows all subdomains.
+	AllowedMethods   []string      // AllowedMethods are the methods allowed in cross-origin requests.
+	AllowedHeaders   []string      // AllowedHeaders are the headers allowed in cross-origin requests, all requested headers are allowed if empty.
+	ExposedHeaders   []string      // ExposedHeaders are the response headers readable by cross-origin clients.
+	AllowCredentials bool          // AllowCredentials specifies that cross-origin requests may include cookies and credentials.
+	MaxAge           time.Duration // MaxAge is the duration that clients may cache preflight responses.
+}
+
+// DefaultCORSConfig returns the default configuration of the cors middleware, which allows no origins.
+func DefaultCORSConfig() CORSConfig {
+	return CORSConfig{
+		AllowedOrigins:   []string{},
+		AllowedMethods:   []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE"},
+		AllowedHeaders:   []string{},
+		ExposedHeaders:   []string{RequestIdHeader, DefaultCSRFConfig().HeaderName},
+		AllowCredentials: false,
+		MaxAge:           10 * time.Minute,
+	}
+}
+
+// Validate checks that the configuration does not allow all origins with credentials,
+// as any origin could then make credentialed requests.
+// Throws an error if the configuration is invalid.
+func (c CORSConfig) Validate() error {
+	if c.AllowCredentials && slices.Contains(c.AllowedOrigins, "*") {
+		return fmt.Errorf("cors origins cannot include \"*\" if credentials are allowed")
+	}
+
+	return nil
+}
+
+// CORSMiddleware returns the MiddlewareFunc that adds the cors headers to responses to allowed origins,
+// and responds to preflight requests.
+// Preflight requests from origins that are not allowed, or for methods that are not allowed, are forbidden.
+// The configuration must be valid, see CORSConfig.Validate.
+func CORSMiddleware(config CORSConfig) common.MiddlewareFunc {
+	allowedMethods := strings.Join(config.AllowedMethods, ", ")
+	allowedHeaders := strings.Join(config.AllowedHeaders, ", ")
+	exposedHeaders := strings.Join(config.ExposedHeaders, ", ")
+	maxAge := strconv.Itoa(int(config.MaxAge.Seconds()))
+	allowAll := slices.Contains(config.AllowedOrigins, "*")
+
+	return func(cosys *common.Cosys) (func(http.HandlerFunc) http.HandlerFunc, error) {
+		return func(next http.HandlerFunc) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				origin := r.Header.Get("Origin")
+				preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+				header := w.Header()
+				header.Add("Vary", "Origin")
+				if preflight {
+					header.Add("Vary", "Access-Control-Request-Method")
+					header.Add("Vary", "Access-Control-Request-Headers")
+				}
+
+				if origin == "" {
+					next(w, r)
+					return
+				}
+
+				if !allowedOrigin(config.AllowedOrigins, origin) {
+					if preflight {
+						response.RespondError(w, "Origin not allowed", http.StatusForbidden)
+						return
+					}
+
+					next(w, r)
+					return
+				}
+
+				if allowAll {
+					header.Set("Access-Control-Allow-Origin", "*")
+				} else {
+					header.Set("Access-Control-Allow-Origin", origin)
+				}
+
+				if config.AllowCredentials {
+					header.Set("Access-Control-Allow-Credentials", "true")
+				}
+
+				if !preflight {
+					if exposedHeaders != "" {
+						header.Set("Access-Control-Expose-Headers", exposedHeaders)
+					}
+
+					next(w, r)
+					return
+				}
+
+				requestMethod := r.Header.Get("Access-Control-Request-Method")
+				if !slices.Contains(config.AllowedMethods, requestMethod) {
+					response.RespondError(w, "Method not allowed", http.StatusForbidden)
+					return
+				}
+
+				header.Set("Access-Control-Allow-Methods", allowedMethods)
+				if allowedHeaders != "" {
+					header.Set("Access-Control-Allow-Headers", allowedHeaders)
+				} else if requestHeaders := r.Header.Get("Access-Control-Request-Headers"); requestHeaders != "" {
+					header.Set("Access-Control-Allow-Headers", requestHeaders)
+				}
+				if config.MaxAge > 0 {
+					header.Set("Access-Control-Max-Age", maxAge)
+				}
+
+				w.WriteHeader(http.StatusNoContent)
+			}
+		}, nil
+	}
+}
+
+// allowedOrigin returns whether the given origin matches any of the allowed origins.
+func allowedOrigin(allowedOrigins []string, origin string) bool {
+	for _, allowed := range allowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+
+		scheme, host, ok := strings.Cut(allowed, "://*.")
+		if ok && strings.HasPrefix(origin, scheme+"://") && strings.HasSuffix(origin, "."+host) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Preflight is the ActionFunc of the routes added for preflight requests,
+// which responds with no content when the request is not handled by the cors middleware.
+func Preflight(cosys *common.Cosys) (http.HandlerFunc, error) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}, nil
+}
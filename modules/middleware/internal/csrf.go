@@ -0,0 +1,118 @@
+package internal
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"github.com/cosys-io/cosys/common"
+	"github.com/cosys-io/cosys/modules/server/response"
+	"mime"
+	"net/http"
+)
+
+// CSRFConfig is the configuration of the csrf middleware.
+type CSRFConfig struct {
+	CookieName   string // CookieName is the name of the cookie holding the csrf token.
+	HeaderName   string // HeaderName is the name of the request header echoing the csrf token.
+	FormField    string // FormField is the name of the form field echoing the csrf token in form submissions.
+	CookieSecure bool   // CookieSecure specifies that the csrf cookie is only sent over https.
+}
+
+// DefaultCSRFConfig returns the default configuration of the csrf middleware.
+func DefaultCSRFConfig() CSRFConfig {
+	return CSRFConfig{
+		CookieName:   "csrf_token",
+		HeaderName:   "X-CSRF-Token",
+		FormField:    "csrf_token",
+		CookieSecure: true,
+	}
+}
+
+// csrfTokenKey is the context key of the csrf token.
+type csrfTokenKey struct{}
+
+// CSRFToken returns the csrf token of the request from its context, or an empty string if none.
+func CSRFToken(ctx context.Context) string {
+	token, _ := ctx.Value(csrfTokenKey{}).(string)
+	return token
+}
+
+// CSRFMiddleware returns the MiddlewareFunc that protects cookie-based sessions with double-submit csrf tokens.
+// A random token is set in a cookie readable by scripts, and requests with unsafe methods must echo it
+// in the csrf header or form field.
+// Requests without cookies are not checked, as they carry no ambient credentials.
+func CSRFMiddleware(config CSRFConfig) common.MiddlewareFunc {
+	return func(cosys *common.Cosys) (func(http.HandlerFunc) http.HandlerFunc, error) {
+		return func(next http.HandlerFunc) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				token := ""
+				if cookie, err := r.Cookie(config.CookieName); err == nil && cookie.Value != "" {
+					token = cookie.Value
+				}
+
+				if !safeMethod(r.Method) && len(r.Cookies()) > 0 {
+					if token == "" || !validCSRFToken(token, submittedCSRFToken(r, config)) {
+						response.RespondError(w, "Invalid CSRF token", http.StatusForbidden)
+						return
+					}
+				}
+
+				if token == "" {
+					token = newCSRFToken()
+					http.SetCookie(w, &http.Cookie{
+						Name:     config.CookieName,
+						Value:    token,
+						Path:     "/",
+						Secure:   config.CookieSecure,
+						HttpOnly: false,
+						SameSite: http.SameSiteLaxMode,
+					})
+				}
+
+				w.Header().Set(config.HeaderName, token)
+				w.Header().Add("Vary", "Cookie")
+
+				next(w, r.WithContext(context.WithValue(r.Context(), csrfTokenKey{}, token)))
+			}
+		}, nil
+	}
+}
+
+// safeMethod returns whether the method is safe, i.e. it should not change state and is not checked.
+func safeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	default:
+		return false
+	}
+}
+
+// submittedCSRFToken returns the csrf token echoed in the header of the request,
+// or in the form field for form submissions.
+func submittedCSRFToken(r *http.Request, config CSRFConfig) string {
+	if token := r.Header.Get(config.HeaderName); token != "" {
+		return token
+	}
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || (mediaType != "application/x-www-form-urlencoded" && mediaType != "multipart/form-data") {
+		return ""
+	}
+
+	return r.FormValue(config.FormField)
+}
+
+// validCSRFToken returns whether the submitted token matches the token of the cookie, in constant time.
+func validCSRFToken(token string, submitted string) bool {
+	return submitted != "" && subtle.ConstantTimeCompare([]byte(token), []byte(submitted)) == 1
+}
+
+// newCSRFToken returns a random csrf token.
+func newCSRFToken() string {
+	token := make([]byte, 32)
+	_, _ = rand.Read(token)
+
+	return base64.RawURLEncoding.EncodeToString(token)
+}
@@ -0,0 +1,66 @@
+package internal
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/cosys-io/cosys/common"
+	"net/http"
+)
+
+// RequestIdHeader is the header propagating the id of a request.
+const RequestIdHeader = "X-Request-ID"
+
+// maxRequestIdLength is the maximum length of a request id propagated from a client.
+const maxRequestIdLength = 128
+
+// requestIdKey is the context key of the request id.
+type requestIdKey struct{}
+
+// RequestId returns the id of the request from its context, or an empty string if none.
+func RequestId(ctx context.Context) string {
+	requestId, _ := ctx.Value(requestIdKey{}).(string)
+	return requestId
+}
+
+// RequestIdMiddleware is the MiddlewareFunc that propagates the X-Request-ID header of requests,
+// or generates one if missing or invalid, adds it to the request context and sets it on the response.
+func RequestIdMiddleware(cosys *common.Cosys) (func(http.HandlerFunc) http.HandlerFunc, error) {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			requestId := r.Header.Get(RequestIdHeader)
+			if !validRequestId(requestId) {
+				requestId = newRequestId()
+			}
+
+			w.Header().Set(RequestIdHeader, requestId)
+
+			ctx := context.WithValue(r.Context(), requestIdKey{}, requestId)
+			next(w, r.WithContext(ctx))
+		}
+	}, nil
+}
+
+// validRequestId returns whether a request id propagated from a client can be used,
+// i.e. it is not empty, not too long and only contains printable ascii characters.
+func validRequestId(requestId string) bool {
+	if requestId == "" || len(requestId) > maxRequestIdLength {
+		return false
+	}
+
+	for _, char := range []byte(requestId) {
+		if char < '!' || char > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// newRequestId returns a random request id.
+func newRequestId() string {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,72 @@
+package internal
+
+import (
+	"github.com/cosys-io/cosys/common"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// SecurityHeadersConfig is the configuration of the security headers middleware.
+// Headers with empty values are not set.
+type SecurityHeadersConfig struct {
+	HSTSMaxAge            time.Duration // HSTSMaxAge is the duration that clients should only use https, hsts is disabled if 0.
+	HSTSIncludeSubdomains bool          // HSTSIncludeSubdomains specifies that hsts also applies to subdomains.
+	HSTSPreload           bool          // HSTSPreload specifies that the domain may be included in browser preload lists.
+	ContentSecurityPolicy string        // ContentSecurityPolicy is the value of the Content-Security-Policy header.
+	FrameOptions          string        // FrameOptions is the value of the X-Frame-Options header.
+	ReferrerPolicy        string        // ReferrerPolicy is the value of the Referrer-Policy header.
+}
+
+// DefaultSecurityHeadersConfig returns the default configuration of the security headers middleware.
+func DefaultSecurityHeadersConfig() SecurityHeadersConfig {
+	return SecurityHeadersConfig{
+		HSTSMaxAge:            365 * 24 * time.Hour,
+		HSTSIncludeSubdomains: true,
+		HSTSPreload:           false,
+		ContentSecurityPolicy: "default-src 'self'; frame-ancestors 'none'",
+		FrameOptions:          "DENY",
+		ReferrerPolicy:        "strict-origin-when-cross-origin",
+	}
+}
+
+// SecurityHeadersMiddleware returns the MiddlewareFunc that sets the security headers on all responses.
+// The Strict-Transport-Security header is only set on responses to https requests,
+// including requests forwarded by a proxy terminating https.
+func SecurityHeadersMiddleware(config SecurityHeadersConfig) common.MiddlewareFunc {
+	hsts := ""
+	if config.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.Itoa(int(config.HSTSMaxAge.Seconds()))
+		if config.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+		if config.HSTSPreload {
+			hsts += "; preload"
+		}
+	}
+
+	return func(cosys *common.Cosys) (func(http.HandlerFunc) http.HandlerFunc, error) {
+		return func(next http.HandlerFunc) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				header := w.Header()
+
+				header.Set("X-Content-Type-Options", "nosniff")
+
+				if hsts != "" && (r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https") {
+					header.Set("Strict-Transport-Security", hsts)
+				}
+				if config.ContentSecurityPolicy != "" {
+					header.Set("Content-Security-Policy", config.ContentSecurityPolicy)
+				}
+				if config.FrameOptions != "" {
+					header.Set("X-Frame-Options", config.FrameOptions)
+				}
+				if config.ReferrerPolicy != "" {
+					header.Set("Referrer-Policy", config.ReferrerPolicy)
+				}
+
+				next(w, r)
+			}
+		}, nil
+	}
+}
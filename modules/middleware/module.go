@@ -0,0 +1,162 @@
+package middleware
+
+import (
+	"context"
+	"github.com/cosys-io/cosys/common"
+	"github.com/cosys-io/cosys/modules/middleware/internal"
+	"net/http"
+	"sync"
+)
+
+// ModuleName is the name the module is registered with, which other modules can depend on.
+const ModuleName = "middleware"
+
+// Uids of the middlewares registered by the module.
+const (
+	RequestIdUid       = "requestId"       // RequestIdUid is the uid of the request id middleware.
+	AccessLogUid       = "accessLog"       // AccessLogUid is the uid of the access log middleware.
+	CORSUid            = "cors"            // CORSUid is the uid of the cors middleware.
+	SecurityHeadersUid = "securityHeaders" // SecurityHeadersUid is the uid of the security headers middleware.
+	CSRFUid            = "csrf"            // CSRFUid is the uid of the csrf middleware.
+)
+
+// RequestIdHeader is the header propagating the id of a request.
+const RequestIdHeader = internal.RequestIdHeader
+
+type (
+	CORSConfig            = internal.CORSConfig            // CORSConfig is the configuration of the cors middleware.
+	SecurityHeadersConfig = internal.SecurityHeadersConfig // SecurityHeadersConfig is the configuration of the security headers middleware.
+	CSRFConfig            = internal.CSRFConfig            // CSRFConfig is the configuration of the csrf middleware.
+)
+
+// Config is the configuration of the middleware module.
+// Configurations set in the project configurations override those set with Configure.
+type Config struct {
+	Global          []string // Global are the uids of the middlewares applied to all routes, in order.
+	CORS            CORSConfig
+	SecurityHeaders SecurityHeadersConfig
+	CSRF            CSRFConfig
+}
+
+// DefaultConfig returns the default configuration of the middleware module,
+// which applies the request id and access log middlewares to all routes.
+func DefaultConfig() Config {
+	return Config{
+		Global:          []string{RequestIdUid, AccessLogUid},
+		CORS:            internal.DefaultCORSConfig(),
+		SecurityHeaders: internal.DefaultSecurityHeadersConfig(),
+		CSRF:            internal.DefaultCSRFConfig(),
+	}
+}
+
+var (
+	configMutex sync.RWMutex
+	config      = DefaultConfig()
+
+	BootstrapHookKey string // BootstrapHookKey can be used to update or remove the bootstrap hook.
+)
+
+// Configure sets the configuration of the middleware module.
+// Must be called before the cosys app is created.
+// Safe for concurrent use.
+func Configure(newConfig Config) {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	config = newConfig
+}
+
+// RequestId returns the id of a request from its context,
+// or an empty string if the request id middleware was not applied.
+func RequestId(ctx context.Context) string {
+	return internal.RequestId(ctx)
+}
+
+// CSRFToken returns the csrf token of a request from its context,
+// or an empty string if the csrf middleware was not applied.
+func CSRFToken(ctx context.Context) string {
+	return internal.CSRFToken(ctx)
+}
+
+// init registers the module to register the built-in middlewares, and applies the global middlewares to all routes.
+// If cors origins are configured, the bootstrap hook adding the routes for preflight requests is also registered.
+// It is registered after the logger module, if registered, which the access log writes to.
+func init() {
+	_ = common.RegisterNamedModule(ModuleName, func(cosys *common.Cosys) error {
+		configMutex.RLock()
+		moduleConfig := config
+		configMutex.RUnlock()
+
+		moduleConfig, err := withProjectConfigs(moduleConfig)
+		if err != nil {
+			return err
+		}
+
+		middlewareFuncs := map[string]common.MiddlewareFunc{
+			RequestIdUid:       internal.RequestIdMiddleware,
+			AccessLogUid:       internal.AccessLogMiddleware,
+			CORSUid:            internal.CORSMiddleware(moduleConfig.CORS),
+			SecurityHeadersUid: internal.SecurityHeadersMiddleware(moduleConfig.SecurityHeaders),
+			CSRFUid:            internal.CSRFMiddleware(moduleConfig.CSRF),
+		}
+
+		middlewares := make([]common.Middleware, 0, len(middlewareFuncs))
+		for uid, middlewareFunc := range middlewareFuncs {
+			middleware, err := common.NewMiddleware(uid, middlewareFunc)
+			if err != nil {
+				return err
+			}
+
+			middlewares = append(middlewares, middleware)
+		}
+
+		if err = cosys.AddMiddlewares(middlewares...); err != nil {
+			return err
+		}
+
+		if len(moduleConfig.Global) > 0 {
+			cosys.Use(common.GetMiddlewares(moduleConfig.Global...))
+		}
+
+		if len(moduleConfig.CORS.AllowedOrigins) > 0 {
+			BootstrapHookKey, err = cosys.AddBootstrapHook(addPreflightRoutes)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, common.OptionallyDependsOn("logger"))
+}
+
+// addPreflightRoutes adds a route for preflight requests for every path without one,
+// in the same group and with the same middlewares as the existing routes of the path,
+// so that the cors middleware handles preflight requests wherever it is applied.
+func addPreflightRoutes(cosys *common.Cosys) error {
+	routes := cosys.Routes()
+
+	hasPreflight := map[string]bool{}
+	for _, route := range routes {
+		if route.Method == http.MethodOptions {
+			hasPreflight[route.Path] = true
+		}
+	}
+
+	for _, route := range routes {
+		if hasPreflight[route.Path] {
+			continue
+		}
+		hasPreflight[route.Path] = true
+
+		preflightRoute := common.NewRoute(http.MethodOptions, route.Path, internal.Preflight,
+			common.UseMiddlewares(route.Middlewares...),
+			common.Describe(common.RouteDoc{Hidden: true}))
+		preflightRoute.Group = route.Group
+
+		if err := cosys.AddRoutes(preflightRoute); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
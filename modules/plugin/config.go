@@ -0,0 +1,46 @@
+package plugin
+
+import (
+	"github.com/cosys-io/cosys/common"
+	"github.com/spf13/viper"
+)
+
+// Keys of the project configurations of the plugin module.
+const (
+	PluginsKey        = "plugins"
+	StartTimeoutKey   = "plugin_start_timeout"
+	CallTimeoutKey    = "plugin_call_timeout"
+	MaxRestartsKey    = "plugin_max_restarts"
+	RestartBackoffKey = "plugin_restart_backoff"
+)
+
+// withProjectConfigs returns the given configuration,
+// overridden by the configurations set in the project configurations.
+func withProjectConfigs(config Config) (Config, error) {
+	if err := common.ReadConfigs(); err != nil {
+		return Config{}, err
+	}
+
+	if viper.IsSet(PluginsKey) {
+		var plugins []PluginConfig
+		if err := viper.UnmarshalKey(PluginsKey, &plugins); err != nil {
+			return Config{}, err
+		}
+
+		config.Plugins = plugins
+	}
+	if viper.IsSet(StartTimeoutKey) {
+		config.StartTimeout = viper.GetDuration(StartTimeoutKey)
+	}
+	if viper.IsSet(CallTimeoutKey) {
+		config.CallTimeout = viper.GetDuration(CallTimeoutKey)
+	}
+	if viper.IsSet(MaxRestartsKey) {
+		config.MaxRestarts = viper.GetInt(MaxRestartsKey)
+	}
+	if viper.IsSet(RestartBackoffKey) {
+		config.RestartBackoff = viper.GetDuration(RestartBackoffKey)
+	}
+
+	return config, nil
+}
@@ -0,0 +1,81 @@
+// Command greeter is a sample plugin, which greets on GET /greet/{name}, and crashes on GET /greet/crash,
+// logs created posts, forbids deleting posts, and has a greet command.
+//
+//	go build -o plugins/greeter ./modules/plugin/examples/greeter
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/cosys-io/cosys/common"
+	"github.com/cosys-io/cosys/modules/plugin/sdk"
+)
+
+func main() {
+	sdk.Serve(sdk.Module{
+		Name: "greeter",
+		Routes: []sdk.Route{
+			{
+				Method: "GET",
+				Path:   "/greet/{name}",
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					name := r.PathValue("name")
+					if name == "crash" {
+						os.Exit(1)
+					}
+
+					w.Header().Set("Content-Type", "application/json")
+					_ = json.NewEncoder(w).Encode(map[string]any{
+						"greeting": "Hello, " + name + "!",
+						"pid":      os.Getpid(),
+					})
+				},
+			},
+		},
+		Hooks: []sdk.Hook{
+			{
+				Model: "api.posts",
+				Event: "afterCreate",
+				Handler: func(ctx context.Context, event sdk.HookEvent) error {
+					var post struct {
+						Title string `json:"title"`
+					}
+					if err := json.Unmarshal(event.Result, &post); err != nil {
+						return err
+					}
+
+					log.Printf("post created: %s", post.Title)
+					return nil
+				},
+			},
+			{
+				Model: "api.posts",
+				Event: "beforeDelete",
+				Handler: func(ctx context.Context, event sdk.HookEvent) error {
+					return common.NewForbiddenError("posts cannot be deleted")
+				},
+			},
+		},
+		Commands: []sdk.Command{
+			{
+				Name:  "greet",
+				Short: "Greet from the greeter plugin",
+				Run: func(ctx context.Context, args []string, out io.Writer) error {
+					if len(args) == 0 {
+						return fmt.Errorf("name not found")
+					}
+
+					_, err := fmt.Fprintf(out, "Hello, %s!\n", strings.Join(args, " "))
+					return err
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,331 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/cosys-io/cosys/modules/plugin/internal/protocol"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-plugin"
+)
+
+// PluginConfig is the configuration of a plugin launched by the host.
+type PluginConfig struct {
+	Name string   `mapstructure:"name"` // Name is the name of the plugin, used in logs and errors.
+	Path string   `mapstructure:"path"` // Path is the path to the executable of the plugin.
+	Args []string `mapstructure:"args"` // Args are the arguments the plugin is launched with.
+	Env  []string `mapstructure:"env"`  // Env are the environment variables set for the plugin, overriding those of the app.
+}
+
+// Options are configurations of the plugins launched by the host.
+type Options struct {
+	// StartTimeout is the maximum time to wait for plugins to handshake after being launched.
+	StartTimeout time.Duration
+	// CallTimeout is the maximum time calls to hooks and commands of plugins take, or unlimited if not positive.
+	// Calls to routes are bound by the context of the request.
+	CallTimeout time.Duration
+	// MaxRestarts is the number of times a crashed plugin is restarted, or unlimited if negative.
+	MaxRestarts int
+	// RestartBackoff is the minimum time between restarts of a plugin, and the interval crashes are checked at.
+	RestartBackoff time.Duration
+}
+
+// Plugin is a plugin process launched by the host, which is restarted when it crashes.
+type Plugin struct {
+	config  PluginConfig
+	options Options
+
+	mutex     sync.Mutex
+	client    *plugin.Client
+	rpc       *protocol.Client
+	restarts  int
+	lastStart time.Time
+	killed    bool
+
+	registration protocol.Registration
+}
+
+// Launch launches the plugin with the given configuration and returns it, along with its registration.
+// The plugin keeps running until it is stopped or killed.
+// Throws an error if the plugin cannot be launched, fails to handshake or to register.
+func Launch(config PluginConfig, options Options) (*Plugin, error) {
+	if config.Name == "" {
+		config.Name = config.Path
+	}
+
+	p := &Plugin{
+		config:  config,
+		options: options,
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if err := p.start(); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Name returns the name of the plugin.
+func (p *Plugin) Name() string {
+	return p.config.Name
+}
+
+// Registration returns what the plugin registered when it was launched.
+func (p *Plugin) Registration() protocol.Registration {
+	return p.registration
+}
+
+// start launches the plugin process and gets its registration.
+// Must be called with the mutex locked.
+func (p *Plugin) start() error {
+	cmd := exec.Command(p.config.Path, p.config.Args...)
+	cmd.Env = append(os.Environ(), p.config.Env...)
+
+	client := plugin.NewClient(&plugin.ClientConfig{
+		HandshakeConfig:  protocol.Handshake,
+		Plugins:          plugin.PluginSet{protocol.PluginName: &protocol.Plugin{}},
+		Cmd:              cmd,
+		SkipHostEnv:      true, // The environment of the app is set before that of the plugin, which overrides it.
+		AllowedProtocols: []plugin.Protocol{plugin.ProtocolGRPC},
+		StartTimeout:     p.options.StartTimeout,
+		Stderr:           os.Stderr,
+		Logger: hclog.New(&hclog.LoggerOptions{
+			Name:   "plugin." + p.config.Name,
+			Output: os.Stderr,
+			Level:  hclog.Warn,
+		}),
+	})
+
+	p.lastStart = time.Now()
+
+	rpc, err := dispense(client)
+	if err != nil {
+		client.Kill()
+		return fmt.Errorf("plugin %s: %w", p.config.Name, err)
+	}
+
+	ctx, cancel := p.callContext(context.Background())
+	defer cancel()
+
+	registration, err := rpc.Register(ctx)
+	if err != nil {
+		client.Kill()
+		return fmt.Errorf("plugin %s registration: %w", p.config.Name, err)
+	}
+
+	p.client = client
+	p.rpc = rpc
+	p.registration = registration
+
+	return nil
+}
+
+// dispense returns the client of the plugin service of the given plugin client, launching the plugin process.
+func dispense(client *plugin.Client) (*protocol.Client, error) {
+	clientProtocol, err := client.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := clientProtocol.Dispense(protocol.PluginName)
+	if err != nil {
+		return nil, err
+	}
+
+	rpc, ok := raw.(*protocol.Client)
+	if !ok {
+		return nil, fmt.Errorf("invalid plugin client")
+	}
+
+	return rpc, nil
+}
+
+// Start launches the plugin again after it was stopped, and does nothing if it is running.
+// Throws protocol.ErrUnavailable if the plugin is killed, or cannot be launched.
+// Safe for concurrent use.
+func (p *Plugin) Start() error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.killed {
+		return fmt.Errorf("plugin %s: %w", p.config.Name, protocol.ErrUnavailable)
+	}
+
+	if p.client != nil {
+		return nil
+	}
+
+	return p.relaunch()
+}
+
+// Stop gracefully shuts down the plugin process, which is launched again by Start or the next call to the plugin.
+// Safe for concurrent use.
+func (p *Plugin) Stop() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.client != nil {
+		p.client.Kill()
+		p.client = nil
+		p.rpc = nil
+	}
+}
+
+// conn returns the client of the plugin service, launching the plugin if it is stopped, or restarting it if it crashed.
+// Throws protocol.ErrUnavailable if the plugin is killed, or cannot be launched, or crashed and cannot be restarted yet or anymore.
+// Safe for concurrent use.
+func (p *Plugin) conn() (*protocol.Client, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.killed {
+		return nil, fmt.Errorf("plugin %s: %w", p.config.Name, protocol.ErrUnavailable)
+	}
+
+	if p.client == nil {
+		if err := p.relaunch(); err != nil {
+			return nil, err
+		}
+
+		return p.rpc, nil
+	}
+
+	if !p.client.Exited() {
+		return p.rpc, nil
+	}
+
+	if err := p.restart(); err != nil {
+		return nil, err
+	}
+
+	return p.rpc, nil
+}
+
+// restart restarts the crashed plugin, unless it was restarted too recently or too many times.
+// Must be called with the mutex locked.
+func (p *Plugin) restart() error {
+	if p.options.MaxRestarts >= 0 && p.restarts >= p.options.MaxRestarts {
+		return fmt.Errorf("plugin %s exceeded %d restarts: %w", p.config.Name, p.options.MaxRestarts, protocol.ErrUnavailable)
+	}
+
+	if time.Since(p.lastStart) < p.options.RestartBackoff {
+		return fmt.Errorf("plugin %s is restarting: %w", p.config.Name, protocol.ErrUnavailable)
+	}
+
+	p.restarts++
+	logf("plugin %s exited, restarting (%d)", p.config.Name, p.restarts)
+
+	return p.relaunch()
+}
+
+// relaunch launches the plugin process again, keeping the registration it was launched with.
+// Must be called with the mutex locked.
+func (p *Plugin) relaunch() error {
+	registration := p.registration
+	if err := p.start(); err != nil {
+		return fmt.Errorf("%w: %w", err, protocol.ErrUnavailable)
+	}
+
+	// Routes, hooks and commands are registered on the cosys app once, so changes are not applied.
+	if !sameRegistration(registration, p.registration) {
+		logf("plugin %s registration changed after relaunch, which is ignored", p.config.Name)
+		p.registration = registration
+	}
+
+	return nil
+}
+
+// Supervise restarts the plugin whenever it crashes, checking at the restart backoff interval, until the context is done.
+// Stopped plugins are not restarted.
+func (p *Plugin) Supervise(ctx context.Context) {
+	interval := p.options.RestartBackoff
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.mutex.Lock()
+			if !p.killed && p.client != nil && p.client.Exited() {
+				if err := p.restart(); err != nil {
+					logf("%v", err)
+				}
+			}
+			p.mutex.Unlock()
+		}
+	}
+}
+
+// Kill gracefully shuts down the plugin process, which is not restarted afterwards.
+// Safe for concurrent use.
+func (p *Plugin) Kill() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.killed = true
+	if p.client != nil {
+		p.client.Kill()
+		p.client = nil
+		p.rpc = nil
+	}
+}
+
+// callContext returns the context of calls to hooks and commands, with the call timeout, if any.
+func (p *Plugin) callContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if p.options.CallTimeout > 0 {
+		return context.WithTimeout(ctx, p.options.CallTimeout)
+	}
+
+	return context.WithCancel(ctx)
+}
+
+// sameRegistration returns whether the given registrations register the same routes, hooks and commands.
+func sameRegistration(a, b protocol.Registration) bool {
+	if len(a.Routes) != len(b.Routes) || len(a.Hooks) != len(b.Hooks) || len(a.Commands) != len(b.Commands) {
+		return false
+	}
+
+	for i := range a.Routes {
+		if a.Routes[i] != b.Routes[i] {
+			return false
+		}
+	}
+
+	for i := range a.Hooks {
+		if a.Hooks[i] != b.Hooks[i] {
+			return false
+		}
+	}
+
+	for i := range a.Commands {
+		if a.Commands[i] != b.Commands[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// logf logs the given message with the standard logger,
+// as plugins are launched during registration, before the logger core service can be used.
+func logf(format string, args ...any) {
+	log.Printf(format, args...)
+}
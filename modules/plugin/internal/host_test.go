@@ -0,0 +1,270 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/cosys-io/cosys/common"
+	"github.com/cosys-io/cosys/modules/plugin/internal/protocol"
+)
+
+// greeterPath is the path to the sample plugin built for the tests.
+var greeterPath string
+
+// TestMain builds the sample plugin before running the tests.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "cosys-plugin")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	greeterPath = filepath.Join(dir, "greeter")
+
+	build := exec.Command(filepath.Join(runtime.GOROOT(), "bin", "go"), "build", "-o", greeterPath, "../examples/greeter")
+	build.Stderr = os.Stderr
+	if err = build.Run(); err != nil {
+		fmt.Fprintln(os.Stderr, "could not build the sample plugin:", err)
+		_ = os.RemoveAll(dir)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// launchGreeter launches the sample plugin, which is killed at the end of the test.
+func launchGreeter(t *testing.T) *Plugin {
+	t.Helper()
+
+	p, err := Launch(PluginConfig{Name: "greeter", Path: greeterPath}, Options{
+		StartTimeout:   10 * time.Second,
+		CallTimeout:    10 * time.Second,
+		MaxRestarts:    1,
+		RestartBackoff: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("could not launch plugin: %v", err)
+	}
+	t.Cleanup(p.Kill)
+
+	return p
+}
+
+// newRouteServer returns a server serving the routes of the given plugin, which is closed at the end of the test.
+func newRouteServer(t *testing.T, p *Plugin) *httptest.Server {
+	t.Helper()
+
+	cosys, err := common.New()
+	if err != nil {
+		t.Fatalf("could not create cosys app: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	for _, route := range p.Routes() {
+		handler, err := route.Action(cosys)
+		if err != nil {
+			t.Fatalf("could not create handler of %s: %v", route, err)
+		}
+
+		mux.HandleFunc(route.String(), handler)
+	}
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// greet calls the greet route of the sample plugin, and returns the status and the pid of the plugin process.
+func greet(t *testing.T, server *httptest.Server, name string) (int, int) {
+	t.Helper()
+
+	resp, err := http.Get(server.URL + "/greet/" + name)
+	if err != nil {
+		t.Fatalf("could not call route: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, 0
+	}
+
+	var body struct {
+		Greeting string `json:"greeting"`
+		Pid      int    `json:"pid"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("could not decode response: %v", err)
+	}
+
+	if want := "Hello, " + name + "!"; body.Greeting != want {
+		t.Errorf("greeting = %q, want %q", body.Greeting, want)
+	}
+
+	return resp.StatusCode, body.Pid
+}
+
+// waitExited waits until the plugin process exits.
+func waitExited(t *testing.T, p *Plugin) {
+	t.Helper()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		p.mutex.Lock()
+		exited := p.client != nil && p.client.Exited()
+		p.mutex.Unlock()
+
+		if exited {
+			return
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatal("plugin did not exit")
+}
+
+func TestPluginRegistration(t *testing.T) {
+	p := launchGreeter(t)
+
+	registration := p.Registration()
+	if len(registration.Routes) != 1 || registration.Routes[0] != (protocol.RouteInfo{Method: "GET", Path: "/greet/{name}"}) {
+		t.Errorf("routes = %+v, want GET /greet/{name}", registration.Routes)
+	}
+	if len(registration.Hooks) != 2 {
+		t.Errorf("hooks = %+v, want afterCreate and beforeDelete on api.posts", registration.Hooks)
+	}
+	if len(registration.Commands) != 1 || registration.Commands[0].Name != "greet" {
+		t.Errorf("commands = %+v, want greet", registration.Commands)
+	}
+}
+
+func TestPluginRoute(t *testing.T) {
+	p := launchGreeter(t)
+	server := newRouteServer(t, p)
+
+	status, pid := greet(t, server, "cosys")
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, want %d", status, http.StatusOK)
+	}
+	if pid == 0 || pid == os.Getpid() {
+		t.Errorf("route served by process %d, want the plugin process", pid)
+	}
+}
+
+func TestPluginHook(t *testing.T) {
+	p := launchGreeter(t)
+
+	for _, info := range p.Registration().Hooks {
+		err := p.lifecycleHook(info)(common.EventQuery{Result: map[string]any{"title": "Hello"}})
+
+		switch info.Event {
+		case "afterCreate":
+			if err != nil {
+				t.Errorf("afterCreate hook failed: %v", err)
+			}
+		case "beforeDelete":
+			if common.ErrorCodeOf(err) != common.ForbiddenCode {
+				t.Errorf("beforeDelete hook error = %v, want a forbidden error", err)
+			}
+		default:
+			t.Errorf("unexpected hook: %+v", info)
+		}
+	}
+}
+
+func TestPluginCommand(t *testing.T) {
+	p := launchGreeter(t)
+	p.Stop()
+
+	commands := p.Commands()
+	if len(commands) != 1 {
+		t.Fatalf("commands = %d, want 1", len(commands))
+	}
+
+	var out bytes.Buffer
+	cmd := commands[0](nil)
+	cmd.SetArgs([]string{"cosys", "--loud"})
+	cmd.SetOut(&out)
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("could not run command: %v", err)
+	}
+
+	if out.String() != "Hello, cosys --loud!\n" {
+		t.Errorf("output = %q, want %q", out.String(), "Hello, cosys --loud!\n")
+	}
+
+	// The stopped plugin is launched for the run of the command, and stopped again once it exits.
+	p.mutex.Lock()
+	running := p.client != nil
+	p.mutex.Unlock()
+
+	if running {
+		t.Error("plugin still running after the command exited")
+	}
+}
+
+func TestPluginRestart(t *testing.T) {
+	p := launchGreeter(t)
+	server := newRouteServer(t, p)
+
+	_, pid := greet(t, server, "first")
+
+	if status, _ := greet(t, server, "crash"); status != http.StatusBadGateway {
+		t.Errorf("crash status = %d, want %d", status, http.StatusBadGateway)
+	}
+	waitExited(t, p)
+	time.Sleep(p.options.RestartBackoff)
+
+	status, restartedPid := greet(t, server, "second")
+	if status != http.StatusOK {
+		t.Fatalf("status after restart = %d, want %d", status, http.StatusOK)
+	}
+	if restartedPid == pid {
+		t.Errorf("route served by the crashed process %d after restart", pid)
+	}
+
+	// The plugin crashes again after its only restart, and is not restarted anymore.
+	greet(t, server, "crash")
+	waitExited(t, p)
+	time.Sleep(p.options.RestartBackoff)
+
+	if _, err := p.conn(); !errors.Is(err, protocol.ErrUnavailable) {
+		t.Errorf("error after exceeding restarts = %v, want %v", err, protocol.ErrUnavailable)
+	}
+}
+
+func TestPluginKill(t *testing.T) {
+	p := launchGreeter(t)
+
+	p.mutex.Lock()
+	client := p.client
+	p.mutex.Unlock()
+
+	p.Kill()
+
+	if !client.Exited() {
+		t.Error("plugin process still running after kill")
+	}
+
+	if _, err := p.conn(); !errors.Is(err, protocol.ErrUnavailable) {
+		t.Errorf("error after kill = %v, want %v", err, protocol.ErrUnavailable)
+	}
+	if err := p.Start(); !errors.Is(err, protocol.ErrUnavailable) {
+		t.Errorf("start error after kill = %v, want %v", err, protocol.ErrUnavailable)
+	}
+}
@@ -0,0 +1,360 @@
+package protocol
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/cosys-io/cosys/common"
+	"github.com/hashicorp/go-plugin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Handshake is the handshake configuration shared by the plugin host and plugins.
+// Plugins are only started by the host if the magic cookie is set in their environment.
+var Handshake = plugin.HandshakeConfig{
+	ProtocolVersion:  1,
+	MagicCookieKey:   "COSYS_PLUGIN",
+	MagicCookieValue: "d6a5c0b2-cosys-module",
+}
+
+// PluginName is the name the plugin service is dispensed with.
+const PluginName = "module"
+
+// ServiceName is the full name of the gRPC service implemented by plugins, described in plugin.proto.
+const ServiceName = "cosys.plugin.v1.Plugin"
+
+// Registration is what a plugin registers on the cosys app.
+type Registration struct {
+	Name     string        `json:"name"`
+	Routes   []RouteInfo   `json:"routes,omitempty"`
+	Hooks    []HookInfo    `json:"hooks,omitempty"`
+	Commands []CommandInfo `json:"commands,omitempty"`
+}
+
+// RouteInfo is a route registered by a plugin, whose requests are proxied to the plugin.
+type RouteInfo struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+}
+
+// HookInfo is a hook registered by a plugin, for a lifecycle event of a model,
+// or for the bootstrap or cleanup events of the cosys app if the model is empty.
+type HookInfo struct {
+	Model string `json:"model,omitempty"`
+	Event string `json:"event"`
+}
+
+// CommandInfo is a command registered by a plugin, whose runs are proxied to the plugin.
+type CommandInfo struct {
+	Name  string `json:"name"`
+	Short string `json:"short,omitempty"`
+}
+
+// RouteRequest is a request to a route registered by a plugin.
+type RouteRequest struct {
+	Method     string            `json:"method"`
+	Pattern    string            `json:"pattern"` // Pattern is the path of the route, e.g. /hello/{name}.
+	Path       string            `json:"path"`
+	Query      string            `json:"query,omitempty"`
+	Params     map[string]string `json:"params,omitempty"` // Params are the values of the wildcards of the pattern.
+	Header     http.Header       `json:"header,omitempty"`
+	Body       []byte            `json:"body,omitempty"`
+	RemoteAddr string            `json:"remoteAddr,omitempty"`
+}
+
+// RouteResponse is the response of a plugin to a RouteRequest.
+type RouteResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   []byte      `json:"body,omitempty"`
+}
+
+// HookRequest is a call of a hook registered by a plugin.
+type HookRequest struct {
+	Model  string          `json:"model,omitempty"`
+	Event  string          `json:"event"`
+	Result json.RawMessage `json:"result,omitempty"` // Result is the json result of the query of lifecycle events, if any.
+}
+
+// CommandRequest is a run of a command registered by a plugin.
+type CommandRequest struct {
+	Name string   `json:"name"`
+	Args []string `json:"args,omitempty"`
+}
+
+// CommandResponse is the result of a run of a command registered by a plugin.
+type CommandResponse struct {
+	Output   string `json:"output,omitempty"`
+	ExitCode int    `json:"exitCode,omitempty"`
+}
+
+// Encode returns the given value as a struct, through its json encoding.
+func Encode(value any) (*structpb.Struct, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]any{}
+	if err = json.Unmarshal(encoded, &fields); err != nil {
+		return nil, err
+	}
+
+	return structpb.NewStruct(fields)
+}
+
+// Decode decodes the given struct into the value pointed to, through its json encoding.
+func Decode(message *structpb.Struct, value any) error {
+	encoded, err := json.Marshal(message.AsMap())
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(encoded, value)
+}
+
+// errorCodes are the status codes of the error codes of typed errors.
+var errorCodes = map[common.ErrorCode]codes.Code{
+	common.NotFoundCode:     codes.NotFound,
+	common.ValidationCode:   codes.InvalidArgument,
+	common.ConflictCode:     codes.AlreadyExists,
+	common.UnauthorizedCode: codes.Unauthenticated,
+	common.ForbiddenCode:    codes.PermissionDenied,
+	common.InternalCode:     codes.Internal,
+}
+
+// ToStatus returns the given error returned by a plugin as a status error,
+// with the status code of the error code of typed errors, so that they are typed errors in the host.
+func ToStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	if typedErr, ok := common.AsTypedError(err); ok {
+		if code, ok := errorCodes[typedErr.Code]; ok {
+			return status.Error(code, typedErr.Message)
+		}
+	}
+
+	return status.Error(codes.Unknown, err.Error())
+}
+
+// FromStatus returns the given status error returned by a plugin as a typed error, if its status code maps to one.
+func FromStatus(err error) error {
+	statusErr, ok := status.FromError(err)
+	if !ok || err == nil {
+		return err
+	}
+
+	for errorCode, code := range errorCodes {
+		if statusErr.Code() == code && errorCode != common.InternalCode {
+			return common.NewTypedError(errorCode, statusErr.Message(), err)
+		}
+	}
+
+	return err
+}
+
+// IsUnavailable returns whether the given error is returned because the plugin is not reachable, e.g. it crashed.
+func IsUnavailable(err error) bool {
+	return status.Code(err) == codes.Unavailable || errors.Is(err, ErrUnavailable)
+}
+
+// ErrUnavailable is returned by the host for calls to plugins that are not running and cannot be restarted.
+var ErrUnavailable = errors.New("plugin unavailable")
+
+// Server is the implementation of the plugin service.
+type Server interface {
+	Register(ctx context.Context) (Registration, error)
+	HandleRoute(ctx context.Context, request RouteRequest) (RouteResponse, error)
+	CallHook(ctx context.Context, request HookRequest) error
+	RunCommand(ctx context.Context, request CommandRequest) (CommandResponse, error)
+}
+
+// ServiceDesc is the description of the plugin service,
+// whose messages are google.protobuf.Struct and google.protobuf.Empty so that plugins need no generated code.
+var ServiceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*Server)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Register",
+			Handler: unaryHandler("Register", func(ctx context.Context, server Server, _ *structpb.Struct) (*structpb.Struct, error) {
+				registration, err := server.Register(ctx)
+				if err != nil {
+					return nil, err
+				}
+
+				return Encode(registration)
+			}),
+		},
+		{
+			MethodName: "HandleRoute",
+			Handler: unaryHandler("HandleRoute", func(ctx context.Context, server Server, in *structpb.Struct) (*structpb.Struct, error) {
+				var request RouteRequest
+				if err := Decode(in, &request); err != nil {
+					return nil, status.Error(codes.InvalidArgument, err.Error())
+				}
+
+				response, err := server.HandleRoute(ctx, request)
+				if err != nil {
+					return nil, err
+				}
+
+				return Encode(response)
+			}),
+		},
+		{
+			MethodName: "CallHook",
+			Handler: unaryHandler("CallHook", func(ctx context.Context, server Server, in *structpb.Struct) (*structpb.Struct, error) {
+				var request HookRequest
+				if err := Decode(in, &request); err != nil {
+					return nil, status.Error(codes.InvalidArgument, err.Error())
+				}
+
+				if err := server.CallHook(ctx, request); err != nil {
+					return nil, err
+				}
+
+				return &structpb.Struct{}, nil
+			}),
+		},
+		{
+			MethodName: "RunCommand",
+			Handler: unaryHandler("RunCommand", func(ctx context.Context, server Server, in *structpb.Struct) (*structpb.Struct, error) {
+				var request CommandRequest
+				if err := Decode(in, &request); err != nil {
+					return nil, status.Error(codes.InvalidArgument, err.Error())
+				}
+
+				response, err := server.RunCommand(ctx, request)
+				if err != nil {
+					return nil, err
+				}
+
+				return Encode(response)
+			}),
+		},
+	},
+	Metadata: "plugin.proto",
+}
+
+// unaryHandler returns the handler of the method with the given name, calling the given function.
+// Register takes a google.protobuf.Empty, and CallHook returns one, which are encoded the same as an empty struct.
+func unaryHandler(method string, call func(context.Context, Server, *structpb.Struct) (*structpb.Struct, error)) func(any, context.Context, func(any) error, grpc.UnaryServerInterceptor) (any, error) {
+	fullMethod := "/" + ServiceName + "/" + method
+
+	return func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+		in := new(structpb.Struct)
+		if err := dec(in); err != nil {
+			return nil, err
+		}
+
+		handler := func(ctx context.Context, req any) (any, error) {
+			out, err := call(ctx, srv.(Server), req.(*structpb.Struct))
+			return out, ToStatus(err)
+		}
+
+		if interceptor == nil {
+			return handler(ctx, in)
+		}
+
+		return interceptor(ctx, in, &grpc.UnaryServerInfo{Server: srv, FullMethod: fullMethod}, handler)
+	}
+}
+
+// Client is the client of the plugin service.
+type Client struct {
+	conn *grpc.ClientConn
+}
+
+// Register returns the registration of the plugin.
+func (c *Client) Register(ctx context.Context) (Registration, error) {
+	out := new(structpb.Struct)
+	if err := c.conn.Invoke(ctx, "/"+ServiceName+"/Register", &emptypb.Empty{}, out); err != nil {
+		return Registration{}, FromStatus(err)
+	}
+
+	var registration Registration
+	if err := Decode(out, &registration); err != nil {
+		return Registration{}, err
+	}
+
+	return registration, nil
+}
+
+// HandleRoute proxies the given route request to the plugin.
+func (c *Client) HandleRoute(ctx context.Context, request RouteRequest) (RouteResponse, error) {
+	var response RouteResponse
+	if err := c.invoke(ctx, "HandleRoute", request, &response); err != nil {
+		return RouteResponse{}, err
+	}
+
+	return response, nil
+}
+
+// CallHook proxies the given hook call to the plugin.
+func (c *Client) CallHook(ctx context.Context, request HookRequest) error {
+	in, err := Encode(request)
+	if err != nil {
+		return err
+	}
+
+	if err = c.conn.Invoke(ctx, "/"+ServiceName+"/CallHook", in, &emptypb.Empty{}); err != nil {
+		return FromStatus(err)
+	}
+
+	return nil
+}
+
+// RunCommand proxies the given command run to the plugin.
+func (c *Client) RunCommand(ctx context.Context, request CommandRequest) (CommandResponse, error) {
+	var response CommandResponse
+	if err := c.invoke(ctx, "RunCommand", request, &response); err != nil {
+		return CommandResponse{}, err
+	}
+
+	return response, nil
+}
+
+// invoke calls the method with the given name with the given request, and decodes its response.
+func (c *Client) invoke(ctx context.Context, method string, request any, response any) error {
+	in, err := Encode(request)
+	if err != nil {
+		return err
+	}
+
+	out := new(structpb.Struct)
+	if err = c.conn.Invoke(ctx, "/"+ServiceName+"/"+method, in, out); err != nil {
+		return FromStatus(err)
+	}
+
+	return Decode(out, response)
+}
+
+// Plugin is the go-plugin plugin of the plugin service, served by plugins and dispensed by the host.
+type Plugin struct {
+	plugin.NetRPCUnsupportedPlugin
+	Impl Server // Impl is the implementation of the service, only set in plugins.
+}
+
+// GRPCServer registers the implementation of the service on the gRPC server of the plugin.
+func (p *Plugin) GRPCServer(_ *plugin.GRPCBroker, server *grpc.Server) error {
+	server.RegisterService(&ServiceDesc, p.Impl)
+	return nil
+}
+
+// GRPCClient returns the client of the service of the plugin.
+func (p *Plugin) GRPCClient(_ context.Context, _ *plugin.GRPCBroker, conn *grpc.ClientConn) (any, error) {
+	return &Client{conn: conn}, nil
+}
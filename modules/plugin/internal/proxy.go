@@ -0,0 +1,248 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/cosys-io/cosys/common"
+	"github.com/cosys-io/cosys/modules/plugin/internal/protocol"
+	"github.com/cosys-io/cosys/modules/server/response"
+	"github.com/spf13/cobra"
+)
+
+// maxBodySize is the maximum size of the body of requests proxied to plugins.
+const maxBodySize = 10 << 20
+
+// App events are the events of the hooks registered by plugins without a model.
+const (
+	BootstrapEvent = "bootstrap"
+	CleanupEvent   = "cleanup"
+)
+
+// Routes returns the routes registered by the plugin, whose requests are proxied to the plugin.
+func (p *Plugin) Routes() []common.Route {
+	routes := make([]common.Route, 0, len(p.registration.Routes))
+	for _, info := range p.registration.Routes {
+		routes = append(routes, common.NewRoute(info.Method, info.Path, p.routeAction(info),
+			common.Describe(common.RouteDoc{
+				Summary: "Served by plugin " + p.config.Name,
+				Tags:    []string{"plugin " + p.config.Name},
+			})))
+	}
+
+	return routes
+}
+
+// routeAction returns the ActionFunc proxying requests to the given route to the plugin.
+// Typed errors returned by the plugin are responded to as such,
+// and other errors, e.g. if the plugin crashed, with 502 Bad Gateway.
+func (p *Plugin) routeAction(info protocol.RouteInfo) common.ActionFunc {
+	params := patternParams(info.Path)
+
+	return func(cosys *common.Cosys) (http.HandlerFunc, error) {
+		return func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+			if err != nil {
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					response.RespondError(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+					return
+				}
+
+				response.RespondErr(w, common.NewValidationError("invalid request body"))
+				return
+			}
+
+			request := protocol.RouteRequest{
+				Method:     r.Method,
+				Pattern:    info.Path,
+				Path:       r.URL.Path,
+				Query:      r.URL.RawQuery,
+				Params:     map[string]string{},
+				Header:     r.Header,
+				Body:       body,
+				RemoteAddr: r.RemoteAddr,
+			}
+
+			for _, param := range params {
+				request.Params[param] = r.PathValue(param)
+			}
+
+			rpc, err := p.conn()
+			var resp protocol.RouteResponse
+			if err == nil {
+				resp, err = rpc.HandleRoute(r.Context(), request)
+			}
+			if err != nil {
+				if _, ok := common.AsTypedError(err); ok {
+					response.RespondErr(w, err)
+					return
+				}
+
+				cosys.LogError("plugin route failed", err, common.F("plugin", p.config.Name), common.F("route", info.Method+" "+info.Path))
+				response.RespondError(w, http.StatusText(http.StatusBadGateway), http.StatusBadGateway)
+				return
+			}
+
+			for key, values := range resp.Header {
+				for _, value := range values {
+					w.Header().Add(key, value)
+				}
+			}
+
+			if resp.Status == 0 {
+				resp.Status = http.StatusOK
+			}
+
+			w.WriteHeader(resp.Status)
+			_, _ = w.Write(resp.Body)
+		}, nil
+	}
+}
+
+// patternParams returns the names of the wildcards of the given route path, e.g. name for /hello/{name}.
+func patternParams(path string) []string {
+	var params []string
+	for _, segment := range strings.Split(path, "/") {
+		if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") {
+			continue
+		}
+
+		name := strings.TrimSuffix(strings.TrimSuffix(segment[1:len(segment)-1], "..."), "$")
+		if name != "" {
+			params = append(params, name)
+		}
+	}
+
+	return params
+}
+
+// AddHooks adds the lifecycle hooks registered by the plugin to their models, whose calls are proxied to the plugin.
+// Must be called after the models are registered, e.g. in a bootstrap hook.
+// Throws an error if a model or event is not found.
+func (p *Plugin) AddHooks(cosys *common.Cosys) error {
+	for _, info := range p.registration.Hooks {
+		if info.Model == "" {
+			if info.Event != BootstrapEvent && info.Event != CleanupEvent {
+				return fmt.Errorf("plugin %s: unknown app event: %s", p.config.Name, info.Event)
+			}
+
+			continue
+		}
+
+		model, err := cosys.Model(info.Model)
+		if err != nil {
+			return fmt.Errorf("plugin %s: %w", p.config.Name, err)
+		}
+
+		if _, err = model.AddLifecycleHook_(info.Event, p.lifecycleHook(info)); err != nil {
+			return fmt.Errorf("plugin %s: %w", p.config.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// lifecycleHook returns the lifecycle hook proxying the calls of the given hook to the plugin, with the json result of the query.
+func (p *Plugin) lifecycleHook(info protocol.HookInfo) common.LifecycleHook {
+	return func(query common.EventQuery) error {
+		request := protocol.HookRequest{
+			Model: info.Model,
+			Event: info.Event,
+		}
+
+		if query.Result != nil {
+			result, err := json.Marshal(query.Result)
+			if err != nil {
+				return err
+			}
+
+			request.Result = result
+		}
+
+		rpc, err := p.conn()
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := p.callContext(query.Context)
+		defer cancel()
+
+		return rpc.CallHook(ctx, request)
+	}
+}
+
+// CallAppHooks calls the hooks registered by the plugin for the given app event, i.e. bootstrap or cleanup.
+func (p *Plugin) CallAppHooks(event string) error {
+	for _, info := range p.registration.Hooks {
+		if info.Model != "" || info.Event != event {
+			continue
+		}
+
+		rpc, err := p.conn()
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := p.callContext(context.Background())
+		err = rpc.CallHook(ctx, protocol.HookRequest{Event: event})
+		cancel()
+
+		if err != nil {
+			return fmt.Errorf("plugin %s %s hook: %w", p.config.Name, event, err)
+		}
+	}
+
+	return nil
+}
+
+// Commands returns the commands registered by the plugin, whose runs are proxied to the plugin.
+// The plugin is launched for the run, and stopped when the command exits.
+// Flags are not parsed by the app, and are passed to the plugin as arguments.
+func (p *Plugin) Commands() []common.Command {
+	commands := make([]common.Command, 0, len(p.registration.Commands))
+	for _, info := range p.registration.Commands {
+		commands = append(commands, func(*common.Cosys) *cobra.Command {
+			return &cobra.Command{
+				Use:                info.Name,
+				Short:              info.Short,
+				DisableFlagParsing: true,
+				SilenceUsage:       true,
+				RunE: func(cmd *cobra.Command, args []string) error {
+					defer p.Stop()
+
+					rpc, err := p.conn()
+					if err != nil {
+						return err
+					}
+
+					ctx, cancel := p.callContext(cmd.Context())
+					defer cancel()
+
+					resp, err := rpc.RunCommand(ctx, protocol.CommandRequest{
+						Name: info.Name,
+						Args: args,
+					})
+					if err != nil {
+						return err
+					}
+
+					_, _ = fmt.Fprint(cmd.OutOrStdout(), resp.Output)
+
+					if resp.ExitCode != 0 {
+						return fmt.Errorf("plugin command %s exited with code %d", info.Name, resp.ExitCode)
+					}
+
+					return nil
+				},
+			}
+		})
+	}
+
+	return commands
+}
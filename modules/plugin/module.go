@@ -0,0 +1,174 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/cosys-io/cosys/common"
+	"github.com/cosys-io/cosys/modules/plugin/internal"
+)
+
+// ModuleName is the name the module is registered with, which other modules can depend on.
+const ModuleName = "plugin"
+
+type (
+	PluginConfig = internal.PluginConfig // PluginConfig is the configuration of a plugin launched by the host.
+	Options      = internal.Options      // Options are configurations of the plugins launched by the host.
+)
+
+// Config is the configuration of the plugin host.
+// Configurations set in the project configurations override those set with Configure.
+type Config struct {
+	Plugins []PluginConfig // Plugins are the plugins launched when the cosys app is created.
+	Options
+}
+
+// DefaultConfig returns the default configuration of the plugin host, which launches no plugins.
+// Plugins have a minute to handshake, and 30 seconds for calls to hooks and commands,
+// and are restarted up to 5 times when they crash, at most once a second.
+func DefaultConfig() Config {
+	return Config{
+		Options: Options{
+			StartTimeout:   time.Minute,
+			CallTimeout:    30 * time.Second,
+			MaxRestarts:    5,
+			RestartBackoff: time.Second,
+		},
+	}
+}
+
+var (
+	configMutex sync.RWMutex
+	config      = DefaultConfig()
+)
+
+// Configure sets the configuration of the plugin host.
+// Must be called before the cosys app is created.
+// Safe for concurrent use.
+func Configure(newConfig Config) {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	config = newConfig
+}
+
+// init registers the module to register the routes and commands of the configured plugins,
+// the bootstrap hook launching them, adding their lifecycle hooks and supervising them, which restarts them when they crash,
+// and the cleanup hook killing them.
+// Plugins are only running during registration to get their registration, so that commands that do not bootstrap the app
+// leave no plugins running, while the commands of plugins launch them for their run.
+// It is registered after the cms and logger modules, if registered, as plugins add hooks to the models and errors are logged.
+func init() {
+	_ = common.RegisterNamedModule(ModuleName, func(cosys *common.Cosys) error {
+		configMutex.RLock()
+		moduleConfig := config
+		configMutex.RUnlock()
+
+		moduleConfig, err := withProjectConfigs(moduleConfig)
+		if err != nil {
+			return err
+		}
+
+		if len(moduleConfig.Plugins) == 0 {
+			return nil
+		}
+
+		plugins, err := launch(cosys, moduleConfig)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		var started []*internal.Plugin
+
+		if _, err = cosys.AddBootstrapHook(func(cosys *common.Cosys) error {
+			for _, p := range plugins {
+				if err := p.Start(); err != nil {
+					return err
+				}
+
+				started = append(started, p)
+
+				if err := p.AddHooks(cosys); err != nil {
+					return err
+				}
+
+				if err := p.CallAppHooks(internal.BootstrapEvent); err != nil {
+					return err
+				}
+
+				go p.Supervise(ctx)
+			}
+
+			return nil
+		}); err != nil {
+			cancel()
+			kill(plugins)
+			return err
+		}
+
+		if _, err = cosys.AddCleanupHook(func(cosys *common.Cosys) error {
+			cancel()
+
+			var errs []error
+			for _, p := range started {
+				errs = append(errs, p.CallAppHooks(internal.CleanupEvent))
+			}
+
+			kill(plugins)
+			return errors.Join(errs...)
+		}); err != nil {
+			cancel()
+			kill(plugins)
+			return err
+		}
+
+		stop(plugins)
+
+		return nil
+	}, common.OptionallyDependsOn("cms", "logger"))
+}
+
+// launch launches the plugins of the given configuration and registers their routes and commands.
+// If a plugin fails to launch or register, the launched plugins are killed.
+func launch(cosys *common.Cosys, moduleConfig Config) ([]*internal.Plugin, error) {
+	var plugins []*internal.Plugin
+
+	for _, pluginConfig := range moduleConfig.Plugins {
+		p, err := internal.Launch(pluginConfig, moduleConfig.Options)
+		if err != nil {
+			kill(plugins)
+			return nil, err
+		}
+
+		plugins = append(plugins, p)
+
+		if err = cosys.AddRoutes(p.Routes()...); err != nil {
+			kill(plugins)
+			return nil, err
+		}
+
+		if err = cosys.AddCommands(p.Commands()...); err != nil {
+			kill(plugins)
+			return nil, err
+		}
+	}
+
+	return plugins, nil
+}
+
+// stop stops the given plugins, which are launched again when they are started or called.
+func stop(plugins []*internal.Plugin) {
+	for _, p := range plugins {
+		p.Stop()
+	}
+}
+
+// kill kills the given plugins.
+func kill(plugins []*internal.Plugin) {
+	for _, p := range plugins {
+		p.Kill()
+	}
+}
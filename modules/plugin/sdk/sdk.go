@@ -0,0 +1,231 @@
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/cosys-io/cosys/modules/plugin/internal/protocol"
+	"github.com/cosys-io/cosys/modules/server/response"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-plugin"
+)
+
+// Module is a cosys module served by a plugin process,
+// whose routes, hooks and commands are registered on the cosys app by the plugin host.
+type Module struct {
+	Name     string
+	Routes   []Route
+	Hooks    []Hook
+	Commands []Command
+}
+
+// Route is a route of a plugin, whose requests are proxied from the cosys app.
+// Requests have the method, path, query, headers, body, remote address and path values of the original request.
+type Route struct {
+	Method  string
+	Path    string // Path is the pattern of the route, e.g. /hello/{name}.
+	Handler http.HandlerFunc
+}
+
+// Hook is a hook of a plugin, for a lifecycle event of a model, e.g. afterCreate of api.posts,
+// or for the bootstrap or cleanup events of the cosys app if the model is empty.
+// Errors returned by hooks of before events abort the query,
+// and typed errors, e.g. common.NewValidationError, are returned as such by the cosys app.
+type Hook struct {
+	Model   string
+	Event   string
+	Handler func(ctx context.Context, event HookEvent) error
+}
+
+// HookEvent is a call of a hook.
+type HookEvent struct {
+	Model  string
+	Event  string
+	Result json.RawMessage // Result is the json result of the query of lifecycle events, if any.
+}
+
+// Command is a cli command of a plugin, whose runs are proxied from the cosys app.
+// Flags are not parsed by the app, and are passed as arguments.
+type Command struct {
+	Name  string
+	Short string
+	Run   func(ctx context.Context, args []string, out io.Writer) error
+}
+
+// Events of the hooks of the cosys app.
+const (
+	BootstrapEvent = "bootstrap"
+	CleanupEvent   = "cleanup"
+)
+
+// Serve serves the given module to the plugin host that launched the process, and returns once the host kills it.
+// The process exits with an error if it is not launched by a plugin host,
+// and exits once the host exits, e.g. after running a command or crashing, without killing it.
+func Serve(module Module) {
+	go exitWithHost()
+
+	plugin.Serve(&plugin.ServeConfig{
+		HandshakeConfig: protocol.Handshake,
+		Plugins: plugin.PluginSet{
+			protocol.PluginName: &protocol.Plugin{Impl: newServer(module)},
+		},
+		GRPCServer: plugin.DefaultGRPCServer,
+		Logger: hclog.New(&hclog.LoggerOptions{
+			Output:     os.Stderr,
+			Level:      hclog.Warn,
+			JSONFormat: true,
+		}),
+	})
+}
+
+// hostCheckInterval is the interval at which plugins check whether the host exited.
+const hostCheckInterval = time.Second
+
+// exitWithHost exits the process once the host that launched it exits, at which point the process is reparented.
+func exitWithHost() {
+	host := os.Getppid()
+
+	ticker := time.NewTicker(hostCheckInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if os.Getppid() != host {
+			os.Exit(0)
+		}
+	}
+}
+
+// server is the implementation of the plugin service serving a module.
+type server struct {
+	module   Module
+	routes   map[string]Route
+	hooks    map[string]Hook
+	commands map[string]Command
+}
+
+// newServer returns the server of the given module.
+func newServer(module Module) *server {
+	s := &server{
+		module:   module,
+		routes:   map[string]Route{},
+		hooks:    map[string]Hook{},
+		commands: map[string]Command{},
+	}
+
+	for _, route := range module.Routes {
+		s.routes[route.Method+" "+route.Path] = route
+	}
+
+	for _, hook := range module.Hooks {
+		s.hooks[hook.Model+" "+hook.Event] = hook
+	}
+
+	for _, command := range module.Commands {
+		s.commands[command.Name] = command
+	}
+
+	return s
+}
+
+// Register returns the routes, hooks and commands of the module.
+func (s *server) Register(context.Context) (protocol.Registration, error) {
+	registration := protocol.Registration{
+		Name: s.module.Name,
+	}
+
+	for _, route := range s.module.Routes {
+		registration.Routes = append(registration.Routes, protocol.RouteInfo{Method: route.Method, Path: route.Path})
+	}
+
+	for _, hook := range s.module.Hooks {
+		registration.Hooks = append(registration.Hooks, protocol.HookInfo{Model: hook.Model, Event: hook.Event})
+	}
+
+	for _, command := range s.module.Commands {
+		registration.Commands = append(registration.Commands, protocol.CommandInfo{Name: command.Name, Short: command.Short})
+	}
+
+	return registration, nil
+}
+
+// HandleRoute calls the handler of the route of the given request, and returns its response.
+func (s *server) HandleRoute(ctx context.Context, request protocol.RouteRequest) (protocol.RouteResponse, error) {
+	route, ok := s.routes[request.Method+" "+request.Pattern]
+	if !ok {
+		return protocol.RouteResponse{}, fmt.Errorf("route not found: %s %s", request.Method, request.Pattern)
+	}
+
+	r, err := http.NewRequestWithContext(ctx, request.Method, (&url.URL{Path: request.Path, RawQuery: request.Query}).String(), bytes.NewReader(request.Body))
+	if err != nil {
+		return protocol.RouteResponse{}, err
+	}
+
+	if request.Header != nil {
+		r.Header = request.Header
+	}
+	r.RemoteAddr = request.RemoteAddr
+	r.Pattern = request.Pattern
+
+	for name, value := range request.Params {
+		r.SetPathValue(name, value)
+	}
+
+	buffer := response.NewBuffer(nil)
+
+	route.Handler(buffer, r)
+
+	status := buffer.Status()
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	return protocol.RouteResponse{
+		Status: status,
+		Header: buffer.Header(),
+		Body:   buffer.Body(),
+	}, nil
+}
+
+// CallHook calls the handler of the hook of the given request.
+func (s *server) CallHook(ctx context.Context, request protocol.HookRequest) error {
+	hook, ok := s.hooks[request.Model+" "+request.Event]
+	if !ok {
+		return fmt.Errorf("hook not found: %s %s", request.Model, request.Event)
+	}
+
+	return hook.Handler(ctx, HookEvent{
+		Model:  request.Model,
+		Event:  request.Event,
+		Result: request.Result,
+	})
+}
+
+// RunCommand runs the command of the given request, and returns its output.
+// Errors are written to the output, and exit with code 1.
+func (s *server) RunCommand(ctx context.Context, request protocol.CommandRequest) (protocol.CommandResponse, error) {
+	command, ok := s.commands[request.Name]
+	if !ok {
+		return protocol.CommandResponse{}, fmt.Errorf("command not found: %s", request.Name)
+	}
+
+	var output bytes.Buffer
+	if err := command.Run(ctx, request.Args, &output); err != nil {
+		_, _ = fmt.Fprintln(&output, err)
+
+		return protocol.CommandResponse{
+			Output:   output.String(),
+			ExitCode: 1,
+		}, nil
+	}
+
+	return protocol.CommandResponse{
+		Output: output.String(),
+	}, nil
+}
@@ -0,0 +1,45 @@
+package ratelimit
+
+import (
+	"github.com/cosys-io/cosys/common"
+	"github.com/spf13/viper"
+)
+
+// Keys of the project configurations of the rate limiting module.
+const (
+	AlgorithmKey    = "ratelimit_algorithm"
+	LimitKey        = "ratelimit_limit"
+	WindowKey       = "ratelimit_window"
+	KeysKey         = "ratelimit_keys"
+	APIKeyHeaderKey = "ratelimit_api_key_header"
+	TrustProxyKey   = "ratelimit_trust_proxy"
+)
+
+// withProjectConfigs returns the given configuration,
+// overridden by the configurations set in the project configurations.
+func withProjectConfigs(config Config) (Config, error) {
+	if err := common.ReadConfigs(); err != nil {
+		return Config{}, err
+	}
+
+	if viper.IsSet(AlgorithmKey) {
+		config.Algorithm = viper.GetString(AlgorithmKey)
+	}
+	if viper.IsSet(LimitKey) {
+		config.Limit = viper.GetInt64(LimitKey)
+	}
+	if viper.IsSet(WindowKey) {
+		config.Window = viper.GetDuration(WindowKey)
+	}
+	if viper.IsSet(KeysKey) {
+		config.Keys = viper.GetStringSlice(KeysKey)
+	}
+	if viper.IsSet(APIKeyHeaderKey) {
+		config.APIKeyHeader = viper.GetString(APIKeyHeaderKey)
+	}
+	if viper.IsSet(TrustProxyKey) {
+		config.TrustProxy = viper.GetBool(TrustProxyKey)
+	}
+
+	return config, nil
+}
@@ -0,0 +1,152 @@
+package internal
+
+import (
+	"math"
+	"time"
+)
+
+// State is the rate limiting state of a key, stored between requests.
+type State struct {
+	Tokens    float64   // Tokens are the remaining tokens of a token bucket.
+	Count     int64     // Count is the number of requests in the current window of a sliding window.
+	PrevCount int64     // PrevCount is the number of requests in the previous window of a sliding window.
+	Time      time.Time // Time is the last refill of a token bucket, or the start of the current window of a sliding window.
+}
+
+// Result is the rate limiting decision for a request.
+type Result struct {
+	Allowed    bool          // Allowed specifies whether the request is allowed.
+	Limit      int64         // Limit is the maximum number of requests in a window.
+	Remaining  int64         // Remaining is the number of requests remaining in the window.
+	Reset      time.Duration // Reset is the duration until the quota is fully restored.
+	RetryAfter time.Duration // RetryAfter is the duration until the next request is allowed, if not allowed.
+}
+
+// Algorithm is a rate limiting algorithm.
+type Algorithm interface {
+	// Take returns the updated state and the decision for a request at the given time,
+	// given the current state of the key, and whether the key has a state.
+	Take(state State, found bool, now time.Time) (State, Result)
+	// TTL returns the duration after which an unused state can be discarded.
+	TTL() time.Duration
+	// Policy returns the quota policy of the algorithm, as the value of the RateLimit-Policy header.
+	Policy() string
+}
+
+// TokenBucket is a rate limiting algorithm allowing bursts of up to limit requests,
+// with tokens refilled continuously at a rate of limit tokens per window.
+type TokenBucket struct {
+	Limit  int64
+	Window time.Duration
+}
+
+// Take takes a token from the bucket, refilling it first.
+func (t TokenBucket) Take(state State, found bool, now time.Time) (State, Result) {
+	capacity := float64(t.Limit)
+	rate := capacity / t.Window.Seconds()
+
+	if !found {
+		state = State{
+			Tokens: capacity,
+			Time:   now,
+		}
+	}
+
+	elapsed := now.Sub(state.Time).Seconds()
+	if elapsed > 0 {
+		state.Tokens = math.Min(capacity, state.Tokens+elapsed*rate)
+		state.Time = now
+	}
+
+	result := Result{
+		Limit: t.Limit,
+	}
+
+	if state.Tokens >= 1 {
+		state.Tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - state.Tokens) / rate)
+	}
+
+	result.Remaining = int64(state.Tokens)
+	result.Reset = seconds((capacity - state.Tokens) / rate)
+
+	return state, result
+}
+
+// TTL returns the window, after which the bucket is full again.
+func (t TokenBucket) TTL() time.Duration {
+	return t.Window
+}
+
+// Policy returns the quota policy of the token bucket.
+func (t TokenBucket) Policy() string {
+	return policy(t.Limit, t.Window)
+}
+
+// SlidingWindow is a rate limiting algorithm allowing up to limit requests in any window,
+// approximated by weighting the count of the previous fixed window by its overlap with the sliding window.
+type SlidingWindow struct {
+	Limit  int64
+	Window time.Duration
+}
+
+// Take counts the request in the current window, if the weighted count is below the limit.
+func (s SlidingWindow) Take(state State, found bool, now time.Time) (State, Result) {
+	windowStart := now.Truncate(s.Window)
+
+	switch {
+	case !found || windowStart.Sub(state.Time) >= 2*s.Window:
+		state = State{Time: windowStart}
+	case windowStart.Sub(state.Time) >= s.Window:
+		state = State{PrevCount: state.Count, Time: windowStart}
+	}
+
+	elapsed := now.Sub(windowStart)
+	prevWeight := 1 - float64(elapsed)/float64(s.Window)
+	count := float64(state.PrevCount)*prevWeight + float64(state.Count)
+
+	result := Result{
+		Limit: s.Limit,
+		Reset: s.Window - elapsed,
+	}
+
+	if count+1 <= float64(s.Limit) {
+		state.Count++
+		count++
+		result.Allowed = true
+	} else if state.PrevCount > 0 {
+		// The weighted count decreases as the previous window slides out,
+		// or resets at the end of the current window if that is sooner.
+		excess := count + 1 - float64(s.Limit)
+		wait := time.Duration(excess / float64(state.PrevCount) * float64(s.Window))
+		result.RetryAfter = min(wait, s.Window-elapsed)
+	} else {
+		result.RetryAfter = s.Window - elapsed
+	}
+
+	result.Remaining = max(0, s.Limit-int64(math.Ceil(count)))
+
+	return state, result
+}
+
+// TTL returns twice the window, after which the previous window no longer counts.
+func (s SlidingWindow) TTL() time.Duration {
+	return 2 * s.Window
+}
+
+// Policy returns the quota policy of the sliding window.
+func (s SlidingWindow) Policy() string {
+	return policy(s.Limit, s.Window)
+}
+
+// policy returns the value of the RateLimit-Policy header for the given limit and window.
+func policy(limit int64, window time.Duration) string {
+	return itoa(limit) + ";w=" + itoa(int64(math.Ceil(window.Seconds())))
+}
+
+// seconds returns the given number of seconds as a duration.
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,135 @@
+package internal
+
+import (
+	"github.com/cosys-io/cosys/common"
+	"github.com/cosys-io/cosys/modules/server/response"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// KeyFunc returns the key that requests are limited by, and whether the request is limited by it.
+type KeyFunc func(r *http.Request) (string, bool)
+
+// ByIP returns the KeyFunc limiting requests by the ip address of the client.
+// If trustProxy is set, the last address of the X-Forwarded-For header is used when present,
+// which is the address the proxy in front of the app received the request from,
+// as the addresses before it are set by the client and can be spoofed.
+func ByIP(trustProxy bool) KeyFunc {
+	return func(r *http.Request) (string, bool) {
+		if trustProxy {
+			if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+				last := forwarded[len(forwarded)-1]
+				if i := strings.LastIndex(last, ","); i >= 0 {
+					last = last[i+1:]
+				}
+
+				if ip := strings.TrimSpace(last); ip != "" {
+					return "ip:" + ip, true
+				}
+			}
+		}
+
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			host = r.RemoteAddr
+		}
+
+		return "ip:" + host, true
+	}
+}
+
+// ByHeader returns the KeyFunc limiting requests by the value of the given header, e.g. an api key.
+// Requests without the header are not limited by it.
+func ByHeader(name string) KeyFunc {
+	return func(r *http.Request) (string, bool) {
+		value := r.Header.Get(name)
+		return "header:" + name + ":" + value, value != ""
+	}
+}
+
+// ByUser returns the KeyFunc limiting requests by the authenticated user returned by the given function.
+// Requests without an authenticated user are not limited by it.
+func ByUser(user func(r *http.Request) (string, bool)) KeyFunc {
+	return func(r *http.Request) (string, bool) {
+		userId, ok := user(r)
+		return "user:" + userId, ok && userId != ""
+	}
+}
+
+// FirstOf returns the KeyFunc using the first of the given KeyFuncs that limits the request,
+// e.g. to limit authenticated users by user, and others by ip address.
+func FirstOf(keyFuncs ...KeyFunc) KeyFunc {
+	return func(r *http.Request) (string, bool) {
+		for _, keyFunc := range keyFuncs {
+			if key, ok := keyFunc(r); ok {
+				return key, true
+			}
+		}
+
+		return "", false
+	}
+}
+
+// Limiter limits the rate of requests by key.
+type Limiter struct {
+	Name      string // Name namespaces the keys of the limiter in the store.
+	Algorithm Algorithm
+	Key       KeyFunc
+	Store     Store
+}
+
+// Middleware returns the MiddlewareFunc limiting the rate of requests.
+// All responses of limited requests carry the RateLimit-Policy and RateLimit headers,
+// and requests over the limit are rejected with 429 Too Many Requests and a Retry-After header.
+// If the store fails, requests are allowed.
+func (l Limiter) Middleware(cosys *common.Cosys) (func(http.HandlerFunc) http.HandlerFunc, error) {
+	policy := l.Algorithm.Policy()
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			key, ok := l.Key(r)
+			if !ok {
+				next(w, r)
+				return
+			}
+
+			var result Result
+			if err := l.Store.Update(r.Context(), l.Name+":"+key, l.Algorithm.TTL(), func(state State, found bool) State {
+				var newState State
+				newState, result = l.Algorithm.Take(state, found, time.Now())
+				return newState
+			}); err != nil {
+				next(w, r)
+				return
+			}
+
+			header := w.Header()
+			header.Set("RateLimit-Policy", policy)
+			header.Set("RateLimit-Limit", itoa(result.Limit))
+			header.Set("RateLimit-Remaining", itoa(result.Remaining))
+			header.Set("RateLimit-Reset", itoa(ceilSeconds(result.Reset)))
+
+			if !result.Allowed {
+				header.Set("Retry-After", itoa(max(1, ceilSeconds(result.RetryAfter))))
+				response.RespondError(w, "Too many requests", http.StatusTooManyRequests)
+				return
+			}
+
+			next(w, r)
+		}
+	}, nil
+}
+
+// ceilSeconds returns the duration in seconds, rounded up.
+func ceilSeconds(d time.Duration) int64 {
+	return int64(math.Ceil(d.Seconds()))
+}
+
+// itoa returns the decimal representation of the given integer.
+func itoa(i int64) string {
+	return strconv.FormatInt(i, 10)
+}
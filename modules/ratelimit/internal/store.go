@@ -0,0 +1,70 @@
+package internal
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Store stores the rate limiting state of keys, e.g. in memory or in a backend shared by multiple instances.
+type Store interface {
+	// Update atomically replaces the state of the key with the state returned by update,
+	// which is called with the current state and whether the key has a state.
+	// The new state expires after the given ttl.
+	Update(ctx context.Context, key string, ttl time.Duration, update func(state State, found bool) State) error
+}
+
+// sweepInterval is the minimum duration between removals of expired states from a MemoryStore.
+const sweepInterval = time.Minute
+
+// MemoryStore is a Store keeping states in memory, local to the process.
+type MemoryStore struct {
+	mutex     sync.Mutex
+	entries   map[string]memoryEntry
+	lastSweep time.Time
+}
+
+// memoryEntry is a state stored in a MemoryStore.
+type memoryEntry struct {
+	state   State
+	expires time.Time
+}
+
+// NewMemoryStore returns a new empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries:   map[string]memoryEntry{},
+		lastSweep: time.Now(),
+	}
+}
+
+// Update atomically replaces the state of the key with the state returned by update.
+// Expired states are removed periodically.
+// Safe for concurrent use.
+func (m *MemoryStore) Update(ctx context.Context, key string, ttl time.Duration, update func(state State, found bool) State) error {
+	now := time.Now()
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if now.Sub(m.lastSweep) >= sweepInterval {
+		for entryKey, entry := range m.entries {
+			if now.After(entry.expires) {
+				delete(m.entries, entryKey)
+			}
+		}
+		m.lastSweep = now
+	}
+
+	entry, found := m.entries[key]
+	if found && now.After(entry.expires) {
+		found = false
+	}
+
+	m.entries[key] = memoryEntry{
+		state:   update(entry.state, found),
+		expires: now.Add(ttl),
+	}
+
+	return nil
+}
@@ -0,0 +1,254 @@
+package ratelimit
+
+import (
+	"fmt"
+	"github.com/cosys-io/cosys/common"
+	"github.com/cosys-io/cosys/modules/ratelimit/internal"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ModuleName is the name the module is registered with, which other modules can depend on.
+const ModuleName = "ratelimit"
+
+// RateLimitUid is the uid of the rate limiting middleware configured with the module configuration.
+const RateLimitUid = "rateLimit"
+
+// Names of the built-in rate limiting algorithms.
+const (
+	TokenBucketAlgorithm   = "token_bucket"
+	SlidingWindowAlgorithm = "sliding_window"
+)
+
+// Names of the built-in key functions.
+const (
+	IPKey     = "ip"
+	APIKeyKey = "api_key"
+	UserKey   = "user"
+)
+
+type (
+	State     = internal.State     // State is the rate limiting state of a key, stored between requests.
+	Result    = internal.Result    // Result is the rate limiting decision for a request.
+	Algorithm = internal.Algorithm // Algorithm is a rate limiting algorithm.
+	Store     = internal.Store     // Store stores the rate limiting state of keys.
+	KeyFunc   = internal.KeyFunc   // KeyFunc returns the key that requests are limited by, and whether the request is limited by it.
+)
+
+// Options are the options of a rate limiting middleware.
+type Options struct {
+	// Name namespaces the keys of the middleware, so that middlewares sharing a store are limited separately.
+	Name string
+	// Algorithm is the rate limiting algorithm, by default a sliding window of 100 requests per minute.
+	Algorithm Algorithm
+	// Key is the key function requests are limited by, by default the ip address of the client.
+	Key KeyFunc
+	// Store is the store of rate limiting states, by default a new in-memory store.
+	Store Store
+}
+
+// Config is the configuration of the rate limiting middleware registered by the module.
+// Configurations set in the project configurations override those set with Configure.
+type Config struct {
+	Algorithm    string        // Algorithm is the name of the rate limiting algorithm.
+	Limit        int64         // Limit is the maximum number of requests in a window.
+	Window       time.Duration // Window is the duration of a window.
+	Keys         []string      // Keys are the names of the key functions, the first of which that applies to a request is used.
+	APIKeyHeader string        // APIKeyHeader is the header of the api key, for the api_key key function.
+	TrustProxy   bool          // TrustProxy specifies whether the ip address is read from the last address of the X-Forwarded-For header, for the ip key function.
+	// User returns the authenticated user of a request, and whether there is one, for the user key function.
+	User func(r *http.Request) (string, bool)
+	// Store is the store of rate limiting states, by default an in-memory store.
+	Store Store
+}
+
+// DefaultConfig returns the default configuration of the rate limiting middleware,
+// which allows 100 requests per minute per ip address, with a sliding window.
+func DefaultConfig() Config {
+	return Config{
+		Algorithm:    SlidingWindowAlgorithm,
+		Limit:        100,
+		Window:       time.Minute,
+		Keys:         []string{IPKey},
+		APIKeyHeader: "X-API-Key",
+	}
+}
+
+var (
+	configMutex sync.RWMutex
+	config      = DefaultConfig()
+)
+
+// Configure sets the configuration of the rate limiting middleware registered by the module.
+// Must be called before the cosys app is created.
+// Safe for concurrent use.
+func Configure(newConfig Config) {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	config = newConfig
+}
+
+// TokenBucket returns the token bucket algorithm, allowing bursts of up to limit requests,
+// with tokens refilled continuously at a rate of limit tokens per window.
+func TokenBucket(limit int64, window time.Duration) Algorithm {
+	return internal.TokenBucket{
+		Limit:  limit,
+		Window: window,
+	}
+}
+
+// SlidingWindow returns the sliding window algorithm, allowing up to limit requests in any window.
+func SlidingWindow(limit int64, window time.Duration) Algorithm {
+	return internal.SlidingWindow{
+		Limit:  limit,
+		Window: window,
+	}
+}
+
+// NewAlgorithm returns the built-in algorithm with the given name.
+// Throws an error if the name is not a built-in algorithm, or the limit or window is not positive.
+func NewAlgorithm(name string, limit int64, window time.Duration) (Algorithm, error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("invalid rate limit: %d", limit)
+	}
+
+	if window <= 0 {
+		return nil, fmt.Errorf("invalid rate limit window: %s", window)
+	}
+
+	switch name {
+	case TokenBucketAlgorithm:
+		return TokenBucket(limit, window), nil
+	case SlidingWindowAlgorithm:
+		return SlidingWindow(limit, window), nil
+	default:
+		return nil, fmt.Errorf("rate limiting algorithm not found: %s", name)
+	}
+}
+
+// NewMemoryStore returns a new empty store keeping states in memory, local to the process.
+func NewMemoryStore() Store {
+	return internal.NewMemoryStore()
+}
+
+// ByIP returns the KeyFunc limiting requests by the ip address of the client.
+// If trustProxy is set, the last address of the X-Forwarded-For header is used when present,
+// which is the address the proxy in front of the app received the request from,
+// as the addresses before it are set by the client and can be spoofed.
+func ByIP(trustProxy bool) KeyFunc {
+	return internal.ByIP(trustProxy)
+}
+
+// ByAPIKey returns the KeyFunc limiting requests by the api key in the given header.
+// Requests without an api key are not limited by it.
+func ByAPIKey(header string) KeyFunc {
+	return internal.ByHeader(header)
+}
+
+// ByUser returns the KeyFunc limiting requests by the authenticated user returned by the given function.
+// Requests without an authenticated user are not limited by it.
+func ByUser(user func(r *http.Request) (string, bool)) KeyFunc {
+	return internal.ByUser(user)
+}
+
+// FirstOf returns the KeyFunc using the first of the given KeyFuncs that limits the request,
+// e.g. to limit authenticated users by user, and others by ip address.
+func FirstOf(keyFuncs ...KeyFunc) KeyFunc {
+	return internal.FirstOf(keyFuncs...)
+}
+
+// Middleware returns a rate limiting middleware with the given options,
+// which can be applied to routes with common.UseMiddlewares, or to route groups with cosys.Group.
+// Responses carry the RateLimit-Policy, RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers,
+// and requests over the limit are rejected with 429 Too Many Requests and a Retry-After header.
+func Middleware(options Options) common.MiddlewareFunc {
+	limiter := internal.Limiter{
+		Name:      options.Name,
+		Algorithm: options.Algorithm,
+		Key:       options.Key,
+		Store:     options.Store,
+	}
+
+	if limiter.Name == "" {
+		limiter.Name = RateLimitUid
+	}
+
+	if limiter.Algorithm == nil {
+		limiter.Algorithm = SlidingWindow(100, time.Minute)
+	}
+
+	if limiter.Key == nil {
+		limiter.Key = ByIP(false)
+	}
+
+	if limiter.Store == nil {
+		limiter.Store = NewMemoryStore()
+	}
+
+	return limiter.Middleware
+}
+
+// init registers the module to register the rate limiting middleware configured with the module configuration.
+func init() {
+	_ = common.RegisterNamedModule(ModuleName, func(cosys *common.Cosys) error {
+		configMutex.RLock()
+		moduleConfig := config
+		configMutex.RUnlock()
+
+		moduleConfig, err := withProjectConfigs(moduleConfig)
+		if err != nil {
+			return err
+		}
+
+		options, err := moduleConfig.options()
+		if err != nil {
+			return err
+		}
+
+		middleware, err := common.NewMiddleware(RateLimitUid, Middleware(options))
+		if err != nil {
+			return err
+		}
+
+		return cosys.AddMiddlewares(middleware)
+	})
+}
+
+// options returns the options of the rate limiting middleware with the configuration.
+// Throws an error if the algorithm or a key function is not found.
+func (c Config) options() (Options, error) {
+	algorithm, err := NewAlgorithm(c.Algorithm, c.Limit, c.Window)
+	if err != nil {
+		return Options{}, err
+	}
+
+	keyFuncs := make([]KeyFunc, 0, len(c.Keys))
+	for _, key := range c.Keys {
+		switch key {
+		case IPKey:
+			keyFuncs = append(keyFuncs, ByIP(c.TrustProxy))
+		case APIKeyKey:
+			keyFuncs = append(keyFuncs, ByAPIKey(c.APIKeyHeader))
+		case UserKey:
+			if c.User == nil {
+				return Options{}, fmt.Errorf("user key function not configured")
+			}
+			keyFuncs = append(keyFuncs, ByUser(c.User))
+		default:
+			return Options{}, fmt.Errorf("key function not found: %s", key)
+		}
+	}
+
+	if len(keyFuncs) == 0 {
+		return Options{}, fmt.Errorf("key function not configured")
+	}
+
+	return Options{
+		Name:      RateLimitUid,
+		Algorithm: algorithm,
+		Key:       FirstOf(keyFuncs...),
+		Store:     c.Store,
+	}, nil
+}
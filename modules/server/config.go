@@ -0,0 +1,60 @@
+package server
+
+import (
+	"github.com/cosys-io/cosys/common"
+	"github.com/spf13/viper"
+)
+
+// Keys of the project configurations of the server module.
+const (
+	PortKey         = "server_port"
+	HTTP2Key        = "server_http2"
+	RedirectPortKey = "server_redirect_port"
+
+	TLSCertFileKey     = "server_tls_cert_file"
+	TLSKeyFileKey      = "server_tls_key_file"
+	TLSMinVersionKey   = "server_tls_min_version"
+	TLSClientCAFileKey = "server_tls_client_ca_file"
+	TLSClientAuthKey   = "server_tls_client_auth"
+
+	OpenAPIPathKey     = "server_openapi_path"
+	OpenAPITitleKey    = "server_openapi_title"
+	OpenAPIVersionKey  = "server_openapi_version"
+	SwaggerUIPathKey   = "server_swagger_ui_path"
+	SwaggerUIAssetsKey = "server_swagger_ui_assets"
+)
+
+// withProjectConfigs returns the given configuration,
+// overridden by the configurations set in the project configurations.
+func withProjectConfigs(config Config) (Config, error) {
+	if err := common.ReadConfigs(); err != nil {
+		return Config{}, err
+	}
+
+	setString(PortKey, &config.Port)
+	if viper.IsSet(HTTP2Key) {
+		config.HTTP2 = viper.GetBool(HTTP2Key)
+	}
+	setString(RedirectPortKey, &config.RedirectPort)
+
+	setString(TLSCertFileKey, &config.TLS.CertFile)
+	setString(TLSKeyFileKey, &config.TLS.KeyFile)
+	setString(TLSMinVersionKey, &config.TLS.MinVersion)
+	setString(TLSClientCAFileKey, &config.TLS.ClientCAFile)
+	setString(TLSClientAuthKey, &config.TLS.ClientAuth)
+
+	setString(OpenAPIPathKey, &config.OpenAPI.Path)
+	setString(OpenAPITitleKey, &config.OpenAPI.Title)
+	setString(OpenAPIVersionKey, &config.OpenAPI.Version)
+	setString(SwaggerUIPathKey, &config.OpenAPI.SwaggerUIPath)
+	setString(SwaggerUIAssetsKey, &config.OpenAPI.SwaggerUIAssets)
+
+	return config, nil
+}
+
+// setString sets the value to the project configuration with the given key, if it is set.
+func setString(key string, value *string) {
+	if viper.IsSet(key) {
+		*value = viper.GetString(key)
+	}
+}
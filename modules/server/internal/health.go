@@ -0,0 +1,162 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"github.com/cosys-io/cosys/common"
+	"github.com/cosys-io/cosys/modules/server/response"
+	"net/http"
+	"time"
+)
+
+// healthCheckTimeout is the maximum duration given to all health checks of a report.
+const healthCheckTimeout = 5 * time.Second
+
+// HealthStatus is the status of a health check or health report.
+type HealthStatus string
+
+const (
+	Healthy   HealthStatus = "ok"
+	Unhealthy HealthStatus = "error"
+)
+
+// HealthReport is the aggregated result of all health checks.
+type HealthReport struct {
+	Status HealthStatus                 `json:"status"`
+	Ready  *bool                        `json:"ready,omitempty"`
+	Checks map[string]HealthCheckResult `json:"checks"`
+}
+
+// HealthCheckResult is the result of a health check.
+type HealthCheckResult struct {
+	Status  HealthStatus `json:"status"`
+	Latency string       `json:"latency"`
+	Error   string       `json:"error,omitempty"`
+}
+
+// HealthRoutes returns the routes for the liveness, health and readiness probes.
+func HealthRoutes() []common.Route {
+	return []common.Route{
+		common.NewRoute("GET", "/livez", livez, common.Describe(healthDoc("Liveness probe"))),
+		common.NewRoute("GET", "/healthz", healthz, common.Describe(healthDoc("Health probe"))),
+		common.NewRoute("GET", "/readyz", readyz, common.Describe(healthDoc("Readiness probe"))),
+	}
+}
+
+// healthDoc returns the documentation of a health route with the given summary.
+func healthDoc(summary string) common.RouteDoc {
+	return common.RouteDoc{
+		Summary:  summary,
+		Tags:     []string{"health"},
+		Response: common.TypeOf(HealthReport{}),
+	}
+}
+
+// livez is the ActionFunc for the liveness probe, which responds as long as the server is running.
+func livez(cosys *common.Cosys) (http.HandlerFunc, error) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		response.RespondOne(w, HealthReport{
+			Status: Healthy,
+			Checks: map[string]HealthCheckResult{},
+		}, http.StatusOK)
+	}, nil
+}
+
+// healthz is the ActionFunc for the health probe, which reports the results of all health checks.
+func healthz(cosys *common.Cosys) (http.HandlerFunc, error) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report := checkHealth(r.Context(), cosys)
+
+		code := http.StatusOK
+		if report.Status != Healthy {
+			code = http.StatusServiceUnavailable
+		}
+
+		response.RespondOne(w, report, code)
+	}, nil
+}
+
+// readyz is the ActionFunc for the readiness probe, which reports the results of all health checks,
+// and whether the cosys app is ready to serve requests.
+func readyz(cosys *common.Cosys) (http.HandlerFunc, error) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report := checkHealth(r.Context(), cosys)
+
+		ready := cosys.Ready()
+		report.Ready = &ready
+		if !ready {
+			report.Status = Unhealthy
+		}
+
+		code := http.StatusOK
+		if report.Status != Healthy {
+			code = http.StatusServiceUnavailable
+		}
+
+		response.RespondOne(w, report, code)
+	}, nil
+}
+
+// checkHealth runs all health checks concurrently and returns the aggregated report.
+// Checks that do not return before the health check timeout are reported as unhealthy, without waiting for them.
+func checkHealth(ctx context.Context, cosys *common.Cosys) HealthReport {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	report := HealthReport{
+		Status: Healthy,
+		Checks: map[string]HealthCheckResult{},
+	}
+
+	checks := cosys.HealthChecks()
+	results := make(chan namedResult, len(checks))
+
+	start := time.Now()
+	for name, check := range checks {
+		go func() {
+			checkStart := time.Now()
+			err := check(ctx)
+			result := HealthCheckResult{
+				Status:  Healthy,
+				Latency: time.Since(checkStart).String(),
+			}
+			if err != nil {
+				result.Status = Unhealthy
+				result.Error = err.Error()
+			}
+
+			results <- namedResult{name, result}
+		}()
+	}
+
+	for len(report.Checks) < len(checks) {
+		select {
+		case result := <-results:
+			report.Checks[result.name] = result.result
+		case <-ctx.Done():
+			for name := range checks {
+				if _, ok := report.Checks[name]; !ok {
+					report.Checks[name] = HealthCheckResult{
+						Status:  Unhealthy,
+						Latency: time.Since(start).String(),
+						Error:   fmt.Sprintf("health check did not return: %s", ctx.Err()),
+					}
+				}
+			}
+		}
+	}
+
+	for _, result := range report.Checks {
+		if result.Status != Healthy {
+			report.Status = Unhealthy
+		}
+	}
+
+	return report
+}
+
+// namedResult is the result of the health check with the given name.
+type namedResult struct {
+	name   string
+	result HealthCheckResult
+}
@@ -0,0 +1,541 @@
+package internal
+
+import (
+	"encoding/json"
+	"github.com/cosys-io/cosys/common"
+	"github.com/cosys-io/cosys/modules/server/response"
+	"github.com/iancoleman/strcase"
+	"math"
+	"net/http"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OpenAPIVersion is the version of the OpenAPI specification of the generated documents.
+const OpenAPIVersion = "3.1.0"
+
+// Document is an OpenAPI document.
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+// Info is the metadata of the api of an OpenAPI document.
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// PathItem is the operations on a path, by lowercase method.
+type PathItem map[string]*Operation
+
+// Operation is an operation on a path.
+type Operation struct {
+	OperationId string               `json:"operationId"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Parameters  []Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+// Parameter is a path or query parameter of an operation.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody is the request body of an operation.
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response is a response of an operation.
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType is the schema of a request or response body of a media type.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components are the reusable schemas of an OpenAPI document.
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// Schema is a json schema.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 any                `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Default              any                `json:"default,omitempty"`
+	Minimum              *int64             `json:"minimum,omitempty"`
+	Maximum              *int64             `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	ReadOnly             bool               `json:"readOnly,omitempty"`
+}
+
+// Names of the component schemas of the response envelope.
+const (
+	metaSchemaName  = "Meta"
+	errorSchemaName = "Error"
+)
+
+// wildcardPattern matches the wildcards of the paths of routes, e.g. {id} and {path...}.
+var wildcardPattern = regexp.MustCompile(`\{([^}.$]+)(\.\.\.)?}`)
+
+// NewDocument returns the OpenAPI document of the routes and models of the cosys app.
+// Models with a schema are documented as component schemas, and routes as operations,
+// with the request and response types set with common.Describe.
+// Response data is documented within the envelope of the response package.
+func NewDocument(cosys *common.Cosys, title string, version string) *Document {
+	document := &Document{
+		OpenAPI: OpenAPIVersion,
+		Info: Info{
+			Title:   title,
+			Version: version,
+		},
+		Paths: map[string]*PathItem{},
+		Components: Components{
+			Schemas: envelopeSchemas(),
+		},
+	}
+
+	modelNames := map[string]string{}
+	for modelUid, model := range cosys.Models() {
+		modelSchema := model.Schema_()
+		if modelSchema == nil {
+			continue
+		}
+
+		name := strcase.ToCamel(modelSchema.SingularName())
+		modelNames[modelUid] = name
+		document.Components.Schemas[name] = schemaOfModel(modelSchema)
+	}
+
+	for _, route := range cosys.Routes() {
+		if route.Doc.Hidden {
+			continue
+		}
+
+		path := strings.TrimSuffix(wildcardPattern.ReplaceAllString(route.Path, "{$1}"), "{$}")
+
+		pathItem, ok := document.Paths[path]
+		if !ok {
+			pathItem = &PathItem{}
+			document.Paths[path] = pathItem
+		}
+
+		(*pathItem)[strings.ToLower(route.Method)] = newOperation(route, path, modelNames)
+	}
+
+	return document
+}
+
+// newOperation returns the operation documenting the given route.
+func newOperation(route common.Route, path string, modelNames map[string]string) *Operation {
+	operation := &Operation{
+		OperationId: operationId(route.Method, path),
+		Summary:     route.Doc.Summary,
+		Description: route.Doc.Description,
+		Tags:        route.Doc.Tags,
+		Responses:   map[string]*Response{},
+	}
+
+	for _, match := range wildcardPattern.FindAllStringSubmatch(path, -1) {
+		paramType := "string"
+		if match[1] == "id" {
+			paramType = "integer"
+		}
+
+		operation.Parameters = append(operation.Parameters, Parameter{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: paramType},
+		})
+	}
+
+	for _, param := range route.Doc.Query {
+		paramType := param.Type
+		if paramType == "" {
+			paramType = "string"
+		}
+
+		operation.Parameters = append(operation.Parameters, Parameter{
+			Name:        param.Name,
+			In:          "query",
+			Description: param.Description,
+			Required:    param.Required,
+			Schema:      &Schema{Type: paramType},
+		})
+	}
+
+	if route.Doc.Request != nil {
+		operation.RequestBody = &RequestBody{
+			Required: true,
+			Content: map[string]MediaType{
+				"application/json": {Schema: schemaOfType(route.Doc.Request, modelNames)},
+			},
+		}
+	}
+
+	var data *Schema
+	if route.Doc.Response != nil {
+		data = schemaOfType(route.Doc.Response, modelNames)
+	}
+
+	operation.Responses[strconv.Itoa(http.StatusOK)] = &Response{
+		Description: http.StatusText(http.StatusOK),
+		Content: map[string]MediaType{
+			"application/json": {Schema: envelopeSchema(data)},
+		},
+	}
+	operation.Responses["default"] = &Response{
+		Description: "Error",
+		Content: map[string]MediaType{
+			"application/json": {Schema: refSchema(errorSchemaName)},
+		},
+	}
+
+	return operation
+}
+
+// envelopeSchemas returns the component schemas of the response envelope.
+func envelopeSchemas() map[string]*Schema {
+	return map[string]*Schema{
+		metaSchemaName: {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"error":     {Type: "string"},
+				"errorCode": {Type: "string"},
+				"pagination": {
+					Type: "object",
+					Properties: map[string]*Schema{
+						"page":     {Type: "integer"},
+						"pageSize": {Type: "integer"},
+					},
+				},
+			},
+		},
+		errorSchemaName: {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"data": {Type: "null"},
+				"meta": refSchema(metaSchemaName),
+			},
+			Required: []string{"data", "meta"},
+		},
+	}
+}
+
+// envelopeSchema returns the schema of a response envelope with the given data, or any data if nil.
+func envelopeSchema(data *Schema) *Schema {
+	if data == nil {
+		data = &Schema{}
+	}
+
+	return &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"data": data,
+			"meta": refSchema(metaSchemaName),
+		},
+		Required: []string{"data", "meta"},
+	}
+}
+
+// refSchema returns the schema referencing the component schema with the given name.
+func refSchema(name string) *Schema {
+	return &Schema{
+		Ref: "#/components/schemas/" + name,
+	}
+}
+
+// schemaOfType returns the schema of the given request or response type.
+func schemaOfType(typeDoc *common.TypeDoc, modelNames map[string]string) *Schema {
+	var schema *Schema
+	if typeDoc.Model != "" {
+		if name, ok := modelNames[typeDoc.Model]; ok {
+			schema = refSchema(name)
+		} else {
+			schema = &Schema{Type: "object", Title: typeDoc.Model}
+		}
+	} else {
+		schema = schemaOfGoType(reflect.TypeOf(typeDoc.Value), map[reflect.Type]bool{})
+	}
+
+	if typeDoc.Many {
+		return &Schema{
+			Type:  "array",
+			Items: schema,
+		}
+	}
+
+	return schema
+}
+
+// schemaOfModel returns the schema of the entities of a model with the given schema.
+// Private attributes are left out, as they are not returned,
+// and attributes that are not editable are read-only.
+func schemaOfModel(modelSchema common.ModelSchema) *Schema {
+	schema := &Schema{
+		Type:       "object",
+		Properties: map[string]*Schema{},
+	}
+
+	if described, ok := modelSchema.(interface{ Description() string }); ok {
+		schema.Description = described.Description()
+	}
+
+	for _, attr := range modelSchema.Attributes() {
+		if attr.Private() {
+			continue
+		}
+
+		schema.Properties[attr.Name()] = schemaOfAttribute(attr)
+		if attr.Required() {
+			schema.Required = append(schema.Required, attr.Name())
+		}
+	}
+
+	return schema
+}
+
+// schemaOfAttribute returns the schema of an attribute with the given schema, with its constraints.
+func schemaOfAttribute(attr common.AttributeSchema) *Schema {
+	schema := &Schema{}
+
+	switch attr.DetailedDataType() {
+	case "Int":
+		schema.Type = "integer"
+	case "Float":
+		schema.Type = "number"
+	case "Boolean":
+		schema.Type = "boolean"
+	case "String":
+		schema.Type = "string"
+	case "Date":
+		schema.Type = "string"
+		schema.Format = "date"
+	case "DateTime", "Timestamp":
+		schema.Type = "string"
+		schema.Format = "date-time"
+	case "Component":
+		schema.Type = "object"
+		if component, ok := attr.(interface {
+			Component() string
+			Repeatable() bool
+		}); ok {
+			schema.Title = component.Component()
+			if component.Repeatable() {
+				schema = &Schema{Type: "array", Items: schema}
+			}
+		}
+	case "DynamicZone":
+		schema.Type = "array"
+		schema.Items = &Schema{Type: "object"}
+		if zone, ok := attr.(interface{ Components() []string }); ok {
+			schema.Description = "One of the components " + strings.Join(zone.Components(), ", ")
+		}
+	}
+
+	if schema.Type == "integer" || schema.Type == "number" {
+		if attr.Min() > math.MinInt32 {
+			minimum := attr.Min()
+			schema.Minimum = &minimum
+		}
+		if attr.Max() < math.MaxInt32 {
+			maximum := attr.Max()
+			schema.Maximum = &maximum
+		}
+	}
+
+	if schema.Type == "string" {
+		if attr.MinLength() >= 0 {
+			minLength := attr.MinLength()
+			schema.MinLength = &minLength
+		}
+		if attr.MaxLength() >= 0 {
+			maxLength := attr.MaxLength()
+			schema.MaxLength = &maxLength
+		}
+	}
+
+	for _, value := range attr.Enum() {
+		schema.Enum = append(schema.Enum, value)
+	}
+
+	if attr.Default() != "" {
+		schema.Default = defaultValue(schema.Type, attr.Default())
+	}
+
+	schema.ReadOnly = !attr.Editable()
+
+	if attr.Nullable() && schema.Type != nil {
+		schema.Type = []string{schema.Type.(string), "null"}
+	}
+
+	return schema
+}
+
+// defaultValue returns the default value of an attribute as a value of the given schema type.
+func defaultValue(schemaType any, value string) any {
+	switch schemaType {
+	case "integer":
+		if parsed, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return parsed
+		}
+	case "number":
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+			return parsed
+		}
+	case "boolean":
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	}
+
+	return value
+}
+
+// timeType is the type of time.Time, documented as a date-time string.
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaOfGoType returns the schema of the json encoding of the given go type.
+// Recursive types are documented as any value where they recur.
+func schemaOfGoType(t reflect.Type, visiting map[reflect.Type]bool) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+
+	if t.Kind() == reflect.Pointer {
+		schema := schemaOfGoType(t.Elem(), visiting)
+		if schemaType, ok := schema.Type.(string); ok {
+			schema.Type = []string{schemaType, "null"}
+		}
+		return schema
+	}
+
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: schemaOfGoType(t.Elem(), visiting)}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: schemaOfGoType(t.Elem(), visiting)}
+	case reflect.Struct:
+		if visiting[t] {
+			return &Schema{}
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		schema := &Schema{
+			Type:       "object",
+			Properties: map[string]*Schema{},
+		}
+		addStructFields(schema, t, visiting)
+		return schema
+	default:
+		return &Schema{}
+	}
+}
+
+// addStructFields adds the json fields of the given struct type to the given object schema,
+// including the fields of embedded structs. Fields without omitempty are required.
+func addStructFields(schema *Schema, t reflect.Type, visiting map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				addStructFields(schema, embedded, visiting)
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		schema.Properties[name] = schemaOfGoType(field.Type, visiting)
+		if !slices.Contains(strings.Split(options, ","), "omitempty") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+// operationId returns the operation id of the route with the given method and path, e.g. getPostsById.
+func operationId(method string, path string) string {
+	words := wildcardPattern.ReplaceAllString(path, "by $1")
+	words = strings.NewReplacer("/", " ", "-", " ", "_", " ", ".", " ").Replace(words)
+
+	return strcase.ToLowerCamel(strings.ToLower(method) + " " + words)
+}
+
+// OpenAPIRoute returns the route serving the OpenAPI document of the cosys app at the given path.
+// The document is generated on every request, so that it includes routes added at any time.
+func OpenAPIRoute(path string, title string, version string) common.Route {
+	return common.NewRoute(http.MethodGet, path, func(cosys *common.Cosys) (http.HandlerFunc, error) {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if err := json.NewEncoder(w).Encode(NewDocument(cosys, title, version)); err != nil {
+				response.RespondInternalError(w)
+			}
+		}, nil
+	}, common.Describe(common.RouteDoc{Hidden: true}))
+}
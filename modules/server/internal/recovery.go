@@ -0,0 +1,46 @@
+package internal
+
+import (
+	"fmt"
+	"github.com/cosys-io/cosys/common"
+	"github.com/cosys-io/cosys/modules/server/response"
+	"net/http"
+	"runtime/debug"
+)
+
+// recoverPanics wraps the handler of the given route to recover from panics in its middlewares, policies and action,
+// which are logged with their stack trace and responded to with an internal server error if the response has not started.
+// Panics with http.ErrAbortHandler are not recovered, as they abort the response on purpose.
+func recoverPanics(cosys *common.Cosys, route common.Route, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		recorder := response.NewRecorder(w)
+
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			err, ok := recovered.(error)
+			if !ok {
+				err = fmt.Errorf("%v", recovered)
+			}
+
+			cosys.LogError("panic recovered", err, common.F("route", route.String()), common.F("stack", string(debug.Stack())))
+
+			if span, ok := common.SpanFromContext(r.Context()); ok {
+				span.RecordError(err)
+			}
+
+			if !recorder.Started() {
+				response.RespondInternalError(recorder)
+			}
+		}()
+
+		next(recorder, r)
+	}
+}
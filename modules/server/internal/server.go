@@ -1,29 +1,75 @@
 package internal
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
 	"github.com/cosys-io/cosys/common"
 	"github.com/cosys-io/cosys/modules/server/response"
+	"net"
 	"net/http"
+	"sync"
 )
 
+// Config is the configuration of the server.
+type Config struct {
+	Port string // Port is the port the server listens on.
+	TLS  TLSConfig
+	// HTTP2 specifies whether HTTP/2 is negotiated with clients supporting it, which requires tls.
+	HTTP2 bool
+	// RedirectPort is the port of the listener redirecting HTTP requests to HTTPS, which requires tls.
+	// The redirect listener is disabled if empty.
+	RedirectPort string
+	OpenAPI      OpenAPIConfig
+}
+
+// OpenAPIConfig is the configuration of the OpenAPI document of the server.
+type OpenAPIConfig struct {
+	Path    string // Path is the path the document is served at. The document is not served if empty.
+	Title   string // Title is the title of the api in the document.
+	Version string // Version is the version of the api in the document.
+	// SwaggerUIPath is the path Swagger UI is served at. Swagger UI is not served if empty.
+	SwaggerUIPath string
+	// SwaggerUIAssets is the base url the Swagger UI assets are loaded from, e.g. to host them alongside the app.
+	SwaggerUIAssets string
+}
+
 // Server is an implementation of the Server core service using the native net/http package.
 type Server struct {
-	port  string
-	mux   *http.ServeMux
-	cosys *common.Cosys
+	config Config
+	mux    *http.ServeMux
+	cosys  *common.Cosys
+
+	httpServerMutex sync.Mutex
+	httpServer      *http.Server
+	redirectServer  *http.Server
+	shutdown        bool
+	listening       chan struct{} // listening is closed once the server is listening.
+
+	// baseContext is the context of all requests, which is cancelled if in-flight requests do not complete
+	// before the shutdown context is done.
+	baseContext context.Context
+	cancelBase  context.CancelFunc
 }
 
 // NewServer returns a new Server.
-func NewServer(port string, cosys *common.Cosys) *Server {
+func NewServer(config Config, cosys *common.Cosys) *Server {
+	baseContext, cancelBase := context.WithCancel(context.Background())
+
 	return &Server{
-		port:  port,
-		mux:   new(http.ServeMux),
-		cosys: cosys,
+		config:      config,
+		mux:         new(http.ServeMux),
+		cosys:       cosys,
+		baseContext: baseContext,
+		cancelBase:  cancelBase,
+		listening:   make(chan struct{}),
 	}
 }
 
 // resolveEndpoints creates the mux from the registered routes, controllers, middlewares and policies.
-func (s Server) resolveEndpoints() error {
+// The policies of a route are applied within its middlewares, so that middlewares also wrap rejected requests.
+func (s *Server) resolveEndpoints() error {
 	mux := http.NewServeMux()
 
 	routes := s.cosys.Routes()
@@ -34,17 +80,9 @@ func (s Server) resolveEndpoints() error {
 			return err
 		}
 
-		for i := len(route.Middlewares) - 1; i > 0; i-- {
-			middleware, err := route.Middlewares[i](s.cosys)
-			if err != nil {
-				return err
-			}
-
-			handleFunc = middleware(handleFunc)
-		}
-
-		for i := len(route.Policies) - 1; i > 0; i-- {
-			policy, err := route.Policies[i](s.cosys)
+		policies := s.cosys.RoutePolicies(route)
+		for i := len(policies) - 1; i >= 0; i-- {
+			policy, err := policies[i](s.cosys)
 			if err != nil {
 				return err
 			}
@@ -52,7 +90,8 @@ func (s Server) resolveEndpoints() error {
 			policyMiddleware := func(next http.HandlerFunc) http.HandlerFunc {
 				return func(w http.ResponseWriter, r *http.Request) {
 					if !policy(r) {
-						response.RespondError(w, "Forbidden", http.StatusForbidden)
+						response.RespondErr(w, common.NewForbiddenError("Forbidden"))
+						return
 					}
 
 					next.ServeHTTP(w, r)
@@ -62,21 +101,195 @@ func (s Server) resolveEndpoints() error {
 			handleFunc = policyMiddleware(handleFunc)
 		}
 
+		middlewares := s.cosys.RouteMiddlewares(route)
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			middleware, err := middlewares[i](s.cosys)
+			if err != nil {
+				return err
+			}
+
+			handleFunc = middleware(handleFunc)
+		}
+
+		handleFunc = recoverPanics(s.cosys, route, handleFunc)
+
+		if _, err = s.cosys.Tracer(); err == nil {
+			handleFunc = traceRoute(s.cosys, route, handleFunc)
+		}
+
+		handleFunc = withRoute(route, handleFunc)
+
 		mux.HandleFunc(route.Method+" "+route.Path, handleFunc)
 	}
 
-	*s.mux = *mux
+	s.mux = mux
 	return nil
 }
 
-// Start resolved the server endpoints and starts the server.
-func (s Server) Start() error {
+// withRoute wraps the handler of the given route to add the route and a request scope to the context of requests,
+// bounded by the timeout of the route, if positive.
+func withRoute(route common.Route, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := common.ContextWithRequestScope(common.ContextWithRoute(r.Context(), route))
+		if route.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, route.Timeout)
+			defer cancel()
+		}
+
+		next(w, r.WithContext(ctx))
+	}
+}
+
+// Start resolves the server endpoints and starts the server, over tls if configured,
+// along with the listener redirecting HTTP requests to HTTPS if configured.
+// If either fails, the other is shut down.
+// Returns nil once the server is shut down.
+func (s *Server) Start() error {
 	if err := s.resolveEndpoints(); err != nil {
 		return err
 	}
-	if err := http.ListenAndServe(":"+s.port, s.mux); err != nil {
+
+	httpServer, redirectServer, err := s.newHTTPServers()
+	if err != nil {
 		return err
 	}
 
+	listener, err := net.Listen("tcp", httpServer.Addr)
+	if err != nil {
+		return err
+	}
+
+	var redirectListener net.Listener
+	if redirectServer != nil {
+		if redirectListener, err = net.Listen("tcp", redirectServer.Addr); err != nil {
+			_ = listener.Close()
+			return err
+		}
+	}
+
+	s.httpServerMutex.Lock()
+	if s.shutdown {
+		s.httpServerMutex.Unlock()
+		_ = listener.Close()
+		if redirectListener != nil {
+			_ = redirectListener.Close()
+		}
+		return nil
+	}
+	s.httpServer = httpServer
+	s.redirectServer = redirectServer
+	s.httpServerMutex.Unlock()
+	close(s.listening)
+
+	errCh := make(chan error, 2)
+
+	if redirectServer != nil {
+		go func() {
+			err := redirectServer.Serve(redirectListener)
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				_ = httpServer.Close()
+			}
+			errCh <- err
+		}()
+	}
+
+	if httpServer.TLSConfig != nil {
+		err = httpServer.ServeTLS(listener, "", "")
+	} else {
+		err = httpServer.Serve(listener)
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		if redirectServer != nil {
+			_ = redirectServer.Close()
+		}
+		return err
+	}
+
+	if redirectServer != nil {
+		if err = <-errCh; err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+	}
+
 	return nil
 }
+
+// newHTTPServers returns the native http server for the resolved endpoints,
+// and the native http server redirecting to HTTPS, or nil if not configured.
+// Throws an error if the tls configuration is invalid, or a redirect listener is configured without tls.
+func (s *Server) newHTTPServers() (*http.Server, *http.Server, error) {
+	httpServer := &http.Server{
+		Addr:    ":" + s.config.Port,
+		Handler: s.mux,
+		BaseContext: func(net.Listener) context.Context {
+			return s.baseContext
+		},
+	}
+
+	if !s.config.TLS.Enabled() {
+		if s.config.RedirectPort != "" {
+			return nil, nil, fmt.Errorf("redirect listener requires tls")
+		}
+
+		return httpServer, nil, nil
+	}
+
+	tlsConfig, err := s.config.TLS.Build()
+	if err != nil {
+		return nil, nil, err
+	}
+	httpServer.TLSConfig = tlsConfig
+
+	// The native http server negotiates HTTP/2 over tls, unless TLSNextProto is non-nil.
+	if !s.config.HTTP2 {
+		httpServer.TLSNextProto = map[string]func(*http.Server, *tls.Conn, http.Handler){}
+	}
+
+	if s.config.RedirectPort == "" {
+		return httpServer, nil, nil
+	}
+
+	redirectServer := &http.Server{
+		Addr:    ":" + s.config.RedirectPort,
+		Handler: redirectHandler(s.config.Port),
+	}
+
+	return httpServer, redirectServer, nil
+}
+
+// Listening returns a channel that is closed once the server is listening.
+func (s *Server) Listening() <-chan struct{} {
+	return s.listening
+}
+
+// Shutdown stops the server and the redirect listener from accepting new connections,
+// and waits for in-flight requests to complete until the context is done,
+// after which the context of in-flight requests is cancelled and their connections are closed.
+// Safe for concurrent use.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.httpServerMutex.Lock()
+	httpServer := s.httpServer
+	redirectServer := s.redirectServer
+	s.shutdown = true
+	s.httpServerMutex.Unlock()
+
+	var errs []error
+
+	if redirectServer != nil {
+		errs = append(errs, redirectServer.Shutdown(ctx))
+	}
+
+	if httpServer != nil {
+		err := httpServer.Shutdown(ctx)
+		if err != nil {
+			s.cancelBase()
+			_ = httpServer.Close()
+		}
+		errs = append(errs, err)
+	}
+
+	s.cancelBase()
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,49 @@
+package internal
+
+import (
+	"github.com/cosys-io/cosys/common"
+	"html/template"
+	"net/http"
+	"strings"
+)
+
+// swaggerUITmpl is the template of the page rendering the OpenAPI document with Swagger UI.
+var swaggerUITmpl = template.Must(template.New("swaggerUI").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="utf-8">
+	<meta name="viewport" content="width=device-width, initial-scale=1">
+	<title>{{.Title}}</title>
+	<link rel="stylesheet" href="{{.Assets}}/swagger-ui.css">
+</head>
+<body>
+	<div id="swagger-ui"></div>
+	<script src="{{.Assets}}/swagger-ui-bundle.js" crossorigin></script>
+	<script>
+		window.ui = SwaggerUIBundle({ url: {{.DocumentPath}}, dom_id: "#swagger-ui" });
+	</script>
+</body>
+</html>
+`))
+
+// SwaggerUIRoute returns the route serving Swagger UI at the given path,
+// rendering the OpenAPI document served at the given document path,
+// with the Swagger UI assets served from the given base url.
+func SwaggerUIRoute(path string, documentPath string, assets string, title string) common.Route {
+	data := struct {
+		Title        string
+		Assets       string
+		DocumentPath string
+	}{
+		Title:        title,
+		Assets:       strings.TrimSuffix(assets, "/"),
+		DocumentPath: documentPath,
+	}
+
+	return common.NewRoute(http.MethodGet, path, func(cosys *common.Cosys) (http.HandlerFunc, error) {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_ = swaggerUITmpl.Execute(w, data)
+		}, nil
+	}, common.Describe(common.RouteDoc{Hidden: true}))
+}
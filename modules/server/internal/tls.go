@@ -0,0 +1,32 @@
+package internal
+
+import (
+	"github.com/cosys-io/cosys/modules/server/tlsconfig"
+	"net"
+	"net/http"
+)
+
+// TLSConfig is the tls configuration of the server.
+type TLSConfig = tlsconfig.Config
+
+// redirectHandler returns the handler redirecting all requests to the same url over https on the given port.
+// Safe methods are redirected with 301 Moved Permanently, and others with 308 Permanent Redirect to keep their method and body.
+func redirectHandler(httpsPort string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			host = r.Host
+		}
+
+		if httpsPort != "443" {
+			host = net.JoinHostPort(host, httpsPort)
+		}
+
+		code := http.StatusPermanentRedirect
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			code = http.StatusMovedPermanently
+		}
+
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), code)
+	}
+}
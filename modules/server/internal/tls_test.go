@@ -0,0 +1,439 @@
+package internal
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"github.com/cosys-io/cosys/common"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// authority is a certificate authority issuing certificates for tests.
+type authority struct {
+	certificate *x509.Certificate
+	key         *ecdsa.PrivateKey
+	pool        *x509.CertPool
+	certFile    string
+}
+
+// newAuthority returns a new self-signed certificate authority, with its certificate written to a temporary file.
+func newAuthority(t *testing.T, name string) *authority {
+	t.Helper()
+
+	key := newKey(t)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("could not create ca certificate: %v", err)
+	}
+
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("could not parse ca certificate: %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(certificate)
+
+	return &authority{
+		certificate: certificate,
+		key:         key,
+		pool:        pool,
+		certFile:    writePEM(t, name+".crt", "CERTIFICATE", der),
+	}
+}
+
+// issued is a certificate issued by an authority, with its certificate and key written to temporary files.
+type issued struct {
+	certificate tls.Certificate
+	certFile    string
+	keyFile     string
+}
+
+// issue returns a new certificate with the given name signed by the authority,
+// for servers on localhost if server is set, and for clients otherwise.
+func (a *authority) issue(t *testing.T, name string, server bool) issued {
+	t.Helper()
+
+	key := newKey(t)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	if server {
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+		template.DNSNames = []string{"localhost"}
+		template.IPAddresses = []net.IP{net.IPv4(127, 0, 0, 1)}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, a.certificate, &key.PublicKey, a.key)
+	if err != nil {
+		t.Fatalf("could not create certificate: %v", err)
+	}
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("could not marshal key: %v", err)
+	}
+
+	certFile := writePEM(t, name+".crt", "CERTIFICATE", der)
+	keyFile := writePEM(t, name+".key", "EC PRIVATE KEY", keyDer)
+
+	certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("could not load certificate: %v", err)
+	}
+
+	return issued{
+		certificate: certificate,
+		certFile:    certFile,
+		keyFile:     keyFile,
+	}
+}
+
+// newKey returns a new ecdsa private key.
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+
+	return key
+}
+
+// writePEM writes the given der bytes as a pem block of the given type to a temporary file, and returns its path.
+func writePEM(t *testing.T, name string, blockType string, der []byte) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatalf("could not write %s: %v", name, err)
+	}
+
+	return path
+}
+
+// freePort returns a port that is free to listen on.
+func freePort(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not find a free port: %v", err)
+	}
+	defer listener.Close()
+
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	return port
+}
+
+// startServer starts a server with the given configuration, serving the protocol of requests at /proto,
+// and shuts it down at the end of the test.
+func startServer(t *testing.T, config Config) {
+	t.Helper()
+
+	cosys, err := common.New()
+	if err != nil {
+		t.Fatalf("could not create cosys app: %v", err)
+	}
+
+	err = cosys.AddRoutes(common.NewRoute(http.MethodGet, "/proto", func(*common.Cosys) (http.HandlerFunc, error) {
+		return func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.WriteString(w, r.Proto)
+		}, nil
+	}))
+	if err != nil {
+		t.Fatalf("could not add route: %v", err)
+	}
+
+	server := NewServer(config, cosys)
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.Start()
+	}()
+
+	select {
+	case <-server.Listening():
+	case err = <-errCh:
+		t.Fatalf("server did not start: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not start listening")
+	}
+
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := server.Shutdown(ctx); err != nil {
+			t.Errorf("could not shut down server: %v", err)
+		}
+		if err := <-errCh; err != nil {
+			t.Errorf("server failed: %v", err)
+		}
+	})
+}
+
+// newClient returns an HTTPS client trusting the given authority, presenting the given client certificates, if any.
+// The client negotiates HTTP/2 if http2 is set.
+// Its connections are closed at the end of the test, before the server is shut down.
+func newClient(t *testing.T, ca *authority, http2 bool, tlsConfig *tls.Config, certificates ...tls.Certificate) *http.Client {
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{}
+	}
+	tlsConfig.RootCAs = ca.pool
+	tlsConfig.Certificates = certificates
+
+	transport := &http.Transport{
+		TLSClientConfig:   tlsConfig,
+		ForceAttemptHTTP2: http2,
+	}
+	t.Cleanup(transport.CloseIdleConnections)
+
+	return &http.Client{
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+		Timeout: 5 * time.Second,
+	}
+}
+
+// get sends a GET request to the given url, and returns the response body.
+func get(client *http.Client, url string) (string, error) {
+	response, err := client.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return "", errors.New(response.Status)
+	}
+
+	return string(body), nil
+}
+
+func TestTLSHandshake(t *testing.T) {
+	ca := newAuthority(t, "ca")
+	serverCert := ca.issue(t, "server", true)
+	port := freePort(t)
+
+	startServer(t, Config{
+		Port: port,
+		TLS:  TLSConfig{CertFile: serverCert.certFile, KeyFile: serverCert.keyFile},
+	})
+
+	body, err := get(newClient(t, ca, false, nil), "https://localhost:"+port+"/proto")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if body != "HTTP/1.1" {
+		t.Errorf("protocol = %s, want HTTP/1.1", body)
+	}
+
+	untrusted := newAuthority(t, "untrusted")
+	if _, err = get(newClient(t, untrusted, false, nil), "https://localhost:"+port+"/proto"); err == nil {
+		t.Error("expected the handshake to fail for a client not trusting the server certificate")
+	}
+
+	response, err := http.Get("http://localhost:" + port + "/proto")
+	if err == nil {
+		response.Body.Close()
+		if response.StatusCode != http.StatusBadRequest {
+			t.Errorf("plain HTTP status = %d, want %d", response.StatusCode, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestTLSMinVersion(t *testing.T) {
+	ca := newAuthority(t, "ca")
+	serverCert := ca.issue(t, "server", true)
+	port := freePort(t)
+
+	startServer(t, Config{
+		Port: port,
+		TLS:  TLSConfig{CertFile: serverCert.certFile, KeyFile: serverCert.keyFile, MinVersion: "1.3"},
+	})
+
+	tls12 := newClient(t, ca, false, &tls.Config{MaxVersion: tls.VersionTLS12})
+	if _, err := get(tls12, "https://localhost:"+port+"/proto"); err == nil || !strings.Contains(err.Error(), "protocol version") {
+		t.Errorf("expected a protocol version error for a tls 1.2 client, got: %v", err)
+	}
+
+	tls13 := newClient(t, ca, false, &tls.Config{MinVersion: tls.VersionTLS13})
+	if _, err := get(tls13, "https://localhost:"+port+"/proto"); err != nil {
+		t.Errorf("request over tls 1.3 failed: %v", err)
+	}
+}
+
+func TestTLSInvalidMinVersion(t *testing.T) {
+	ca := newAuthority(t, "ca")
+	serverCert := ca.issue(t, "server", true)
+
+	_, err := TLSConfig{CertFile: serverCert.certFile, KeyFile: serverCert.keyFile, MinVersion: "1.1"}.Build()
+	if err == nil {
+		t.Error("expected an error for tls 1.1")
+	}
+}
+
+func TestMutualTLS(t *testing.T) {
+	ca := newAuthority(t, "ca")
+	serverCert := ca.issue(t, "server", true)
+	clientCert := ca.issue(t, "client", false)
+	port := freePort(t)
+
+	startServer(t, Config{
+		Port: port,
+		TLS: TLSConfig{
+			CertFile:     serverCert.certFile,
+			KeyFile:      serverCert.keyFile,
+			ClientCAFile: ca.certFile,
+		},
+	})
+
+	url := "https://localhost:" + port + "/proto"
+
+	if _, err := get(newClient(t, ca, false, nil, clientCert.certificate), url); err != nil {
+		t.Errorf("request with a trusted client certificate failed: %v", err)
+	}
+
+	if _, err := get(newClient(t, ca, false, nil), url); err == nil {
+		t.Error("expected a request without a client certificate to be rejected")
+	}
+
+	other := newAuthority(t, "other")
+	otherCert := other.issue(t, "other-client", false)
+	if _, err := get(newClient(t, ca, false, nil, otherCert.certificate), url); err == nil {
+		t.Error("expected a request with an untrusted client certificate to be rejected")
+	}
+}
+
+func TestHTTP2(t *testing.T) {
+	ca := newAuthority(t, "ca")
+	serverCert := ca.issue(t, "server", true)
+
+	for _, test := range []struct {
+		name  string
+		http2 bool
+		proto string
+	}{
+		{name: "enabled", http2: true, proto: "HTTP/2.0"},
+		{name: "disabled", http2: false, proto: "HTTP/1.1"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			port := freePort(t)
+			startServer(t, Config{
+				Port:  port,
+				TLS:   TLSConfig{CertFile: serverCert.certFile, KeyFile: serverCert.keyFile},
+				HTTP2: test.http2,
+			})
+
+			body, err := get(newClient(t, ca, true, nil), "https://localhost:"+port+"/proto")
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			if body != test.proto {
+				t.Errorf("protocol = %s, want %s", body, test.proto)
+			}
+		})
+	}
+}
+
+func TestRedirectListener(t *testing.T) {
+	ca := newAuthority(t, "ca")
+	serverCert := ca.issue(t, "server", true)
+	port := freePort(t)
+	redirectPort := freePort(t)
+
+	startServer(t, Config{
+		Port:         port,
+		TLS:          TLSConfig{CertFile: serverCert.certFile, KeyFile: serverCert.keyFile},
+		RedirectPort: redirectPort,
+	})
+
+	client := newClient(t, ca, false, nil)
+
+	for _, test := range []struct {
+		method string
+		code   int
+	}{
+		{method: http.MethodGet, code: http.StatusMovedPermanently},
+		{method: http.MethodHead, code: http.StatusMovedPermanently},
+		{method: http.MethodPost, code: http.StatusPermanentRedirect},
+		{method: http.MethodDelete, code: http.StatusPermanentRedirect},
+	} {
+		request, err := http.NewRequest(test.method, "http://localhost:"+redirectPort+"/proto?a=b", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		response, err := client.Do(request)
+		if err != nil {
+			t.Fatalf("%s request failed: %v", test.method, err)
+		}
+		response.Body.Close()
+
+		if response.StatusCode != test.code {
+			t.Errorf("%s status = %d, want %d", test.method, response.StatusCode, test.code)
+		}
+
+		want := "https://localhost:" + port + "/proto?a=b"
+		if location := response.Header.Get("Location"); location != want {
+			t.Errorf("%s location = %s, want %s", test.method, location, want)
+		}
+	}
+
+	body, err := get(&http.Client{Transport: client.Transport, Timeout: client.Timeout}, "http://localhost:"+redirectPort+"/proto")
+	if err != nil {
+		t.Fatalf("following the redirect failed: %v", err)
+	}
+	if body != "HTTP/1.1" {
+		t.Errorf("protocol = %s, want HTTP/1.1", body)
+	}
+}
+
+func TestRedirectListenerRequiresTLS(t *testing.T) {
+	cosys, err := common.New()
+	if err != nil {
+		t.Fatalf("could not create cosys app: %v", err)
+	}
+
+	if err = NewServer(Config{Port: freePort(t), RedirectPort: freePort(t)}, cosys).Start(); err == nil {
+		t.Error("expected an error for a redirect listener without tls")
+	}
+}
@@ -0,0 +1,37 @@
+package internal
+
+import (
+	"fmt"
+	"github.com/cosys-io/cosys/common"
+	"github.com/cosys-io/cosys/modules/server/response"
+	"net/http"
+)
+
+// traceRoute wraps the handler of the given route in a span,
+// which continues the trace of the traceparent header of the request, if any.
+// The traceparent of the span is set on the response.
+func traceRoute(cosys *common.Cosys, route common.Route, next http.HandlerFunc) http.HandlerFunc {
+	name := route.String()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := common.ExtractTraceparent(r.Context(), r.Header)
+		ctx, span := cosys.StartSpan(ctx, name)
+		defer span.End()
+
+		span.SetAttribute("http.request.method", r.Method)
+		span.SetAttribute("http.route", route.Path)
+		span.SetAttribute("url.path", r.URL.Path)
+
+		common.InjectTraceparent(ctx, w.Header())
+
+		recorder := response.NewRecorder(w)
+
+		next(recorder, r.WithContext(ctx))
+
+		status := recorder.Status()
+		span.SetAttribute("http.response.status_code", status)
+		if status >= http.StatusInternalServerError {
+			span.RecordError(fmt.Errorf("%d %s", status, http.StatusText(status)))
+		}
+	}
+}
@@ -3,11 +3,79 @@ package server
 import (
 	"github.com/cosys-io/cosys/common"
 	"github.com/cosys-io/cosys/modules/server/internal"
+	"sync"
 )
 
-// init registers the module to register the Server core service.
+// ModuleName is the name the module is registered with, which other modules can depend on.
+const ModuleName = "server"
+
+type (
+	Config        = internal.Config        // Config is the configuration of the server.
+	TLSConfig     = internal.TLSConfig     // TLSConfig is the tls configuration of the server.
+	OpenAPIConfig = internal.OpenAPIConfig // OpenAPIConfig is the configuration of the OpenAPI document of the server.
+)
+
+// DefaultConfig returns the default configuration of the server,
+// which serves plain HTTP on port 3000, negotiates HTTP/2 once tls is configured,
+// and serves the OpenAPI document at /openapi.json.
+func DefaultConfig() Config {
+	return Config{
+		Port:  "3000",
+		HTTP2: true,
+		OpenAPI: OpenAPIConfig{
+			Path:            "/openapi.json",
+			Title:           "cosys",
+			Version:         "1.0.0",
+			SwaggerUIAssets: "https://unpkg.com/swagger-ui-dist@5",
+		},
+	}
+}
+
+var (
+	configMutex sync.RWMutex
+	config      = DefaultConfig()
+)
+
+// Configure sets the configuration of the server.
+// Must be called before the cosys app is created.
+// Safe for concurrent use.
+func Configure(newConfig Config) {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	config = newConfig
+}
+
+// init registers the module to register the Server core service,
+// the routes for the liveness, health and readiness probes,
+// and the routes serving the OpenAPI document and Swagger UI, if configured.
+// It is registered after the logger and tracing modules, if registered, which requests are logged and traced with.
 func init() {
-	_ = common.RegisterModule(func(cosys *common.Cosys) error {
-		return cosys.UseServer(internal.NewServer("3000", cosys))
-	})
+	_ = common.RegisterNamedModule(ModuleName, func(cosys *common.Cosys) error {
+		configMutex.RLock()
+		moduleConfig := config
+		configMutex.RUnlock()
+
+		moduleConfig, err := withProjectConfigs(moduleConfig)
+		if err != nil {
+			return err
+		}
+
+		if err = cosys.UseServer(internal.NewServer(moduleConfig, cosys)); err != nil {
+			return err
+		}
+
+		routes := internal.HealthRoutes()
+
+		openAPI := moduleConfig.OpenAPI
+		if openAPI.Path != "" {
+			routes = append(routes, internal.OpenAPIRoute(openAPI.Path, openAPI.Title, openAPI.Version))
+
+			if openAPI.SwaggerUIPath != "" {
+				routes = append(routes, internal.SwaggerUIRoute(openAPI.SwaggerUIPath, openAPI.Path, openAPI.SwaggerUIAssets, openAPI.Title))
+			}
+		}
+
+		return cosys.AddRoutes(routes...)
+	}, common.OptionallyDependsOn("logger", "tracing"))
 }
@@ -0,0 +1,125 @@
+package response
+
+import (
+	"bytes"
+	"net/http"
+)
+
+// Recorder is a ResponseWriter that writes the response to the underlying ResponseWriter,
+// recording its status code, the number of bytes of its body and whether it has started,
+// e.g. to instrument or log responses.
+type Recorder struct {
+	http.ResponseWriter
+	status  int
+	bytes   int
+	started bool
+}
+
+// NewRecorder returns a new Recorder writing to the given ResponseWriter,
+// or the given ResponseWriter if it is a Recorder, so that nested middlewares share the Recorder of a response.
+func NewRecorder(w http.ResponseWriter) *Recorder {
+	if recorder, ok := w.(*Recorder); ok {
+		return recorder
+	}
+
+	return &Recorder{
+		ResponseWriter: w,
+	}
+}
+
+// WriteHeader records the status code and writes it to the underlying ResponseWriter.
+func (r *Recorder) WriteHeader(status int) {
+	if !r.started {
+		r.status = status
+		r.started = true
+	}
+
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write records the number of bytes and writes them to the underlying ResponseWriter.
+func (r *Recorder) Write(data []byte) (int, error) {
+	if !r.started {
+		r.status = http.StatusOK
+		r.started = true
+	}
+
+	n, err := r.ResponseWriter.Write(data)
+	r.bytes += n
+
+	return n, err
+}
+
+// Unwrap returns the underlying ResponseWriter, for use by http.ResponseController.
+func (r *Recorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// Status returns the status code of the response, which is 200 OK if the response has not started.
+func (r *Recorder) Status() int {
+	if !r.started {
+		return http.StatusOK
+	}
+
+	return r.status
+}
+
+// Bytes returns the number of bytes of the body of the response written so far.
+func (r *Recorder) Bytes() int {
+	return r.bytes
+}
+
+// Started returns whether the status code or body of the response has been written.
+func (r *Recorder) Started() bool {
+	return r.started
+}
+
+// Buffer is a ResponseWriter that buffers the response instead of writing it,
+// e.g. to cache or forward the response.
+type Buffer struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+// NewBuffer returns a new empty Buffer with the given header, or an empty header if nil.
+func NewBuffer(header http.Header) *Buffer {
+	if header == nil {
+		header = http.Header{}
+	}
+
+	return &Buffer{
+		header: header,
+	}
+}
+
+// Header returns the header of the buffered response.
+func (b *Buffer) Header() http.Header {
+	return b.header
+}
+
+// WriteHeader records the status code of the buffered response, if not written.
+func (b *Buffer) WriteHeader(status int) {
+	if b.status == 0 {
+		b.status = status
+	}
+}
+
+// Write buffers the body of the response, whose status code is 200 OK if not written.
+func (b *Buffer) Write(data []byte) (int, error) {
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+
+	return b.body.Write(data)
+}
+
+// Status returns the status code of the buffered response, or 0 if the response has not started.
+func (b *Buffer) Status() int {
+	return b.status
+}
+
+// Body returns the buffered body of the response.
+func (b *Buffer) Body() []byte {
+	return b.body.Bytes()
+}
@@ -2,9 +2,11 @@ package response
 
 import (
 	"encoding/json"
+	"github.com/cosys-io/cosys/common"
 	"log"
 	"net/http"
 	"reflect"
+	"strings"
 )
 
 // Response is the standard server response.
@@ -16,6 +18,7 @@ type Response struct {
 // Meta contains meta data about the response.
 type Meta struct {
 	Error      string      `json:"error,omitempty"`
+	ErrorCode  string      `json:"errorCode,omitempty"`
 	Pagination *Pagination `json:"pagination,omitempty"`
 }
 
@@ -89,8 +92,57 @@ func RespondMany(w http.ResponseWriter, data any, page int, code int) {
 	}
 }
 
-// RespondError responds with an error message.
+// RespondError responds with an error message,
+// and the error code of the status, e.g. NOT_FOUND for 404 Not Found.
 func RespondError(w http.ResponseWriter, message string, code int) {
+	respondError(w, message, StatusErrorCode(code), code)
+}
+
+// RespondErr responds with the message of the given typed error, and the status and error code mapped from its code.
+// Untyped errors are not reported to clients, and are responded to with an internal server error.
+func RespondErr(w http.ResponseWriter, err error) {
+	typedErr, ok := common.AsTypedError(err)
+	if !ok {
+		RespondInternalError(w)
+		return
+	}
+
+	respondError(w, typedErr.Message, string(typedErr.Code), ErrorStatus(typedErr.Code))
+}
+
+// errorStatuses maps the codes of typed errors to response statuses.
+var errorStatuses = map[common.ErrorCode]int{
+	common.NotFoundCode:     http.StatusNotFound,
+	common.ValidationCode:   http.StatusBadRequest,
+	common.ConflictCode:     http.StatusConflict,
+	common.UnauthorizedCode: http.StatusUnauthorized,
+	common.ForbiddenCode:    http.StatusForbidden,
+	common.InternalCode:     http.StatusInternalServerError,
+}
+
+// ErrorStatus returns the response status of typed errors with the given code,
+// or 500 Internal Server Error if the code is unknown.
+func ErrorStatus(code common.ErrorCode) int {
+	if status, ok := errorStatuses[code]; ok {
+		return status
+	}
+
+	return http.StatusInternalServerError
+}
+
+// StatusErrorCode returns the error code of the given response status,
+// i.e. its status text in upper snake case, e.g. TOO_MANY_REQUESTS for 429 Too Many Requests.
+func StatusErrorCode(code int) string {
+	text := http.StatusText(code)
+	if text == "" {
+		return ""
+	}
+
+	return strings.ToUpper(strings.NewReplacer(" ", "_", "-", "_", "'", "").Replace(text))
+}
+
+// respondError responds with an error message and error code.
+func respondError(w http.ResponseWriter, message string, errorCode string, code int) {
 	if w == nil {
 		RespondInternalError(w)
 		return
@@ -99,7 +151,8 @@ func RespondError(w http.ResponseWriter, message string, code int) {
 	resp := Response{
 		Data: nil,
 		Meta: Meta{
-			Error: message,
+			Error:     message,
+			ErrorCode: errorCode,
 		},
 	}
 
@@ -127,7 +180,8 @@ func RespondInternalError(w http.ResponseWriter) {
 	resp := Response{
 		Data: nil,
 		Meta: Meta{
-			Error: http.StatusText(http.StatusInternalServerError),
+			Error:     http.StatusText(http.StatusInternalServerError),
+			ErrorCode: string(common.InternalCode),
 		},
 	}
 
@@ -144,3 +198,16 @@ func RespondInternalError(w http.ResponseWriter) {
 		log.Println(err)
 	}
 }
+
+// HandlerFunc is an http handler returning an error rather than responding with it.
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// Handle returns the http handler calling the given handler,
+// and responding with the error it returns, if any, using RespondErr.
+func Handle(handler HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := handler(w, r); err != nil {
+			RespondErr(w, err)
+		}
+	}
+}
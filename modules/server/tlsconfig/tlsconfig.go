@@ -0,0 +1,110 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Config is the tls configuration of a server.
+type Config struct {
+	CertFile     string // CertFile is the path to the pem certificate of the server, including intermediates.
+	KeyFile      string // KeyFile is the path to the pem private key of the server.
+	MinVersion   string // MinVersion is the minimum tls version accepted, either 1.2 or 1.3.
+	ClientCAFile string // ClientCAFile is the path to the pem certificates of the authorities trusted to sign client certificates.
+	// ClientAuth is the policy for client certificates, one of none, request, require, verify_if_given and require_and_verify.
+	// Defaults to require_and_verify if ClientCAFile is set, and none otherwise.
+	ClientAuth string
+}
+
+// Enabled returns whether tls is enabled, i.e. a certificate and key are configured.
+func (c Config) Enabled() bool {
+	return c.CertFile != "" || c.KeyFile != ""
+}
+
+// Build returns the tls configuration of the native crypto/tls package,
+// loading the certificate, key and client certificate authorities from their files.
+// Throws an error if a file cannot be loaded, or the minimum version or client auth policy is invalid.
+func (c Config) Build() (*tls.Config, error) {
+	if c.CertFile == "" || c.KeyFile == "" {
+		return nil, fmt.Errorf("tls requires both a certificate and a key")
+	}
+
+	certificate, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("tls certificate: %w", err)
+	}
+
+	minVersion, err := ParseTLSVersion(c.MinVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	clientAuth := c.ClientAuth
+	if clientAuth == "" && c.ClientCAFile != "" {
+		clientAuth = "require_and_verify"
+	}
+
+	clientAuthType, err := ParseClientAuth(clientAuth)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		MinVersion:   minVersion,
+		ClientAuth:   clientAuthType,
+	}
+
+	if c.ClientCAFile != "" {
+		pem, err := os.ReadFile(c.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("tls client ca: %w", err)
+		}
+
+		clientCAs := x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("tls client ca: no certificates found in %s", c.ClientCAFile)
+		}
+
+		tlsConfig.ClientCAs = clientCAs
+	} else if clientAuthType >= tls.VerifyClientCertIfGiven {
+		return nil, fmt.Errorf("tls client auth %s requires a client ca", clientAuth)
+	}
+
+	return tlsConfig, nil
+}
+
+// ParseTLSVersion returns the tls version with the given name, defaulting to 1.2.
+// Throws an error if the name is not a supported tls version.
+func ParseTLSVersion(name string) (uint16, error) {
+	switch strings.TrimPrefix(strings.ToLower(strings.TrimSpace(name)), "tls") {
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("tls version not supported: %s", name)
+	}
+}
+
+// ParseClientAuth returns the client certificate policy with the given name, defaulting to none.
+// Throws an error if the name is not a client certificate policy.
+func ParseClientAuth(name string) (tls.ClientAuthType, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "none":
+		return tls.NoClientCert, nil
+	case "request":
+		return tls.RequestClientCert, nil
+	case "require":
+		return tls.RequireAnyClientCert, nil
+	case "verify_if_given":
+		return tls.VerifyClientCertIfGiven, nil
+	case "require_and_verify":
+		return tls.RequireAndVerifyClientCert, nil
+	default:
+		return 0, fmt.Errorf("tls client auth not found: %s", name)
+	}
+}
@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"github.com/cosys-io/cosys/common"
@@ -16,27 +17,45 @@ type Database struct {
 // NewDatabase returns a new Database.
 func NewDatabase(cosys *common.Cosys) *Database {
 	return &Database{
-		db:    new(sql.DB),
+		db:    nil,
 		cosys: cosys,
 	}
 }
 
 // Open starts the connection to the SQLite3 database.
-func (d Database) Open(dataSourceName string) error {
+func (d *Database) Open(dataSourceName string) error {
 	db, err := sql.Open("sqlite3", dataSourceName)
 	if err != nil {
 		return err
 	}
 
-	*d.db = *db
+	d.db = db
 	return nil
 }
 
-// LoadSchema loads the schema of all registered models.
-func (d Database) LoadSchema() error {
+// Close closes the connection to the SQLite3 database.
+func (d *Database) Close() error {
+	if d.db == nil {
+		return nil
+	}
+
+	return d.db.Close()
+}
+
+// Ping checks that the connection to the SQLite3 database is alive.
+func (d *Database) Ping(ctx context.Context) error {
+	if d.db == nil {
+		return fmt.Errorf("database not open")
+	}
+
+	return d.db.PingContext(ctx)
+}
+
+// LoadSchema loads the schema of all registered models, until the context is done.
+func (d Database) LoadSchema(ctx context.Context) error {
 	for _, model := range d.cosys.Models() {
 		schema := schemaQuery(model.Schema_())
-		if _, err := d.db.Exec(schema); err != nil {
+		if _, err := d.db.ExecContext(ctx, schema); err != nil {
 			return err
 		}
 	}
@@ -46,18 +65,28 @@ func (d Database) LoadSchema() error {
 
 // FindOne returns one entity of the model with the given uid.
 func (d Database) FindOne(uid string, params common.DBParams) (common.Entity, error) {
+	ctx, span := d.startSpan(&params, uid, "findOne")
+	defer span.End()
+
 	model, err := d.cosys.Model(uid)
 	if err != nil {
 		return nil, err
 	}
 
+	if err = localize(&params, model); err != nil {
+		return nil, err
+	}
+
+	excludeDeleted(&params, model)
+
 	params.Limit = 1
 
 	var state any
 	if err = model.CallLifecycle_("beforeFindOne", common.EventQuery{
-		Params: params,
-		Result: nil,
-		State:  &state,
+		Params:  params,
+		Result:  nil,
+		State:   &state,
+		Context: ctx,
 	}); err != nil {
 		return nil, err
 	}
@@ -67,14 +96,14 @@ func (d Database) FindOne(uid string, params common.DBParams) (common.Entity, er
 		return nil, err
 	}
 
-	rows, err := d.db.Query(query)
+	rows, err := d.query(ctx, span, query)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
 	if !rows.Next() {
-		return nil, fmt.Errorf("entity not found")
+		return nil, common.NewNotFoundError("entity not found")
 	}
 
 	entity, err := scan(rows, &params, model)
@@ -82,10 +111,15 @@ func (d Database) FindOne(uid string, params common.DBParams) (common.Entity, er
 		return nil, err
 	}
 
+	if err = rows.Close(); err != nil {
+		return nil, err
+	}
+
 	if err = model.CallLifecycle_("afterFindOne", common.EventQuery{
-		Params: params,
-		Result: entity,
-		State:  &state,
+		Params:  params,
+		Result:  entity,
+		State:   &state,
+		Context: ctx,
 	}); err != nil {
 		return nil, err
 	}
@@ -95,16 +129,26 @@ func (d Database) FindOne(uid string, params common.DBParams) (common.Entity, er
 
 // FindMany returns multiple entities of the model with the given uid.
 func (d Database) FindMany(uid string, params common.DBParams) ([]common.Entity, error) {
+	ctx, span := d.startSpan(&params, uid, "findMany")
+	defer span.End()
+
 	model, err := d.cosys.Model(uid)
 	if err != nil {
 		return nil, err
 	}
 
+	if err = localize(&params, model); err != nil {
+		return nil, err
+	}
+
+	excludeDeleted(&params, model)
+
 	var state any
 	if err = model.CallLifecycle_("beforeFindMany", common.EventQuery{
-		Params: params,
-		Result: nil,
-		State:  &state,
+		Params:  params,
+		Result:  nil,
+		State:   &state,
+		Context: ctx,
 	}); err != nil {
 		return nil, err
 	}
@@ -116,7 +160,7 @@ func (d Database) FindMany(uid string, params common.DBParams) ([]common.Entity,
 
 	entities := []common.Entity{}
 
-	rows, err := d.db.Query(query)
+	rows, err := d.query(ctx, span, query)
 	if err != nil {
 		return nil, err
 	}
@@ -132,10 +176,15 @@ func (d Database) FindMany(uid string, params common.DBParams) ([]common.Entity,
 		entities = append(entities, entity)
 	}
 
+	if err = rows.Close(); err != nil {
+		return nil, err
+	}
+
 	if err = model.CallLifecycle_("afterFindMany", common.EventQuery{
-		Params: params,
-		Result: entities,
-		State:  &state,
+		Params:  params,
+		Result:  entities,
+		State:   &state,
+		Context: ctx,
 	}); err != nil {
 		return nil, err
 	}
@@ -146,6 +195,9 @@ func (d Database) FindMany(uid string, params common.DBParams) ([]common.Entity,
 // Create creates one entity of the model with the given uid with the given data
 // and returns the entity after creation.
 func (d Database) Create(uid string, data common.Entity, params common.DBParams) (common.Entity, error) {
+	ctx, span := d.startSpan(&params, uid, "create")
+	defer span.End()
+
 	model, err := d.cosys.Model(uid)
 	if err != nil {
 		return nil, err
@@ -153,9 +205,10 @@ func (d Database) Create(uid string, data common.Entity, params common.DBParams)
 
 	var state any
 	if err = model.CallLifecycle_("beforeCreate", common.EventQuery{
-		Params: params,
-		Result: nil,
-		State:  &state,
+		Params:  params,
+		Result:  nil,
+		State:   &state,
+		Context: ctx,
 	}); err != nil {
 		return nil, err
 	}
@@ -170,13 +223,17 @@ func (d Database) Create(uid string, data common.Entity, params common.DBParams)
 		return nil, err
 	}
 
-	rows, err := d.db.Query(query, values...)
+	rows, err := d.query(ctx, span, query, values...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
 	if !rows.Next() {
+		if err = rows.Err(); err != nil {
+			return nil, conflictError(err)
+		}
+
 		return nil, fmt.Errorf("entity could not be created")
 	}
 
@@ -185,10 +242,15 @@ func (d Database) Create(uid string, data common.Entity, params common.DBParams)
 		return nil, err
 	}
 
+	if err = rows.Close(); err != nil {
+		return nil, err
+	}
+
 	if err = model.CallLifecycle_("afterCreate", common.EventQuery{
-		Params: params,
-		Result: entity,
-		State:  &state,
+		Params:  params,
+		Result:  entity,
+		State:   &state,
+		Context: ctx,
 	}); err != nil {
 		return nil, err
 	}
@@ -199,6 +261,9 @@ func (d Database) Create(uid string, data common.Entity, params common.DBParams)
 // CreateMany creates multiple entities of the model with the given uid with the given data
 // and returns the entities after creation.
 func (d Database) CreateMany(uid string, datas []common.Entity, params common.DBParams) ([]common.Entity, error) {
+	ctx, span := d.startSpan(&params, uid, "createMany")
+	defer span.End()
+
 	model, err := d.cosys.Model(uid)
 	if err != nil {
 		return nil, err
@@ -206,9 +271,10 @@ func (d Database) CreateMany(uid string, datas []common.Entity, params common.DB
 
 	var state any
 	if err = model.CallLifecycle_("beforeCreateMany", common.EventQuery{
-		Params: params,
-		Result: nil,
-		State:  &state,
+		Params:  params,
+		Result:  nil,
+		State:   &state,
+		Context: ctx,
 	}); err != nil {
 		return nil, err
 	}
@@ -230,14 +296,18 @@ func (d Database) CreateMany(uid string, datas []common.Entity, params common.DB
 				errCh <- err
 			}
 
-			rows, err := d.db.Query(query, values...)
+			rows, err := d.query(ctx, span, query, values...)
 			if err != nil {
 				errCh <- err
 			}
 			defer rows.Close()
 
 			if !rows.Next() {
-				errCh <- fmt.Errorf("entity could not be created")
+				if err = rows.Err(); err != nil {
+					errCh <- conflictError(err)
+				} else {
+					errCh <- fmt.Errorf("entity could not be created")
+				}
 			}
 
 			entity, err := scan(rows, &params, model)
@@ -263,9 +333,10 @@ func (d Database) CreateMany(uid string, datas []common.Entity, params common.DB
 	}
 
 	if err = model.CallLifecycle_("afterCreateMany", common.EventQuery{
-		Params: params,
-		Result: entities,
-		State:  &state,
+		Params:  params,
+		Result:  entities,
+		State:   &state,
+		Context: ctx,
 	}); err != nil {
 		return nil, err
 	}
@@ -276,6 +347,9 @@ func (d Database) CreateMany(uid string, datas []common.Entity, params common.DB
 // Update updates one entity of the model with the given uid with the given data
 // and returns the entity after updating.
 func (d Database) Update(uid string, data common.Entity, params common.DBParams) (common.Entity, error) {
+	ctx, span := d.startSpan(&params, uid, "update")
+	defer span.End()
+
 	params.Limit = 1
 
 	model, err := d.cosys.Model(uid)
@@ -283,11 +357,18 @@ func (d Database) Update(uid string, data common.Entity, params common.DBParams)
 		return nil, err
 	}
 
+	if err = localize(&params, model); err != nil {
+		return nil, err
+	}
+
+	excludeDeleted(&params, model)
+
 	var state any
 	if err = model.CallLifecycle_("beforeUpdate", common.EventQuery{
-		Params: params,
-		Result: nil,
-		State:  &state,
+		Params:  params,
+		Result:  nil,
+		State:   &state,
+		Context: ctx,
 	}); err != nil {
 		return nil, err
 	}
@@ -302,14 +383,14 @@ func (d Database) Update(uid string, data common.Entity, params common.DBParams)
 		return nil, err
 	}
 
-	rows, err := d.db.Query(query, values...)
+	rows, err := d.query(ctx, span, query, values...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
 	if !rows.Next() {
-		return nil, fmt.Errorf("entity could not be updated")
+		return nil, common.NewNotFoundError("entity not found")
 	}
 
 	entity, err := scan(rows, &params, model)
@@ -317,10 +398,15 @@ func (d Database) Update(uid string, data common.Entity, params common.DBParams)
 		return nil, err
 	}
 
+	if err = rows.Close(); err != nil {
+		return nil, err
+	}
+
 	if err = model.CallLifecycle_("afterUpdate", common.EventQuery{
-		Params: params,
-		Result: entity,
-		State:  &state,
+		Params:  params,
+		Result:  entity,
+		State:   &state,
+		Context: ctx,
 	}); err != nil {
 		return nil, err
 	}
@@ -331,16 +417,26 @@ func (d Database) Update(uid string, data common.Entity, params common.DBParams)
 // UpdateMany updates multiple entities of the model with the given uid with the given data
 // and returns the entities after updating.
 func (d Database) UpdateMany(uid string, data common.Entity, params common.DBParams) ([]common.Entity, error) {
+	ctx, span := d.startSpan(&params, uid, "updateMany")
+	defer span.End()
+
 	model, err := d.cosys.Model(uid)
 	if err != nil {
 		return nil, err
 	}
 
+	if err = localize(&params, model); err != nil {
+		return nil, err
+	}
+
+	excludeDeleted(&params, model)
+
 	var state any
 	if err = model.CallLifecycle_("beforeUpdateMany", common.EventQuery{
-		Params: params,
-		Result: nil,
-		State:  &state,
+		Params:  params,
+		Result:  nil,
+		State:   &state,
+		Context: ctx,
 	}); err != nil {
 		return nil, err
 	}
@@ -355,7 +451,7 @@ func (d Database) UpdateMany(uid string, data common.Entity, params common.DBPar
 		return nil, err
 	}
 
-	rows, err := d.db.Query(query, values...)
+	rows, err := d.query(ctx, span, query, values...)
 	if err != nil {
 		return nil, err
 	}
@@ -372,10 +468,15 @@ func (d Database) UpdateMany(uid string, data common.Entity, params common.DBPar
 		entities = append(entities, entity)
 	}
 
+	if err = rows.Close(); err != nil {
+		return nil, err
+	}
+
 	if err = model.CallLifecycle_("afterUpdateMany", common.EventQuery{
-		Params: params,
-		Result: entities,
-		State:  &state,
+		Params:  params,
+		Result:  entities,
+		State:   &state,
+		Context: ctx,
 	}); err != nil {
 		return nil, err
 	}
@@ -384,7 +485,11 @@ func (d Database) UpdateMany(uid string, data common.Entity, params common.DBPar
 }
 
 // Delete deletes one entity of the model with the given uid and returns the entity before deletion.
+// Entities of soft delete models are only marked as deleted, unless the delete is permanent.
 func (d Database) Delete(uid string, params common.DBParams) (common.Entity, error) {
+	ctx, span := d.startSpan(&params, uid, "delete")
+	defer span.End()
+
 	params.Limit = 1
 
 	model, err := d.cosys.Model(uid)
@@ -392,28 +497,33 @@ func (d Database) Delete(uid string, params common.DBParams) (common.Entity, err
 		return nil, err
 	}
 
+	if err = localize(&params, model); err != nil {
+		return nil, err
+	}
+
 	var state any
 	if err = model.CallLifecycle_("beforeDelete", common.EventQuery{
-		Params: params,
-		Result: nil,
-		State:  &state,
+		Params:  params,
+		Result:  nil,
+		State:   &state,
+		Context: ctx,
 	}); err != nil {
 		return nil, err
 	}
 
-	query, err := deleteQuery(&params, model)
+	query, values, err := removeQuery(&params, model)
 	if err != nil {
 		return nil, err
 	}
 
-	rows, err := d.db.Query(query)
+	rows, err := d.query(ctx, span, query, values...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
 	if !rows.Next() {
-		return nil, fmt.Errorf("entity not found")
+		return nil, common.NewNotFoundError("entity not found")
 	}
 
 	entity, err := scan(rows, &params, model)
@@ -421,10 +531,15 @@ func (d Database) Delete(uid string, params common.DBParams) (common.Entity, err
 		return nil, err
 	}
 
+	if err = rows.Close(); err != nil {
+		return nil, err
+	}
+
 	if err = model.CallLifecycle_("afterDelete", common.EventQuery{
-		Params: params,
-		Result: entity,
-		State:  &state,
+		Params:  params,
+		Result:  entity,
+		State:   &state,
+		Context: ctx,
 	}); err != nil {
 		return nil, err
 	}
@@ -433,27 +548,36 @@ func (d Database) Delete(uid string, params common.DBParams) (common.Entity, err
 }
 
 // DeleteMany deletes multiple entities of the model with the given uid and returns the entities before deletion.
+// Entities of soft delete models are only marked as deleted, unless the delete is permanent.
 func (d Database) DeleteMany(uid string, params common.DBParams) ([]common.Entity, error) {
+	ctx, span := d.startSpan(&params, uid, "deleteMany")
+	defer span.End()
+
 	model, err := d.cosys.Model(uid)
 	if err != nil {
 		return nil, err
 	}
 
+	if err = localize(&params, model); err != nil {
+		return nil, err
+	}
+
 	var state any
 	if err = model.CallLifecycle_("beforeDeleteMany", common.EventQuery{
-		Params: params,
-		Result: nil,
-		State:  &state,
+		Params:  params,
+		Result:  nil,
+		State:   &state,
+		Context: ctx,
 	}); err != nil {
 		return nil, err
 	}
 
-	query, err := deleteQuery(&params, model)
+	query, values, err := removeQuery(&params, model)
 	if err != nil {
 		return nil, err
 	}
 
-	rows, err := d.db.Query(query)
+	rows, err := d.query(ctx, span, query, values...)
 	if err != nil {
 		return nil, err
 	}
@@ -470,10 +594,15 @@ func (d Database) DeleteMany(uid string, params common.DBParams) ([]common.Entit
 		entities = append(entities, entity)
 	}
 
+	if err = rows.Close(); err != nil {
+		return nil, err
+	}
+
 	if err = model.CallLifecycle_("afterDeleteMany", common.EventQuery{
-		Params: params,
-		Result: entities,
-		State:  &state,
+		Params:  params,
+		Result:  entities,
+		State:   &state,
+		Context: ctx,
 	}); err != nil {
 		return nil, err
 	}
@@ -3,10 +3,32 @@ package internal
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/cosys-io/cosys/common"
 )
 
+// removeQuery returns the sql query and values for deleting entities from the given params.
+// Entities of soft delete models are marked as deleted with an update query instead, unless the delete is permanent.
+func removeQuery(params *common.DBParams, model common.Model) (string, []any, error) {
+	if model == nil {
+		return "", nil, fmt.Errorf("model is nil")
+	}
+
+	deletedAt, softDelete := common.SoftDeleteAttribute(model)
+	if !softDelete || params.Permanent {
+		query, err := deleteQuery(params, model)
+		return query, nil, err
+	}
+
+	softParams := *params
+	softParams.Columns = []common.Attribute{deletedAt}
+	softParams.Where = append(append([]common.Condition{}, params.Where...), deletedAt.Null())
+
+	query, err := updateQuery(&softParams, model)
+	return query, []any{time.Now().UTC()}, err
+}
+
 // deleteQuery returns a delete sql query from the given params.
 func deleteQuery(params *common.DBParams, model common.Model) (string, error) {
 	if model == nil {
@@ -30,9 +52,11 @@ func deleteQuery(params *common.DBParams, model common.Model) (string, error) {
 				return "", err
 			}
 
+			sb.WriteString("( ")
 			sb.WriteString(whereString)
+			sb.WriteString(" )")
 			if index < num-1 {
-				sb.WriteString(", ")
+				sb.WriteString(" AND")
 			}
 		}
 	} else {
@@ -3,6 +3,7 @@ package internal
 import (
 	"fmt"
 	"github.com/cosys-io/cosys/common"
+	"github.com/iancoleman/strcase"
 	"strconv"
 	"strings"
 )
@@ -16,25 +17,27 @@ func schemaQuery(schema common.ModelSchema) string {
 	sb.WriteString(" ( id INTEGER PRIMARY KEY AUTOINCREMENT, ")
 
 	for index, attr := range schema.Attributes()[1:] {
-		sb.WriteString(attr.Name())
+		column := strcase.ToSnake(attr.Name())
+
+		sb.WriteString(column)
 		sb.WriteString(" ")
 		sb.WriteString(getType(attr.DetailedDataType()))
 
 		var checks []string
 		if attr.DetailedDataType() == "Boolean" {
-			checks = append(checks, attr.Name()+" IN (0, 1)")
+			checks = append(checks, column+" IN (0, 1)")
 		}
 		if attr.Max() != 2147483647 {
-			checks = append(checks, attr.Name()+" <= "+strconv.FormatInt(attr.Max(), 10))
+			checks = append(checks, column+" <= "+strconv.FormatInt(attr.Max(), 10))
 		}
 		if attr.Min() != -2147483648 {
-			checks = append(checks, attr.Name()+" >= "+strconv.FormatInt(attr.Min(), 10))
+			checks = append(checks, column+" >= "+strconv.FormatInt(attr.Min(), 10))
 		}
 		if attr.MaxLength() != -1 {
-			checks = append(checks, fmt.Sprintf("length(%s) <= %d", attr.Name(), attr.MaxLength()))
+			checks = append(checks, fmt.Sprintf("length(%s) <= %d", column, attr.MaxLength()))
 		}
 		if attr.MinLength() != -1 {
-			checks = append(checks, fmt.Sprintf("length(%s) >= %d", attr.Name(), attr.MinLength()))
+			checks = append(checks, fmt.Sprintf("length(%s) >= %d", column, attr.MinLength()))
 		}
 		if len(checks) > 0 {
 			sb.WriteString(" CHECK( ")
@@ -79,6 +82,10 @@ func getType(attrType string) string {
 		return "INTEGER"
 	case "String":
 		return "TEXT"
+	case "Date", "DateTime", "Timestamp":
+		return "DATETIME"
+	case "Component", "DynamicZone":
+		return "TEXT"
 	default:
 		return ""
 	}
@@ -47,9 +47,11 @@ func updateQuery(params *common.DBParams, model common.Model) (string, error) {
 				return "", err
 			}
 
+			sb.WriteString("( ")
 			sb.WriteString(whereString)
+			sb.WriteString(" )")
 			if index < num-1 {
-				sb.WriteString(", ")
+				sb.WriteString(" AND")
 			}
 		}
 	} else {
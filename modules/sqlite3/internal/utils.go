@@ -1,14 +1,62 @@
 package internal
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/cosys-io/cosys/common"
+	"github.com/mattn/go-sqlite3"
 )
 
+// timeFormat is the format in which the SQLite3 driver stores datetime values.
+const timeFormat = "2006-01-02 15:04:05.999999999-07:00"
+
+// localeColumn is the name of the attribute holding the locale of entities of localized models.
+const localeColumn = "locale"
+
+// localize adds the where condition for the locale in the given params,
+// and returns an error if the model does not have a locale attribute.
+func localize(params *common.DBParams, model common.Model) error {
+	if params.Locale == "" {
+		return nil
+	}
+
+	for _, attr := range model.Attributes_() {
+		if attr.CamelName() != localeColumn {
+			continue
+		}
+
+		localeAttr, ok := attr.(common.StringAttribute)
+		if !ok {
+			return fmt.Errorf("locale attribute is not a string attribute")
+		}
+
+		params.Where = append(params.Where, localeAttr.Eq(params.Locale))
+		return nil
+	}
+
+	return fmt.Errorf("model is not localized: %s", model.PluralCamelName_())
+}
+
+// excludeDeleted adds the where condition excluding soft-deleted entities to the given params,
+// unless the params include deleted entities.
+func excludeDeleted(params *common.DBParams, model common.Model) {
+	if params.IncludeDeleted {
+		return
+	}
+
+	if deletedAt, ok := common.SoftDeleteAttribute(model); ok {
+		params.Where = append(params.Where, deletedAt.Null())
+	}
+}
+
 // extract returns a slice of the values of an entity's fields.
 func extract(data common.Entity, params *common.DBParams, model common.Model) ([]any, error) {
 	if data == nil {
@@ -41,6 +89,16 @@ func extract(data common.Entity, params *common.DBParams, model common.Model) ([
 			return nil, fmt.Errorf("attribute not found: %s", attrName)
 		}
 
+		if common.IsEmbedded(col) {
+			embedded, err := json.Marshal(attributeValue.Interface())
+			if err != nil {
+				return nil, err
+			}
+
+			attrs = append(attrs, string(embedded))
+			continue
+		}
+
 		attrs = append(attrs, attributeValue.Interface())
 	}
 
@@ -75,7 +133,14 @@ func scan(rows *sql.Rows, params *common.DBParams, model common.Model) (common.E
 
 	numCols := len(selects)
 	columns := make([]any, numCols)
+	embedded := map[int]*sql.NullString{}
 	for index, attribute := range selects {
+		if common.IsEmbedded(attribute) {
+			embedded[index] = &sql.NullString{}
+			columns[index] = embedded[index]
+			continue
+		}
+
 		field := entityValue.FieldByName(attribute.PascalName())
 		columns[index] = field.Addr().Interface()
 	}
@@ -85,6 +150,17 @@ func scan(rows *sql.Rows, params *common.DBParams, model common.Model) (common.E
 		return nil, err
 	}
 
+	for index, column := range embedded {
+		if !column.Valid || column.String == "" {
+			continue
+		}
+
+		field := entityValue.FieldByName(selects[index].PascalName())
+		if err = json.Unmarshal([]byte(column.String), field.Addr().Interface()); err != nil {
+			return nil, err
+		}
+	}
+
 	return entity, nil
 }
 
@@ -167,7 +243,7 @@ func stringExpressions(where *common.ExpressionCondition) (string, error) {
 	case common.Eq, common.Neq:
 		switch r := where.Right.(type) {
 		case string:
-			return fmt.Sprintf(`%s %s "%s"`, left, where.Op, r), nil
+			return fmt.Sprintf("%s %s %s", left, where.Op, quote(r)), nil
 		case int:
 			right := strconv.Itoa(r)
 
@@ -176,16 +252,22 @@ func stringExpressions(where *common.ExpressionCondition) (string, error) {
 			right := strconv.FormatBool(r)
 
 			return fmt.Sprintf("%s %s %s", left, where.Op, right), nil
+		case time.Time:
+			return fmt.Sprintf("%s %s %s", left, where.Op, quote(r.Format(timeFormat))), nil
 		default:
 			return "", fmt.Errorf("illegal right operand: %s", where.Right)
 		}
 	case common.Lt, common.Gt, common.Lte, common.Gte:
-		if _, ok := where.Right.(int); !ok {
+		switch r := where.Right.(type) {
+		case int:
+			right := strconv.Itoa(r)
+
+			return fmt.Sprintf("%s %s %s", left, where.Op, right), nil
+		case time.Time:
+			return fmt.Sprintf("%s %s %s", left, where.Op, quote(r.Format(timeFormat))), nil
+		default:
 			return "", fmt.Errorf("illegal right operand: %s", where.Right)
 		}
-		right := strconv.Itoa(where.Right.(int))
-
-		return fmt.Sprintf("%s %s %s", left, where.Op, right), nil
 	case common.Null, common.NotNull:
 		return fmt.Sprintf("%s %s", left, where.Op), nil
 	default:
@@ -193,6 +275,11 @@ func stringExpressions(where *common.ExpressionCondition) (string, error) {
 	}
 }
 
+// quote returns the given string as a sql string literal, escaping single quotes.
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
 // stringOrder returns the sql condition for an order condition.
 func stringOrder(orderBy *common.Order) (string, error) {
 	if orderBy == nil {
@@ -204,3 +291,37 @@ func stringOrder(orderBy *common.Order) (string, error) {
 	}
 	return "", fmt.Errorf("illegal operation for order condition: %s", orderBy.Order)
 }
+
+// startSpan starts the span of a query on the model with the given uid,
+// as a child of the span in the params context, and sets the params context to the new span.
+func (d Database) startSpan(params *common.DBParams, uid string, operation string) (context.Context, common.Span) {
+	ctx, span := d.cosys.StartSpan(params.Context, "sqlite3 "+operation+" "+uid)
+	span.SetAttribute("db.system", "sqlite")
+	span.SetAttribute("db.operation", operation)
+	span.SetAttribute("cosys.model", uid)
+
+	params.Context = ctx
+	return ctx, span
+}
+
+// query runs the given sql query, which is cancelled once the given context is done,
+// and records the statement and any error on the given span.
+func (d Database) query(ctx context.Context, span common.Span, query string, args ...any) (*sql.Rows, error) {
+	span.SetAttribute("db.statement", query)
+
+	rows, err := d.db.QueryContext(ctx, query, args...)
+	span.RecordError(err)
+
+	return rows, conflictError(err)
+}
+
+// conflictError returns the given error as a conflict error if it is a violation of a unique constraint,
+// so that callers can retry or report the conflict, and otherwise returns the error as is.
+func conflictError(err error) error {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+		return common.NewTypedError(common.ConflictCode, "unique constraint violated", err)
+	}
+
+	return err
+}
@@ -1,19 +1,31 @@
 package sqlite3
 
 import (
+	"context"
 	"github.com/cosys-io/cosys/common"
 	"github.com/cosys-io/cosys/modules/sqlite3/internal"
 	_ "github.com/mattn/go-sqlite3"
 )
 
+// ModuleName is the name the module is registered with, which other modules can depend on.
+const ModuleName = "sqlite3"
+
 var (
 	database         *internal.Database // database is the Database core service.
 	BootstrapHookKey string             // BootstrapHookKey can be used to update or remove the bootstrap hook.
 )
 
-// init registers the module to register the Database core service and the bootstrap hook.
+// HealthCheckName is the name of the health check pinging the database.
+const HealthCheckName = "sqlite3"
+
+// BootstrapHookName is the uid of the bootstrap hook opening the database and loading the schema,
+// which hooks using the database, e.g. seeding it, can be ordered after with common.HookAfter.
+const BootstrapHookName = "sqlite3.bootstrap"
+
+// init registers the module to register the Database core service, the bootstrap hook and the health check.
+// It is registered after the tracing module, if registered, which queries are traced with.
 func init() {
-	_ = common.RegisterModule(func(cosys *common.Cosys) error {
+	_ = common.RegisterNamedModule(ModuleName, func(cosys *common.Cosys) error {
 		var err error
 
 		database = internal.NewDatabase(cosys)
@@ -21,21 +33,25 @@ func init() {
 			return err
 		}
 
-		BootstrapHookKey, err = cosys.AddBootstrapHook(bootstrap)
+		BootstrapHookKey, err = cosys.AddBootstrapHookContext(bootstrap, common.HookName(BootstrapHookName))
 		if err != nil {
 			return err
 		}
 
+		if err = cosys.AddHealthCheck(HealthCheckName, database.Ping); err != nil {
+			return err
+		}
+
 		return nil
-	})
+	}, common.OptionallyDependsOn("tracing"))
 }
 
 // bootstrap opens the connection to the SQLite3 database and
-// loads the schema for all registered models.
-func bootstrap(cosys *common.Cosys) error {
+// loads the schema for all registered models, until the context is done.
+func bootstrap(ctx context.Context, cosys *common.Cosys) error {
 	if err := database.Open("data.db"); err != nil {
 		return err
 	}
 
-	return database.LoadSchema()
+	return database.LoadSchema(ctx)
 }
@@ -0,0 +1,214 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/cosys-io/cosys/common"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// otlpTimeout is the maximum duration of a request to the OTLP collector.
+const otlpTimeout = 10 * time.Second
+
+// otlpScope is the instrumentation scope name of exported spans.
+const otlpScope = "github.com/cosys-io/cosys"
+
+// OTLP status codes and span kinds, as defined by the OTLP protocol.
+const (
+	otlpStatusUnset = 0
+	otlpStatusError = 2
+
+	otlpKindInternal = 1
+	otlpKindServer   = 2
+)
+
+// OTLPExporter is an exporter sending spans to an OpenTelemetry collector
+// using the OTLP/HTTP protocol with JSON encoding.
+type OTLPExporter struct {
+	endpoint    string
+	serviceName string
+	client      *http.Client
+}
+
+// NewOTLPExporter returns a new OTLPExporter sending spans of the given service
+// to the collector at the given endpoint, e.g. http://localhost:4318.
+func NewOTLPExporter(endpoint string, serviceName string) *OTLPExporter {
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	if !strings.HasSuffix(endpoint, "/v1/traces") {
+		endpoint += "/v1/traces"
+	}
+
+	return &OTLPExporter{
+		endpoint:    endpoint,
+		serviceName: serviceName,
+		client: &http.Client{
+			Timeout: otlpTimeout,
+		},
+	}
+}
+
+type (
+	otlpRequest struct {
+		ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+	}
+	otlpResourceSpans struct {
+		Resource   otlpResource     `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	}
+	otlpResource struct {
+		Attributes []otlpKeyValue `json:"attributes"`
+	}
+	otlpScopeSpans struct {
+		Scope otlpInstrumentationScope `json:"scope"`
+		Spans []otlpSpan               `json:"spans"`
+	}
+	otlpInstrumentationScope struct {
+		Name string `json:"name"`
+	}
+	otlpSpan struct {
+		TraceId           string         `json:"traceId"`
+		SpanId            string         `json:"spanId"`
+		ParentSpanId      string         `json:"parentSpanId,omitempty"`
+		Name              string         `json:"name"`
+		Kind              int            `json:"kind"`
+		StartTimeUnixNano string         `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+		Attributes        []otlpKeyValue `json:"attributes"`
+		Status            otlpStatus     `json:"status"`
+	}
+	otlpStatus struct {
+		Code    int    `json:"code"`
+		Message string `json:"message,omitempty"`
+	}
+	otlpKeyValue struct {
+		Key   string       `json:"key"`
+		Value otlpAnyValue `json:"value"`
+	}
+	otlpAnyValue struct {
+		StringValue *string  `json:"stringValue,omitempty"`
+		BoolValue   *bool    `json:"boolValue,omitempty"`
+		IntValue    *string  `json:"intValue,omitempty"`
+		DoubleValue *float64 `json:"doubleValue,omitempty"`
+	}
+)
+
+// Export sends the given spans to the collector.
+// Throws an error if the collector does not accept the spans.
+func (e *OTLPExporter) Export(ctx context.Context, spans []SpanData) error {
+	otlpSpans := make([]otlpSpan, 0, len(spans))
+	for _, span := range spans {
+		otlpSpans = append(otlpSpans, toOTLPSpan(span))
+	}
+
+	body, err := json.Marshal(otlpRequest{
+		ResourceSpans: []otlpResourceSpans{
+			{
+				Resource: otlpResource{
+					Attributes: []otlpKeyValue{toOTLPKeyValue("service.name", e.serviceName)},
+				},
+				ScopeSpans: []otlpScopeSpans{
+					{
+						Scope: otlpInstrumentationScope{Name: otlpScope},
+						Spans: otlpSpans,
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := e.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("collector responded with status: %s", response.Status)
+	}
+
+	return nil
+}
+
+// Shutdown closes idle connections to the collector.
+func (e *OTLPExporter) Shutdown(ctx context.Context) error {
+	e.client.CloseIdleConnections()
+	return nil
+}
+
+// toOTLPSpan returns the OTLP representation of a span.
+func toOTLPSpan(span SpanData) otlpSpan {
+	kind := otlpKindInternal
+	if _, ok := span.Attributes["http.route"]; ok {
+		kind = otlpKindServer
+	}
+
+	out := otlpSpan{
+		TraceId:           span.SpanContext.TraceId.String(),
+		SpanId:            span.SpanContext.SpanId.String(),
+		Name:              span.Name,
+		Kind:              kind,
+		StartTimeUnixNano: strconv.FormatInt(span.Start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(span.End.UnixNano(), 10),
+		Attributes:        make([]otlpKeyValue, 0, len(span.Attributes)),
+		Status:            otlpStatus{Code: otlpStatusUnset},
+	}
+
+	if span.ParentSpanId != (common.SpanId{}) {
+		out.ParentSpanId = span.ParentSpanId.String()
+	}
+
+	for key, value := range span.Attributes {
+		out.Attributes = append(out.Attributes, toOTLPKeyValue(key, value))
+	}
+
+	if span.Err != nil {
+		out.Status = otlpStatus{
+			Code:    otlpStatusError,
+			Message: span.Err.Error(),
+		}
+	}
+
+	return out
+}
+
+// toOTLPKeyValue returns the OTLP representation of an attribute.
+func toOTLPKeyValue(key string, value any) otlpKeyValue {
+	var anyValue otlpAnyValue
+
+	switch v := value.(type) {
+	case string:
+		anyValue.StringValue = &v
+	case bool:
+		anyValue.BoolValue = &v
+	case int:
+		intValue := strconv.FormatInt(int64(v), 10)
+		anyValue.IntValue = &intValue
+	case int64:
+		intValue := strconv.FormatInt(v, 10)
+		anyValue.IntValue = &intValue
+	case float64:
+		anyValue.DoubleValue = &v
+	default:
+		stringValue := fmt.Sprint(v)
+		anyValue.StringValue = &stringValue
+	}
+
+	return otlpKeyValue{
+		Key:   key,
+		Value: anyValue,
+	}
+}
@@ -0,0 +1,208 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/cosys-io/cosys/common"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// collector is an OTLP/HTTP receiver recording the spans it is sent.
+type collector struct {
+	*httptest.Server
+
+	mutex       sync.Mutex
+	spans       []otlpSpan
+	serviceName string
+}
+
+// newCollector starts a collector, which is closed at the end of the test.
+func newCollector(t *testing.T) *collector {
+	c := &collector{}
+
+	c.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/traces" {
+			http.NotFound(w, r)
+			return
+		}
+
+		if contentType := r.Header.Get("Content-Type"); contentType != "application/json" {
+			t.Errorf("unexpected content type: %s", contentType)
+		}
+
+		var request otlpRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("could not decode request: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+
+		for _, resourceSpans := range request.ResourceSpans {
+			for _, attribute := range resourceSpans.Resource.Attributes {
+				if attribute.Key == "service.name" && attribute.Value.StringValue != nil {
+					c.serviceName = *attribute.Value.StringValue
+				}
+			}
+
+			for _, scopeSpans := range resourceSpans.ScopeSpans {
+				c.spans = append(c.spans, scopeSpans.Spans...)
+			}
+		}
+	}))
+	t.Cleanup(c.Close)
+
+	return c
+}
+
+// span returns the recorded span with the given name.
+func (c *collector) span(t *testing.T, name string) otlpSpan {
+	t.Helper()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, span := range c.spans {
+		if span.Name == name {
+			return span
+		}
+	}
+
+	t.Fatalf("span not exported: %s", name)
+	return otlpSpan{}
+}
+
+// newTestTracer returns a tracer exporting to the given collector, and its started processor.
+func newTestTracer(c *collector) (*Tracer, *Processor) {
+	processor := NewProcessor(NewOTLPExporter(c.URL, "test-service"))
+	processor.Start()
+
+	return NewTracer(processor), processor
+}
+
+// shutdown exports the queued spans of the given processor.
+func shutdown(t *testing.T, processor *Processor) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := processor.Shutdown(ctx); err != nil {
+		t.Fatalf("could not shut down processor: %v", err)
+	}
+}
+
+func TestOTLPExportParentChild(t *testing.T) {
+	c := newCollector(t)
+	tracer, processor := newTestTracer(c)
+
+	ctx, parent := tracer.Start(context.Background(), "parent")
+	parent.SetAttribute("http.route", "/posts")
+	_, child := tracer.Start(ctx, "child")
+	child.SetAttribute("count", 3)
+	child.RecordError(context.DeadlineExceeded)
+	child.End()
+	parent.End()
+
+	shutdown(t, processor)
+
+	if c.serviceName != "test-service" {
+		t.Errorf("service name = %q, want %q", c.serviceName, "test-service")
+	}
+
+	exportedParent := c.span(t, "parent")
+	exportedChild := c.span(t, "child")
+
+	if exportedParent.TraceId != parent.SpanContext().TraceId.String() {
+		t.Errorf("parent trace id = %s, want %s", exportedParent.TraceId, parent.SpanContext().TraceId)
+	}
+	if exportedParent.SpanId != parent.SpanContext().SpanId.String() {
+		t.Errorf("parent span id = %s, want %s", exportedParent.SpanId, parent.SpanContext().SpanId)
+	}
+	if exportedParent.ParentSpanId != "" {
+		t.Errorf("root span has parent span id %s", exportedParent.ParentSpanId)
+	}
+	if exportedParent.Kind != otlpKindServer {
+		t.Errorf("parent kind = %d, want %d", exportedParent.Kind, otlpKindServer)
+	}
+
+	if exportedChild.TraceId != exportedParent.TraceId {
+		t.Errorf("child trace id = %s, want %s", exportedChild.TraceId, exportedParent.TraceId)
+	}
+	if exportedChild.ParentSpanId != exportedParent.SpanId {
+		t.Errorf("child parent span id = %s, want %s", exportedChild.ParentSpanId, exportedParent.SpanId)
+	}
+	if exportedChild.SpanId == exportedParent.SpanId {
+		t.Errorf("child and parent have the same span id %s", exportedChild.SpanId)
+	}
+	if exportedChild.Kind != otlpKindInternal {
+		t.Errorf("child kind = %d, want %d", exportedChild.Kind, otlpKindInternal)
+	}
+	if exportedChild.Status.Code != otlpStatusError || exportedChild.Status.Message != context.DeadlineExceeded.Error() {
+		t.Errorf("child status = %+v, want error %q", exportedChild.Status, context.DeadlineExceeded.Error())
+	}
+}
+
+func TestOTLPExportTraceparentPropagation(t *testing.T) {
+	c := newCollector(t)
+	tracer, processor := newTestTracer(c)
+
+	// The client starts a span and sends its span context in the traceparent header.
+	clientCtx, client := tracer.Start(context.Background(), "client")
+	header := http.Header{}
+	common.InjectTraceparent(clientCtx, header)
+	client.End()
+
+	if header.Get(common.TraceparentHeader) != client.SpanContext().Traceparent() {
+		t.Fatalf("traceparent = %q, want %q", header.Get(common.TraceparentHeader), client.SpanContext().Traceparent())
+	}
+
+	// The server continues the trace from the traceparent header, and propagates its own span downstream.
+	serverCtx, server := tracer.Start(common.ExtractTraceparent(context.Background(), header), "server")
+	downstream := http.Header{}
+	common.InjectTraceparent(serverCtx, downstream)
+	server.End()
+
+	shutdown(t, processor)
+
+	exportedClient := c.span(t, "client")
+	exportedServer := c.span(t, "server")
+
+	if exportedServer.TraceId != exportedClient.TraceId {
+		t.Errorf("server trace id = %s, want %s", exportedServer.TraceId, exportedClient.TraceId)
+	}
+	if exportedServer.ParentSpanId != exportedClient.SpanId {
+		t.Errorf("server parent span id = %s, want %s", exportedServer.ParentSpanId, exportedClient.SpanId)
+	}
+
+	propagated, err := common.ParseTraceparent(downstream.Get(common.TraceparentHeader))
+	if err != nil {
+		t.Fatalf("could not parse propagated traceparent: %v", err)
+	}
+	if propagated.TraceId.String() != exportedClient.TraceId {
+		t.Errorf("propagated trace id = %s, want %s", propagated.TraceId, exportedClient.TraceId)
+	}
+	if propagated.SpanId.String() != exportedServer.SpanId {
+		t.Errorf("propagated span id = %s, want %s", propagated.SpanId, exportedServer.SpanId)
+	}
+}
+
+func TestOTLPExportRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	err := NewOTLPExporter(server.URL, "test-service").Export(context.Background(), []SpanData{
+		{Name: "span", SpanContext: common.SpanContext{TraceId: newTraceId(), SpanId: newSpanId(), Sampled: true}},
+	})
+	if err == nil {
+		t.Fatal("expected an error for a rejected export")
+	}
+}
@@ -0,0 +1,136 @@
+package internal
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	queueSize     = 2048            // queueSize is the maximum number of ended spans waiting to be exported.
+	batchSize     = 512             // batchSize is the maximum number of spans exported at once.
+	batchInterval = 5 * time.Second // batchInterval is the maximum duration ended spans wait before being exported.
+)
+
+// Exporter sends ended spans to a tracing backend.
+type Exporter interface {
+	Export(ctx context.Context, spans []SpanData) error
+	Shutdown(ctx context.Context) error
+}
+
+// Processor batches ended spans and sends them to an exporter in the background.
+// Spans ended while the queue is full are dropped.
+type Processor struct {
+	exporter Exporter
+
+	mutex   sync.Mutex
+	started bool
+	closed  bool
+	queue   chan SpanData
+	done    chan struct{}
+}
+
+// NewProcessor returns a new Processor sending spans to the given exporter.
+func NewProcessor(exporter Exporter) *Processor {
+	return &Processor{
+		exporter: exporter,
+		queue:    make(chan SpanData, queueSize),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start starts exporting spans in the background.
+// Calls after the first have no effect.
+// Safe for concurrent use.
+func (p *Processor) Start() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.started || p.closed {
+		return
+	}
+	p.started = true
+
+	go p.run()
+}
+
+// OnEnd queues an ended span for exporting.
+// Safe for concurrent use.
+func (p *Processor) OnEnd(span SpanData) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.closed {
+		return
+	}
+
+	select {
+	case p.queue <- span:
+	default:
+	}
+}
+
+// Shutdown exports all queued spans and shuts down the exporter.
+// Calls after the first have no effect.
+// Safe for concurrent use.
+func (p *Processor) Shutdown(ctx context.Context) error {
+	p.mutex.Lock()
+	if p.closed {
+		p.mutex.Unlock()
+		return nil
+	}
+	p.closed = true
+	started := p.started
+	close(p.queue)
+	p.mutex.Unlock()
+
+	if !started {
+		go p.run()
+	}
+
+	select {
+	case <-p.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return p.exporter.Shutdown(ctx)
+}
+
+// run exports queued spans in batches until the queue is closed.
+func (p *Processor) run() {
+	defer close(p.done)
+
+	ticker := time.NewTicker(batchInterval)
+	defer ticker.Stop()
+
+	batch := make([]SpanData, 0, batchSize)
+	export := func() {
+		if len(batch) == 0 {
+			return
+		}
+
+		if err := p.exporter.Export(context.Background(), batch); err != nil {
+			log.Printf("tracing: could not export %d spans: %v", len(batch), err)
+		}
+		batch = make([]SpanData, 0, batchSize)
+	}
+
+	for {
+		select {
+		case span, ok := <-p.queue:
+			if !ok {
+				export()
+				return
+			}
+
+			batch = append(batch, span)
+			if len(batch) >= batchSize {
+				export()
+			}
+		case <-ticker.C:
+			export()
+		}
+	}
+}
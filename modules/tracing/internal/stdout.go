@@ -0,0 +1,73 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/cosys-io/cosys/common"
+	"io"
+	"sync"
+	"time"
+)
+
+// StdoutExporter is an exporter writing spans as JSON lines, e.g. to standard output.
+type StdoutExporter struct {
+	mutex  sync.Mutex
+	writer io.Writer
+}
+
+// stdoutSpan is the JSON representation of a span written by StdoutExporter.
+type stdoutSpan struct {
+	Name         string         `json:"name"`
+	TraceId      string         `json:"traceId"`
+	SpanId       string         `json:"spanId"`
+	ParentSpanId string         `json:"parentSpanId,omitempty"`
+	Start        time.Time      `json:"start"`
+	End          time.Time      `json:"end"`
+	Duration     string         `json:"duration"`
+	Attributes   map[string]any `json:"attributes,omitempty"`
+	Error        string         `json:"error,omitempty"`
+}
+
+// NewStdoutExporter returns a new StdoutExporter writing to the given writer.
+func NewStdoutExporter(writer io.Writer) *StdoutExporter {
+	return &StdoutExporter{
+		writer: writer,
+	}
+}
+
+// Export writes the given spans, one JSON object per line.
+// Safe for concurrent use.
+func (e *StdoutExporter) Export(ctx context.Context, spans []SpanData) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	encoder := json.NewEncoder(e.writer)
+	for _, span := range spans {
+		out := stdoutSpan{
+			Name:       span.Name,
+			TraceId:    span.SpanContext.TraceId.String(),
+			SpanId:     span.SpanContext.SpanId.String(),
+			Start:      span.Start,
+			End:        span.End,
+			Duration:   span.End.Sub(span.Start).String(),
+			Attributes: span.Attributes,
+		}
+		if span.ParentSpanId != (common.SpanId{}) {
+			out.ParentSpanId = span.ParentSpanId.String()
+		}
+		if span.Err != nil {
+			out.Error = span.Err.Error()
+		}
+
+		if err := encoder.Encode(out); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Shutdown does nothing, as the writer is not owned by the exporter.
+func (e *StdoutExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
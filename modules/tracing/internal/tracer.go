@@ -0,0 +1,156 @@
+package internal
+
+import (
+	"context"
+	"crypto/rand"
+	"github.com/cosys-io/cosys/common"
+	"sync"
+	"time"
+)
+
+// SpanData is the record of an ended span, passed to exporters.
+type SpanData struct {
+	Name         string
+	SpanContext  common.SpanContext
+	ParentSpanId common.SpanId
+	Start        time.Time
+	End          time.Time
+	Attributes   map[string]any
+	Err          error
+}
+
+// Tracer is an implementation of the Tracer core service,
+// which sends ended spans to a processor for exporting.
+type Tracer struct {
+	processor *Processor
+}
+
+// NewTracer returns a new Tracer sending ended spans to the given processor.
+func NewTracer(processor *Processor) *Tracer {
+	return &Tracer{
+		processor: processor,
+	}
+}
+
+// Start starts a span with the given name, as a child of the span or remote span context
+// in the given context, and returns a context containing the new span.
+func (t *Tracer) Start(ctx context.Context, name string) (context.Context, common.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	spanContext := common.SpanContext{
+		SpanId:  newSpanId(),
+		Sampled: true,
+	}
+
+	var parentSpanId common.SpanId
+	if parent, ok := common.SpanContextFromContext(ctx); ok {
+		spanContext.TraceId = parent.TraceId
+		spanContext.Sampled = parent.Sampled
+		parentSpanId = parent.SpanId
+	} else {
+		spanContext.TraceId = newTraceId()
+	}
+
+	span := &Span{
+		tracer: t,
+		data: SpanData{
+			Name:         name,
+			SpanContext:  spanContext,
+			ParentSpanId: parentSpanId,
+			Start:        time.Now(),
+			Attributes:   map[string]any{},
+		},
+	}
+
+	return common.ContextWithSpan(ctx, span), span
+}
+
+// Span is an implementation of Span created by Tracer.
+type Span struct {
+	tracer *Tracer
+
+	mutex sync.Mutex
+	data  SpanData
+	ended bool
+}
+
+// SpanContext returns the span context identifying the span.
+func (s *Span) SpanContext() common.SpanContext {
+	return s.data.SpanContext
+}
+
+// Tracer returns the tracer that created the span.
+func (s *Span) Tracer() common.Tracer {
+	return s.tracer
+}
+
+// SetAttribute sets an attribute of the span.
+// Safe for concurrent use.
+func (s *Span) SetAttribute(key string, value any) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.ended {
+		return
+	}
+
+	s.data.Attributes[key] = value
+}
+
+// RecordError marks the span as failed with the given error, if it is not nil.
+// Safe for concurrent use.
+func (s *Span) RecordError(err error) {
+	if err == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.ended {
+		return
+	}
+
+	s.data.Err = err
+}
+
+// End ends the span and sends it for exporting if it is sampled.
+// Calls after the first have no effect.
+// Safe for concurrent use.
+func (s *Span) End() {
+	s.mutex.Lock()
+	if s.ended {
+		s.mutex.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = time.Now()
+	data := s.data
+	s.mutex.Unlock()
+
+	if data.SpanContext.Sampled {
+		s.tracer.processor.OnEnd(data)
+	}
+}
+
+// newTraceId returns a random non-zero trace id.
+func newTraceId() common.TraceId {
+	var traceId common.TraceId
+	for traceId == (common.TraceId{}) {
+		_, _ = rand.Read(traceId[:])
+	}
+
+	return traceId
+}
+
+// newSpanId returns a random non-zero span id.
+func newSpanId() common.SpanId {
+	var spanId common.SpanId
+	for spanId == (common.SpanId{}) {
+		_, _ = rand.Read(spanId[:])
+	}
+
+	return spanId
+}
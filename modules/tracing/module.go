@@ -0,0 +1,147 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"github.com/cosys-io/cosys/common"
+	"github.com/cosys-io/cosys/modules/tracing/internal"
+	"os"
+	"sync"
+)
+
+// ModuleName is the name the module is registered with, which other modules can depend on.
+const ModuleName = "tracing"
+
+// Exporters of the tracing module.
+const (
+	StdoutExporter = "stdout" // StdoutExporter writes spans as JSON lines to standard output.
+	OTLPExporter   = "otlp"   // OTLPExporter sends spans to an OpenTelemetry collector using OTLP/HTTP.
+	NoExporter     = "none"   // NoExporter disables tracing.
+)
+
+// Config is the configuration of the tracing module.
+type Config struct {
+	Exporter    string // Exporter is the exporter that spans are sent to.
+	Endpoint    string // Endpoint is the base url of the OpenTelemetry collector for the otlp exporter.
+	ServiceName string // ServiceName is the name of the service reported to the collector.
+}
+
+var (
+	configMutex sync.RWMutex
+	config      = defaultConfig()
+
+	processor        *internal.Processor // processor batches and exports ended spans.
+	BootstrapHookKey string              // BootstrapHookKey can be used to update or remove the bootstrap hook.
+	CleanupHookKey   string              // CleanupHookKey can be used to update or remove the cleanup hook.
+)
+
+// hookPriority is the priority of the cleanup hook, and the negated priority of the bootstrap hook.
+const hookPriority = 100
+
+// defaultConfig returns the configuration from the standard OpenTelemetry environment variables,
+// defaulting to the stdout exporter.
+func defaultConfig() Config {
+	defaultConfig := Config{
+		Exporter:    StdoutExporter,
+		Endpoint:    "http://localhost:4318",
+		ServiceName: "cosys",
+	}
+
+	switch os.Getenv("OTEL_TRACES_EXPORTER") {
+	case "otlp":
+		defaultConfig.Exporter = OTLPExporter
+	case "none":
+		defaultConfig.Exporter = NoExporter
+	}
+
+	if endpoint := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"); endpoint != "" {
+		defaultConfig.Endpoint = endpoint
+	} else if endpoint = os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); endpoint != "" {
+		defaultConfig.Endpoint = endpoint
+	}
+
+	if serviceName := os.Getenv("OTEL_SERVICE_NAME"); serviceName != "" {
+		defaultConfig.ServiceName = serviceName
+	}
+
+	return defaultConfig
+}
+
+// Configure sets the configuration of the tracing module.
+// Must be called before the cosys app is created.
+// Safe for concurrent use.
+func Configure(newConfig Config) error {
+	switch newConfig.Exporter {
+	case StdoutExporter, NoExporter:
+	case OTLPExporter:
+		if newConfig.Endpoint == "" {
+			return fmt.Errorf("otlp endpoint not specified")
+		}
+	default:
+		return fmt.Errorf("exporter not found: %s", newConfig.Exporter)
+	}
+
+	if newConfig.ServiceName == "" {
+		newConfig.ServiceName = "cosys"
+	}
+
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	config = newConfig
+	return nil
+}
+
+// init registers the module to register the Tracer core service,
+// and the hooks starting and stopping the export of spans.
+func init() {
+	_ = common.RegisterNamedModule(ModuleName, func(cosys *common.Cosys) error {
+		configMutex.RLock()
+		moduleConfig := config
+		configMutex.RUnlock()
+
+		var exporter internal.Exporter
+		switch moduleConfig.Exporter {
+		case StdoutExporter:
+			exporter = internal.NewStdoutExporter(os.Stdout)
+		case OTLPExporter:
+			exporter = internal.NewOTLPExporter(moduleConfig.Endpoint, moduleConfig.ServiceName)
+		default:
+			return nil
+		}
+
+		processor = internal.NewProcessor(exporter)
+		if err := cosys.UseTracer(internal.NewTracer(processor)); err != nil {
+			return err
+		}
+
+		var err error
+
+		// The processor is started before, and shut down after, the hooks of other modules, so that their spans are exported.
+		BootstrapHookKey, err = cosys.AddBootstrapHook(bootstrap, common.HookPriority(-hookPriority))
+		if err != nil {
+			return err
+		}
+
+		CleanupHookKey, err = cosys.AddCleanupHook(cleanup, common.HookPriority(hookPriority))
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// bootstrap starts exporting spans in the background.
+func bootstrap(cosys *common.Cosys) error {
+	processor.Start()
+	return nil
+}
+
+// cleanup exports all remaining spans and shuts down the exporter.
+func cleanup(cosys *common.Cosys) error {
+	ctx, cancel := context.WithTimeout(context.Background(), cosys.ShutdownTimeout())
+	defer cancel()
+
+	return processor.Shutdown(ctx)
+}